@@ -0,0 +1,7 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_agents" "agents" {
+  organization_id = data.terrakube_organization.org.id
+}
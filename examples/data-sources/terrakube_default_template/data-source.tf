@@ -0,0 +1,8 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_default_template" "plan_apply" {
+  organization_id = data.terrakube_organization.org.id
+  kind            = "plan_apply"
+}
@@ -0,0 +1,14 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_health" "health" {
+  organization_id = data.terrakube_organization.org.id
+
+  lifecycle {
+    postcondition {
+      condition     = self.status == "UP" && self.pending_jobs + self.queued_jobs < 20
+      error_message = "Terrakube is down or its job queue is backed up."
+    }
+  }
+}
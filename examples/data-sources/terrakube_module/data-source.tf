@@ -0,0 +1,10 @@
+data "terrakube_module" "vpc" {
+  organization_id = data.terrakube_organization.org.id
+  name            = "vpc"
+  provider_name   = "aws"
+}
+
+output "vpc_module_created" {
+  # Attributes the provider does not expose yet are read from raw_attributes.
+  value = lookup(data.terrakube_module.vpc.raw_attributes, "createdDate", null)
+}
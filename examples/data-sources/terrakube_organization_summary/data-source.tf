@@ -0,0 +1,8 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_organization_summary" "report" {
+  organization_id    = data.terrakube_organization.org.id
+  include_job_status = true
+}
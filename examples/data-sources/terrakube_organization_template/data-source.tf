@@ -5,4 +5,16 @@ data "terrakube_organization" "org" {
 data "terrakube_organization_template" "template" {
   name            = "sample"
   organization_id = data.terrakube_organization.org.id
-}
\ No newline at end of file
+}
+
+data "terrakube_organization_template" "rendered" {
+  name            = "sample"
+  organization_id = data.terrakube_organization.org.id
+  parameters = {
+    terraform_version = "1.5.7"
+  }
+}
+
+output "template_missing_parameters" {
+  value = data.terrakube_organization_template.rendered.missing_parameters
+}
@@ -0,0 +1,7 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_ssh_keys" "keys" {
+  organization_id = data.terrakube_organization.org.id
+}
@@ -0,0 +1,8 @@
+data "terrakube_team" "platform" {
+  organization_id = data.terrakube_organization.org.id
+  name            = "TERRAKUBE_PLATFORM"
+}
+
+output "platform_can_manage_state" {
+  value = data.terrakube_team.platform.manage_state
+}
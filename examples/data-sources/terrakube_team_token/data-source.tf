@@ -0,0 +1,4 @@
+data "terrakube_team_token" "rotation" {
+  team_name         = "TERRAKUBE_ADMIN"
+  description_regex = "^rotation 2024-Q[1-4]$"
+}
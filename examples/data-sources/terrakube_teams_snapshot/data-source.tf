@@ -0,0 +1,14 @@
+data "terrakube_teams_snapshot" "all" {}
+
+resource "local_file" "teams_backup" {
+  filename = "${path.module}/backup/teams.json"
+  content  = data.terrakube_teams_snapshot.all.json
+}
+
+# Compare with the previous backup before it is overwritten.
+check "teams_unchanged" {
+  assert {
+    condition     = !fileexists("${path.module}/backup/teams.json") || filesha256("${path.module}/backup/teams.json") == data.terrakube_teams_snapshot.all.sha256
+    error_message = "Team permissions changed since the last backup, compare backup/teams.json with the new snapshot."
+  }
+}
@@ -0,0 +1,8 @@
+data "terrakube_workspace" "networking" {
+  organization_id = data.terrakube_organization.org.id
+  name            = "networking"
+}
+
+output "collection_precedence" {
+  value = [for collection in data.terrakube_workspace.networking.collections : collection.name]
+}
@@ -0,0 +1,8 @@
+data "terrakube_workspace_remote_state" "networking" {
+  organization = "platform"
+  workspace    = "networking"
+}
+
+output "vpc_id" {
+  value = data.terrakube_workspace_remote_state.networking.outputs.vpc_id
+}
@@ -0,0 +1,7 @@
+data "terrakube_workspace_schedules" "schedules" {
+  workspace_id = terrakube_workspace_cli.sample1.id
+}
+
+output "nightly_exists" {
+  value = contains(data.terrakube_workspace_schedules.schedules.schedules[*].cron, "0 0 0 ? * * *")
+}
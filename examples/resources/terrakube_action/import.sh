@@ -0,0 +1,2 @@
+# Action can be import with id
+terraform import terrakube_action.example 00000000-0000-0000-0000-000000000000
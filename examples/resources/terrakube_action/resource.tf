@@ -0,0 +1,8 @@
+resource "terrakube_action" "open_documentation" {
+  name     = "Open Documentation"
+  type     = "Workspace/Action"
+  category = "General"
+  version  = "1.0.0"
+  active   = true
+  action   = file("${path.module}/actions/open-documentation.js")
+}
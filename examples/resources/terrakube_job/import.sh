@@ -0,0 +1,2 @@
+# Job can be import with organization_id,id
+terraform import terrakube_job.example 00000000-0000-0000-0000-000000000000,1
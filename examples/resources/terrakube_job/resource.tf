@@ -0,0 +1,16 @@
+resource "terrakube_job" "apply" {
+  organization_id     = data.terrakube_organization.org.id
+  workspace_id        = terrakube_workspace_vcs.workspace.id
+  template_name       = "Terraform-Plan/Apply"
+  wait_for_completion = true
+  timeout             = "45m"
+
+  parameters = {
+    TARGET = "module.network"
+  }
+
+  # The value is read from the DEPLOY_KEY environment variable.
+  sensitive_parameters = {
+    DEPLOY_KEY = "DEPLOY_KEY"
+  }
+}
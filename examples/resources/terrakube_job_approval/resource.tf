@@ -0,0 +1,12 @@
+resource "terrakube_job" "promote" {
+  organization_id = data.terrakube_organization.org.id
+  workspace_id    = terrakube_workspace_vcs.production.id
+  template_name   = "plan-approve-apply"
+}
+
+resource "terrakube_job_approval" "promote" {
+  organization_id = data.terrakube_organization.org.id
+  job_id          = terrakube_job.promote.id
+  decision        = "approve"
+  timeout         = "15m"
+}
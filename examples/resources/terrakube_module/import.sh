@@ -0,0 +1,2 @@
+# Module can be import with organization_id,id
+terraform import terrakube_module.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
@@ -0,0 +1,2 @@
+# Module version can be import with organization_id,module_id,version
+terraform import terrakube_module_version.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000,v5.1.0
@@ -0,0 +1,5 @@
+resource "terrakube_module_version" "vpc" {
+  organization_id = data.terrakube_organization.org.id
+  module_id       = terrakube_module.module.id
+  version         = "v5.1.0"
+}
@@ -0,0 +1,2 @@
+# Organization can be import with the organization id
+terraform import terrakube_organization.organization 00000000-0000-0000-0000-000000000000
@@ -1,5 +1,5 @@
 resource "terrakube_organization" "organization" {
-  name            = "sample-organization"
-  description     = "sample organization description"
-  executionModule = "remote"
+  name           = "sample-organization"
+  description    = "sample organization description"
+  execution_mode = "remote"
 }
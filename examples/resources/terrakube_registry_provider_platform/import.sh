@@ -0,0 +1,2 @@
+# Registry provider platform can be import with organization_id,provider_id,version_id,id
+terraform import terrakube_registry_provider_platform.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
@@ -0,0 +1,14 @@
+resource "terrakube_registry_provider_platform" "linux_amd64" {
+  organization_id       = data.terrakube_organization.org.id
+  provider_id           = terrakube_registry_provider_version.random.provider_id
+  version_id            = terrakube_registry_provider_version.random.id
+  os                    = "linux"
+  arch                  = "amd64"
+  filename              = "terraform-provider-random_3.6.0_linux_amd64.zip"
+  download_url          = "https://releases.example.com/terraform-provider-random/3.6.0/terraform-provider-random_3.6.0_linux_amd64.zip"
+  shasum                = "0000000000000000000000000000000000000000000000000000000000000000"
+  shasums_url           = "https://releases.example.com/terraform-provider-random/3.6.0/terraform-provider-random_3.6.0_SHA256SUMS"
+  shasums_signature_url = "https://releases.example.com/terraform-provider-random/3.6.0/terraform-provider-random_3.6.0_SHA256SUMS.sig"
+  key_id                = "51852D87348FFC4C"
+  ascii_armor           = file("signing-key.asc")
+}
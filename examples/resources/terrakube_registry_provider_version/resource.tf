@@ -0,0 +1,6 @@
+resource "terrakube_registry_provider_version" "random" {
+  organization_id = data.terrakube_organization.org.id
+  provider_id     = "00000000-0000-0000-0000-000000000000"
+  version_number  = "3.6.0"
+  protocols       = ["5.0"]
+}
@@ -1,2 +1,2 @@
-# Team can be import with organization_id,id
+# SSH key can be import with organization_id,id
 terraform import terrakube_ssh.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
@@ -1,2 +1,3 @@
-# Organization VCS can be import with organization_id,id
+# Organization VCS can be import with organization_id,id. Secrets are not read back,
+# leave client_secret unset to keep the secret already set in Terrakube.
 terraform import terrakube_vcs.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
\ No newline at end of file
@@ -8,3 +8,10 @@ resource "terrakube_vcs" "vcs" {
   endpoint        = "https://github.com"
   api_url         = "https://api.github.com"
 }
+
+# callback_url is known right after the connection is created, so the OAuth
+# application can be registered in the same apply by any resource that
+# references it.
+output "vcs_callback_url" {
+  value = terrakube_vcs.vcs.callback_url
+}
@@ -0,0 +1,2 @@
+# Workspace access can be import with organization_id,workspace_id,id
+terraform import terrakube_workspace_access.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
@@ -6,4 +6,17 @@ resource "terrakube_workspace_access" "workspace_access" {
   manage_job       = true
   manage_state     = false
   manage_workspace = false
-}
\ No newline at end of file
+}
+resource "terrakube_workspace_access" "workspace_admins" {
+  name            = "my_terrakube_admins"
+  organization_id = "my_organization_id"
+  workspace_id    = "my_workspace_id"
+  preset          = "admin"
+}
+
+resource "terrakube_workspace_access" "workspace_operators" {
+  team_id         = terrakube_team.operators.id
+  organization_id = "my_organization_id"
+  workspace_id    = "my_workspace_id"
+  preset          = "write"
+}
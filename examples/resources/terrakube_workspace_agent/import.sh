@@ -0,0 +1,2 @@
+# Workspace agent can be import with organization_id,workspace_id
+terraform import terrakube_workspace_agent.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
@@ -0,0 +1,5 @@
+resource "terrakube_workspace_agent" "private_network" {
+  organization_id = data.terrakube_organization.org.id
+  workspace_id    = terrakube_workspace_vcs.workspace.id
+  agent_id        = terrakube_self_hosted_agent.private_network.id
+}
@@ -0,0 +1,2 @@
+# Run trigger can be import with organization_id,workspace_id,id
+terraform import terrakube_workspace_run_trigger.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
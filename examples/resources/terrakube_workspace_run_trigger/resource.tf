@@ -0,0 +1,16 @@
+resource "terrakube_workspace_run_trigger" "networking_to_compute" {
+  organization_id         = data.terrakube_organization.org.id
+  workspace_id            = terrakube_workspace_vcs.networking.id
+  downstream_workspace_id = terrakube_workspace_vcs.compute.id
+  downstream_template_id  = data.terrakube_organization_template.apply.id
+  token                   = terrakube_team_token.run_trigger.value
+}
+
+resource "terrakube_workspace_webhook" "networking" {
+  organization_id = data.terrakube_organization.org.id
+  workspace_id    = terrakube_workspace_vcs.networking.id
+  path            = ["/networking/.*.tf"]
+  branch          = ["main"]
+  template_id     = terrakube_workspace_run_trigger.networking_to_compute.template_id
+  event           = "PUSH"
+}
@@ -0,0 +1,2 @@
+# Workspace SSH key can be import with organization_id,workspace_id
+terraform import terrakube_workspace_ssh_key.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
@@ -0,0 +1,5 @@
+resource "terrakube_workspace_ssh_key" "submodules" {
+  organization_id = data.terrakube_organization.org.id
+  workspace_id    = terrakube_workspace_vcs.workspace.id
+  ssh_id          = terrakube_ssh.submodules.id
+}
@@ -0,0 +1,4 @@
+resource "terrakube_workspace_state" "migrated" {
+  workspace_id = terrakube_workspace_cli.workspace.id
+  state        = "${path.module}/states/networking.tfstate"
+}
@@ -0,0 +1,2 @@
+# Workspace Variables can be import with organization_id,workspace_id, every variable of the workspace is adopted
+terraform import terrakube_workspace_variables.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
@@ -0,0 +1,17 @@
+resource "terrakube_workspace_variables" "sample1" {
+  organization_id = data.terrakube_organization.org.id
+  workspace_id    = terrakube_workspace_cli.sample1.id
+
+  variables = {
+    "sample-env-var" = {
+      value       = "sample-value"
+      description = "sample env var"
+      category    = "ENV"
+    }
+    "sample-terra-var" = {
+      value     = "sample-TERRAFORM"
+      category  = "TERRAFORM"
+      sensitive = true
+    }
+  }
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultAcceptedTimeout bounds the wait for an accepted create or
+	// delete when the context of the request has no deadline.
+	DefaultAcceptedTimeout = 10 * time.Minute
+
+	acceptedInterval = 2 * time.Second
+)
+
+// acceptedTransport waits for the creates and deletes of the JSON:API that
+// are answered with 202 Accepted, so the callers, the Crud as well as the
+// resources sending their own requests, only see the final response: 201
+// Created with the entity for a POST, 204 No Content for a DELETE. The wait
+// ends with the deadline of the request context, DefaultAcceptedTimeout
+// without one.
+type acceptedTransport struct {
+	next http.RoundTripper
+
+	// interval between two polls when the response has no Retry-After,
+	// acceptedInterval when zero.
+	interval time.Duration
+}
+
+func (t *acceptedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.next.RoundTrip(request)
+	if err != nil || response.StatusCode != http.StatusAccepted || !strings.Contains(request.URL.Path, "/api/v1/") {
+		return response, err
+	}
+
+	switch request.Method {
+	case http.MethodPost:
+		return t.awaitCreated(request, response)
+	case http.MethodDelete:
+		return t.awaitDeleted(request, response)
+	}
+	return response, nil
+}
+
+// awaitCreated polls the Location of the response, the status URL or the
+// entity itself, until it returns a JSON:API document with the entity.
+// Without Location the id of the entity in the body of the response is
+// polled instead. A status URL answering with something else than the
+// entity, or 404, is polled again.
+func (t *acceptedTransport) awaitCreated(request *http.Request, accepted *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(accepted.Body)
+	accepted.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	statusUrl := acceptedLocation(request, accepted)
+	if statusUrl == "" {
+		id := documentId(body)
+		if id == "" {
+			return nil, fmt.Errorf("the create was accepted without a Location header or entity id to follow: %s", string(body))
+		}
+		statusUrl = strings.TrimSuffix(request.URL.String(), "/") + "/" + id
+	}
+
+	return t.await(request, accepted, statusUrl, func(response *http.Response, body []byte) bool {
+		if response.StatusCode != http.StatusOK || documentId(body) == "" {
+			return false
+		}
+		response.StatusCode = http.StatusCreated
+		response.Status = "201 Created"
+		return true
+	})
+}
+
+// awaitDeleted polls the entity until it answers 404 or 410.
+func (t *acceptedTransport) awaitDeleted(request *http.Request, accepted *http.Response) (*http.Response, error) {
+	accepted.Body.Close()
+
+	return t.await(request, accepted, request.URL.String(), func(response *http.Response, body []byte) bool {
+		if response.StatusCode != http.StatusNotFound && response.StatusCode != http.StatusGone {
+			return false
+		}
+		response.StatusCode = http.StatusNoContent
+		response.Status = "204 No Content"
+		return true
+	})
+}
+
+// await sends a GET to pollUrl until done accepts the response, waiting
+// between the polls as long as the Retry-After header of the accepted
+// response asks. The accepted response is returned by done with its final
+// status, its body is read before returning so it outlives the polling.
+func (t *acceptedTransport) await(request *http.Request, accepted *http.Response, pollUrl string, done func(response *http.Response, body []byte) bool) (*http.Response, error) {
+	ctx := request.Context()
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultAcceptedTimeout)
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+	timeout := time.Until(deadline).Round(time.Second)
+
+	interval := t.interval
+	if interval <= 0 {
+		interval = acceptedInterval
+	}
+	if seconds, err := strconv.Atoi(accepted.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	for {
+		tflog.Debug(ctx, "Waiting for accepted request", map[string]any{"method": request.Method, "url": pollUrl})
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("the %s request was accepted but did not complete within %s", request.Method, timeout)
+			}
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		poll, err := http.NewRequestWithContext(ctx, http.MethodGet, pollUrl, nil)
+		if err != nil {
+			return nil, err
+		}
+		poll.Header = request.Header.Clone()
+		poll.Header.Del("Content-Type")
+
+		response, err := t.next.RoundTrip(poll)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return nil, err
+		}
+
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode >= 400 && response.StatusCode != http.StatusNotFound && response.StatusCode != http.StatusGone {
+			response.Body = io.NopCloser(bytes.NewReader(body))
+			return response, nil
+		}
+
+		if done(response, body) {
+			if response.StatusCode == http.StatusNoContent {
+				body = nil
+			}
+			response.Body = io.NopCloser(bytes.NewReader(body))
+			response.ContentLength = int64(len(body))
+			response.Header.Del("Content-Length")
+			response.Request = request
+			return response, nil
+		}
+	}
+}
+
+// documentId returns the id of the entity of a JSON:API document, empty for
+// any other body.
+func documentId(body []byte) string {
+	var document struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return ""
+	}
+	return document.Data.ID
+}
+
+// acceptedLocation returns the Location of the response resolved against
+// the request URL, empty without one.
+func acceptedLocation(request *http.Request, response *http.Response) string {
+	value := response.Header.Get("Location")
+	if value == "" {
+		return ""
+	}
+	location, err := request.URL.Parse(value)
+	if err != nil {
+		return ""
+	}
+	return location.String()
+}
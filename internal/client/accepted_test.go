@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const teamDocument = `{"data":{"type":"team","id":"t1","attributes":{"name":"developers"}}}`
+
+func newAcceptedClient() *http.Client {
+	return &http.Client{Transport: &acceptedTransport{next: http.DefaultTransport, interval: 10 * time.Millisecond}}
+}
+
+func TestAcceptedTransportCreated(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(teamDocument))
+	}))
+	defer server.Close()
+
+	teams := NewCrud[TeamEntity](newAcceptedClient(), server.URL, "token", "/api/v1/organization/%s/team")
+	team, err := teams.Create(context.Background(), &TeamEntity{Name: "developers"}, "o1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if team.ID != "t1" || team.Name != "developers" {
+		t.Errorf("unexpected team: %+v", team)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", requests.Load())
+	}
+}
+
+func TestAcceptedTransportAcceptedCreate(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch {
+		case r.Method == http.MethodPost:
+			w.Header().Set("Location", "/api/v1/organization/o1/team/t1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/organization/o1/team/t1":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			// The entity exists from the third poll on.
+			if polls.Add(1) < 3 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(teamDocument))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	teams := NewCrud[TeamEntity](newAcceptedClient(), server.URL, "token", "/api/v1/organization/%s/team")
+	team, err := teams.Create(context.Background(), &TeamEntity{Name: "developers"}, "o1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if team.ID != "t1" {
+		t.Errorf("expected team t1, got %+v", team)
+	}
+	if polls.Load() != 3 {
+		t.Errorf("expected 3 polls, got %d", polls.Load())
+	}
+}
+
+func TestAcceptedTransportAcceptedDelete(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodGet:
+			if polls.Add(1) < 2 {
+				w.Header().Set("Content-Type", "application/vnd.api+json")
+				w.Write([]byte(teamDocument))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// A resource sending its own request sees the final status.
+	request, _ := http.NewRequest(http.MethodDelete, server.URL+"/api/v1/organization/o1/tag/t1", nil)
+	response, err := newAcceptedClient().Do(request)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", response.StatusCode)
+	}
+	if polls.Load() != 2 {
+		t.Errorf("expected 2 polls, got %d", polls.Load())
+	}
+}
+
+func TestAcceptedTransportTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Header().Set("Location", "/api/v1/organization/o1/team/t1")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	teams := NewCrud[TeamEntity](newAcceptedClient(), server.URL, "token", "/api/v1/organization/%s/team")
+	_, err := teams.Create(ctx, &TeamEntity{Name: "developers"}, "o1")
+	if err == nil || !strings.Contains(err.Error(), "was accepted but did not complete") {
+		t.Fatalf("expected an accepted timeout error, got %v", err)
+	}
+}
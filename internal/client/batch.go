@@ -0,0 +1,237 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	batchWindow  = 50 * time.Millisecond
+	batchMaxSize = 100
+	batchTimeout = 2 * time.Minute
+
+	atomicContentType = `application/vnd.api+json; ext="https://jsonapi.org/ext/atomic"`
+)
+
+// Batcher coalesces the PATCH requests issued concurrently during an apply
+// into JSON:API atomic operations sent to /api/v1/operations. Requests are
+// collected for a short window, each caller waits for the result of its own
+// operation.
+//
+// Atomic operations are all or nothing: when one operation fails it gets the
+// error of the server and the others of the same batch are sent again one by
+// one, so a single bad resource does not fail its neighbours.
+type Batcher struct {
+	httpClient *http.Client
+	endpoint   string
+	token      string
+
+	mu      sync.Mutex
+	pending []*batchItem
+	timer   *time.Timer
+}
+
+type batchItem struct {
+	operation atomicOperation
+	fallback  func() error
+	done      chan error
+}
+
+type atomicOperation struct {
+	Op   string          `json:"op"`
+	Href string          `json:"href"`
+	Data json.RawMessage `json:"data"`
+}
+
+type atomicRequest struct {
+	Operations []atomicOperation `json:"atomic:operations"`
+}
+
+type atomicError struct {
+	Status string `json:"status"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+	Source struct {
+		Pointer string `json:"pointer"`
+	} `json:"source"`
+}
+
+func NewBatcher(httpClient *http.Client, endpoint string, token string) *Batcher {
+	return &Batcher{
+		httpClient: httpClient,
+		endpoint:   endpoint,
+		token:      token,
+	}
+}
+
+// Update queues an update of the entity found at href, a path relative to
+// /api/v1. The fallback sends the same update without batching and is used
+// when the batch is rejected because of another operation.
+func (b *Batcher) Update(ctx context.Context, href string, entity any, fallback func() error) error {
+	var out = new(bytes.Buffer)
+	if err := jsonapi.MarshalPayload(out, entity); err != nil {
+		return fmt.Errorf("unable to marshal payload: %w", err)
+	}
+
+	var document struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &document); err != nil {
+		return fmt.Errorf("unable to marshal payload: %w", err)
+	}
+
+	item := &batchItem{
+		operation: atomicOperation{Op: "update", Href: href, Data: document.Data},
+		fallback:  fallback,
+		done:      make(chan error, 1),
+	}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, item)
+	if len(b.pending) >= batchMaxSize {
+		b.flushLocked(ctx)
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(batchWindow, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.flushLocked(ctx)
+		})
+	}
+	b.mu.Unlock()
+
+	select {
+	case err := <-item.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Batcher) flushLocked(ctx context.Context) {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	items := b.pending
+	b.pending = nil
+
+	if len(items) > 0 {
+		go b.send(ctx, items)
+	}
+}
+
+// send runs detached from the caller that flushed the batch, the batch
+// carries the updates of other callers which must not fail when that one is
+// canceled.
+func (b *Batcher) send(ctx context.Context, items []*batchItem) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), batchTimeout)
+	defer cancel()
+
+	errs := b.sendOperations(ctx, items)
+
+	for i, item := range items {
+		switch {
+		case errs == nil:
+			item.done <- nil
+		case errs[i] != nil:
+			item.done <- errs[i]
+		default:
+			item.done <- item.fallback()
+		}
+	}
+}
+
+// sendOperations returns nil when every operation was applied, otherwise one
+// error per operation. Operations that were only rolled back have a nil
+// error.
+func (b *Batcher) sendOperations(ctx context.Context, items []*batchItem) []error {
+	body := atomicRequest{Operations: make([]atomicOperation, len(items))}
+	for i, item := range items {
+		body.Operations[i] = item.operation
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return batchErrors(len(items), err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint+"/api/v1/operations", bytes.NewReader(payload))
+	if err != nil {
+		return batchErrors(len(items), fmt.Errorf("error creating request: %w", err))
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", b.token))
+	request.Header.Add("Content-Type", atomicContentType)
+	request.Header.Add("Accept", atomicContentType)
+
+	response, err := b.httpClient.Do(request)
+	if err != nil {
+		return batchErrors(len(items), fmt.Errorf("error executing request: %w", err))
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return batchErrors(len(items), fmt.Errorf("error reading response body: %w", err))
+	}
+
+	tflog.Info(ctx, "Batch Response", map[string]any{"operations": len(items), "statusCode": response.StatusCode, "bodyResponse": string(responseBody)})
+
+	if response.StatusCode < 300 {
+		return nil
+	}
+
+	var document struct {
+		Errors []atomicError `json:"errors"`
+	}
+	_ = json.Unmarshal(responseBody, &document)
+
+	errs := make([]error, len(items))
+	matched := false
+	for _, apiError := range document.Errors {
+		index, ok := operationIndex(apiError.Source.Pointer)
+		if !ok || index >= len(items) {
+			continue
+		}
+		matched = true
+		errs[index] = fmt.Errorf("batched update of %s failed with status %s: %s %s", items[index].operation.Href, apiError.Status, apiError.Title, apiError.Detail)
+	}
+
+	// Without a pointer to the failed operation every update is sent again
+	// on its own, which also covers servers without atomic operations.
+	if !matched {
+		tflog.Warn(ctx, "Batch rejected, sending updates one by one", map[string]any{"statusCode": response.StatusCode})
+	}
+
+	return errs
+}
+
+// operationIndex parses pointers like "/atomic:operations/3" or
+// "/atomic:operations/3/data/attributes/name".
+func operationIndex(pointer string) (int, bool) {
+	rest, found := strings.CutPrefix(pointer, "/atomic:operations/")
+	if !found {
+		return 0, false
+	}
+	index, err := strconv.Atoi(strings.SplitN(rest, "/", 2)[0])
+	return index, err == nil
+}
+
+func batchErrors(size int, err error) []error {
+	errs := make([]error, size)
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
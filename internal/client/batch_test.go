@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// batchServer answers /api/v1/operations with the status and body of
+// answer, keeping the operations of every batch.
+type batchServer struct {
+	mu      sync.Mutex
+	batches [][]atomicOperation
+	answer  func(operations []atomicOperation) (int, string)
+}
+
+func (s *batchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request atomicRequest
+	if r.URL.Path != "/api/v1/operations" || json.NewDecoder(r.Body).Decode(&request) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.batches = append(s.batches, request.Operations)
+	s.mu.Unlock()
+
+	status, body := s.answer(request.Operations)
+	w.Header().Set("Content-Type", atomicContentType)
+	w.WriteHeader(status)
+	fmt.Fprint(w, body)
+}
+
+// updateTeams sends one batched update per context concurrently and
+// returns their errors and how many fell back to a single PATCH.
+func updateTeams(batcher *Batcher, contexts []context.Context) ([]error, int) {
+	errs := make([]error, len(contexts))
+	var fallbacks atomic.Int32
+	var wg sync.WaitGroup
+	for i, ctx := range contexts {
+		i, ctx := i, ctx
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			team := &TeamEntity{ID: fmt.Sprintf("t%d", i), Name: fmt.Sprintf("team-%d", i)}
+			errs[i] = batcher.Update(ctx, "/organization/o1/team/"+team.ID, team, func() error {
+				fallbacks.Add(1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	return errs, int(fallbacks.Load())
+}
+
+func backgroundContexts(count int) []context.Context {
+	contexts := make([]context.Context, count)
+	for i := range contexts {
+		contexts[i] = context.Background()
+	}
+	return contexts
+}
+
+func TestBatcherCoalescesUpdates(t *testing.T) {
+	t.Parallel()
+
+	api := &batchServer{answer: func([]atomicOperation) (int, string) { return http.StatusOK, `{"atomic:results":[]}` }}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	errs, fallbacks := updateTeams(NewBatcher(http.DefaultClient, server.URL, "token"), backgroundContexts(5))
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("update %d: unexpected error: %s", i, err)
+		}
+	}
+	if fallbacks != 0 {
+		t.Errorf("expected no fallback, got %d", fallbacks)
+	}
+	if len(api.batches) != 1 || len(api.batches[0]) != 5 {
+		t.Fatalf("expected one batch of 5 operations, got %v", api.batches)
+	}
+	for _, operation := range api.batches[0] {
+		if operation.Op != "update" || !strings.HasPrefix(operation.Href, "/organization/o1/team/t") {
+			t.Errorf("unexpected operation %+v", operation)
+		}
+	}
+}
+
+func TestBatcherFallsBackWithoutPointer(t *testing.T) {
+	t.Parallel()
+
+	api := &batchServer{answer: func([]atomicOperation) (int, string) {
+		return http.StatusNotFound, `{"errors":[{"status":"404","title":"Not Found"}]}`
+	}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	errs, fallbacks := updateTeams(NewBatcher(http.DefaultClient, server.URL, "token"), backgroundContexts(4))
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("update %d: unexpected error: %s", i, err)
+		}
+	}
+	if fallbacks != 4 {
+		t.Errorf("every update should be sent again on its own, got %d fallbacks", fallbacks)
+	}
+}
+
+func TestBatcherErrorFanOut(t *testing.T) {
+	t.Parallel()
+
+	api := &batchServer{answer: func(operations []atomicOperation) (int, string) {
+		for i, operation := range operations {
+			if operation.Href == "/organization/o1/team/t2" {
+				return http.StatusBadRequest, fmt.Sprintf(`{"errors":[{"status":"400","title":"Invalid","detail":"name taken","source":{"pointer":"/atomic:operations/%d/data/attributes/name"}}]}`, i)
+			}
+		}
+		return http.StatusOK, `{}`
+	}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	errs, fallbacks := updateTeams(NewBatcher(http.DefaultClient, server.URL, "token"), backgroundContexts(4))
+	for i, err := range errs {
+		switch {
+		case i == 2 && (err == nil || !strings.Contains(err.Error(), "name taken")):
+			t.Errorf("the failed operation should get the error of the server, got %v", err)
+		case i != 2 && err != nil:
+			t.Errorf("update %d: unexpected error: %s", i, err)
+		}
+	}
+	if fallbacks != 3 {
+		t.Errorf("the rolled back updates should be sent again on their own, got %d fallbacks", fallbacks)
+	}
+}
+
+func TestBatcherIgnoresCanceledOpener(t *testing.T) {
+	t.Parallel()
+
+	api := &batchServer{answer: func([]atomicOperation) (int, string) { return http.StatusOK, `{}` }}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	batcher := NewBatcher(http.DefaultClient, server.URL, "token")
+
+	// The first update opens the batch and gives up before it is sent.
+	opener, cancel := context.WithCancel(context.Background())
+	openerErr := make(chan error, 1)
+	go func() {
+		openerErr <- batcher.Update(opener, "/organization/o1/team/opener", &TeamEntity{ID: "opener"}, func() error { return nil })
+	}()
+	for {
+		batcher.mu.Lock()
+		queued := len(batcher.pending)
+		batcher.mu.Unlock()
+		if queued == 1 {
+			break
+		}
+	}
+	cancel()
+
+	errs, _ := updateTeams(batcher, backgroundContexts(3))
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("update %d failed with the context of the opener: %s", i, err)
+		}
+	}
+	if err := <-openerErr; err != context.Canceled {
+		t.Errorf("the opener should get its own cancellation, got %v", err)
+	}
+}
@@ -46,21 +46,24 @@ type TeamTokenEntity struct {
 	Minutes     int32  `json:"minutes"`
 	Group       string `json:"group"`
 	Value       string `json:"token"`
+	CreatedDate string `json:"createdDate,omitempty"`
 }
 
 type WorkspaceEntity struct {
-	ID            string     `jsonapi:"primary,workspace"`
-	Name          string     `jsonapi:"attr,name"`
-	Description   string     `jsonapi:"attr,description"`
-	Source        string     `jsonapi:"attr,source"`
-	Branch        string     `jsonapi:"attr,branch"`
-	Folder        string     `jsonapi:"attr,folder"`
-	TemplateId    string     `jsonapi:"attr,defaultTemplate"`
-	IaCType       string     `jsonapi:"attr,iacType"`
-	IaCVersion    string     `jsonapi:"attr,terraformVersion"`
-	ExecutionMode string     `jsonapi:"attr,executionMode"`
-	Deleted       bool       `jsonapi:"attr,deleted"`
-	Vcs           *VcsEntity `jsonapi:"relation,vcs,omitempty"`
+	ID            string       `jsonapi:"primary,workspace"`
+	Name          string       `jsonapi:"attr,name"`
+	Description   string       `jsonapi:"attr,description"`
+	Source        string       `jsonapi:"attr,source"`
+	Branch        string       `jsonapi:"attr,branch"`
+	Folder        string       `jsonapi:"attr,folder"`
+	TemplateId    string       `jsonapi:"attr,defaultTemplate"`
+	IaCType       string       `jsonapi:"attr,iacType"`
+	IaCVersion    string       `jsonapi:"attr,terraformVersion"`
+	ExecutionMode string       `jsonapi:"attr,executionMode"`
+	Deleted       bool         `jsonapi:"attr,deleted"`
+	Vcs           *VcsEntity   `jsonapi:"relation,vcs,omitempty"`
+	Ssh           *SshEntity   `jsonapi:"relation,ssh,omitempty"`
+	Agent         *AgentEntity `jsonapi:"relation,agent,omitempty"`
 }
 
 type WorkspaceTagEntity struct {
@@ -79,11 +82,12 @@ type WorkspaceVariableEntity struct {
 }
 
 type WorkspaceAccessEntity struct {
-	ID              string `jsonapi:"primary,access"`
-	ManageState     bool   `jsonapi:"attr,manageState"`
-	ManageWorkspace bool   `jsonapi:"attr,manageWorkspace"`
-	ManageJob       bool   `jsonapi:"attr,manageJob"`
-	Name            string `jsonapi:"attr,name"`
+	ID              string  `jsonapi:"primary,access"`
+	ManageState     bool    `jsonapi:"attr,manageState"`
+	ManageWorkspace bool    `jsonapi:"attr,manageWorkspace"`
+	ManageJob       bool    `jsonapi:"attr,manageJob"`
+	Name            string  `jsonapi:"attr,name"`
+	ExpiresAt       *string `jsonapi:"attr,expiresAt"`
 }
 
 type OrganizationVariableEntity struct {
@@ -130,6 +134,32 @@ type ModuleEntity struct {
 	TagPrefix   *string    `jsonapi:"attr,tagPrefix"`
 }
 
+type RegistryProviderEntity struct {
+	ID          string `jsonapi:"primary,provider"`
+	Name        string `jsonapi:"attr,name"`
+	Description string `jsonapi:"attr,description"`
+}
+
+type RegistryProviderVersionEntity struct {
+	ID            string                  `jsonapi:"primary,version"`
+	VersionNumber string                  `jsonapi:"attr,versionNumber"`
+	Protocols     string                  `jsonapi:"attr,protocols"`
+	Provider      *RegistryProviderEntity `jsonapi:"relation,provider,omitempty"`
+}
+
+type RegistryProviderImplementationEntity struct {
+	ID                  string `jsonapi:"primary,implementation"`
+	Os                  string `jsonapi:"attr,os"`
+	Arch                string `jsonapi:"attr,arch"`
+	Filename            string `jsonapi:"attr,filename"`
+	DownloadUrl         string `jsonapi:"attr,downloadUrl"`
+	ShasumsUrl          string `jsonapi:"attr,shasumsUrl"`
+	ShasumsSignatureUrl string `jsonapi:"attr,shasumsSignatureUrl"`
+	Shasum              string `jsonapi:"attr,shasum"`
+	KeyId               string `jsonapi:"attr,keyId"`
+	AsciiArmor          string `jsonapi:"attr,asciiArmor"`
+}
+
 type CollectionEntity struct {
 	ID          string `jsonapi:"primary,collection"`
 	Name        string `jsonapi:"attr,name"`
@@ -174,4 +204,34 @@ type WorkspaceScheduleEntity struct {
 	ID         string `jsonapi:"primary,schedule"`
 	Schedule   string `jsonapi:"attr,cron"`
 	TemplateId string `jsonapi:"attr,templateReference"`
+	Enabled    *bool  `jsonapi:"attr,enabled,omitempty"`
+}
+
+type JobEntity struct {
+	ID                string                 `jsonapi:"primary,job"`
+	Status            string                 `jsonapi:"attr,status,omitempty"`
+	TemplateReference string                 `jsonapi:"attr,templateReference,omitempty"`
+	Tcl               string                 `jsonapi:"attr,tcl,omitempty"`
+	Comments          string                 `jsonapi:"attr,comments,omitempty"`
+	ApprovalTeam      string                 `jsonapi:"attr,approvalTeam,omitempty"`
+	Parameters        map[string]interface{} `jsonapi:"attr,parameters,omitempty"`
+	Workspace         *WorkspaceEntity       `jsonapi:"relation,workspace,omitempty"`
+}
+
+type JobStepEntity struct {
+	ID         string `jsonapi:"primary,step"`
+	StepNumber int32  `jsonapi:"attr,stepNumber"`
+	Name       string `jsonapi:"attr,name"`
+	Status     string `jsonapi:"attr,status"`
+	Output     string `jsonapi:"attr,output"`
+}
+
+type ActionEntity struct {
+	ID       string `jsonapi:"primary,action"`
+	Name     string `jsonapi:"attr,name"`
+	Type     string `jsonapi:"attr,type"`
+	Category string `jsonapi:"attr,category"`
+	Version  string `jsonapi:"attr,version"`
+	Action   string `jsonapi:"attr,action"`
+	Active   bool   `jsonapi:"attr,active"`
 }
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/jsonapi"
+)
+
+// jsonapiEntities lists every entity exchanged as a JSON:API document, a new
+// entity only needs to be added here to be covered by the round trip test.
+var jsonapiEntities = []any{
+	&OrganizationEntity{},
+	&OrganizationTemplateEntity{},
+	&OrganizationTagEntity{},
+	&TeamEntity{},
+	&WorkspaceEntity{},
+	&WorkspaceTagEntity{},
+	&WorkspaceVariableEntity{},
+	&WorkspaceAccessEntity{},
+	&OrganizationVariableEntity{},
+	&VcsEntity{},
+	&SshEntity{},
+	&ModuleEntity{},
+	&RegistryProviderEntity{},
+	&RegistryProviderVersionEntity{},
+	&RegistryProviderImplementationEntity{},
+	&CollectionEntity{},
+	&AgentEntity{},
+	&CollectionItemEntity{},
+	&CollectionReferenceEntity{},
+	&WorkspaceWebhookEntity{},
+	&WorkspaceScheduleEntity{},
+	&JobEntity{},
+	&JobStepEntity{},
+	&ActionEntity{},
+}
+
+// TestEntityRoundTrip marshals every entity with all its fields set and
+// unmarshals it again, a missing or mistyped jsonapi tag loses the field.
+func TestEntityRoundTrip(t *testing.T) {
+	for _, empty := range jsonapiEntities {
+		entityType := reflect.TypeOf(empty).Elem()
+		t.Run(entityType.Name(), func(t *testing.T) {
+			entity := reflect.New(entityType)
+			populateEntity(t, entity.Elem())
+
+			var out bytes.Buffer
+			if err := jsonapi.MarshalPayload(&out, entity.Interface()); err != nil {
+				t.Fatalf("unexpected marshal error: %s", err)
+			}
+
+			roundTrip := reflect.New(entityType)
+			if err := jsonapi.UnmarshalPayload(bytes.NewReader(out.Bytes()), roundTrip.Interface()); err != nil {
+				t.Fatalf("unexpected unmarshal error: %s\n%s", err, out.String())
+			}
+
+			for i := 0; i < entityType.NumField(); i++ {
+				field := entityType.Field(i)
+				if !reflect.DeepEqual(entity.Elem().Field(i).Interface(), roundTrip.Elem().Field(i).Interface()) {
+					t.Errorf("field %s (%s) did not round trip:\n%s", field.Name, field.Tag.Get("jsonapi"), out.String())
+				}
+			}
+		})
+	}
+}
+
+// populateEntity sets every field of the entity to a value that is not the
+// zero value, the related entities only get their id.
+func populateEntity(t *testing.T, entity reflect.Value) {
+	t.Helper()
+
+	names := map[string]string{}
+	for i := 0; i < entity.NumField(); i++ {
+		field := entity.Type().Field(i)
+		tag := strings.Split(field.Tag.Get("jsonapi"), ",")
+		if len(tag) < 2 {
+			t.Fatalf("field %s has no jsonapi tag", field.Name)
+		}
+		if previous, ok := names[tag[0]+","+tag[1]]; ok {
+			t.Fatalf("fields %s and %s share the jsonapi tag %s,%s", previous, field.Name, tag[0], tag[1])
+		}
+		names[tag[0]+","+tag[1]] = field.Name
+
+		value := entity.Field(i)
+		switch {
+		case tag[0] == "relation":
+			related := reflect.New(value.Type().Elem())
+			related.Elem().Field(0).SetString(fmt.Sprintf("%s-id", field.Name))
+			value.Set(related)
+		case value.Kind() == reflect.String:
+			value.SetString(fmt.Sprintf("%s-value", field.Name))
+		case value.Kind() == reflect.Bool:
+			value.SetBool(true)
+		case value.Kind() == reflect.Int32:
+			value.SetInt(int64(i + 1))
+		case value.Kind() == reflect.Map:
+			value.Set(reflect.ValueOf(map[string]interface{}{"key": fmt.Sprintf("%s-value", field.Name)}))
+		case value.Kind() == reflect.Pointer && value.Type().Elem().Kind() == reflect.String:
+			text := fmt.Sprintf("%s-value", field.Name)
+			value.Set(reflect.ValueOf(&text))
+		case value.Kind() == reflect.Pointer && value.Type().Elem().Kind() == reflect.Bool:
+			enabled := true
+			value.Set(reflect.ValueOf(&enabled))
+		default:
+			t.Fatalf("field %s has the unsupported type %s", field.Name, value.Type())
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// CacheValidators are the ETag and Last-Modified headers of the last
+// response, sent back as If-None-Match and If-Modified-Since so the API can
+// answer 304 Not Modified instead of the whole document.
+type CacheValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// ConditionalGet fetches url unless it did not change since the response the
+// validators come from. When notModified is true the body is nil and the
+// caller keeps what it has. Servers without the headers always get a plain
+// GET.
+func ConditionalGet(ctx context.Context, httpClient *http.Client, token string, url string, validators CacheValidators) (body []byte, latest CacheValidators, notModified bool, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, validators, false, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+	if validators.ETag != "" {
+		request.Header.Add("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		request.Header.Add("If-Modified-Since", validators.LastModified)
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, validators, false, fmt.Errorf("error executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotModified {
+		tflog.Debug(ctx, "Not modified since last read", map[string]any{"url": url})
+		return nil, validators, true, nil
+	}
+
+	body, err = io.ReadAll(response.Body)
+	if err != nil {
+		return nil, validators, false, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	tflog.Debug(ctx, "Read response", map[string]any{"url": url, "status": response.StatusCode, "bytes": len(body)})
+
+	if response.StatusCode >= 400 {
+		return nil, CacheValidators{}, false, NewStatusError(response, body)
+	}
+
+	latest = CacheValidators{
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+	}
+
+	return body, latest, false, nil
+}
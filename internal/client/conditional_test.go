@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestConditionalGet(t *testing.T) {
+	t.Parallel()
+
+	const document = `{"data":{"type":"team","id":"t1","attributes":{"name":"platform"}}}`
+	var mu sync.Mutex
+	var conditions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		conditions = append(conditions, r.Header.Get("If-None-Match")+"|"+r.Header.Get("If-Modified-Since"))
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/cached":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Last-Modified", "Wed, 14 Oct 2026 10:00:00 GMT")
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"status":"404","title":"Not Found"}]}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		fmt.Fprint(w, document)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	httpClient := NewHttpClient(HttpClientOptions{})
+	reset := func() {
+		mu.Lock()
+		conditions = nil
+		mu.Unlock()
+	}
+
+	body, validators, notModified, err := ConditionalGet(ctx, httpClient, "token", server.URL+"/cached", CacheValidators{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if notModified || string(body) != document {
+		t.Errorf("the first read should return the document, got %t %s", notModified, body)
+	}
+	if validators != (CacheValidators{ETag: `"v1"`, LastModified: "Wed, 14 Oct 2026 10:00:00 GMT"}) {
+		t.Errorf("unexpected validators %+v", validators)
+	}
+
+	reset()
+	body, latest, notModified, err := ConditionalGet(ctx, httpClient, "token", server.URL+"/cached", validators)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !notModified || body != nil || latest != validators {
+		t.Errorf("an unchanged document should answer 304 and keep the validators, got %t %s %+v", notModified, body, latest)
+	}
+	if fmt.Sprint(conditions) != `["v1"|Wed, 14 Oct 2026 10:00:00 GMT]` {
+		t.Errorf("the validators should be sent back, got %v", conditions)
+	}
+
+	reset()
+	body, latest, notModified, err = ConditionalGet(ctx, httpClient, "token", server.URL+"/plain", CacheValidators{})
+	if err != nil || notModified || string(body) != document || latest != (CacheValidators{}) {
+		t.Errorf("a server without validators should answer the document, got %v %t %s %+v", err, notModified, body, latest)
+	}
+	if fmt.Sprint(conditions) != "[|]" {
+		t.Errorf("no condition should be sent without validators, got %v", conditions)
+	}
+
+	_, latest, _, err = ConditionalGet(ctx, httpClient, "token", server.URL+"/missing", validators)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound || latest != (CacheValidators{}) {
+		t.Errorf("expected a 404 status error and no validators, got %v %+v", err, latest)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, _, _, err := ConditionalGet(canceled, httpClient, "token", server.URL+"/cached", CacheValidators{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("a canceled context should stop the request, got %v", err)
+	}
+}
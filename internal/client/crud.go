@@ -0,0 +1,357 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// RequestIDHeader carries the id a gateway in front of the API gives to the
+// request, the API itself does not set one.
+const RequestIDHeader = "X-Request-Id"
+
+// StatusError is returned when the API answers with an error status.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+	RequestID  string
+}
+
+func NewStatusError(response *http.Response, body []byte) *StatusError {
+	return &StatusError{
+		StatusCode: response.StatusCode,
+		Status:     response.Status,
+		Body:       string(body),
+		RequestID:  response.Header.Get(RequestIDHeader),
+	}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request failed with status %s: %s", e.Status, e.Body)
+}
+
+// Code returns the code of the first JSON:API error of the body, empty when
+// the body is not a JSON:API error document or the error has no code.
+func (e *StatusError) Code() string {
+	var document struct {
+		Errors []struct {
+			Code string `json:"code"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(e.Body), &document); err != nil || len(document.Errors) == 0 {
+		return ""
+	}
+	return document.Errors[0].Code
+}
+
+// Detail returns the detail of the first JSON:API error of the body, or its
+// title when it has no detail, empty when the body is not a JSON:API error
+// document.
+func (e *StatusError) Detail() string {
+	var document struct {
+		Errors []struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(e.Body), &document); err != nil || len(document.Errors) == 0 {
+		return ""
+	}
+	if document.Errors[0].Detail == "" {
+		return document.Errors[0].Title
+	}
+	return document.Errors[0].Detail
+}
+
+// Crud implements the JSON:API create, read, update, delete and list calls
+// for one entity type. The collection path is a format string whose %s verbs
+// are filled with the parent ids, for example "/api/v1/organization/%s/team".
+type Crud[T any] struct {
+	httpClient     *http.Client
+	endpoint       string
+	token          string
+	collectionPath string
+
+	// DeleteOverride replaces the DELETE call for entities the API does not
+	// delete directly, like organizations and workspaces that are disabled
+	// with a PATCH.
+	DeleteOverride func(ctx context.Context, id string, parentIds ...string) error
+
+	// Batcher, when set, coalesces updates with the updates of other
+	// resources into atomic operations.
+	Batcher *Batcher
+
+	// FindCreated, when set, returns by id the entities of the parent with
+	// the name or key of entity. Terrakube has no idempotency key, so Create
+	// lists them before the POST and, when the response of the POST was
+	// lost, adopts the single match that did not exist before, see
+	// CreatedEntity. An entity that existed before the create is never
+	// adopted.
+	FindCreated func(ctx context.Context, entity *T, parentIds ...string) (map[string]*T, error)
+}
+
+// LostCreateError is returned when the response of a create was lost and
+// several entities matching it were created meanwhile, the one made by the
+// create cannot be told apart from the others.
+type LostCreateError struct {
+	Err error
+	IDs []string
+}
+
+func (e *LostCreateError) Error() string {
+	return fmt.Sprintf("%s: the create response was lost and the entities %s were created meanwhile", e.Err, strings.Join(e.IDs, ", "))
+}
+
+func (e *LostCreateError) Unwrap() error {
+	return e.Err
+}
+
+// CreatedEntity returns the entity made by a create whose response was lost,
+// given the entities matching it by id before and after the create: the
+// only one that is new. It returns the create error when no entity is new
+// and a LostCreateError when several are.
+func CreatedEntity[T any](before map[string]*T, after map[string]*T, createErr error) (*T, error) {
+	var ids []string
+	for id := range after {
+		if _, found := before[id]; !found {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	switch len(ids) {
+	case 0:
+		return nil, createErr
+	case 1:
+		return after[ids[0]], nil
+	default:
+		return nil, &LostCreateError{Err: createErr, IDs: ids}
+	}
+}
+
+func NewCrud[T any](httpClient *http.Client, endpoint string, token string, collectionPath string) *Crud[T] {
+	return &Crud[T]{
+		httpClient:     httpClient,
+		endpoint:       endpoint,
+		token:          token,
+		collectionPath: collectionPath,
+	}
+}
+
+// CollectionURL returns the url of the entity collection for the parent ids.
+func (c *Crud[T]) CollectionURL(parentIds ...string) string {
+	args := make([]any, len(parentIds))
+	for i, id := range parentIds {
+		args[i] = id
+	}
+	return c.endpoint + fmt.Sprintf(c.collectionPath, args...)
+}
+
+// ItemURL returns the url of a single entity.
+func (c *Crud[T]) ItemURL(id string, parentIds ...string) string {
+	return fmt.Sprintf("%s/%s", c.CollectionURL(parentIds...), id)
+}
+
+// Create posts the entity to the collection and returns the created entity.
+func (c *Crud[T]) Create(ctx context.Context, entity *T, parentIds ...string) (*T, error) {
+	var existing map[string]*T
+	if c.FindCreated != nil {
+		var err error
+		if existing, err = c.FindCreated(ctx, entity, parentIds...); err != nil {
+			return nil, fmt.Errorf("unable to list the matching entities before the create: %w", err)
+		}
+	}
+
+	body, err := c.do(ctx, http.MethodPost, c.CollectionURL(parentIds...), entity)
+	if c.FindCreated != nil && RequestMayHaveArrived(err) {
+		matches, findErr := c.FindCreated(ctx, entity, parentIds...)
+		if findErr != nil {
+			return nil, err
+		}
+		created, err := CreatedEntity(existing, matches, err)
+		if created != nil {
+			tflog.Warn(ctx, "Create response lost, adopting the entity created meanwhile", map[string]any{"url": c.CollectionURL(parentIds...)})
+		}
+		return created, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.unmarshal(body)
+}
+
+// Get returns a single entity.
+func (c *Crud[T]) Get(ctx context.Context, id string, parentIds ...string) (*T, error) {
+	body, err := c.do(ctx, http.MethodGet, c.ItemURL(id, parentIds...), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.unmarshal(body)
+}
+
+// GetIfModified returns the entity unless it did not change since the
+// response the validators come from, in which case modified is false and the
+// entity nil.
+func (c *Crud[T]) GetIfModified(ctx context.Context, id string, validators CacheValidators, parentIds ...string) (entity *T, latest CacheValidators, modified bool, err error) {
+	body, latest, notModified, err := ConditionalGet(ctx, c.httpClient, c.token, c.ItemURL(id, parentIds...), validators)
+	if err != nil || notModified {
+		return nil, latest, false, err
+	}
+
+	entity, err = c.unmarshal(body)
+	return entity, latest, err == nil, err
+}
+
+// Update patches the entity, the response body is not used because Terrakube
+// answers 204 No Content.
+func (c *Crud[T]) Update(ctx context.Context, id string, entity *T, parentIds ...string) error {
+	patch := func() error {
+		_, err := c.do(ctx, http.MethodPatch, c.ItemURL(id, parentIds...), entity)
+		return err
+	}
+
+	if c.Batcher != nil {
+		href := strings.TrimPrefix(c.ItemURL(id, parentIds...), c.endpoint+"/api/v1")
+		return c.Batcher.Update(ctx, href, entity, patch)
+	}
+
+	return patch()
+}
+
+// UpdateWithout patches the entity leaving out the given attributes, see
+// MarshalSparsePayload. It is never batched since the batcher sends whole
+// entities.
+func (c *Crud[T]) UpdateWithout(ctx context.Context, id string, entity *T, omit []string, parentIds ...string) error {
+	var out = new(bytes.Buffer)
+	if err := MarshalSparsePayload(out, entity, omit...); err != nil {
+		return fmt.Errorf("unable to marshal payload: %w", err)
+	}
+
+	_, err := c.send(ctx, http.MethodPatch, c.ItemURL(id, parentIds...), out)
+	return err
+}
+
+// Delete removes the entity, using DeleteOverride when set.
+func (c *Crud[T]) Delete(ctx context.Context, id string, parentIds ...string) error {
+	if c.DeleteOverride != nil {
+		return c.DeleteOverride(ctx, id, parentIds...)
+	}
+	_, err := c.do(ctx, http.MethodDelete, c.ItemURL(id, parentIds...), nil)
+
+	// An entity that is already gone does not need to be deleted.
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// ListPageSize is the page[size] of the pages List asks for.
+const ListPageSize = 100
+
+// List returns every entity of the collection. The collection is walked page
+// by page using the Elide page[number]/page[size] parameters, until a page
+// returns fewer entities than the page size.
+func (c *Crud[T]) List(ctx context.Context, parentIds ...string) ([]*T, error) {
+	entities := []*T{}
+
+	for page := 1; ; page++ {
+		pageURL, err := url.Parse(c.CollectionURL(parentIds...))
+		if err != nil {
+			return nil, fmt.Errorf("invalid collection url %q: %w", c.CollectionURL(parentIds...), err)
+		}
+
+		query := pageURL.Query()
+		query.Set("page[number]", strconv.Itoa(page))
+		query.Set("page[size]", strconv.Itoa(ListPageSize))
+		pageURL.RawQuery = query.Encode()
+
+		body, err := c.do(ctx, http.MethodGet, pageURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := jsonapi.UnmarshalManyPayload(bytes.NewReader(body), reflect.TypeOf(new(T)))
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshal payload response: %w", err)
+		}
+
+		for _, item := range items {
+			entities = append(entities, item.(*T))
+		}
+
+		if len(items) < ListPageSize {
+			return entities, nil
+		}
+	}
+}
+
+func (c *Crud[T]) do(ctx context.Context, method string, url string, entity *T) ([]byte, error) {
+	var payload *bytes.Buffer
+	if entity != nil {
+		var out = new(bytes.Buffer)
+		if err := jsonapi.MarshalPayload(out, entity); err != nil {
+			return nil, fmt.Errorf("unable to marshal payload: %w", err)
+		}
+		payload = out
+	}
+
+	return c.send(ctx, method, url, payload)
+}
+
+func (c *Crud[T]) send(ctx context.Context, method string, url string, payload *bytes.Buffer) ([]byte, error) {
+	var requestBody io.Reader
+	if payload != nil {
+		tflog.Debug(ctx, "Body Request", map[string]any{"bodyRequest": payload.String()})
+		requestBody = payload
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	if method != http.MethodDelete {
+		request.Header.Add("Content-Type", "application/vnd.api+json")
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(body)})
+
+	if response.StatusCode >= 400 {
+		return nil, NewStatusError(response, body)
+	}
+
+	return body, nil
+}
+
+func (c *Crud[T]) unmarshal(body []byte) (*T, error) {
+	entity := new(T)
+	if err := jsonapi.UnmarshalPayload(bytes.NewReader(body), entity); err != nil {
+		return nil, fmt.Errorf("error unmarshal payload response: %w", err)
+	}
+	return entity, nil
+}
@@ -0,0 +1,300 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of testdata with the payloads sent")
+
+// payloadRecorder answers every request like Terrakube does and keeps the
+// body of the last one.
+type payloadRecorder struct {
+	mu   sync.Mutex
+	body []byte
+}
+
+func (p *payloadRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	p.mu.Lock()
+	p.body = body
+	p.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	switch r.Method {
+	case http.MethodPost:
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// assertGolden compares the JSON payload to testdata/<name>.golden, ignoring
+// the formatting.
+func assertGolden(t *testing.T, name string, payload []byte) {
+	t.Helper()
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, payload, "", "  "); err != nil {
+		t.Fatalf("invalid payload %s: %s", payload, err)
+	}
+	indented.WriteString("\n")
+
+	golden := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		if err := os.WriteFile(golden, indented.Bytes(), 0o644); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("unexpected error, run the test with -update to create the golden file: %s", err)
+	}
+	if !bytes.Equal(indented.Bytes(), expected) {
+		t.Errorf("payload does not match %s:\n%s", golden, indented.String())
+	}
+}
+
+func TestCrudPayloads(t *testing.T) {
+	t.Parallel()
+
+	recorder := &payloadRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	ctx := context.Background()
+	httpClient := NewHttpClient(HttpClientOptions{})
+	teams := NewCrud[TeamEntity](httpClient, server.URL, "token", "/api/v1/organization/%s/team")
+	modules := NewCrud[ModuleEntity](httpClient, server.URL, "token", "/api/v1/organization/%s/module")
+	variables := NewCrud[WorkspaceVariableEntity](httpClient, server.URL, "token", "/api/v1/organization/%s/workspace/%s/variable")
+	workspaces := NewCrud[WorkspaceEntity](httpClient, server.URL, "token", "/api/v1/organization/%s/workspace")
+	agentId := "a1"
+	folder := "/modules/vpc/"
+
+	for _, test := range []struct {
+		name string
+		send func() error
+	}{
+		{"team_create", func() error {
+			_, err := teams.Create(ctx, &TeamEntity{Name: "platform", ManageWorkspace: true, ManageJob: true}, "o1")
+			return err
+		}},
+		{"team_update", func() error {
+			return teams.Update(ctx, "t1", &TeamEntity{ID: "t1", Name: "platform", ManageState: true}, "o1")
+		}},
+		{"module_create", func() error {
+			_, err := modules.Create(ctx, &ModuleEntity{Name: "vpc", Description: "network", Provider: "aws", Source: "https://github.com/platform/modules.git", Folder: &folder, Vcs: &VcsEntity{ID: "v1"}}, "o1")
+			return err
+		}},
+		{"workspace_variable_update_without_value", func() error {
+			return variables.UpdateWithout(ctx, "v1", &WorkspaceVariableEntity{ID: "v1", Key: "password", Description: "rotated", Category: "ENV", Sensitive: true}, []string{"value"}, "o1", "w1")
+		}},
+		{"relationship_attach", func() error {
+			return workspaces.PatchRelationship(ctx, "w1", "agent", &agentId, "o1")
+		}},
+		{"relationship_detach", func() error {
+			return workspaces.PatchRelationship(ctx, "w1", "vcs", nil, "o1")
+		}},
+	} {
+		if err := test.send(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		recorder.mu.Lock()
+		body := recorder.body
+		recorder.mu.Unlock()
+		assertGolden(t, test.name, body)
+	}
+}
+
+func TestCrudListPages(t *testing.T) {
+	t.Parallel()
+
+	const total = ListPageSize + 3
+	var mu sync.Mutex
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		pages = append(pages, r.URL.Query().Get("page[number]")+"/"+r.URL.Query().Get("page[size]"))
+		mu.Unlock()
+
+		number, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("page[size]"))
+		var items []json.RawMessage
+		for i := (number - 1) * size; i < min(number*size, total); i++ {
+			items = append(items, json.RawMessage(fmt.Sprintf(`{"type":"team","id":"t%d","attributes":{"name":"team-%d"}}`, i, i)))
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		json.NewEncoder(w).Encode(map[string]any{"data": items})
+	}))
+	defer server.Close()
+
+	teams := NewCrud[TeamEntity](NewHttpClient(HttpClientOptions{}), server.URL, "token", "/api/v1/organization/%s/team")
+	list, err := teams.List(context.Background(), "o1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(list) != total || list[total-1].Name != fmt.Sprintf("team-%d", total-1) {
+		t.Errorf("expected %d teams, got %d", total, len(list))
+	}
+	expected := fmt.Sprintf("[1/%d 2/%d]", ListPageSize, ListPageSize)
+	if fmt.Sprint(pages) != expected {
+		t.Errorf("expected the pages %s, got %v", expected, pages)
+	}
+}
+
+func TestPatchRelationshipRequest(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	workspaces := NewCrud[WorkspaceEntity](NewHttpClient(HttpClientOptions{}), server.URL, "token", "/api/v1/organization/%s/workspace")
+	if err := workspaces.PatchRelationship(context.Background(), "w1", "ssh", nil, "o1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := workspaces.PatchRelationship(canceled, "w1", "ssh", nil, "o1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("a canceled context should stop the request, got %v", err)
+	}
+
+	if fmt.Sprint(requests) != "[PATCH /api/v1/organization/o1/workspace/w1/relationships/ssh]" {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
+
+func TestCreatedEntity(t *testing.T) {
+	t.Parallel()
+
+	createErr := errors.New("connection reset by peer")
+	existing := &TeamEntity{ID: "t1"}
+	created := &TeamEntity{ID: "t2"}
+	other := &TeamEntity{ID: "t3"}
+
+	for _, test := range []struct {
+		name    string
+		before  map[string]*TeamEntity
+		after   map[string]*TeamEntity
+		created *TeamEntity
+		lost    []string
+	}{
+		{"nothing created", nil, map[string]*TeamEntity{}, nil, nil},
+		{"existing only", map[string]*TeamEntity{"t1": existing}, map[string]*TeamEntity{"t1": existing}, nil, nil},
+		{"created", map[string]*TeamEntity{}, map[string]*TeamEntity{"t2": created}, created, nil},
+		{"created next to existing", map[string]*TeamEntity{"t1": existing}, map[string]*TeamEntity{"t1": existing, "t2": created}, created, nil},
+		{"several created", map[string]*TeamEntity{"t1": existing}, map[string]*TeamEntity{"t1": existing, "t3": other, "t2": created}, nil, []string{"t2", "t3"}},
+	} {
+		entity, err := CreatedEntity(test.before, test.after, createErr)
+		if entity != test.created {
+			t.Errorf("%s: expected the entity %v, got %v", test.name, test.created, entity)
+		}
+
+		var lost *LostCreateError
+		switch {
+		case test.created != nil && err != nil:
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		case test.lost != nil && (!errors.As(err, &lost) || fmt.Sprint(lost.IDs) != fmt.Sprint(test.lost)):
+			t.Errorf("%s: expected a lost create of %v, got %v", test.name, test.lost, err)
+		case test.created == nil && !errors.Is(err, createErr):
+			t.Errorf("%s: expected the create error, got %v", test.name, err)
+		}
+	}
+}
+
+func TestCrudCreateLostResponse(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		existing []string
+		created  []string
+		postErr  error
+		adopted  string
+		lost     bool
+	}{
+		{"created", nil, []string{"t2"}, errors.New("connection reset by peer"), "t2", false},
+		{"created next to existing", []string{"t1"}, []string{"t2"}, errors.New("connection reset by peer"), "t2", false},
+		{"not created", []string{"t1"}, nil, errors.New("connection reset by peer"), "", false},
+		{"created twice", nil, []string{"t2", "t3"}, errors.New("connection reset by peer"), "", true},
+		{"never sent", []string{"t1"}, nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}, "", false},
+	} {
+		var mu sync.Mutex
+		teams := map[string]*TeamEntity{}
+		for _, id := range test.existing {
+			teams[id] = &TeamEntity{ID: id, Name: "platform"}
+		}
+		lookups := 0
+		httpClient := &http.Client{Transport: roundTripFunc(func(request *http.Request) (*http.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if request.Method == http.MethodPost {
+				for _, id := range test.created {
+					teams[id] = &TeamEntity{ID: id, Name: "platform"}
+				}
+				return nil, test.postErr
+			}
+			lookups++
+			var items []json.RawMessage
+			for id := range teams {
+				items = append(items, json.RawMessage(fmt.Sprintf(`{"type":"team","id":%q,"attributes":{"name":"platform"}}`, id)))
+			}
+			body, _ := json.Marshal(map[string]any{"data": items})
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body)), Header: http.Header{}}, nil
+		})}
+
+		crud := NewCrud[TeamEntity](httpClient, "http://terrakube", "token", "/api/v1/organization/%s/team")
+		crud.FindCreated = func(ctx context.Context, entity *TeamEntity, parentIds ...string) (map[string]*TeamEntity, error) {
+			list, err := crud.List(ctx, parentIds...)
+			matches := map[string]*TeamEntity{}
+			for _, team := range list {
+				matches[team.ID] = team
+			}
+			return matches, err
+		}
+
+		team, err := crud.Create(context.Background(), &TeamEntity{Name: "platform"}, "o1")
+		var lost *LostCreateError
+		switch {
+		case test.adopted != "" && (err != nil || team == nil || team.ID != test.adopted):
+			t.Errorf("%s: expected the team %s to be adopted, got %v %v", test.name, test.adopted, team, err)
+		case test.adopted == "" && team != nil:
+			t.Errorf("%s: no team should be adopted, got %s", test.name, team.ID)
+		case test.lost && !errors.As(err, &lost):
+			t.Errorf("%s: expected a lost create, got %v", test.name, err)
+		case test.adopted == "" && !errors.Is(err, test.postErr):
+			t.Errorf("%s: expected the create error, got %v", test.name, err)
+		}
+
+		expectedLookups := 2
+		if _, connect := test.postErr.(*net.OpError); connect {
+			expectedLookups = 1
+		}
+		if lookups != expectedLookups {
+			t.Errorf("%s: expected %d lookups, got %d", test.name, expectedLookups, lookups)
+		}
+	}
+}
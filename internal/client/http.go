@@ -0,0 +1,268 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// HttpClientOptions holds the provider level settings used to build the
+// http client shared by every resource and data source.
+type HttpClientOptions struct {
+	InsecureSkipVerify bool
+	TLSConfig          *tls.Config
+	FullPayloads       bool
+	Metrics            *Metrics
+
+	// ConnectTimeout limits the dial and the TLS handshake, zero keeps the
+	// defaults of http.DefaultTransport.
+	ConnectTimeout time.Duration
+	// ResponseHeaderTimeout limits the wait for the response headers once
+	// the request is sent, zero waits without limit.
+	ResponseHeaderTimeout time.Duration
+	// Network restricts the connections to "tcp4" or "tcp6", empty dials
+	// both address families.
+	Network string
+}
+
+// NewHttpClient returns the http client used to call the Terrakube API.
+func NewHttpClient(options HttpClientOptions) *http.Client {
+	var transport http.RoundTripper = http.DefaultTransport
+
+	tlsConfig := options.TLSConfig
+	if options.InsecureSkipVerify {
+		tlsConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	if custom, ok := http.DefaultTransport.(*http.Transport); ok {
+		customTransport := custom.Clone()
+		if tlsConfig != nil {
+			customTransport.TLSClientConfig = tlsConfig
+		}
+		if options.ConnectTimeout > 0 || options.Network != "" {
+			dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+			if options.ConnectTimeout > 0 {
+				dialer.Timeout = options.ConnectTimeout
+				customTransport.TLSHandshakeTimeout = options.ConnectTimeout
+			}
+			customTransport.DialContext = restrictNetwork(dialer.DialContext, options.Network)
+		}
+		customTransport.ResponseHeaderTimeout = options.ResponseHeaderTimeout
+		transport = customTransport
+	}
+
+	if options.Metrics != nil {
+		transport = &metricsTransport{next: transport, metrics: options.Metrics}
+	}
+
+	transport = &uiEndpointTransport{next: transport}
+
+	if !options.FullPayloads {
+		transport = &sparsePayloadTransport{next: transport}
+	}
+
+	transport = &retryTransport{next: transport, metrics: options.Metrics}
+	transport = &acceptedTransport{next: transport}
+
+	return &http.Client{Transport: transport}
+}
+
+// restrictNetwork dials tcp connections with the given network, so a host
+// with a broken IPv6 route is not tried first on every new connection.
+func restrictNetwork(dial func(ctx context.Context, network string, address string) (net.Conn, error), restricted string) func(ctx context.Context, network string, address string) (net.Conn, error) {
+	if restricted == "" {
+		return dial
+	}
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		if network == "tcp" {
+			network = restricted
+		}
+		return dial(ctx, network, address)
+	}
+}
+
+// sparsePayloadTransport asks for JSON:API sparse fieldsets on GET
+// requests: the primary resources only return the attributes and
+// relationships declared by the entities of this package, less the secrets,
+// so the server does not serialize the linkage of every workspace of an
+// organization, and no included data is requested. Requests choosing their
+// own include or fields parameters, or sent with a WithFullPayloads context,
+// are left unchanged.
+type sparsePayloadTransport struct {
+	next http.RoundTripper
+}
+
+type fullPayloadsKey struct{}
+
+// WithFullPayloads returns a context for requests that need the response as
+// the server builds it, such as the raw attributes of a data source.
+func WithFullPayloads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fullPayloadsKey{}, true)
+}
+
+// sparseFieldsets maps the path segment of a collection to the fieldset of
+// its entity, the query parameter name and the comma separated fields.
+var sparseFieldsets = buildSparseFieldsets(map[string]any{
+	"organization":   OrganizationEntity{},
+	"template":       OrganizationTemplateEntity{},
+	"tag":            OrganizationTagEntity{},
+	"team":           TeamEntity{},
+	"workspace":      WorkspaceEntity{},
+	"workspaceTag":   WorkspaceTagEntity{},
+	"variable":       WorkspaceVariableEntity{},
+	"access":         WorkspaceAccessEntity{},
+	"globalvar":      OrganizationVariableEntity{},
+	"vcs":            VcsEntity{},
+	"ssh":            SshEntity{},
+	"module":         ModuleEntity{},
+	"provider":       RegistryProviderEntity{},
+	"version":        RegistryProviderVersionEntity{},
+	"implementation": RegistryProviderImplementationEntity{},
+	"collection":     CollectionEntity{},
+	"agent":          AgentEntity{},
+	"item":           CollectionItemEntity{},
+	"reference":      CollectionReferenceEntity{},
+	"webhook":        WorkspaceWebhookEntity{},
+	"schedule":       WorkspaceScheduleEntity{},
+	"job":            JobEntity{},
+	"step":           JobStepEntity{},
+	"action":         ActionEntity{},
+})
+
+// sparseSecretFields are left out of every fieldset, a GET has no use for
+// the secrets written by the resources and should not pull them into the
+// responses and logs.
+var sparseSecretFields = map[string]bool{
+	"privateKey":   true,
+	"clientSecret": true,
+}
+
+type sparseFieldset struct {
+	parameter string
+	fields    string
+}
+
+func buildSparseFieldsets(entities map[string]any) map[string]sparseFieldset {
+	fieldsets := map[string]sparseFieldset{}
+	for segment, entity := range entities {
+		var resourceType string
+		var fields []string
+		entityType := reflect.TypeOf(entity)
+		for i := 0; i < entityType.NumField(); i++ {
+			tag := strings.Split(entityType.Field(i).Tag.Get("jsonapi"), ",")
+			if len(tag) < 2 {
+				continue
+			}
+			switch tag[0] {
+			case "primary":
+				resourceType = tag[1]
+			case "attr", "relation":
+				if !sparseSecretFields[tag[1]] {
+					fields = append(fields, tag[1])
+				}
+			}
+		}
+		fieldsets[segment] = sparseFieldset{parameter: fmt.Sprintf("fields[%s]", resourceType), fields: strings.Join(fields, ",")}
+	}
+	return fieldsets
+}
+
+// sparseQuery returns the query of the request with the fieldset of the
+// requested collection, false when the request is sent unchanged.
+func sparseQuery(request *http.Request) (string, bool) {
+	if request.Method != http.MethodGet || request.Context().Value(fullPayloadsKey{}) != nil {
+		return "", false
+	}
+
+	path := strings.Trim(request.URL.Path, "/")
+	if !strings.HasPrefix(path, "api/v1/") {
+		return "", false
+	}
+
+	// The path alternates collections and ids, a path with an even number
+	// of segments ends with the id of a resource of the previous collection.
+	segments := strings.Split(strings.TrimPrefix(path, "api/v1/"), "/")
+	collection := segments[len(segments)-1]
+	if len(segments)%2 == 0 {
+		collection = segments[len(segments)-2]
+	}
+	fieldset, ok := sparseFieldsets[collection]
+	if !ok {
+		return "", false
+	}
+
+	query := request.URL.Query()
+	for name := range query {
+		if name == "include" || strings.HasPrefix(name, "fields[") {
+			return "", false
+		}
+	}
+	query.Set(fieldset.parameter, fieldset.fields)
+	return query.Encode(), true
+}
+
+func (t *sparsePayloadTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	query, ok := sparseQuery(request)
+	if !ok {
+		return t.next.RoundTrip(request)
+	}
+
+	sparse := request.Clone(request.Context())
+	sparse.URL.RawQuery = query
+	response, err := t.next.RoundTrip(sparse)
+	if err != nil || response.StatusCode != http.StatusBadRequest {
+		return response, err
+	}
+
+	// Older Terrakube versions do not know every attribute of the entities
+	// and reject the fieldset, the full resource is requested instead.
+	io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+	return t.next.RoundTrip(request)
+}
+
+// ErrUIEndpoint is returned for successful responses with an HTML body, the
+// usual symptom of an endpoint pointing at the Terrakube UI host.
+var ErrUIEndpoint = errors.New("endpoint appears to serve the Terrakube UI, not the API; expected application/vnd.api+json")
+
+// uiEndpointTransport turns HTML answers into ErrUIEndpoint. The UI host
+// answers 200 with its index page for every path, which would otherwise
+// surface as a confusing unmarshal error.
+type uiEndpointTransport struct {
+	next http.RoundTripper
+}
+
+func (t *uiEndpointTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.next.RoundTrip(request)
+	if err != nil || response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response, err
+	}
+
+	html := strings.Contains(response.Header.Get("Content-Type"), "text/html")
+	if !html {
+		reader := bufio.NewReader(response.Body)
+		start, _ := reader.Peek(512)
+		start = bytes.ToLower(bytes.TrimSpace(start))
+		html = bytes.HasPrefix(start, []byte("<!doctype")) || bytes.HasPrefix(start, []byte("<html"))
+		response.Body = struct {
+			io.Reader
+			io.Closer
+		}{reader, response.Body}
+	}
+
+	if html {
+		response.Body.Close()
+		return nil, ErrUIEndpoint
+	}
+
+	return response, nil
+}
@@ -0,0 +1,242 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSparsePayloadQuery(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var lastQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastQuery = r.URL.RawQuery
+		mu.Unlock()
+		if r.URL.Query().Get("fields[workspace]") != "" && r.URL.Query().Get("filter[workspace]") == "old" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		fmt.Fprint(w, `{"data":{"type":"workspace","id":"w1","attributes":{"name":"name"}}}`)
+	}))
+	defer server.Close()
+
+	httpClient := NewHttpClient(HttpClientOptions{})
+	for _, test := range []struct {
+		name     string
+		ctx      context.Context
+		path     string
+		expected string
+	}{
+		{"resource", context.Background(), "/api/v1/organization/o1/workspace/w1", "fields%5Bworkspace%5D="},
+		{"collection", context.Background(), "/api/v1/organization/o1/workspace?filter[workspace]=new", "fields%5Bworkspace%5D="},
+		{"rejected fieldset", context.Background(), "/api/v1/organization/o1/workspace?filter[workspace]=old", "filter[workspace]=old"},
+		{"include", context.Background(), "/api/v1/organization/o1/workspace?include=vcs", "include=vcs"},
+		{"full payloads", WithFullPayloads(context.Background()), "/api/v1/organization/o1/workspace/w1", ""},
+		{"unknown collection", context.Background(), "/api/v1/organization/o1/unknown", ""},
+	} {
+		request, _ := http.NewRequestWithContext(test.ctx, http.MethodGet, server.URL+test.path, nil)
+		original := request.URL.RawQuery
+		response, err := httpClient.Do(request)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		response.Body.Close()
+
+		mu.Lock()
+		query := lastQuery
+		mu.Unlock()
+		if !strings.HasPrefix(query, test.expected) || (test.expected == "" && query != original) {
+			t.Errorf("%s: unexpected query %q", test.name, query)
+		}
+		if request.URL.RawQuery != original {
+			t.Errorf("%s: the request of the caller was modified: %q", test.name, request.URL.RawQuery)
+		}
+	}
+}
+
+// sparseBenchmarkWorkspaces makes the full organization document about 5MB.
+const sparseBenchmarkWorkspaces = 10000
+
+// sparseBenchmarkServer answers an organization the way a server side
+// loading its workspaces does, unless a sparse fieldset is asked.
+func sparseBenchmarkServer() *httptest.Server {
+	var full strings.Builder
+	full.WriteString(`{"data":{"type":"organization","id":"o1","attributes":{"name":"name","description":"description","executionMode":"remote","disabled":false},"relationships":{"workspace":{"data":[`)
+	for i := 0; i < sparseBenchmarkWorkspaces; i++ {
+		if i > 0 {
+			full.WriteString(",")
+		}
+		fmt.Fprintf(&full, `{"type":"workspace","id":"w%d"}`, i)
+	}
+	full.WriteString(`]}}},"included":[`)
+	for i := 0; i < sparseBenchmarkWorkspaces; i++ {
+		if i > 0 {
+			full.WriteString(",")
+		}
+		fmt.Fprintf(&full, `{"type":"workspace","id":"w%d","attributes":{"name":"workspace-%d","description":"%s","source":"https://github.com/org/repository-%d.git","branch":"main","folder":"/","terraformVersion":"1.5.7","executionMode":"remote","iacType":"terraform"}}`, i, i, strings.Repeat("d", 300), i)
+	}
+	full.WriteString(`]}`)
+
+	sparse := `{"data":{"type":"organization","id":"o1","attributes":{"name":"name","description":"description","executionMode":"remote","disabled":false}}}`
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		if r.URL.Query().Has("fields[organization]") {
+			fmt.Fprint(w, sparse)
+			return
+		}
+		fmt.Fprint(w, full.String())
+	}))
+}
+
+// BenchmarkSparsePayload reads an organization with and without sparse
+// fieldsets, run with -benchmem to compare the allocations.
+func BenchmarkSparsePayload(b *testing.B) {
+	server := sparseBenchmarkServer()
+	defer server.Close()
+
+	for _, benchmark := range []struct {
+		name         string
+		fullPayloads bool
+	}{
+		{"full", true},
+		{"sparse", false},
+	} {
+		b.Run(benchmark.name, func(b *testing.B) {
+			organizations := NewCrud[OrganizationEntity](NewHttpClient(HttpClientOptions{FullPayloads: benchmark.fullPayloads}), server.URL, "token", "/api/v1/organization")
+			for i := 0; i < b.N; i++ {
+				organization, err := organizations.Get(context.Background(), "o1")
+				if err != nil {
+					b.Fatalf("unexpected error: %s", err)
+				}
+				if organization.Name != "name" {
+					b.Fatalf("unexpected organization %v", organization)
+				}
+			}
+		})
+	}
+}
+
+func TestSparseFieldsetsLeaveOutSecrets(t *testing.T) {
+	t.Parallel()
+
+	for segment, fieldset := range sparseFieldsets {
+		for _, field := range strings.Split(fieldset.fields, ",") {
+			if sparseSecretFields[field] {
+				t.Errorf("the %s fieldset asks for the secret %s", segment, field)
+			}
+		}
+	}
+	if fields := sparseFieldsets["ssh"].fields; fields != "name,description,sshType" {
+		t.Errorf("unexpected ssh fieldset %q", fields)
+	}
+}
+
+func TestUIEndpointDetection(t *testing.T) {
+	t.Parallel()
+
+	const index = "\n  <!DOCTYPE html><html><head><title>Terrakube</title></head><body><div id=\"root\"></div></body></html>"
+	for _, test := range []struct {
+		name        string
+		contentType string
+		status      int
+		body        string
+		ui          bool
+	}{
+		{"html content type", "text/html; charset=utf-8", http.StatusOK, "<p>Terrakube</p>", true},
+		{"html body without content type", "application/octet-stream", http.StatusOK, index, true},
+		{"html root element", "", http.StatusOK, "<HTML><body></body></HTML>", true},
+		{"api", "application/vnd.api+json", http.StatusOK, `{"data":{"type":"organization","id":"o1"}}`, false},
+		{"html error page", "text/html", http.StatusNotFound, index, false},
+	} {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Header().Set("Content-Type", test.contentType)
+			w.WriteHeader(test.status)
+			fmt.Fprint(w, test.body)
+		}))
+
+		request, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/organization/o1", nil)
+		response, err := NewHttpClient(HttpClientOptions{}).Do(request)
+		switch {
+		case test.ui && !errors.Is(err, ErrUIEndpoint):
+			t.Errorf("%s: expected ErrUIEndpoint, got %v", test.name, err)
+		case !test.ui && err != nil:
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		case !test.ui:
+			// The detection peeks at the body, it must still be read whole.
+			body, _ := io.ReadAll(response.Body)
+			response.Body.Close()
+			if string(body) != test.body {
+				t.Errorf("%s: unexpected body %q", test.name, body)
+			}
+		}
+		if count := requests.Load(); count != 1 {
+			t.Errorf("%s: expected a single request, got %d", test.name, count)
+		}
+		server.Close()
+	}
+}
+
+func TestNetworkRestriction(t *testing.T) {
+	t.Parallel()
+
+	servers := map[string]*httptest.Server{}
+	for family, address := range map[string]string{"tcp4": "127.0.0.1:0", "tcp6": "[::1]:0"} {
+		listener, err := net.Listen(family, address)
+		if err != nil {
+			t.Skipf("cannot listen on %s: %s", address, err)
+		}
+		family := family
+		server := &httptest.Server{Listener: listener, Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, family)
+		})}}
+		server.Start()
+		t.Cleanup(server.Close)
+		servers[family] = server
+	}
+
+	for _, network := range []string{"", "tcp4", "tcp6"} {
+		dial := restrictNetwork((&net.Dialer{}).DialContext, network)
+		httpClient := NewHttpClient(HttpClientOptions{Network: network})
+		for family, server := range servers {
+			connection, err := dial(context.Background(), "tcp", server.Listener.Addr().String())
+			if err == nil {
+				connection.Close()
+			}
+			reachable := network == "" || network == family
+			if reachable != (err == nil) {
+				t.Errorf("network %q: expected dialing the %s server %t, got %v", network, family, reachable, err)
+			}
+			if !reachable || err != nil {
+				continue
+			}
+
+			// The client dials through the restricted network too.
+			request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			response, err := httpClient.Do(request)
+			if err != nil {
+				t.Errorf("network %q: unexpected error calling %s: %s", network, server.URL, err)
+				continue
+			}
+			body, _ := io.ReadAll(response.Body)
+			response.Body.Close()
+			if string(body) != family {
+				t.Errorf("network %q: expected the %s server, got %q", network, family, body)
+			}
+		}
+	}
+}
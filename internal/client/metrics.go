@@ -0,0 +1,130 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// Metrics counts the requests sent to the Terrakube API. It is safe for
+// concurrent use by the resources and data sources sharing the http client.
+type Metrics struct {
+	mu             sync.Mutex
+	writeMu        sync.Mutex
+	path           string
+	totalRequests  int
+	retries        int
+	errorsByStatus map[string]int
+}
+
+// MetricsSummary is the JSON document logged and written to metrics_path.
+// Transport errors without an http response are counted with the "error"
+// status.
+type MetricsSummary struct {
+	TotalRequests  int            `json:"total_requests"`
+	Retries        int            `json:"retries"`
+	ErrorsByStatus map[string]int `json:"errors_by_status"`
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{errorsByStatus: map[string]int{}}
+}
+
+// SetPath sets the file the summary is written to, an empty path disables
+// the file.
+func (m *Metrics) SetPath(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.path = path
+}
+
+// RecordRetry counts a request sent again after a failed attempt.
+func (m *Metrics) RecordRetry() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+}
+
+func (m *Metrics) record(response *http.Response, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.totalRequests++
+	switch {
+	case err != nil:
+		m.errorsByStatus["error"]++
+	case response.StatusCode >= 400:
+		m.errorsByStatus[strconv.Itoa(response.StatusCode)]++
+	}
+}
+
+// Summary returns a copy of the current counters.
+func (m *Metrics) Summary() MetricsSummary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	errorsByStatus := make(map[string]int, len(m.errorsByStatus))
+	for status, count := range m.errorsByStatus {
+		errorsByStatus[status] = count
+	}
+
+	return MetricsSummary{
+		TotalRequests:  m.totalRequests,
+		Retries:        m.retries,
+		ErrorsByStatus: errorsByStatus,
+	}
+}
+
+// WriteFile writes the summary to the configured path. The file is replaced
+// atomically so a reader never sees a partial document, and writes are
+// serialized so a concurrent call never replaces it with older counters.
+func (m *Metrics) WriteFile() error {
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	m.mu.Lock()
+	path := m.path
+	m.mu.Unlock()
+
+	if path == "" {
+		return nil
+	}
+
+	content, err := json.Marshal(m.Summary())
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), path)
+}
+
+// metricsTransport records every round trip. The summary file is written by
+// the provider server once the call that sent the requests is done, not
+// after each request.
+type metricsTransport struct {
+	next    http.RoundTripper
+	metrics *Metrics
+}
+
+func (t *metricsTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.next.RoundTrip(request)
+	t.metrics.record(response, err)
+	return response, err
+}
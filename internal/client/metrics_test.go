@@ -0,0 +1,100 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestMetricsConcurrent(t *testing.T) {
+	t.Parallel()
+
+	metrics := NewMetrics()
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	metrics.SetPath(path)
+
+	const workers, requests = 50, 100
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		worker := worker
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < requests; i++ {
+				switch i % 4 {
+				case 0:
+					metrics.record(&http.Response{StatusCode: http.StatusOK}, nil)
+				case 1:
+					metrics.record(&http.Response{StatusCode: http.StatusTooManyRequests}, nil)
+					metrics.RecordRetry()
+				case 2:
+					metrics.record(nil, errors.New("connection refused"))
+				case 3:
+					metrics.record(&http.Response{StatusCode: http.StatusNotFound}, nil)
+				}
+				if (worker+i)%10 == 0 {
+					if err := metrics.WriteFile(); err != nil {
+						t.Errorf("unexpected error: %s", err)
+					}
+				}
+				metrics.Summary()
+			}
+		}()
+	}
+	wg.Wait()
+
+	summary := metrics.Summary()
+	if summary.TotalRequests != workers*requests {
+		t.Errorf("expected %d requests, got %d", workers*requests, summary.TotalRequests)
+	}
+	if summary.Retries != workers*requests/4 {
+		t.Errorf("expected %d retries, got %d", workers*requests/4, summary.Retries)
+	}
+	for _, status := range []string{"429", "404", "error"} {
+		if summary.ErrorsByStatus[status] != workers*requests/4 {
+			t.Errorf("expected %d errors with status %s, got %d", workers*requests/4, status, summary.ErrorsByStatus[status])
+		}
+	}
+
+	if err := metrics.WriteFile(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var written MetricsSummary
+	if err := json.Unmarshal(content, &written); err != nil {
+		t.Fatalf("the metrics file is not a complete document: %s", err)
+	}
+	if written.TotalRequests != summary.TotalRequests {
+		t.Errorf("the metrics file has %d requests, expected %d", written.TotalRequests, summary.TotalRequests)
+	}
+}
+
+func TestMetricsTransportDoesNotWriteFile(t *testing.T) {
+	t.Parallel()
+
+	metrics := NewMetrics()
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	metrics.SetPath(path)
+
+	transport := &metricsTransport{metrics: metrics, next: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})}
+	request, _ := http.NewRequest(http.MethodGet, "http://terrakube/api/v1/organization", nil)
+	if _, err := transport.RoundTrip(request); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if metrics.Summary().TotalRequests != 1 {
+		t.Errorf("the request was not recorded")
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the transport should leave the file to the provider server, stat: %v", err)
+	}
+}
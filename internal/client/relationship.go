@@ -0,0 +1,65 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type relationshipIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type relationshipDocument struct {
+	Data *relationshipIdentifier `json:"data"`
+}
+
+// PatchRelationship replaces the to-one relationship of the entity at
+// itemURL. The related entity type is the relationship name, which holds for
+// the vcs and ssh relationships. A nil relatedId detaches the relationship,
+// which the attribute payload of a PATCH cannot express.
+func PatchRelationship(ctx context.Context, httpClient *http.Client, token string, itemURL string, relationship string, relatedId *string) error {
+	document := relationshipDocument{}
+	if relatedId != nil {
+		document.Data = &relationshipIdentifier{Type: relationship, ID: *relatedId}
+	}
+
+	payload, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("unable to marshal payload: %w", err)
+	}
+
+	tflog.Debug(ctx, "Body Request", map[string]any{"bodyRequest": string(payload)})
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf("%s/relationships/%s", itemURL, relationship), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("error executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		body, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("relationship %s update failed: %w", relationship, NewStatusError(response, body))
+	}
+
+	return nil
+}
+
+// PatchRelationship replaces a to-one relationship of the entity, see
+// PatchRelationship.
+func (c *Crud[T]) PatchRelationship(ctx context.Context, id string, relationship string, relatedId *string, parentIds ...string) error {
+	return PatchRelationship(ctx, c.httpClient, c.token, c.ItemURL(id, parentIds...), relationship, relatedId)
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	retryAttempts = 3
+	retryBackoff  = 500 * time.Millisecond
+)
+
+// retryTransport sends a request again after a transport error. A request
+// that failed while connecting never reached the server, so it is retried
+// whatever its method. Once the request was sent, only GET requests are
+// retried, including when the response body is cut off while reading it,
+// since repeating a POST or PATCH could apply it twice.
+type retryTransport struct {
+	next    http.RoundTripper
+	metrics *Metrics
+}
+
+func (t *retryTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		// A RoundTripper must not modify the request of the caller, every
+		// attempt sends a copy with a fresh body.
+		attemptRequest := request.Clone(request.Context())
+		if attempt > 1 && request.GetBody != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptRequest.Body = body
+		}
+
+		response, err := t.next.RoundTrip(attemptRequest)
+		if err == nil && request.Method == http.MethodGet {
+			if err = bufferBody(response); err != nil {
+				response = nil
+			}
+		}
+		if err == nil || attempt == retryAttempts || !t.retryable(request, err) {
+			return response, err
+		}
+
+		select {
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		case <-time.After(time.Duration(attempt) * retryBackoff):
+		}
+
+		if t.metrics != nil {
+			t.metrics.RecordRetry()
+		}
+	}
+}
+
+func (t *retryTransport) retryable(request *http.Request, err error) bool {
+	if request.Context().Err() != nil || errors.Is(err, ErrUIEndpoint) {
+		return false
+	}
+	// A body that cannot be read again, like a streamed state file, is
+	// consumed by the first attempt.
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return false
+	}
+	return isConnectError(err) || request.Method == http.MethodGet
+}
+
+// RequestMayHaveArrived reports whether a request that failed without a
+// complete response may still have been received and applied by the
+// server: the errors of the http client raised after connecting, and a
+// response body cut off while reading it.
+func RequestMayHaveArrived(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, ErrUIEndpoint) && !isConnectError(err)
+}
+
+// isConnectError reports errors raised before the request was written: the
+// dial itself or the TLS handshake.
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return strings.Contains(err.Error(), "TLS handshake timeout")
+}
+
+// bufferBody reads the whole response body so an interrupted download fails
+// inside the retry loop instead of in the caller.
+func bufferBody(response *http.Response) error {
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	return nil
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func TestRetryBodyPerAttempt(t *testing.T) {
+	t.Parallel()
+
+	var bodies []string
+	var sent []*http.Request
+	transport := &retryTransport{next: roundTripFunc(func(request *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(request.Body)
+		bodies = append(bodies, string(body))
+		sent = append(sent, request)
+		if len(sent) == 1 {
+			return nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+		}
+		return &http.Response{StatusCode: http.StatusCreated, Body: http.NoBody}, nil
+	})}
+
+	request, _ := http.NewRequest(http.MethodPost, "http://terrakube/api/v1/organization", strings.NewReader(`{"data":{}}`))
+	originalBody := request.Body
+	if _, err := transport.RoundTrip(request); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(bodies) != 2 || bodies[0] != `{"data":{}}` || bodies[1] != `{"data":{}}` {
+		t.Errorf("every attempt should send the whole body, got %q", bodies)
+	}
+	for _, attempt := range sent {
+		if attempt == request {
+			t.Error("the request of the caller was sent instead of a copy")
+		}
+	}
+	if request.Body != originalBody {
+		t.Error("the body of the caller request was replaced")
+	}
+}
+
+// truncatedBody fails like a response body cut off by the server.
+type truncatedBody struct{}
+
+func (truncatedBody) Read([]byte) (int, error) { return 0, io.ErrUnexpectedEOF }
+func (truncatedBody) Close() error             { return nil }
+
+func TestRetryTruncatedBody(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	transport := &retryTransport{next: roundTripFunc(func(request *http.Request) (*http.Response, error) {
+		attempts++
+		return &http.Response{StatusCode: http.StatusOK, Body: truncatedBody{}}, nil
+	})}
+
+	request, _ := http.NewRequest(http.MethodGet, "http://terrakube/api/v1/organization", nil)
+	response, err := transport.RoundTrip(request)
+	if response != nil || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected no response and the read error, got %v %v", response, err)
+	}
+	if attempts != retryAttempts {
+		t.Errorf("expected %d attempts, got %d", retryAttempts, attempts)
+	}
+}
+
+func TestRetryCanceledDuringBackoff(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	transport := &retryTransport{next: roundTripFunc(func(request *http.Request) (*http.Response, error) {
+		time.AfterFunc(retryBackoff/10, cancel)
+		return nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	})}
+
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://terrakube/api/v1/organization", nil)
+	start := time.Now()
+	response, err := transport.RoundTrip(request)
+	if response != nil || err != context.Canceled {
+		t.Errorf("expected the context error, got %v %v", response, err)
+	}
+	if elapsed := time.Since(start); elapsed >= retryBackoff {
+		t.Errorf("the cancellation should stop the backoff, waited %s", elapsed)
+	}
+}
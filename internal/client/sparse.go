@@ -0,0 +1,40 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/jsonapi"
+)
+
+// MarshalSparsePayload writes the JSON:API payload of the model without the
+// given attributes. Elide leaves the attributes missing from a PATCH
+// untouched, so updating the description of a sensitive variable does not
+// send back the value kept in the Terraform state, which could revert a
+// secret rotated outside of Terraform.
+func MarshalSparsePayload(w io.Writer, model any, omit ...string) error {
+	var out = new(bytes.Buffer)
+	if err := jsonapi.MarshalPayload(out, model); err != nil {
+		return err
+	}
+
+	if len(omit) == 0 {
+		_, err := out.WriteTo(w)
+		return err
+	}
+
+	var payload map[string]map[string]any
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		return fmt.Errorf("unable to read payload: %w", err)
+	}
+
+	if attributes, ok := payload["data"]["attributes"].(map[string]any); ok {
+		for _, name := range omit {
+			delete(attributes, name)
+		}
+	}
+
+	return json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/jsonapi"
+)
+
+func TestMarshalSparsePayload(t *testing.T) {
+	t.Parallel()
+
+	variable := &WorkspaceVariableEntity{ID: "v1", Key: "password", Value: "kept in the state", Description: "rotated monthly", Category: "ENV", Sensitive: true}
+	attributes := func(omit ...string) map[string]any {
+		t.Helper()
+		var out bytes.Buffer
+		if err := MarshalSparsePayload(&out, variable, omit...); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var payload struct {
+			Data struct {
+				ID         string         `json:"id"`
+				Attributes map[string]any `json:"attributes"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+			t.Fatalf("invalid payload %s: %s", out.String(), err)
+		}
+		if payload.Data.ID != "v1" {
+			t.Errorf("the payload lost the id: %s", out.String())
+		}
+		return payload.Data.Attributes
+	}
+
+	sparse := attributes("value")
+	if _, ok := sparse["value"]; ok {
+		t.Errorf("a description only update should not send the value: %v", sparse)
+	}
+	if sparse["description"] != "rotated monthly" || sparse["key"] != "password" || sparse["sensitive"] != true || sparse["hcl"] != false {
+		t.Errorf("the other attributes should be sent, false ones included: %v", sparse)
+	}
+
+	var full bytes.Buffer
+	if err := jsonapi.MarshalPayload(&full, variable); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var expected map[string]any
+	json.Unmarshal(full.Bytes(), &expected)
+	if whole, unknown := attributes(), attributes("unknown"); whole["value"] != "kept in the state" || len(whole) != len(expected["data"].(map[string]any)["attributes"].(map[string]any)) || len(unknown) != len(whole) {
+		t.Errorf("without attributes to omit the payload should be complete: %v %v", whole, unknown)
+	}
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// TLSOptions are the PEM encoded certificates and the hosts whose
+// certificate is not verified.
+type TLSOptions struct {
+	CACertPEM     string
+	ClientCertPEM string
+	ClientKeyPEM  string
+	InsecureHosts []string
+}
+
+// TLSOptionError reports which option of TLSOptions is invalid, using the
+// name of the provider attribute.
+type TLSOptionError struct {
+	Option string
+	Err    error
+}
+
+func (e *TLSOptionError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Option, e.Err)
+}
+
+func (e *TLSOptionError) Unwrap() error {
+	return e.Err
+}
+
+// NewTLSConfig returns the TLS configuration for the options, nil when every
+// option is empty and the default configuration applies.
+func NewTLSConfig(options TLSOptions) (*tls.Config, error) {
+	if options.CACertPEM == "" && options.ClientCertPEM == "" && options.ClientKeyPEM == "" && len(options.InsecureHosts) == 0 {
+		return nil, nil
+	}
+
+	config := &tls.Config{}
+
+	if options.CACertPEM != "" {
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM([]byte(options.CACertPEM)) {
+			return nil, &TLSOptionError{Option: "ca_cert", Err: errors.New("no PEM encoded certificate found")}
+		}
+		config.RootCAs = roots
+	}
+
+	if options.ClientCertPEM != "" || options.ClientKeyPEM != "" {
+		certificate, err := tls.X509KeyPair([]byte(options.ClientCertPEM), []byte(options.ClientKeyPEM))
+		if err != nil {
+			return nil, &TLSOptionError{Option: "client_cert", Err: err}
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	if len(options.InsecureHosts) > 0 {
+		insecure := make(map[string]bool, len(options.InsecureHosts))
+		for _, host := range options.InsecureHosts {
+			insecure[host] = true
+		}
+
+		// Verification is done here instead of by the handshake so it can
+		// be skipped for the insecure hosts only.
+		roots := config.RootCAs
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			if insecure[state.ServerName] {
+				return nil
+			}
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("server did not present a certificate")
+			}
+
+			intermediates := x509.NewCertPool()
+			for _, certificate := range state.PeerCertificates[1:] {
+				intermediates.AddCert(certificate)
+			}
+
+			_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+				DNSName:       state.ServerName,
+				Roots:         roots,
+				Intermediates: intermediates,
+			})
+			return err
+		}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ActionResource{}
+var _ resource.ResourceWithImportState = &ActionResource{}
+
+type ActionResource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+	actions  *client.Crud[client.ActionEntity]
+}
+
+type ActionResourceModel struct {
+	ID       types.String         `tfsdk:"id"`
+	Name     types.String         `tfsdk:"name"`
+	Type     types.String         `tfsdk:"type"`
+	Category types.String         `tfsdk:"category"`
+	Version  types.String         `tfsdk:"version"`
+	Active   types.Bool           `tfsdk:"active"`
+	Action   templateContentValue `tfsdk:"action"`
+}
+
+func NewActionResource() resource.Resource {
+	return &ActionResource{}
+}
+
+func (r *ActionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_action"
+}
+
+func (r *ActionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Create a UI action, the extension scripts shown on the workspace pages. " +
+			"Actions are shared by every organization of the Terrakube instance.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Action Id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Action name",
+			},
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "Where the action is displayed, for example `Workspace/Action` or `Workspace/ResourceDrawer/Tab`",
+			},
+			"category": schema.StringAttribute{
+				Required:    true,
+				Description: "Category of the action, for example `General`",
+			},
+			"version": schema.StringAttribute{
+				Required:    true,
+				Description: "Version of the action, for example `1.0.0`",
+			},
+			"active": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Display the action in the UI, default is `true`. An action disabled in the UI is reported as a change.",
+			},
+			"action": schema.StringAttribute{
+				Required:    true,
+				CustomType:  templateContentType{},
+				Description: "Code of the action. Differences only in line endings, trailing whitespace or blank lines at the start and end are not reported as changes.",
+			},
+		},
+	}
+}
+
+func (r *ActionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+	r.actions = client.NewCrud[client.ActionEntity](r.client, r.endpoint, r.token, "/api/v1/action")
+
+	tflog.Debug(ctx, "Configuring Action resource", map[string]any{"success": true})
+}
+
+func (r *ActionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ActionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	action, err := r.actions.Create(ctx, actionEntity(plan))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing action resource request", apiErrorDetail(err, fmt.Sprintf("Error executing action resource request: %s", err)))
+		return
+	}
+
+	setActionState(&plan, action)
+
+	tflog.Info(ctx, "Action Resource Created", map[string]any{"success": true})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *ActionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ActionResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	action, err := r.actions.Get(ctx, state.ID.ValueString())
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing action resource request", apiErrorDetail(err, fmt.Sprintf("Error executing action resource request: %s", err)))
+		return
+	}
+
+	setActionState(&state, action)
+
+	// Set refreshed state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Action Resource reading", map[string]any{"success": true})
+}
+
+func (r *ActionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan ActionResourceModel
+	var state ActionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	bodyRequest := actionEntity(plan)
+	bodyRequest.ID = state.ID.ValueString()
+
+	err := r.actions.Update(ctx, state.ID.ValueString(), bodyRequest)
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing action resource request", apiErrorDetail(err, fmt.Sprintf("Error executing action resource request: %s", err)))
+		return
+	}
+
+	action, err := r.actions.Get(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing action resource request", apiErrorDetail(err, fmt.Sprintf("Error executing action resource request: %s", err)))
+		return
+	}
+
+	setActionState(&plan, action)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *ActionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ActionResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.actions.Delete(ctx, data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing action resource request", apiErrorDetail(err, fmt.Sprintf("Error executing action resource request: %s", err)))
+		return
+	}
+}
+
+func (r *ActionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func actionEntity(plan ActionResourceModel) *client.ActionEntity {
+	return &client.ActionEntity{
+		Name:     plan.Name.ValueString(),
+		Type:     plan.Type.ValueString(),
+		Category: plan.Category.ValueString(),
+		Version:  plan.Version.ValueString(),
+		Action:   plan.Action.ValueString(),
+		Active:   plan.Active.ValueBool(),
+	}
+}
+
+// setActionState copies the action returned by the API, an action disabled
+// or edited in the UI is then planned back to the configuration.
+func setActionState(model *ActionResourceModel, action *client.ActionEntity) {
+	model.ID = types.StringValue(action.ID)
+	model.Name = types.StringValue(action.Name)
+	model.Type = types.StringValue(action.Type)
+	model.Category = types.StringValue(action.Category)
+	model.Version = types.StringValue(action.Version)
+	model.Active = types.BoolValue(action.Active)
+	model.Action = newTemplateContentValue(action.Action)
+}
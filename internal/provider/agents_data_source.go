@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &AgentsDataSource{}
+	_ datasource.DataSourceWithConfigure = &AgentsDataSource{}
+)
+
+type AgentsDataSourceModel struct {
+	OrganizationId types.String         `tfsdk:"organization_id"`
+	Agents         []AgentListItemModel `tfsdk:"agents"`
+}
+
+type AgentListItemModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Url         types.String `tfsdk:"url"`
+}
+
+type AgentsDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewAgentsDataSource() datasource.DataSource {
+	return &AgentsDataSource{}
+}
+
+func (d *AgentsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Agents Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Agents datasource")
+}
+
+func (d *AgentsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_agents"
+}
+
+func (d *AgentsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List all the self hosted agents of an organization sorted by name.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+			},
+			"agents": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Self hosted agents defined in the organization",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Agent Id",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Self hosted agent name",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Description of the self hosted agent",
+						},
+						"url": schema.StringAttribute{
+							Computed:    true,
+							Description: "Url of the self hosted agent",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AgentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state AgentsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agentList, err := fetchAllPages(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/organization/%s/agent", d.endpoint, state.OrganizationId.ValueString()), reflect.TypeOf(new(client.AgentEntity)))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading agents", apiErrorDetail(err, fmt.Sprintf("Error reading agents: %s", err)))
+		return
+	}
+
+	state.Agents = make([]AgentListItemModel, 0, len(agentList))
+	for _, agent := range agentList {
+		data, _ := agent.(*client.AgentEntity)
+		state.Agents = append(state.Agents, AgentListItemModel{
+			ID:          types.StringValue(data.ID),
+			Name:        types.StringValue(data.Name),
+			Description: types.StringValue(data.Description),
+			Url:         types.StringValue(data.Url),
+		})
+	}
+
+	sort.SliceStable(state.Agents, func(i, j int) bool {
+		if state.Agents[i].Name.ValueString() != state.Agents[j].Name.ValueString() {
+			return state.Agents[i].Name.ValueString() < state.Agents[j].Name.ValueString()
+		}
+		return state.Agents[i].ID.ValueString() < state.Agents[j].ID.ValueString()
+	})
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// publicVcsHosts are the hosts that cannot be reached from an air-gapped
+// Terrakube instance.
+var publicVcsHosts = []string{"github.com", "gitlab.com", "bitbucket.org"}
+
+// airgapPolicy reports repositories hosted on public VCS providers when the
+// provider is configured for an air-gapped instance. Such modules and
+// workspaces are created without error but never sync.
+type airgapPolicy struct {
+	enabled  bool
+	allowed  map[string]bool
+	warnings *warningPolicy
+}
+
+func newAirgapPolicy(enabled bool, allowedHosts []string, warnings *warningPolicy) *airgapPolicy {
+	allowed := make(map[string]bool, len(allowedHosts))
+	for _, host := range allowedHosts {
+		allowed[normalizeHost(host)] = true
+	}
+	return &airgapPolicy{enabled: enabled, allowed: allowed, warnings: warnings}
+}
+
+// warnExternalSource adds a warning on the attribute of the plan holding the
+// repository url when it points at a public host that is not allowed.
+func (p *airgapPolicy) warnExternalSource(ctx context.Context, plan tfsdk.Plan, attribute string, diags *diag.Diagnostics) {
+	if p == nil || !p.enabled || plan.Raw.IsNull() {
+		return
+	}
+
+	var source types.String
+	diags.Append(plan.GetAttribute(ctx, path.Root(attribute), &source)...)
+	if source.IsNull() || source.IsUnknown() {
+		return
+	}
+
+	host := sourceHost(source.ValueString())
+	if host == "" || p.allowed[host] {
+		return
+	}
+
+	for _, public := range publicVcsHosts {
+		if host == public || strings.HasSuffix(host, "."+public) {
+			p.warnings.addAttribute(
+				diags,
+				path.Root(attribute),
+				"Public repository in an air-gapped instance",
+				fmt.Sprintf("%s is hosted on %s, which an air-gapped Terrakube instance cannot reach, so it will never sync. "+
+					"Use a mirror reachable from the instance, or add %q to allowed_external_hosts if it is reachable.", source.ValueString(), host, host),
+			)
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAirgapPublicRepositories(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.put("/api/v1/organization/o1", "organization", map[string]any{"name": "platform"})
+	hosts := func(hosts ...string) tftypes.Value {
+		values := make([]tftypes.Value, 0, len(hosts))
+		for _, host := range hosts {
+			values = append(values, tftypes.NewValue(tftypes.String, host))
+		}
+		return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, values)
+	}
+	connected := newTestProvider(t, server.URL, nil)
+	airgapped := newTestProvider(t, server.URL, map[string]tftypes.Value{"airgapped": tftypes.NewValue(tftypes.Bool, true)})
+	proxied := newTestProvider(t, server.URL, map[string]tftypes.Value{
+		"airgapped":              tftypes.NewValue(tftypes.Bool, true),
+		"allowed_external_hosts": hosts("WWW.GitHub.com."),
+	})
+
+	for _, test := range []struct {
+		name      string
+		terrakube *testProvider
+		source    string
+		warned    bool
+	}{
+		{"connected instance", connected, "https://github.com/platform/terraform-aws-vpc.git", false},
+		{"github", airgapped, "https://github.com/platform/terraform-aws-vpc.git", true},
+		{"scp-like gitlab", airgapped, "git@gitlab.com:platform/terraform-aws-vpc.git", true},
+		{"getter prefix", airgapped, "git::https://bitbucket.org/platform/terraform-aws-vpc.git", true},
+		{"subdomain", airgapped, "https://codeload.github.com/platform/terraform-aws-vpc.git", true},
+		{"internal host", airgapped, "https://gitlab.platform.internal/platform/terraform-aws-vpc.git", false},
+		{"look-alike host", airgapped, "https://mygithub.com/platform/terraform-aws-vpc.git", false},
+		{"allowed host", proxied, "https://github.com/platform/terraform-aws-vpc.git", false},
+		{"other public host", proxied, "https://gitlab.com/platform/terraform-aws-vpc.git", true},
+	} {
+		plan := test.terrakube.plan("terrakube_module", test.terrakube.null("terrakube_module"), moduleConfig(test.terrakube, "vpc", "aws", map[string]tftypes.Value{
+			"source": tftypes.NewValue(tftypes.String, test.source),
+		}))
+		if err := diagnosticsError(plan.Diagnostics); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		if warned := hasDiagnostic(plan.Diagnostics, tfprotov6.DiagnosticSeverityWarning, "Public repository in an air-gapped instance"); warned != test.warned {
+			t.Errorf("%s: expected a warning %t for %s, got %v", test.name, test.warned, test.source, plan.Diagnostics)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Terrakube returns "" for an optional string that was never set, while
+// Terraform keeps it null, which shows as a diff after an import. Optional
+// strings read from the API go through stringFromAPI so an empty value is
+// always null in state, and their schemas reject "" so a configuration can
+// not ask for the other convention.
+func stringFromAPI(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// stringPointerFromAPI is stringFromAPI for attributes the API may omit.
+func stringPointerFromAPI(s *string) types.String {
+	if s == nil {
+		return types.StringNull()
+	}
+	return stringFromAPI(*s)
+}
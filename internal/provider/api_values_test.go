@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestStringFromAPI(t *testing.T) {
+	t.Parallel()
+
+	empty, value := "", "/modules/vpc/"
+	for _, test := range []struct {
+		name     string
+		actual   types.String
+		expected types.String
+	}{
+		{"empty", stringFromAPI(""), types.StringNull()},
+		{"value", stringFromAPI("vpc/"), types.StringValue("vpc/")},
+		{"blank is a value", stringFromAPI(" "), types.StringValue(" ")},
+		{"omitted", stringPointerFromAPI(nil), types.StringNull()},
+		{"empty pointer", stringPointerFromAPI(&empty), types.StringNull()},
+		{"pointer", stringPointerFromAPI(&value), types.StringValue(value)},
+	} {
+		if !test.actual.Equal(test.expected) {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, test.actual)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// defaultAPIParallelism is the number of concurrent deletes when
+// api_parallelism is not set.
+const defaultAPIParallelism = 4
+
+// bulkDeleteJitter is the longest random pause before each delete, so the
+// workers do not send their requests in lockstep.
+const bulkDeleteJitter = 100 * time.Millisecond
+
+// deleteAll calls remove for every key with at most parallelism calls at a
+// time. Every key is attempted, the errors are returned by key so the caller
+// keeps the failed items in state.
+func deleteAll(ctx context.Context, parallelism int, keys []string, remove func(ctx context.Context, key string) error) map[string]error {
+	if parallelism < 1 {
+		parallelism = defaultAPIParallelism
+	}
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		errs  = map[string]error{}
+		slots = make(chan struct{}, parallelism)
+	)
+
+	for _, item := range keys {
+		key := item
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			err := ctx.Err()
+			if err == nil {
+				select {
+				case <-ctx.Done():
+					err = ctx.Err()
+				case <-time.After(time.Duration(rand.Int63n(int64(bulkDeleteJitter)))):
+					err = remove(ctx, key)
+				}
+			}
+			if err == nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			errs[key] = err
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// deletePages deletes the items of a paginated collection. list returns the
+// keys of a page and whether more pages follow. Elide pages by offset, so
+// deleting while walking the pages would move the following items onto the
+// pages already read: every page is read before the first delete.
+func deletePages(ctx context.Context, parallelism int, list func(ctx context.Context, page int) ([]string, bool, error), remove func(ctx context.Context, key string) error) (map[string]error, error) {
+	var keys []string
+	for page := 1; ; page++ {
+		pageKeys, more, err := list(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, pageKeys...)
+		if !more {
+			break
+		}
+	}
+
+	return deleteAll(ctx, parallelism, keys, remove), nil
+}
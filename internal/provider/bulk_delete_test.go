@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDeleteAllConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var running, busiest atomic.Int32
+	var mu sync.Mutex
+	var removed []string
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+
+	errs := deleteAll(context.Background(), 3, keys, func(ctx context.Context, key string) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			previous := busiest.Load()
+			if current <= previous || busiest.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		// A slow API keeps the workers busy.
+		time.Sleep(50 * time.Millisecond)
+
+		mu.Lock()
+		removed = append(removed, key)
+		mu.Unlock()
+		if key == "b" || key == "g" {
+			return fmt.Errorf("%s is locked", key)
+		}
+		return nil
+	})
+
+	if concurrent := busiest.Load(); concurrent > 3 || concurrent < 2 {
+		t.Errorf("expected at most 3 and more than 1 concurrent deletes, got %d", concurrent)
+	}
+	sort.Strings(removed)
+	if !reflect.DeepEqual(removed, keys) {
+		t.Errorf("every key should be attempted, got %v", removed)
+	}
+	if len(errs) != 2 || errs["b"] == nil || errs["g"] == nil {
+		t.Errorf("expected the errors of b and g, got %v", errs)
+	}
+}
+
+func TestDeleteAllCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	errs := deleteAll(ctx, 2, []string{"a", "b", "c"}, func(ctx context.Context, key string) error {
+		calls.Add(1)
+		return nil
+	})
+	if calls.Load() != 0 {
+		t.Errorf("a canceled context should not delete, got %d deletes", calls.Load())
+	}
+	if len(errs) != 3 || !errors.Is(errs["a"], context.Canceled) {
+		t.Errorf("every key should fail with the context error, got %v", errs)
+	}
+}
+
+func TestDeletePages(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var events []string
+	pages := [][]string{{"a", "b"}, {"c"}, {}}
+
+	errs, err := deletePages(context.Background(), 2, func(ctx context.Context, page int) ([]string, bool, error) {
+		mu.Lock()
+		events = append(events, fmt.Sprintf("page %d", page))
+		mu.Unlock()
+		return pages[page-1], page < len(pages), nil
+	}, func(ctx context.Context, key string) error {
+		mu.Lock()
+		events = append(events, "delete "+key)
+		mu.Unlock()
+		if key == "c" {
+			return errors.New("conflict")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(events[:3], []string{"page 1", "page 2", "page 3"}) {
+		t.Errorf("every page should be read before the first delete, got %v", events)
+	}
+	deleted := append([]string{}, events[3:]...)
+	sort.Strings(deleted)
+	if !reflect.DeepEqual(deleted, []string{"delete a", "delete b", "delete c"}) {
+		t.Errorf("every listed key should be deleted, got %v", events)
+	}
+	if len(errs) != 1 || errs["c"] == nil {
+		t.Errorf("expected the error of c, got %v", errs)
+	}
+
+	failed := errors.New("page unavailable")
+	var deletes atomic.Int32
+	_, err = deletePages(context.Background(), 2, func(ctx context.Context, page int) ([]string, bool, error) {
+		if page == 2 {
+			return nil, false, failed
+		}
+		return []string{"a"}, true, nil
+	}, func(ctx context.Context, key string) error {
+		deletes.Add(1)
+		return nil
+	})
+	if err != failed || deletes.Load() != 0 {
+		t.Errorf("a failed page should stop before deleting, got %v and %d deletes", err, deletes.Load())
+	}
+}
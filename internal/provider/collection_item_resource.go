@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -73,7 +72,10 @@ func (r *CollectionItemResource) Schema(ctx context.Context, req resource.Schema
 			},
 			"key": schema.StringAttribute{
 				Required:    true,
-				Description: "Variable key",
+				Description: "Variable key. Changing it forces a new variable.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"value": schema.StringAttribute{
 				Required:    true,
@@ -85,7 +87,10 @@ func (r *CollectionItemResource) Schema(ctx context.Context, req resource.Schema
 			},
 			"category": schema.StringAttribute{
 				Required:    true,
-				Description: "Variable category (ENV or TERRAFORM). ENV variables are injected in workspace environment at runtime.",
+				Description: "Variable category (ENV or TERRAFORM). ENV variables are injected in workspace environment at runtime. Changing it forces a new variable.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"sensitive": schema.BoolAttribute{
 				Required:    true,
@@ -114,17 +119,7 @@ func (r *CollectionItemResource) Configure(ctx context.Context, req resource.Con
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -158,7 +153,7 @@ func (r *CollectionItemResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	collectionItemRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/item", r.endpoint, plan.OrganizationId.ValueString(), plan.CollectionId.ValueString()), strings.NewReader(out.String()))
+	collectionItemRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/item", r.endpoint, plan.OrganizationId.ValueString(), plan.CollectionId.ValueString()), strings.NewReader(out.String()))
 	collectionItemRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionItemRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -181,7 +176,7 @@ func (r *CollectionItemResource) Create(ctx context.Context, req resource.Create
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), collectionItem)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(collectionItemResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, collectionItemResponse.Status)))
 		return
 	}
 
@@ -239,7 +234,7 @@ func (r *CollectionItemResource) Read(ctx context.Context, req resource.ReadRequ
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), collectionItem)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(collectionItemResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, collectionItemResponse.Status)))
 		return
 	}
 
@@ -291,7 +286,7 @@ func (r *CollectionItemResource) Update(ctx context.Context, req resource.Update
 	}
 
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err := client.MarshalSparsePayload(out, bodyRequest, unchangedValue(plan.Value, state.Value)...)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
@@ -335,7 +330,7 @@ func (r *CollectionItemResource) Update(ctx context.Context, req resource.Update
 
 	bodyResponse, err = io.ReadAll(collectionItemResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading collection item resource response body", fmt.Sprintf("Error reading collection item resource response body: %s", err))
+		resp.Diagnostics.AddError("Error reading collection item resource response body", responseErrorDetail(collectionItemResponse, fmt.Sprintf("Error reading collection item resource response body, error: %s, response status: %s", err, collectionItemResponse.Status)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -344,7 +339,7 @@ func (r *CollectionItemResource) Update(ctx context.Context, req resource.Update
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), collectionItem)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(collectionItemResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, collectionItemResponse.Status)))
 		return
 	}
 
@@ -376,18 +371,25 @@ func (r *CollectionItemResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/item/%s", r.endpoint, data.OrganizationId.ValueString(), data.CollectionId.ValueString(), data.ID.ValueString()), nil)
+	workspaceRequest, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/item/%s", r.endpoint, data.OrganizationId.ValueString(), data.CollectionId.ValueString(), data.ID.ValueString()), nil)
 	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating collection item resource request", fmt.Sprintf("Error creating collection item resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(workspaceRequest)
+	deleteResponse, err := r.client.Do(workspaceRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing collection item resource request", fmt.Sprintf("Error executing collection item resource request: %s", err))
 		return
 	}
+	defer deleteResponse.Body.Close()
+
+	if deleteResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(deleteResponse.Body)
+		resp.Diagnostics.AddError("Error executing collection item resource request", responseErrorDetail(deleteResponse, fmt.Sprintf("Error executing collection item resource request, response status: %s, response body: %s", deleteResponse.Status, bodyResponse)))
+		return
+	}
 }
 
 func (r *CollectionItemResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -94,17 +93,7 @@ func (r *CollectionReferenceResource) Configure(ctx context.Context, req resourc
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -135,7 +124,7 @@ func (r *CollectionReferenceResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
-	collectionReferenceRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/reference", r.endpoint, plan.OrganizationId.ValueString(), plan.CollectionId.ValueString()), strings.NewReader(out.String()))
+	collectionReferenceRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/reference", r.endpoint, plan.OrganizationId.ValueString(), plan.CollectionId.ValueString()), strings.NewReader(out.String()))
 	collectionReferenceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionReferenceRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -158,7 +147,7 @@ func (r *CollectionReferenceResource) Create(ctx context.Context, req resource.C
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), collectionReference)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(collectionReferenceResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, collectionReferenceResponse.Status)))
 		return
 	}
 
@@ -206,7 +195,7 @@ func (r *CollectionReferenceResource) Read(ctx context.Context, req resource.Rea
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), collectionReference)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(collectionReferenceResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, collectionReferenceResponse.Status)))
 		return
 	}
 
@@ -289,7 +278,7 @@ func (r *CollectionReferenceResource) Update(ctx context.Context, req resource.U
 
 	bodyResponse, err = io.ReadAll(collectionReferenceResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading collection reference resource response body", fmt.Sprintf("Error reading collection reference resource response body: %s", err))
+		resp.Diagnostics.AddError("Error reading collection reference resource response body", responseErrorDetail(collectionReferenceResponse, fmt.Sprintf("Error reading collection reference resource response body, error: %s, response status: %s", err, collectionReferenceResponse.Status)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -298,7 +287,7 @@ func (r *CollectionReferenceResource) Update(ctx context.Context, req resource.U
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), collectionReference)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(collectionReferenceResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, collectionReferenceResponse.Status)))
 		return
 	}
 
@@ -320,18 +309,25 @@ func (r *CollectionReferenceResource) Delete(ctx context.Context, req resource.D
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/reference/%s", r.endpoint, data.ID.ValueString()), nil)
+	workspaceRequest, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/reference/%s", r.endpoint, data.ID.ValueString()), nil)
 	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating collection reference resource request", fmt.Sprintf("Error creating collection reference resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(workspaceRequest)
+	deleteResponse, err := r.client.Do(workspaceRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing collection reference resource request", fmt.Sprintf("Error executing collection reference resource request: %s", err))
 		return
 	}
+	defer deleteResponse.Body.Close()
+
+	if deleteResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(deleteResponse.Body)
+		resp.Diagnostics.AddError("Error executing collection reference resource request", responseErrorDetail(deleteResponse, fmt.Sprintf("Error executing collection reference resource request, response status: %s, response body: %s", deleteResponse.Status, bodyResponse)))
+		return
+	}
 }
 
 func (r *CollectionReferenceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
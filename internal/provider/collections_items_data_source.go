@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// collectionsItemsParallelism bounds the collections read at the same time.
+const collectionsItemsParallelism = 4
+
+var (
+	_ datasource.DataSource              = &CollectionsItemsDataSource{}
+	_ datasource.DataSourceWithConfigure = &CollectionsItemsDataSource{}
+)
+
+type CollectionsItemsDataSourceModel struct {
+	OrganizationId types.String                   `tfsdk:"organization_id"`
+	Items          []CollectionsItemListItemModel `tfsdk:"items"`
+}
+
+type CollectionsItemListItemModel struct {
+	CollectionId   types.String `tfsdk:"collection_id"`
+	CollectionName types.String `tfsdk:"collection_name"`
+	Key            types.String `tfsdk:"key"`
+	Category       types.String `tfsdk:"category"`
+	Sensitive      types.Bool   `tfsdk:"sensitive"`
+	Description    types.String `tfsdk:"description"`
+}
+
+type CollectionsItemsDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewCollectionsItemsDataSource() datasource.DataSource {
+	return &CollectionsItemsDataSource{}
+}
+
+func (d *CollectionsItemsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Collections Items Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Collections Items datasource")
+}
+
+func (d *CollectionsItemsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_collections_items"
+}
+
+func (d *CollectionsItemsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the keys defined in every collection of an organization, sorted by collection name and key. Values are never returned.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+			},
+			"items": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Items of all the collections of the organization",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"collection_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Collection Id",
+						},
+						"collection_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Collection name",
+						},
+						"key": schema.StringAttribute{
+							Computed:    true,
+							Description: "Item key",
+						},
+						"category": schema.StringAttribute{
+							Computed:    true,
+							Description: "Item category (ENV or TERRAFORM)",
+						},
+						"sensitive": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the item is sensitive",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Item description",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *CollectionsItemsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state CollectionsItemsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	items, err := listCollectionItems(ctx, d.client, d.endpoint, d.token, state.OrganizationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading collection items", apiErrorDetail(err, fmt.Sprintf("Error reading collection items: %s", err)))
+		return
+	}
+
+	state.Items = make([]CollectionsItemListItemModel, 0, len(items))
+	for _, item := range items {
+		state.Items = append(state.Items, CollectionsItemListItemModel{
+			CollectionId:   types.StringValue(item.collection.ID),
+			CollectionName: types.StringValue(item.collection.Name),
+			Key:            types.StringValue(item.item.Key),
+			Category:       types.StringValue(item.item.Category),
+			Sensitive:      types.BoolValue(item.item.Sensitive),
+			Description:    types.StringValue(item.item.Description),
+		})
+	}
+
+	sort.SliceStable(state.Items, func(i, j int) bool {
+		a, b := state.Items[i], state.Items[j]
+		if a.CollectionName.ValueString() != b.CollectionName.ValueString() {
+			return a.CollectionName.ValueString() < b.CollectionName.ValueString()
+		}
+		if a.CollectionId.ValueString() != b.CollectionId.ValueString() {
+			return a.CollectionId.ValueString() < b.CollectionId.ValueString()
+		}
+		if a.Key.ValueString() != b.Key.ValueString() {
+			return a.Key.ValueString() < b.Key.ValueString()
+		}
+		return a.Category.ValueString() < b.Category.ValueString()
+	})
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// collectionItem is an item with the collection holding it.
+type collectionItem struct {
+	collection *client.CollectionEntity
+	item       *client.CollectionItemEntity
+}
+
+// listCollectionItems lists the items of every collection of the
+// organization, reading collectionsItemsParallelism collections at a time.
+// The items are returned in no particular order.
+func listCollectionItems(ctx context.Context, httpClient *http.Client, endpoint string, token string, organizationId string) ([]collectionItem, error) {
+	collections, err := fetchAllPages(ctx, httpClient, token, fmt.Sprintf("%s/api/v1/organization/%s/collection", endpoint, organizationId), reflect.TypeOf(new(client.CollectionEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("listing collections: %w", err)
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		errs   []error
+		result []collectionItem
+		slots  = make(chan struct{}, collectionsItemsParallelism)
+	)
+
+	for _, item := range collections {
+		collection := item.(*client.CollectionEntity)
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			items, err := fetchAllPages(ctx, httpClient, token, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/item", endpoint, organizationId, collection.ID), reflect.TypeOf(new(client.CollectionItemEntity)))
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("collection %s (%s): %w", collection.Name, collection.ID, err))
+				return
+			}
+
+			for _, item := range items {
+				result = append(result, collectionItem{collection: collection, item: item.(*client.CollectionItemEntity)})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+		return nil, fmt.Errorf("reading the items of %d of %d collections:\n%w", len(errs), len(collections), errors.Join(errs...))
+	}
+
+	return result, nil
+}
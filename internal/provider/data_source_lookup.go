@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func allowMissingSchema() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Optional:    true,
+		Description: "Return null attributes and `found = false` instead of an error when nothing matches, default is `false`.",
+	}
+}
+
+func foundSchema() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Computed:    true,
+		Description: "Whether a matching object was found",
+	}
+}
+
+// lookupNotFound reports a data source lookup that matched nothing. It adds
+// an error unless allow_missing is set, in which case the data source keeps
+// its computed attributes null and sets found to false.
+func lookupNotFound(diags *diag.Diagnostics, allowMissing types.Bool, kind string, name string) {
+	if allowMissing.ValueBool() {
+		return
+	}
+
+	diags.AddAttributeError(
+		path.Root("name"),
+		fmt.Sprintf("%s not found", kind),
+		fmt.Sprintf("No %s named %q was found. Set allow_missing = true to get found = false instead of an error.", kind, name),
+	)
+}
+
+// idOrNameSchema is the id or the name of the data sources found either by
+// id or by name, both are set after the read.
+func idOrNameSchema(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: description + ", set either id or name",
+	}
+}
+
+// idOrNameValidators requires exactly one of id and name.
+func idOrNameValidators() []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+// rsqlString quotes a value for an RSQL comparison, Elide reads the
+// backslash escapes of a quoted argument.
+func rsqlString(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// lookupBody returns the response body of a data source lookup: the entity
+// read directly when the id is set, nil when it does not exist, and the
+// collection filtered on the name otherwise.
+func lookupBody(ctx context.Context, httpClient *http.Client, token string, collectionURL string, filterParameter string, id types.String, name types.String) ([]byte, error) {
+	if !id.IsNull() {
+		body, err := fetchPageBody(ctx, httpClient, token, collectionURL+"/"+url.PathEscape(id.ValueString()))
+		var statusErr *client.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return body, err
+	}
+
+	query := url.Values{}
+	query.Set(filterParameter, "name=="+rsqlString(name.ValueString()))
+	return fetchPageBody(ctx, httpClient, token, collectionURL+"?"+query.Encode())
+}
+
+// unmarshalLookup returns the entities of a lookupBody response, which holds
+// a single resource when the lookup is done by id.
+func unmarshalLookup(body []byte, entityType reflect.Type) ([]interface{}, error) {
+	if body == nil {
+		return nil, nil
+	}
+	if !singleResourceDocument(body) {
+		return jsonapi.UnmarshalManyPayload(bytes.NewReader(body), entityType)
+	}
+
+	entity := reflect.New(entityType.Elem())
+	if err := jsonapi.UnmarshalPayload(bytes.NewReader(body), entity.Interface()); err != nil {
+		return nil, err
+	}
+	return []interface{}{entity.Interface()}, nil
+}
+
+// singleResourceDocument reports whether the primary data of the JSON:API
+// document is a single resource object instead of an array.
+func singleResourceDocument(body []byte) bool {
+	var document struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return false
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(document.Data), []byte("{"))
+}
+
+// lookupIdOrNameNotFound is lookupNotFound for the data sources found
+// either by id or by name.
+func lookupIdOrNameNotFound(diags *diag.Diagnostics, allowMissing types.Bool, kind string, id types.String, name types.String) {
+	if id.IsNull() {
+		lookupNotFound(diags, allowMissing, kind, name.ValueString())
+		return
+	}
+	if allowMissing.ValueBool() {
+		return
+	}
+
+	diags.AddAttributeError(
+		path.Root("id"),
+		fmt.Sprintf("%s not found", kind),
+		fmt.Sprintf("No %s with id %q was found. Set allow_missing = true to get found = false instead of an error.", kind, id.ValueString()),
+	)
+}
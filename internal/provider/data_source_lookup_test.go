@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"terraform-provider-terrakube/internal/client"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestRsqlString(t *testing.T) {
+	t.Parallel()
+
+	for value, expected := range map[string]string{
+		"team":          `'team'`,
+		"o'brien":       `'o\'brien'`,
+		`back\slash`:    `'back\\slash'`,
+		`x' or 'a'=='a`: `'x\' or \'a\'==\'a'`,
+	} {
+		if quoted := rsqlString(value); quoted != expected {
+			t.Errorf("rsqlString(%q) = %s, expected %s", value, quoted, expected)
+		}
+	}
+}
+
+func TestLookupBody(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch r.URL.Path {
+		case "/api/v1/organization/o1/team/t1":
+			fmt.Fprint(w, `{"data":{"type":"team","id":"t1","attributes":{"name":"by-id"}}}`)
+		case "/api/v1/organization/o1/team":
+			if filter := r.URL.Query().Get("filter[team]"); filter != `name=='o\'brien'` {
+				t.Errorf("unexpected filter %s", filter)
+			}
+			fmt.Fprint(w, `{"data":[{"type":"team","id":"t2","attributes":{"name":"o'brien"}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	collectionURL := server.URL + "/api/v1/organization/o1/team"
+	teamType := reflect.TypeOf(new(client.TeamEntity))
+	for _, test := range []struct {
+		id       types.String
+		name     types.String
+		expected string
+	}{
+		{types.StringValue("t1"), types.StringNull(), "by-id"},
+		{types.StringNull(), types.StringValue("o'brien"), "o'brien"},
+		{types.StringValue("missing"), types.StringNull(), ""},
+	} {
+		body, err := lookupBody(context.Background(), http.DefaultClient, "token", collectionURL, "filter[team]", test.id, test.name)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		teams, err := unmarshalLookup(body, teamType)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if test.expected == "" {
+			if len(teams) != 0 {
+				t.Errorf("expected no team for id %s, got %d", test.id, len(teams))
+			}
+			continue
+		}
+		if len(teams) != 1 || teams[0].(*client.TeamEntity).Name != test.expected {
+			t.Errorf("expected the team %s, got %v", test.expected, teams)
+		}
+	}
+}
+
+// TestLookupNotFound reads the lookup data sources the way a plan refresh
+// does, for an object that exists and one that does not, with and without
+// allow_missing.
+func TestLookupNotFound(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.put("/api/v1/organization/o1", "organization", map[string]any{"name": "platform", "executionMode": "remote"})
+	api.put("/api/v1/organization/o1/vcs/v1", "vcs", map[string]any{"name": "github", "vcsType": "GITHUB"})
+	api.put("/api/v1/organization/o1/template/tp1", "template", map[string]any{"name": "plan", "tcl": "ZmxvdzogW10="})
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	organization := map[string]tftypes.Value{}
+	inOrganization := map[string]tftypes.Value{"organization_id": tftypes.NewValue(tftypes.String, "o1")}
+	for _, test := range []struct {
+		typeName   string
+		attributes map[string]tftypes.Value
+		name       string
+		id         string
+		summary    string
+	}{
+		{"terrakube_organization", organization, "platform", "o1", "organization not found"},
+		{"terrakube_vcs", inOrganization, "github", "v1", "VCS connection not found"},
+		{"terrakube_organization_template", inOrganization, "plan", "tp1", "template not found"},
+	} {
+		lookup := func(name string, allowMissing bool) (tftypes.Value, []*tfprotov6.Diagnostic) {
+			attributes := map[string]tftypes.Value{
+				"name":          tftypes.NewValue(tftypes.String, name),
+				"allow_missing": tftypes.NewValue(tftypes.Bool, allowMissing),
+			}
+			for attributeName, value := range test.attributes {
+				attributes[attributeName] = value
+			}
+			return terrakube.readDataSource(test.typeName, attributes)
+		}
+
+		state, diagnostics := lookup(test.name, false)
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.typeName, err)
+		}
+		if id := stringAttribute(t, state, "id"); id != test.id || !attribute(t, state, "found").Equal(tftypes.NewValue(tftypes.Bool, true)) {
+			t.Errorf("%s: expected %s to be found, got %s", test.typeName, test.id, state)
+		}
+
+		_, diagnostics = lookup("missing", false)
+		if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, test.summary) {
+			t.Errorf("%s: expected the error %q, got %v", test.typeName, test.summary, diagnostics)
+		}
+
+		state, diagnostics = lookup("missing", true)
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Fatalf("%s: allow_missing should not fail: %s", test.typeName, err)
+		}
+		if !attribute(t, state, "found").Equal(tftypes.NewValue(tftypes.Bool, false)) || !attribute(t, state, "id").IsNull() {
+			t.Errorf("%s: expected found = false and a null id, got %s", test.typeName, state)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the wrapper keeps the optional interfaces of the data sources.
+var (
+	_ datasource.DataSourceWithConfigure        = &cachedDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &cachedDataSource{}
+)
+
+// dataSourceCache keeps the result of every data source read of the
+// operation, keyed by data source and configuration. Terraform starts a new
+// provider process for each plan or apply, so nothing is ever invalidated.
+// A read running for a key makes the concurrent reads of the same key wait
+// for its result, a for_each over identical inputs sends one request.
+type dataSourceCache struct {
+	mu      sync.Mutex
+	entries map[string]*dataSourceCacheEntry
+}
+
+type dataSourceCacheEntry struct {
+	done  chan struct{}
+	state tftypes.Value
+	diags diag.Diagnostics
+}
+
+func newDataSourceCache() *dataSourceCache {
+	return &dataSourceCache{entries: map[string]*dataSourceCacheEntry{}}
+}
+
+// read answers from the cache or calls read and caches its result. Failed
+// reads are not cached, the waiting reads and the later ones try again.
+func (c *dataSourceCache) read(ctx context.Context, key string, resp *datasource.ReadResponse, read func()) {
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	if !found {
+		entry = &dataSourceCacheEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	if found {
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			resp.Diagnostics.AddError("Data source read cancelled", ctx.Err().Error())
+			return
+		}
+
+		if entry.diags.HasError() {
+			read()
+			return
+		}
+		resp.State.Raw = entry.state.Copy()
+		resp.Diagnostics.Append(entry.diags...)
+		return
+	}
+
+	read()
+
+	entry.state = resp.State.Raw.Copy()
+	entry.diags = append(diag.Diagnostics{}, resp.Diagnostics...)
+	if entry.diags.HasError() {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+	}
+	close(entry.done)
+}
+
+// cachedDataSource reads the wrapped data source through the cache of the
+// provider, a nil cache reads it directly.
+type cachedDataSource struct {
+	datasource.DataSource
+	cache *dataSourceCache
+}
+
+// withDataSourceCache wraps the data sources of the provider.
+func withDataSourceCache(dataSources []func() datasource.DataSource) []func() datasource.DataSource {
+	wrapped := make([]func() datasource.DataSource, 0, len(dataSources))
+	for _, item := range dataSources {
+		newDataSource := item
+		wrapped = append(wrapped, func() datasource.DataSource {
+			return &cachedDataSource{DataSource: newDataSource()}
+		})
+	}
+	return wrapped
+}
+
+func (d *cachedDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if providerData, ok := req.ProviderData.(*TerrakubeConnectionData); ok {
+		d.cache = providerData.DataSourceCache
+	}
+
+	if configurable, ok := d.DataSource.(datasource.DataSourceWithConfigure); ok {
+		configurable.Configure(ctx, req, resp)
+	}
+}
+
+func (d *cachedDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	if validated, ok := d.DataSource.(datasource.DataSourceWithConfigValidators); ok {
+		return validated.ConfigValidators(ctx)
+	}
+	return nil
+}
+
+func (d *cachedDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.cache == nil {
+		d.DataSource.Read(ctx, req, resp)
+		return
+	}
+
+	// The string form of a value sorts the map keys, the same configuration
+	// always gives the same key.
+	hash := sha256.Sum256([]byte(req.Config.Raw.String()))
+	key := fmt.Sprintf("%T/%s", d.DataSource, hex.EncodeToString(hash[:]))
+
+	d.cache.read(ctx, key, resp, func() {
+		d.DataSource.Read(ctx, req, resp)
+	})
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// countingDataSource counts its reads, which wait for release and fail for
+// the names in failing.
+type countingDataSource struct {
+	reads   atomic.Int32
+	release chan struct{}
+	failing map[string]bool
+}
+
+func (d *countingDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "terrakube_counting"
+}
+
+func (d *countingDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name":  schema.StringAttribute{Required: true},
+			"value": schema.StringAttribute{Computed: true},
+		},
+	}
+}
+
+func (d *countingDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	d.reads.Add(1)
+	if d.release != nil {
+		<-d.release
+	}
+
+	var name string
+	req.Config.GetAttribute(ctx, path.Root("name"), &name)
+	if d.failing[name] {
+		resp.Diagnostics.AddError("Read failed", name)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("value"), "value of "+name)...)
+}
+
+// readCounting reads the data source through the cache with the name.
+func readCounting(t *testing.T, dataSource *cachedDataSource, name string) (string, bool) {
+	t.Helper()
+
+	var schemaResponse datasource.SchemaResponse
+	dataSource.Schema(context.Background(), datasource.SchemaRequest{}, &schemaResponse)
+	objectType := schemaResponse.Schema.Type().TerraformType(context.Background()).(tftypes.Object)
+
+	request := datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResponse.Schema, Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+		"name":  tftypes.NewValue(tftypes.String, name),
+		"value": tftypes.NewValue(tftypes.String, nil),
+	})}}
+	response := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResponse.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+	dataSource.Read(context.Background(), request, &response)
+	if response.Diagnostics.HasError() {
+		return "", false
+	}
+
+	var value string
+	response.State.GetAttribute(context.Background(), path.Root("value"), &value)
+	return value, true
+}
+
+func TestDataSourceCacheConcurrentReads(t *testing.T) {
+	t.Parallel()
+
+	counting := &countingDataSource{release: make(chan struct{})}
+	dataSource := &cachedDataSource{DataSource: counting, cache: newDataSourceCache()}
+
+	var wg sync.WaitGroup
+	values := make([]string, 10)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i], _ = readCounting(t, dataSource, "platform")
+		}(i)
+	}
+
+	// Every read is waiting for the first one before it answers.
+	time.Sleep(50 * time.Millisecond)
+	close(counting.release)
+	wg.Wait()
+
+	if reads := counting.reads.Load(); reads != 1 {
+		t.Errorf("concurrent reads of the same configuration should read once, got %d reads", reads)
+	}
+	for _, value := range values {
+		if value != "value of platform" {
+			t.Errorf("every read should get the cached state, got %q", value)
+		}
+	}
+}
+
+func TestDataSourceCacheKeys(t *testing.T) {
+	t.Parallel()
+
+	counting := &countingDataSource{failing: map[string]bool{"broken": true}}
+	dataSource := &cachedDataSource{DataSource: counting, cache: newDataSourceCache()}
+
+	for _, name := range []string{"platform", "platform", "developers", "platform"} {
+		if value, ok := readCounting(t, dataSource, name); !ok || value != "value of "+name {
+			t.Errorf("unexpected read of %s: %q %t", name, value, ok)
+		}
+	}
+	if reads := counting.reads.Load(); reads != 2 {
+		t.Errorf("each configuration should be read once, got %d reads", reads)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, ok := readCounting(t, dataSource, "broken"); ok {
+			t.Errorf("the read of broken should fail")
+		}
+	}
+	if reads := counting.reads.Load(); reads != 4 {
+		t.Errorf("a failed read should not be cached, got %d reads", reads)
+	}
+
+	uncached := &countingDataSource{}
+	for i := 0; i < 2; i++ {
+		readCounting(t, &cachedDataSource{DataSource: uncached}, "platform")
+	}
+	if reads := uncached.reads.Load(); reads != 2 {
+		t.Errorf("without cache every read should reach the data source, got %d reads", reads)
+	}
+}
+
+func TestDataSourceCacheProvider(t *testing.T) {
+	t.Parallel()
+
+	for _, disabled := range []bool{false, true} {
+		api, server := newFakeAPI(t)
+		api.put("/api/v1/organization/o1/team/t1", "team", map[string]any{"name": "platform"})
+		terrakube := newTestProvider(t, server.URL, map[string]tftypes.Value{
+			"disable_datasource_cache": tftypes.NewValue(tftypes.Bool, disabled),
+		})
+
+		for i := 0; i < 3; i++ {
+			_, diagnostics := terrakube.readDataSource("terrakube_team", map[string]tftypes.Value{
+				"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+				"name":            tftypes.NewValue(tftypes.String, "platform"),
+			})
+			if err := diagnosticsError(diagnostics); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+
+		expected := 1
+		if disabled {
+			expected = 3
+		}
+		if count := api.count("GET", teamCollectionPath); count != expected {
+			t.Errorf("disable_datasource_cache %t: expected %d requests, got %d", disabled, expected, count)
+		}
+	}
+}
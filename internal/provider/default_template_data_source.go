@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &DefaultTemplateDataSource{}
+	_ datasource.DataSourceWithConfigure = &DefaultTemplateDataSource{}
+)
+
+// defaultTemplateNames lists, for each kind, the names of the templates that
+// Terrakube creates with a new organization, in lookup order.
+var defaultTemplateNames = map[string][]string{
+	"plan_apply":     {"Plan and apply"},
+	"plan":           {"Plan"},
+	"destroy":        {"Destroy"},
+	"cli_plan_apply": {"Terraform-Plan/Apply-Cli"},
+	"cli_destroy":    {"Terraform-Plan/Destroy-Cli"},
+}
+
+type DefaultTemplateDataSourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	Kind           types.String `tfsdk:"kind"`
+	OrganizationId types.String `tfsdk:"organization_id"`
+}
+
+type DefaultTemplateDataSource struct {
+	client        *http.Client
+	endpoint      string
+	token         string
+	templateNames map[string]string
+}
+
+func NewDefaultTemplateDataSource() datasource.DataSource {
+	return &DefaultTemplateDataSource{}
+}
+
+func (d *DefaultTemplateDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Default Template Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+	d.templateNames = providerData.DefaultTemplateNames
+
+	tflog.Info(ctx, "Default Template Data Source configured")
+}
+
+func (d *DefaultTemplateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_default_template"
+}
+
+func (d *DefaultTemplateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	kinds := make([]string, 0, len(defaultTemplateNames))
+	for kind := range defaultTemplateNames {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	resp.Schema = schema.Schema{
+		Description: "Find one of the templates Terrakube creates with every organization. " +
+			"The names searched for each kind can be changed with the `default_template_names` provider attribute.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Template Id",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Template name",
+			},
+			"kind": schema.StringAttribute{
+				Required:    true,
+				Description: "Template kind (" + strings.Join(kinds, ", ") + ")",
+				Validators: []validator.String{
+					stringvalidator.OneOf(kinds...),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Organization ID",
+			},
+		},
+	}
+}
+
+func (d *DefaultTemplateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state DefaultTemplateDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	kind := state.Kind.ValueString()
+	names := defaultTemplateNames[kind]
+	if override, ok := d.templateNames[kind]; ok {
+		names = []string{override}
+	}
+
+	items, err := fetchAllPages(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/organization/%s/template", d.endpoint, state.OrganizationId.ValueString()), reflect.TypeOf(new(client.OrganizationTemplateEntity)))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading organization templates", apiErrorDetail(err, fmt.Sprintf("Error reading organization templates: %s", err)))
+		return
+	}
+
+	for _, name := range names {
+		for _, item := range items {
+			template := item.(*client.OrganizationTemplateEntity)
+			if template.Name == name {
+				state.ID = types.StringValue(template.ID)
+				state.Name = types.StringValue(template.Name)
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+				return
+			}
+		}
+	}
+
+	resp.Diagnostics.AddError(
+		"Default template not found",
+		fmt.Sprintf("No %s template found in organization %s, searched names: %q. Set default_template_names in the provider configuration if the templates were renamed.", kind, state.OrganizationId.ValueString(), names),
+	)
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Descriptions are often generated with templatestring and differ from the
+// value stored by Terrakube only in trailing newlines, CRLF line endings or
+// repeated spaces. descriptionType treats those values as equal so they do
+// not show as changes, any other difference is still a diff.
+var (
+	_ basetypes.StringTypable                    = descriptionType{}
+	_ basetypes.StringValuableWithSemanticEquals = descriptionValue{}
+)
+
+type descriptionType struct {
+	basetypes.StringType
+}
+
+func (t descriptionType) Equal(o attr.Type) bool {
+	other, ok := o.(descriptionType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t descriptionType) String() string {
+	return "descriptionType"
+}
+
+func (t descriptionType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return descriptionValue{StringValue: in}, nil
+}
+
+func (t descriptionType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return descriptionValue{StringValue: stringValue}, nil
+}
+
+func (t descriptionType) ValueType(ctx context.Context) attr.Value {
+	return descriptionValue{}
+}
+
+type descriptionValue struct {
+	basetypes.StringValue
+}
+
+func newDescriptionValue(value string) descriptionValue {
+	return descriptionValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func (v descriptionValue) Equal(o attr.Value) bool {
+	other, ok := o.(descriptionValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v descriptionValue) Type(ctx context.Context) attr.Type {
+	return descriptionType{}
+}
+
+func (v descriptionValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(descriptionValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	return normalizeDescription(v.ValueString()) == normalizeDescription(newValue.ValueString()), diags
+}
+
+// normalizeDescription collapses every run of whitespace, including line
+// breaks, into a single space and trims the result.
+func normalizeDescription(description string) string {
+	return strings.Join(strings.Fields(description), " ")
+}
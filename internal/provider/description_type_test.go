@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDescriptionSemanticEquals(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		prior    string
+		new      string
+		expected bool
+	}{
+		{"network", "network", true},
+		{"network\n", "network", true},
+		{"VPC of the\r\nplatform team\r\n", "VPC of the\nplatform team", true},
+		{"VPC  of the\tplatform", "VPC of the platform", true},
+		{"  network  ", "network", true},
+		{"network", "Network", false},
+		{"vpc network", "vpcnetwork", false},
+		{"network", "", false},
+	} {
+		equal, diags := newDescriptionValue(test.prior).StringSemanticEquals(context.Background(), newDescriptionValue(test.new))
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if equal != test.expected {
+			t.Errorf("%q and %q: expected semantic equality %t, got %t", test.prior, test.new, test.expected, equal)
+		}
+	}
+}
+
+// TestModuleDescriptionTemplate applies a description rendered by a template,
+// with a trailing newline, that Terrakube stores trimmed. The refreshed state
+// keeps the configured value so no change is planned, while a real change
+// made in Terrakube is still read.
+func TestModuleDescriptionTemplate(t *testing.T) {
+	t.Parallel()
+
+	api, terrakube := newModuleAPI(t)
+	rendered := "VPC of the\r\nplatform team\r\n"
+	config := moduleConfig(terrakube, "vpc", "aws", map[string]tftypes.Value{"description": tftypes.NewValue(tftypes.String, rendered)})
+	state, diagnostics := terrakube.apply("terrakube_module", terrakube.null("terrakube_module"), config)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	modulePath := "/api/v1/organization/o1/module/" + stringAttribute(t, state, "id")
+
+	api.set(modulePath, "description", "VPC of the\nplatform team")
+	state, diagnostics = terrakube.read("terrakube_module", state)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if description := stringAttribute(t, state, "description"); description != rendered {
+		t.Errorf("the configured description should be kept, got %q", description)
+	}
+	plan := terrakube.plan("terrakube_module", state, config)
+	if planned := terrakube.value("terrakube_module", plan.PlannedState); !planned.Equal(state) {
+		t.Errorf("a whitespace difference should plan no change:\n%s\n%s", planned, state)
+	}
+
+	api.set(modulePath, "description", "VPC of the network team")
+	state, diagnostics = terrakube.read("terrakube_module", state)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if description := stringAttribute(t, state, "description"); description != "VPC of the network team" {
+		t.Errorf("a changed description should be read, got %q", description)
+	}
+}
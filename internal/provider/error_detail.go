@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"terraform-provider-terrakube/internal/client"
+)
+
+// apiErrorMetadata is the first line of the detail of an error caused by an
+// API response. Automation reading the JSON output of Terraform parses this
+// line instead of the prose that follows it, so its keys must not change.
+type apiErrorMetadata struct {
+	Status    int    `json:"status"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id"`
+}
+
+// apiErrorDetail starts the detail with the metadata of the API response when
+// err carries one, any other error keeps the detail unchanged.
+func apiErrorDetail(err error, detail string) string {
+	var statusErr *client.StatusError
+	if !errors.As(err, &statusErr) {
+		return detail
+	}
+	return withErrorMetadata(apiErrorMetadata{Status: statusErr.StatusCode, Code: statusErr.Code(), RequestID: statusErr.RequestID}, detail)
+}
+
+// responseErrorDetail starts the detail with the status of a response the
+// resource read itself instead of through the client.
+func responseErrorDetail(response *http.Response, detail string) string {
+	if response == nil {
+		return detail
+	}
+	return withErrorMetadata(apiErrorMetadata{Status: response.StatusCode, RequestID: response.Header.Get(client.RequestIDHeader)}, detail)
+}
+
+func withErrorMetadata(metadata apiErrorMetadata, detail string) string {
+	line, err := json.Marshal(metadata)
+	if err != nil {
+		return detail
+	}
+	return string(line) + "\n" + detail
+}
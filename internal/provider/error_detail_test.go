@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestErrorDetailMetadata(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		status   int
+		body     string
+		metadata apiErrorMetadata
+	}{
+		{http.StatusUnprocessableEntity, `{"errors":[{"code":"invalid_value","detail":"name is invalid"}]}`, apiErrorMetadata{Status: 422, Code: "invalid_value", RequestID: "req-422"}},
+		{http.StatusServiceUnavailable, "upstream connect error", apiErrorMetadata{Status: 503, RequestID: "req-503"}},
+	} {
+		api, server := newFakeAPI(t)
+		api.handle = func(w http.ResponseWriter, r *http.Request) bool {
+			if r.Method != http.MethodPost {
+				return false
+			}
+			w.Header().Set(client.RequestIDHeader, test.metadata.RequestID)
+			w.WriteHeader(test.status)
+			fmt.Fprint(w, test.body)
+			return true
+		}
+		terrakube := newTestProvider(t, server.URL, nil)
+
+		for _, typeName := range []string{"terrakube_team", "terrakube_organization_variable"} {
+			config := teamConfig(terrakube, "platform", nil)
+			if typeName == "terrakube_organization_variable" {
+				config = terrakube.object(typeName, map[string]tftypes.Value{
+					"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+					"key":             tftypes.NewValue(tftypes.String, "region"),
+					"value":           tftypes.NewValue(tftypes.String, "eu-west-1"),
+					"description":     tftypes.NewValue(tftypes.String, "description"),
+					"category":        tftypes.NewValue(tftypes.String, "TERRAFORM"),
+					"sensitive":       tftypes.NewValue(tftypes.Bool, false),
+					"hcl":             tftypes.NewValue(tftypes.Bool, false),
+				})
+			}
+
+			_, diagnostics := terrakube.apply(typeName, terrakube.null(typeName), config)
+			var detail string
+			for _, diagnostic := range diagnostics {
+				if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+					detail = diagnostic.Detail
+					break
+				}
+			}
+			if detail == "" {
+				t.Errorf("%s %d: expected an error, got %v", typeName, test.status, diagnostics)
+				continue
+			}
+
+			// Automation parses the first line only.
+			line, _, _ := strings.Cut(detail, "\n")
+			var metadata apiErrorMetadata
+			if err := json.Unmarshal([]byte(line), &metadata); err != nil {
+				t.Errorf("%s %d: the first detail line is not JSON: %q", typeName, test.status, line)
+				continue
+			}
+			expected := test.metadata
+			if typeName == "terrakube_organization_variable" {
+				// The resources reading the response themselves do not
+				// decode the error document.
+				expected.Code = ""
+			}
+			if metadata != expected {
+				t.Errorf("%s %d: expected the metadata %+v, got %+v", typeName, test.status, expected, metadata)
+			}
+		}
+	}
+}
+
+func TestErrorDetailWithoutResponse(t *testing.T) {
+	t.Parallel()
+
+	if detail := apiErrorDetail(fmt.Errorf("connection refused"), "Error executing request"); detail != "Error executing request" {
+		t.Errorf("an error without response should keep the detail, got %q", detail)
+	}
+	if detail := responseErrorDetail(nil, "Error executing request"); detail != "Error executing request" {
+		t.Errorf("a missing response should keep the detail, got %q", detail)
+	}
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// examplesRoot is the examples directory of the repository, relative to
+// this package.
+const examplesRoot = "../../examples"
+
+// ignoredExampleDiagnostics are reported because the examples refer to
+// resources and variables declared in other examples, they say nothing
+// about the schema of the provider.
+var ignoredExampleDiagnostics = []string{
+	"Reference to undeclared resource",
+	"Reference to undeclared input variable",
+	"Reference to undeclared local value",
+	"Reference to undeclared module",
+}
+
+// TestAccExamples validates every configuration of the examples directory
+// against the schema of the provider built from this tree, so an example
+// using an attribute that does not exist fails the build. It needs a
+// terraform binary, from TF_ACC_TERRAFORM_PATH or the PATH.
+func TestAccExamples(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("acceptance tests are skipped unless TF_ACC is set")
+	}
+
+	terraform := os.Getenv("TF_ACC_TERRAFORM_PATH")
+	if terraform == "" {
+		var err error
+		if terraform, err = exec.LookPath("terraform"); err != nil {
+			t.Skip("terraform is not installed, set TF_ACC_TERRAFORM_PATH")
+		}
+	}
+
+	// The provider is installed through a development override, no
+	// terraform init is needed.
+	pluginDir := t.TempDir()
+	build := exec.Command("go", "build", "-o", filepath.Join(pluginDir, "terraform-provider-terrakube"), ".")
+	build.Dir = "../.."
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("unable to build the provider: %s\n%s", err, output)
+	}
+
+	cliConfig := filepath.Join(t.TempDir(), "terraformrc")
+	overrides := fmt.Sprintf("provider_installation {\n  dev_overrides {\n    \"AzBuilder/terrakube\" = %q\n  }\n  direct {}\n}\n", pluginDir)
+	if err := os.WriteFile(cliConfig, []byte(overrides), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range exampleDirs(t) {
+		dir := dir
+		name, _ := filepath.Rel(examplesRoot, dir)
+		t.Run(name, func(t *testing.T) {
+			workDir := t.TempDir()
+			copyExample(t, dir, workDir)
+
+			validate := exec.Command(terraform, "validate", "-json", "-no-color")
+			validate.Dir = workDir
+			validate.Env = append(os.Environ(), "TF_CLI_CONFIG_FILE="+cliConfig)
+			output, _ := validate.Output()
+
+			var result struct {
+				Diagnostics []struct {
+					Severity string `json:"severity"`
+					Summary  string `json:"summary"`
+					Detail   string `json:"detail"`
+				} `json:"diagnostics"`
+			}
+			if err := json.Unmarshal(output, &result); err != nil {
+				t.Fatalf("unexpected output of terraform validate: %s\n%s", err, output)
+			}
+
+			for _, diagnostic := range result.Diagnostics {
+				if diagnostic.Severity == "error" && !containsString(ignoredExampleDiagnostics, diagnostic.Summary) {
+					t.Errorf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+				}
+			}
+		})
+	}
+}
+
+// exampleDirs returns the resource and data source example directories.
+func exampleDirs(t *testing.T) []string {
+	t.Helper()
+
+	var dirs []string
+	for _, kind := range []string{"resources", "data-sources"} {
+		entries, err := os.ReadDir(filepath.Join(examplesRoot, kind))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				dirs = append(dirs, filepath.Join(examplesRoot, kind, entry.Name()))
+			}
+		}
+	}
+	return dirs
+}
+
+// copyExample copies the configuration files of the example and declares
+// the provider, which the examples leave to examples/provider.
+func copyExample(t *testing.T, from string, to string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".tf") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(from, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(to, entry.Name()), content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	providers := "terraform {\n  required_providers {\n    terrakube = {\n      source = \"AzBuilder/terrakube\"\n    }\n  }\n}\n"
+	if err := os.WriteFile(filepath.Join(to, "providers_test.tf"), []byte(providers), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
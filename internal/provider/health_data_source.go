@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &HealthDataSource{}
+	_ datasource.DataSourceWithConfigure = &HealthDataSource{}
+)
+
+type HealthDataSourceModel struct {
+	OrganizationId types.String `tfsdk:"organization_id"`
+	Status         types.String `tfsdk:"status"`
+	PendingJobs    types.Int64  `tfsdk:"pending_jobs"`
+	QueuedJobs     types.Int64  `tfsdk:"queued_jobs"`
+	RunningJobs    types.Int64  `tfsdk:"running_jobs"`
+}
+
+type HealthDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewHealthDataSource() datasource.DataSource {
+	return &HealthDataSource{}
+}
+
+func (d *HealthDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Health Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Health datasource")
+}
+
+func (d *HealthDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_health"
+}
+
+func (d *HealthDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Read the health of the Terrakube API and count the jobs of an organization that have not finished yet. " +
+			"Terrakube does not publish the size of the executor queue or the number of executors, the job counts are read from the job list of the organization.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Terrakube organization id whose jobs are counted, the job counts are null when it is not set",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Status of the API health endpoint, for example `UP` or `DOWN`, null when the server does not expose /actuator/health",
+			},
+			"pending_jobs": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of jobs with status `pending`, waiting to be scheduled",
+			},
+			"queued_jobs": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of jobs with status `queue`, sent to an executor and not started yet",
+			},
+			"running_jobs": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of jobs with status `running`",
+			},
+		},
+	}
+}
+
+func (d *HealthDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state HealthDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status, err := d.healthStatus(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading health", fmt.Sprintf("Error reading the health of %s: %s", d.endpoint, err))
+		return
+	}
+	state.Status = status
+
+	state.PendingJobs = types.Int64Null()
+	state.QueuedJobs = types.Int64Null()
+	state.RunningJobs = types.Int64Null()
+	if !state.OrganizationId.IsNull() {
+		query := url.Values{}
+		query.Set("filter[job]", "status=in=('pending','queue','running')")
+		jobs, err := fetchAllPages(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/organization/%s/job?%s", d.endpoint, state.OrganizationId.ValueString(), query.Encode()), reflect.TypeOf(new(client.JobEntity)))
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading health", apiErrorDetail(err, fmt.Sprintf("Error listing the jobs of organization %s: %s", state.OrganizationId.ValueString(), err)))
+			return
+		}
+
+		counts := map[string]int64{}
+		for _, item := range jobs {
+			counts[item.(*client.JobEntity).Status]++
+		}
+		state.PendingJobs = types.Int64Value(counts["pending"])
+		state.QueuedJobs = types.Int64Value(counts["queue"])
+		state.RunningJobs = types.Int64Value(counts["running"])
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// healthStatus reads the status of the Spring Boot health endpoint. A
+// server that does not expose it, or answers with something else than a
+// health document, gives a null status. Only an unreachable server is an
+// error.
+func (d *HealthDataSource) healthStatus(ctx context.Context) (types.String, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, d.endpoint+"/actuator/health", nil)
+	if err != nil {
+		return types.StringNull(), fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+	request.Header.Add("Accept", "application/json")
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return types.StringNull(), fmt.Errorf("error executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	// A server that is down answers 503 with the same document.
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusServiceUnavailable {
+		tflog.Debug(ctx, "Health endpoint not available", map[string]any{"status": response.StatusCode})
+		return types.StringNull(), nil
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return types.StringNull(), fmt.Errorf("error reading response body: %w", err)
+	}
+
+	var health struct {
+		Status *string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &health); err != nil || health.Status == nil {
+		tflog.Debug(ctx, "Health endpoint returned an unexpected document", map[string]any{"body": string(body)})
+		return types.StringNull(), nil
+	}
+
+	return types.StringValue(*health.Status), nil
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestHealthStatus(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		status int
+		body   string
+		health tftypes.Value
+	}{
+		{"up", http.StatusOK, `{"status":"UP","components":{"db":{"status":"UP"}}}`, tftypes.NewValue(tftypes.String, "UP")},
+		{"down", http.StatusServiceUnavailable, `{"status":"DOWN"}`, tftypes.NewValue(tftypes.String, "DOWN")},
+		{"not exposed", http.StatusNotFound, `{"error":"Not Found"}`, tftypes.NewValue(tftypes.String, nil)},
+		{"unauthorized", http.StatusUnauthorized, "", tftypes.NewValue(tftypes.String, nil)},
+		{"not a health document", http.StatusOK, "OK", tftypes.NewValue(tftypes.String, nil)},
+		{"no status", http.StatusOK, `{"components":{}}`, tftypes.NewValue(tftypes.String, nil)},
+	} {
+		api, server := newFakeAPI(t)
+		api.handle = func(w http.ResponseWriter, r *http.Request) bool {
+			if r.URL.Path != "/actuator/health" {
+				return false
+			}
+			w.WriteHeader(test.status)
+			fmt.Fprint(w, test.body)
+			return true
+		}
+		terrakube := newTestProvider(t, server.URL, nil)
+
+		state, diagnostics := terrakube.readDataSource("terrakube_health", nil)
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		if status := attribute(t, state, "status"); !status.Equal(test.health) {
+			t.Errorf("%s: expected the status %s, got %s", test.name, test.health, status)
+		}
+		for _, name := range []string{"pending_jobs", "queued_jobs", "running_jobs"} {
+			if count := attribute(t, state, name); !count.IsNull() {
+				t.Errorf("%s: without organization_id %s should be null, got %s", test.name, name, count)
+			}
+		}
+		if count := api.count("GET", "/api/v1/organization"); count != 0 {
+			t.Errorf("%s: without organization_id the jobs should not be listed, got %d requests", test.name, count)
+		}
+	}
+}
+
+func TestHealthJobCounts(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	// More jobs than one page, the fake ignores the status filter so the
+	// finished jobs are returned too.
+	statuses := map[string]int{"pending": 3, "queue": 101, "running": 2, "completed": 5}
+	id := 0
+	for status, count := range statuses {
+		for i := 0; i < count; i++ {
+			id++
+			api.put(fmt.Sprintf("/api/v1/organization/o1/job/%04d", id), "job", map[string]any{"status": status})
+		}
+	}
+	var mu sync.Mutex
+	var filters []string
+	api.handle = func(w http.ResponseWriter, r *http.Request) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/api/v1/organization/o1/job" {
+			filters = append(filters, r.URL.Query().Get("filter[job]"))
+		}
+		return false
+	}
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	state, diagnostics := terrakube.readDataSource("terrakube_health", map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+	})
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for name, expected := range map[string]int64{"pending_jobs": 3, "queued_jobs": 101, "running_jobs": 2} {
+		if count := attribute(t, state, name); !count.Equal(tftypes.NewValue(tftypes.Number, expected)) {
+			t.Errorf("expected %s %d, got %s", name, expected, count)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(filters) != 2 {
+		t.Errorf("the jobs should be read in 2 pages, got %d requests", len(filters))
+	}
+	for _, filter := range filters {
+		if filter != "status=in=('pending','queue','running')" {
+			t.Errorf("unexpected job filter %q", filter)
+		}
+	}
+}
+
+func TestHealthJobListError(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.handle = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path != "/api/v1/organization/o1/job" {
+			return false
+		}
+		w.WriteHeader(http.StatusForbidden)
+		return true
+	}
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	_, diagnostics := terrakube.readDataSource("terrakube_health", map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+	})
+	if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, "Error reading health") {
+		t.Errorf("a failed job list should fail the read, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func ignoreServerChangesSchema(attributes []string) schema.SetAttribute {
+	sorted := append([]string(nil), attributes...)
+	sort.Strings(sorted)
+
+	return schema.SetAttribute{
+		Optional:    true,
+		ElementType: types.StringType,
+		Description: "Attributes whose value is kept from the prior state when it is changed outside of Terraform, for installations where a controller adjusts them. " +
+			"Changes made in the configuration are still applied, but drift on these attributes is never reported. Allowed values: " + strings.Join(sorted, ", ") + ".",
+		Validators: []validator.Set{
+			setvalidator.ValueStringsAre(stringvalidator.OneOf(attributes...)),
+		},
+	}
+}
+
+// keepIgnoredServerChanges copies the attributes listed in ignored from the
+// prior model to the refreshed one. Both must be pointers to the same model
+// struct, attributes are matched with their tfsdk tag.
+func keepIgnoredServerChanges(ctx context.Context, ignored types.Set, prior any, refreshed any) {
+	if ignored.IsNull() || ignored.IsUnknown() {
+		return
+	}
+
+	var names []string
+	ignored.ElementsAs(ctx, &names, false)
+
+	priorValue := reflect.ValueOf(prior).Elem()
+	refreshedValue := reflect.ValueOf(refreshed).Elem()
+	for _, name := range names {
+		for i := 0; i < priorValue.NumField(); i++ {
+			if priorValue.Type().Field(i).Tag.Get("tfsdk") == name {
+				refreshedValue.Field(i).Set(priorValue.Field(i))
+			}
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &JobApprovalResource{}
+
+// jobWaitingApprovalStatus is the status of a job stopped on an approval
+// step, the decision replaces it with the status of jobApprovalStatuses.
+const jobWaitingApprovalStatus = "waitingApproval"
+
+var jobApprovalStatuses = map[string]string{
+	"approve": "approved",
+	"reject":  "rejected",
+}
+
+type JobApprovalResource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+	jobs     *client.Crud[client.JobEntity]
+}
+
+type JobApprovalResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	OrganizationId types.String `tfsdk:"organization_id"`
+	JobId          types.String `tfsdk:"job_id"`
+	Decision       types.String `tfsdk:"decision"`
+	Timeout        types.String `tfsdk:"timeout"`
+	ApprovalTeam   types.String `tfsdk:"approval_team"`
+	Status         types.String `tfsdk:"status"`
+}
+
+func NewJobApprovalResource() resource.Resource {
+	return &JobApprovalResource{}
+}
+
+func (r *JobApprovalResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_job_approval"
+}
+
+func (r *JobApprovalResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Approve or reject a job stopped on an approval step of its template, like the approve and reject buttons of the UI. " +
+			"The token must belong to the approval team of the step. A decision can not be taken back, destroying the resource only removes it from the state.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Id of the approval, the job id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"job_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Id of the job waiting for approval",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"decision": schema.StringAttribute{
+				Required:    true,
+				Description: "Decision sent for the job, `approve` or `reject`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("approve", "reject"),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long to wait for the job to reach its approval step, for example `15m`. When not set the apply fails right away if the job is not waiting for approval.",
+			},
+			"approval_team": schema.StringAttribute{
+				Computed:    true,
+				Description: "Team allowed to approve the job",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Status of the job when it was last read, `approved` or `rejected` right after the decision and then the status of the rest of the run.",
+			},
+		},
+	}
+}
+
+func (r *JobApprovalResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Job Approval Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+	r.jobs = client.NewCrud[client.JobEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/job")
+
+	tflog.Debug(ctx, "Configuring Job Approval resource", map[string]any{"success": true})
+}
+
+func (r *JobApprovalResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan JobApprovalResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organizationId := plan.OrganizationId.ValueString()
+	jobId := plan.JobId.ValueString()
+	decided := jobApprovalStatuses[plan.Decision.ValueString()]
+
+	job, err := r.jobs.Get(ctx, jobId, organizationId)
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing job approval resource request", apiErrorDetail(err, fmt.Sprintf("Error executing job approval resource request: %s", err)))
+		return
+	}
+
+	if job.Status != jobWaitingApprovalStatus && job.Status != decided && !plan.Timeout.IsNull() {
+		timeout := parseTimeout(plan.Timeout, "timeout", defaultJobTimeout, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		err = waitFor(ctx, timeout, jobFrequency, func() (bool, error) {
+			job, err = r.jobs.Get(ctx, jobId, organizationId)
+			if err != nil {
+				return false, err
+			}
+			tflog.Debug(ctx, "Waiting for job approval step", map[string]any{"job": job.ID, "status": job.Status})
+			return job.Status == jobWaitingApprovalStatus || containsString(jobFinishedStatuses, job.Status), nil
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Error waiting for job approval step", apiErrorDetail(err, fmt.Sprintf("Job %s did not reach an approval step: %s. Last status: %s.", jobId, err, job.Status)))
+			return
+		}
+	}
+
+	// A job already carrying the decision, for example after a failed apply
+	// that sent it, is recorded without sending it again.
+	if job.Status != decided {
+		if job.Status != jobWaitingApprovalStatus {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("job_id"),
+				"Job not waiting for approval",
+				fmt.Sprintf("Job %s has status %s, only a job with status %s can be approved or rejected. Set timeout to wait for the job to reach its approval step.", jobId, job.Status, jobWaitingApprovalStatus),
+			)
+			return
+		}
+
+		err = r.jobs.UpdateWithout(ctx, jobId, &client.JobEntity{ID: jobId, Status: decided}, []string{"templateReference"}, organizationId)
+		var statusErr *client.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusForbidden {
+			resp.Diagnostics.AddError(
+				"Job approval denied",
+				apiErrorDetail(err, fmt.Sprintf("The token is not allowed to %s job %s, it must belong to the approval team %q: %s", plan.Decision.ValueString(), jobId, job.ApprovalTeam, err)),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Error executing job approval resource request", apiErrorDetail(err, fmt.Sprintf("Error executing job approval resource request: %s", err)))
+			return
+		}
+	}
+
+	plan.ID = types.StringValue(jobId)
+	plan.ApprovalTeam = types.StringValue(job.ApprovalTeam)
+	plan.Status = types.StringValue(decided)
+
+	tflog.Info(ctx, "Job Approval Resource Created", map[string]any{"success": true, "job": jobId, "status": decided})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *JobApprovalResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state JobApprovalResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	job, err := r.jobs.Get(ctx, state.JobId.ValueString(), state.OrganizationId.ValueString())
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing job approval resource request", apiErrorDetail(err, fmt.Sprintf("Error executing job approval resource request: %s", err)))
+		return
+	}
+
+	state.ApprovalTeam = types.StringValue(job.ApprovalTeam)
+	state.Status = types.StringValue(job.Status)
+
+	// Set refreshed state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Job Approval Resource reading", map[string]any{"success": true})
+}
+
+// Update only stores the timeout, every other attribute replaces the
+// resource.
+func (r *JobApprovalResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan JobApprovalResourceModel
+	var state JobApprovalResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Status = state.Status
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the resource from the state, the decision is part of
+// the job history.
+func (r *JobApprovalResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data JobApprovalResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+}
@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// jobMetadataComments formats the job_metadata of the provider as the
+// comments of a job, one key=value line per entry. The keys are sorted so
+// the same metadata always gives the same comments.
+func jobMetadataComments(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, metadata[key]))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,520 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &JobResource{}
+var _ resource.ResourceWithImportState = &JobResource{}
+var _ resource.ResourceWithValidateConfig = &JobResource{}
+
+const (
+	defaultJobTimeout = 30 * time.Minute
+	jobFrequency      = 10 * time.Second
+)
+
+// Statuses of a job that will not change anymore, the failed ones fail the
+// apply when waiting for the job.
+var (
+	jobFinishedStatuses = []string{"completed", "noChanges", "notExecuted", "failed", "rejected", "cancelled"}
+	jobFailedStatuses   = []string{"failed", "rejected", "cancelled"}
+)
+
+type JobResource struct {
+	client    *http.Client
+	endpoint  string
+	token     string
+	jobs      *client.Crud[client.JobEntity]
+	jobSteps  *client.Crud[client.JobStepEntity]
+	templates *client.Crud[client.OrganizationTemplateEntity]
+	metadata  map[string]string
+}
+
+type JobResourceModel struct {
+	ID                  types.String         `tfsdk:"id"`
+	OrganizationId      types.String         `tfsdk:"organization_id"`
+	WorkspaceId         types.String         `tfsdk:"workspace_id"`
+	TemplateId          types.String         `tfsdk:"template_id"`
+	TemplateName        types.String         `tfsdk:"template_name"`
+	InlineTemplateTcl   templateContentValue `tfsdk:"inline_template_tcl"`
+	SkipValidation      types.Bool           `tfsdk:"skip_validation"`
+	Parameters          types.Map            `tfsdk:"parameters"`
+	SensitiveParameters types.Map            `tfsdk:"sensitive_parameters"`
+	WaitForCompletion   types.Bool           `tfsdk:"wait_for_completion"`
+	Timeout             types.String         `tfsdk:"timeout"`
+	Status              types.String         `tfsdk:"status"`
+	OutputSummary       types.String         `tfsdk:"output_summary"`
+}
+
+func NewJobResource() resource.Resource {
+	return &JobResource{}
+}
+
+func (r *JobResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_job"
+}
+
+func (r *JobResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Queue a job running a template in a workspace. " +
+			"Jobs are the run history of the workspace, changing the workspace or the template queues a new job and destroying the resource only removes it from the state.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Job Id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube workspace id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"template_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Id of the template run by the job. Conflicts with `template_name` and `inline_template_tcl`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("template_id"), path.MatchRoot("template_name"), path.MatchRoot("inline_template_tcl")),
+				},
+			},
+			"template_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the template run by the job, resolved to its id in the organization. Conflicts with `template_id` and `inline_template_tcl`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"inline_template_tcl": schema.StringAttribute{
+				Optional:   true,
+				CustomType: templateContentType{},
+				Description: "Flow run by this job only, as plain YAML in the format of the `content` of `terrakube_organization_template`, for example to run only a plan step. " +
+					"Conflicts with `template_id` and `template_name`. Changing it queues a new job.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"skip_validation": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Do not check `inline_template_tcl` against the steps, keys and runtimes known by the provider, default is `false`. Needed for flows using features of a newer Terrakube version.",
+			},
+			"parameters": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Parameters passed to the template when the job is queued. Changing them queues a new job.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"sensitive_parameters": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "Parameters whose values are read from environment variables when the job is queued, as a map of parameter name to environment variable name. " +
+					"The values are sent with the parameters but never written to the plan, the state or the logs. " +
+					"Changing the map queues a new job, a new value in the same environment variable does not.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"wait_for_completion": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Wait until the job finishes, a failed, rejected or cancelled job fails the apply. Default `false`.",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("30m"),
+				Description: "How long to wait for the job when `wait_for_completion` is set, for example `45m`. Default `30m`.",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Status of the job, for example `pending`, `running`, `completed` or `failed`.",
+			},
+			"output_summary": schema.StringAttribute{
+				Computed:    true,
+				Description: "Status of each step of the job, one step per line.",
+			},
+		},
+	}
+}
+
+// ValidateConfig checks the inline flow like the content of a template and
+// rejects a parameter set both in parameters and in sensitive_parameters.
+func (r *JobResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config JobResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.InlineTemplateTcl.IsNull() && !config.InlineTemplateTcl.IsUnknown() && !config.SkipValidation.ValueBool() && !config.SkipValidation.IsUnknown() {
+		for _, problem := range validateTemplateContent(config.InlineTemplateTcl.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("inline_template_tcl"),
+				"Invalid template flow",
+				fmt.Sprintf("%s. Set skip_validation = true if the flow uses features this provider does not know yet.", problem),
+			)
+		}
+	}
+
+	if config.Parameters.IsNull() || config.Parameters.IsUnknown() || config.SensitiveParameters.IsNull() || config.SensitiveParameters.IsUnknown() {
+		return
+	}
+
+	for name := range config.SensitiveParameters.Elements() {
+		if _, ok := config.Parameters.Elements()[name]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("sensitive_parameters").AtMapKey(name),
+				"Duplicate job parameter",
+				fmt.Sprintf("Parameter %q is set in both parameters and sensitive_parameters.", name),
+			)
+		}
+	}
+}
+
+func (r *JobResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Job Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+	r.metadata = providerData.JobMetadata
+	r.jobs = client.NewCrud[client.JobEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/job")
+	r.jobSteps = client.NewCrud[client.JobStepEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/job/%s/step")
+	r.templates = client.NewCrud[client.OrganizationTemplateEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/template")
+
+	tflog.Debug(ctx, "Configuring Job resource", map[string]any{"success": true})
+}
+
+func (r *JobResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan JobResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout := parseTimeout(plan.Timeout, "timeout", defaultJobTimeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templateId := plan.TemplateId.ValueString()
+	if !plan.TemplateName.IsNull() {
+		var err error
+		templateId, err = r.templateIdByName(ctx, plan.OrganizationId.ValueString(), plan.TemplateName.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("template_name"), "Error reading template", apiErrorDetail(err, err.Error()))
+			return
+		}
+	}
+
+	parameters, secrets, diags := jobParameters(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// The payload and the response are logged by the client.
+	ctx = tflog.MaskLogStrings(ctx, secrets...)
+
+	bodyRequest := &client.JobEntity{
+		TemplateReference: templateId,
+		Comments:          jobMetadataComments(r.metadata),
+		Parameters:        parameters,
+		Workspace:         &client.WorkspaceEntity{ID: plan.WorkspaceId.ValueString()},
+	}
+	if !plan.InlineTemplateTcl.IsNull() {
+		bodyRequest.Tcl = base64.StdEncoding.EncodeToString([]byte(plan.InlineTemplateTcl.ValueString()))
+	}
+
+	job, err := r.jobs.Create(ctx, bodyRequest, plan.OrganizationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing job resource request", apiErrorDetail(err, fmt.Sprintf("Error executing job resource request: %s", err)))
+		return
+	}
+
+	plan.ID = types.StringValue(job.ID)
+	plan.TemplateId = types.StringValue(templateId)
+	plan.Status = types.StringValue(job.Status)
+	plan.OutputSummary = types.StringValue("")
+
+	tflog.Info(ctx, "Job Resource Created", map[string]any{"success": true, "job": job.ID})
+
+	if !plan.WaitForCompletion.ValueBool() {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	err = waitFor(ctx, timeout, jobFrequency, func() (bool, error) {
+		job, err = r.jobs.Get(ctx, plan.ID.ValueString(), plan.OrganizationId.ValueString())
+		if err != nil {
+			return false, err
+		}
+		tflog.Debug(ctx, "Waiting for job", map[string]any{"job": job.ID, "status": job.Status})
+		return containsString(jobFinishedStatuses, job.Status), nil
+	})
+	if job != nil {
+		plan.Status = types.StringValue(job.Status)
+	}
+	steps, stepsErr := r.readSteps(ctx, &plan)
+	if stepsErr != nil {
+		tflog.Warn(ctx, "Error reading job steps", map[string]any{"job": plan.ID.ValueString(), "error": stepsErr.Error()})
+	}
+
+	// The job is part of the workspace history even when it failed, the
+	// state is saved so the resource is tainted and a new apply queues it
+	// again.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error waiting for job",
+			fmt.Sprintf("Job %s in workspace %s did not finish: %s. Last status: %s.\n%s",
+				plan.ID.ValueString(), plan.WorkspaceId.ValueString(), err, plan.Status.ValueString(), plan.OutputSummary.ValueString()),
+		)
+		return
+	}
+
+	if containsString(jobFailedStatuses, job.Status) {
+		resp.Diagnostics.AddError(
+			"Job failed",
+			fmt.Sprintf("Job %s in workspace %s finished with status %s.\n%s%s",
+				plan.ID.ValueString(), plan.WorkspaceId.ValueString(), job.Status, plan.OutputSummary.ValueString(), failedStepLogs(steps)),
+		)
+	}
+}
+
+func (r *JobResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state JobResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	job, err := r.jobs.Get(ctx, state.ID.ValueString(), state.OrganizationId.ValueString())
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing job resource request", apiErrorDetail(err, fmt.Sprintf("Error executing job resource request: %s", err)))
+		return
+	}
+
+	state.ID = types.StringValue(job.ID)
+	state.Status = types.StringValue(job.Status)
+	state.TemplateId = types.StringValue(job.TemplateReference)
+	if job.Workspace != nil && job.Workspace.ID != "" {
+		state.WorkspaceId = types.StringValue(job.Workspace.ID)
+	}
+	if state.WaitForCompletion.IsNull() {
+		state.WaitForCompletion = types.BoolValue(false)
+	}
+	if state.Timeout.IsNull() {
+		state.Timeout = types.StringValue("30m")
+	}
+
+	if _, err := r.readSteps(ctx, &state); err != nil {
+		resp.Diagnostics.AddError("Error reading job steps", apiErrorDetail(err, fmt.Sprintf("Error reading the steps of job %s: %s", state.ID.ValueString(), err)))
+		return
+	}
+
+	// Set refreshed state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Job Resource reading", map[string]any{"success": true})
+}
+
+func (r *JobResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only wait_for_completion and timeout can change without queueing a new
+	// job, they apply to the next job.
+	var plan JobResourceModel
+	var state JobResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = state.ID
+	plan.TemplateId = state.TemplateId
+	plan.Status = state.Status
+	plan.OutputSummary = state.OutputSummary
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *JobResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data JobResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Jobs are the run history of the workspace and are never deleted.
+	tflog.Info(ctx, "Job removed from the state, the job is kept in the workspace history", map[string]any{"job": data.ID.ValueString()})
+}
+
+func (r *JobResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,ID', Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+}
+
+// readSteps returns the steps of the job sorted by step number and sets the
+// output summary from them.
+func (r *JobResource) readSteps(ctx context.Context, model *JobResourceModel) ([]*client.JobStepEntity, error) {
+	found, err := r.jobSteps.List(ctx, model.OrganizationId.ValueString(), model.ID.ValueString())
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].StepNumber < found[j].StepNumber })
+
+	lines := make([]string, 0, len(found))
+	for _, step := range found {
+		lines = append(lines, fmt.Sprintf("step %d %s: %s", step.StepNumber, step.Name, step.Status))
+	}
+	model.OutputSummary = types.StringValue(strings.Join(lines, "\n"))
+	return found, nil
+}
+
+func (r *JobResource) templateIdByName(ctx context.Context, organizationId string, name string) (string, error) {
+	query := url.Values{}
+	query.Set("filter[template]", "name=="+rsqlString(name))
+	templates, err := fetchAllPages(ctx, r.client, r.token, r.templates.CollectionURL(organizationId)+"?"+query.Encode(), reflect.TypeOf(new(client.OrganizationTemplateEntity)))
+	if err != nil {
+		return "", err
+	}
+
+	for _, item := range templates {
+		if template, ok := item.(*client.OrganizationTemplateEntity); ok && template.Name == name {
+			return template.ID, nil
+		}
+	}
+	return "", fmt.Errorf("template %q not found in organization %s", name, organizationId)
+}
+
+// jobParameters returns the parameters of the job payload, with the values
+// of the sensitive parameters read from their environment variables, and
+// those values so they can be masked in the logs.
+func jobParameters(ctx context.Context, model *JobResourceModel) (map[string]interface{}, []string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	plain := map[string]string{}
+	environment := map[string]string{}
+	diags.Append(model.Parameters.ElementsAs(ctx, &plain, false)...)
+	diags.Append(model.SensitiveParameters.ElementsAs(ctx, &environment, false)...)
+	if diags.HasError() || len(plain)+len(environment) == 0 {
+		return nil, nil, diags
+	}
+
+	parameters := make(map[string]interface{}, len(plain)+len(environment))
+	for name, value := range plain {
+		parameters[name] = value
+	}
+
+	var secrets []string
+	for name, variable := range environment {
+		value, ok := os.LookupEnv(variable)
+		if !ok {
+			diags.AddAttributeError(
+				path.Root("sensitive_parameters").AtMapKey(name),
+				"Missing sensitive parameter",
+				fmt.Sprintf("Environment variable %s holding the value of parameter %q is not set.", variable, name),
+			)
+			continue
+		}
+		parameters[name] = value
+		if value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+
+	return parameters, secrets, diags
+}
+
+// failedStepLogs lists where to find the logs of the steps that failed.
+func failedStepLogs(steps []*client.JobStepEntity) string {
+	var logs []string
+	for _, step := range steps {
+		if containsString(jobFailedStatuses, step.Status) && step.Output != "" {
+			logs = append(logs, fmt.Sprintf("Logs of step %d %s: %s", step.StepNumber, step.Name, step.Output))
+		}
+	}
+	if len(logs) == 0 {
+		return ""
+	}
+	return "\n" + strings.Join(logs, "\n")
+}
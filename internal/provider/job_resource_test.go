@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"terraform-provider-terrakube/internal/client"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestJobParameters(t *testing.T) {
+	t.Setenv("TEST_DEPLOY_KEY", "secret-value")
+
+	model := &JobResourceModel{
+		Parameters:          types.MapValueMust(types.StringType, map[string]attr.Value{"TARGET": types.StringValue("module.network")}),
+		SensitiveParameters: types.MapValueMust(types.StringType, map[string]attr.Value{"DEPLOY_KEY": types.StringValue("TEST_DEPLOY_KEY")}),
+	}
+
+	parameters, secrets, diags := jobParameters(context.Background(), model)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if parameters["TARGET"] != "module.network" || parameters["DEPLOY_KEY"] != "secret-value" {
+		t.Errorf("unexpected parameters: %v", parameters)
+	}
+	if len(secrets) != 1 || secrets[0] != "secret-value" {
+		t.Errorf("expected the sensitive value to be masked, got %v", secrets)
+	}
+}
+
+func TestJobParametersMissingEnvironment(t *testing.T) {
+	model := &JobResourceModel{
+		Parameters:          types.MapNull(types.StringType),
+		SensitiveParameters: types.MapValueMust(types.StringType, map[string]attr.Value{"DEPLOY_KEY": types.StringValue("TEST_JOB_PARAMETER_NOT_SET")}),
+	}
+
+	_, _, diags := jobParameters(context.Background(), model)
+	if !diags.HasError() {
+		t.Fatal("expected an error for the missing environment variable")
+	}
+}
+
+func TestJobParametersNone(t *testing.T) {
+	model := &JobResourceModel{
+		Parameters:          types.MapNull(types.StringType),
+		SensitiveParameters: types.MapNull(types.StringType),
+	}
+
+	parameters, _, diags := jobParameters(context.Background(), model)
+	if diags.HasError() || parameters != nil {
+		t.Errorf("expected no parameters, got %v %v", parameters, diags)
+	}
+}
+
+// jobConfig returns a configuration of the job resource with the given
+// attributes, every other attribute is null.
+func jobConfig(t *testing.T, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+
+	schemaResponse := &resource.SchemaResponse{}
+	NewJobResource().Schema(ctx, resource.SchemaRequest{}, schemaResponse)
+	objectType := schemaResponse.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	return tfsdk.Config{Schema: schemaResponse.Schema, Raw: objectValue(objectType, values)}
+}
+
+func TestJobValidateInlineTemplate(t *testing.T) {
+	valid := "flow:\n  - type: \"terraformPlan\"\n    name: \"Plan\"\n    step: 100\n"
+	invalid := "flow:\n  - type: \"terraformPlan\"\n    name: \"Plan\"\n"
+
+	for name, test := range map[string]struct {
+		content string
+		skip    bool
+		errors  bool
+	}{
+		"valid":            {content: valid},
+		"invalid":          {content: invalid, errors: true},
+		"invalid, skipped": {content: invalid, skip: true},
+	} {
+		config := jobConfig(t, map[string]tftypes.Value{
+			"inline_template_tcl": tftypes.NewValue(tftypes.String, test.content),
+			"skip_validation":     tftypes.NewValue(tftypes.Bool, test.skip),
+		})
+
+		response := &resource.ValidateConfigResponse{}
+		(&JobResource{}).ValidateConfig(context.Background(), resource.ValidateConfigRequest{Config: config}, response)
+		if response.Diagnostics.HasError() != test.errors {
+			t.Errorf("%s: expected errors %t, got %v", name, test.errors, response.Diagnostics)
+		}
+	}
+}
+
+func TestJobTemplateIdByName(t *testing.T) {
+	t.Parallel()
+
+	const name = "plan & apply's"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if filter := r.URL.Query().Get("filter[template]"); filter != "name=="+rsqlString(name) {
+			t.Errorf("unexpected filter %s", filter)
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		fmt.Fprintf(w, `{"data":[{"type":"template","id":"t1","attributes":{"name":%q}}]}`, name)
+	}))
+	defer server.Close()
+
+	r := &JobResource{
+		client:    http.DefaultClient,
+		token:     "token",
+		templates: client.NewCrud[client.OrganizationTemplateEntity](http.DefaultClient, server.URL, "token", "/api/v1/organization/%s/template"),
+	}
+	id, err := r.templateIdByName(context.Background(), "o1", name)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if id != "t1" {
+		t.Errorf("expected the template t1, got %s", id)
+	}
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// loseCreateResponses makes the POST requests store the resources at the
+// paths and close the connection before answering, like a create whose
+// response was lost.
+func loseCreateResponses(t *testing.T, api *fakeAPI, resourceType string, created map[string]map[string]any) {
+	api.handle = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method != http.MethodPost {
+			return false
+		}
+		for path, attributes := range created {
+			api.put(path, resourceType, attributes)
+		}
+		connection, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return true
+		}
+		connection.Close()
+		return true
+	}
+}
+
+func TestTeamLostCreateResponse(t *testing.T) {
+	t.Parallel()
+
+	team := map[string]any{"name": "platform"}
+	for _, test := range []struct {
+		name     string
+		existing map[string]map[string]any
+		created  map[string]map[string]any
+		adopted  string
+		summary  string
+	}{
+		{"created", nil, map[string]map[string]any{teamCollectionPath + "/t2": team}, "t2", ""},
+		{"created next to existing", map[string]map[string]any{teamCollectionPath + "/t1": team}, map[string]map[string]any{teamCollectionPath + "/t2": team}, "t2", ""},
+		{"existing only", map[string]map[string]any{teamCollectionPath + "/t1": team}, nil, "", "Error executing team resource request"},
+		{"created twice", nil, map[string]map[string]any{teamCollectionPath + "/t2": team, teamCollectionPath + "/t3": team}, "", "Team create response lost"},
+	} {
+		api, server := newFakeAPI(t)
+		for path, attributes := range test.existing {
+			api.put(path, "team", attributes)
+		}
+		loseCreateResponses(t, api, "team", test.created)
+		terrakube := newTestProvider(t, server.URL, nil)
+
+		state, diagnostics := terrakube.apply("terrakube_team", terrakube.null("terrakube_team"), teamConfig(terrakube, "platform", nil))
+		if test.adopted != "" {
+			if err := diagnosticsError(diagnostics); err != nil {
+				t.Errorf("%s: unexpected error: %s", test.name, err)
+			} else if id := stringAttribute(t, state, "id"); id != test.adopted {
+				t.Errorf("%s: expected the team %s to be adopted, got %s", test.name, test.adopted, id)
+			}
+			continue
+		}
+		if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, test.summary) {
+			t.Errorf("%s: expected the error %q, got %v", test.name, test.summary, diagnostics)
+		}
+		if test.summary == "Team create response lost" && !strings.Contains(diagnostics[0].Detail, "t2, t3") {
+			t.Errorf("%s: the error should list the created teams, got %q", test.name, diagnostics[0].Detail)
+		}
+	}
+}
+
+func TestWorkspaceVariableLostCreateResponse(t *testing.T) {
+	t.Parallel()
+
+	const variablePath = "/api/v1/organization/o1/workspace/w1/variable"
+	variable := map[string]any{"key": "region", "value": "value", "description": "description", "category": "TERRAFORM"}
+	for _, test := range []struct {
+		name     string
+		existing map[string]map[string]any
+		created  map[string]map[string]any
+		adopted  string
+		summary  string
+	}{
+		{"created", nil, map[string]map[string]any{variablePath + "/v2": variable}, "v2", ""},
+		{"existing only", map[string]map[string]any{variablePath + "/v1": variable}, nil, "", "Error executing workspace variable  resource request"},
+		{"created twice", nil, map[string]map[string]any{variablePath + "/v2": variable, variablePath + "/v3": variable}, "", "Workspace variable create response lost"},
+	} {
+		api, server := newFakeAPI(t)
+		for path, attributes := range test.existing {
+			api.put(path, "variable", attributes)
+		}
+		loseCreateResponses(t, api, "variable", test.created)
+		terrakube := newTestProvider(t, server.URL, nil)
+
+		state, diagnostics := terrakube.apply("terrakube_workspace_variable", terrakube.null("terrakube_workspace_variable"), workspaceVariableConfig(terrakube, "region", "TERRAFORM", nil))
+		if test.adopted != "" {
+			if err := diagnosticsError(diagnostics); err != nil {
+				t.Errorf("%s: unexpected error: %s", test.name, err)
+			} else if id := stringAttribute(t, state, "id"); id != test.adopted {
+				t.Errorf("%s: expected the variable %s to be adopted, got %s", test.name, test.adopted, id)
+			}
+			continue
+		}
+		if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, test.summary) {
+			t.Errorf("%s: expected the error %q, got %v", test.name, test.summary, diagnostics)
+		}
+	}
+}
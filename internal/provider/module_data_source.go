@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &ModuleDataSource{}
+	_ datasource.DataSourceWithConfigure = &ModuleDataSource{}
+)
+
+type ModuleDataSourceModel struct {
+	ID             types.String      `tfsdk:"id"`
+	OrganizationId types.String      `tfsdk:"organization_id"`
+	Name           types.String      `tfsdk:"name"`
+	ProviderName   types.String      `tfsdk:"provider_name"`
+	Description    types.String      `tfsdk:"description"`
+	Source         types.String      `tfsdk:"source"`
+	Folder         types.String      `tfsdk:"folder"`
+	TagPrefix      types.String      `tfsdk:"tag_prefix"`
+	RawAttributes  map[string]string `tfsdk:"raw_attributes"`
+	AllowMissing   types.Bool        `tfsdk:"allow_missing"`
+	Found          types.Bool        `tfsdk:"found"`
+}
+
+type ModuleDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewModuleDataSource() datasource.DataSource {
+	return &ModuleDataSource{}
+}
+
+func (d *ModuleDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Module Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Module datasource")
+}
+
+func (d *ModuleDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module"
+}
+
+func (d *ModuleDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Find a registry module by name and provider.",
+		Attributes: map[string]schema.Attribute{
+			"allow_missing": allowMissingSchema(),
+			"found":         foundSchema(),
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Module Id",
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Module name",
+			},
+			"provider_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Module provider name. Example: azurerm, google, aws, etc.",
+			},
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "Module description",
+			},
+			"source": schema.StringAttribute{
+				Computed:    true,
+				Description: "Source repository for the module(git using https or ssh protocol)",
+			},
+			"folder": schema.StringAttribute{
+				Computed:    true,
+				Description: "Folder of the module inside the repository, null for the repository root",
+			},
+			"tag_prefix": schema.StringAttribute{
+				Computed:    true,
+				Description: "Prefix tag mono-repository modules, null when every tag is a version",
+			},
+			"raw_attributes": rawAttributesSchema(),
+		},
+	}
+}
+
+func (d *ModuleDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ModuleDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := url.Values{}
+	query.Set("filter[module]", fmt.Sprintf("name==%s;provider==%s", rsqlString(state.Name.ValueString()), rsqlString(state.ProviderName.ValueString())))
+	// The raw attributes need every attribute the server knows.
+	body, err := fetchPageBody(client.WithFullPayloads(ctx), d.client, d.token, fmt.Sprintf("%s/api/v1/organization/%s/module?%s", d.endpoint, state.OrganizationId.ValueString(), query.Encode()))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing module datasource request", apiErrorDetail(err, fmt.Sprintf("Error executing module datasource request: %s", err)))
+		return
+	}
+
+	modules, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(body)), reflect.TypeOf(new(client.ModuleEntity)))
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to unmarshal payload", fmt.Sprintf("Unable to unmarshal payload, response body: %s, error: %s", string(body), err))
+		return
+	}
+
+	raw, err := rawAttributes(body)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read raw attributes", err.Error())
+		return
+	}
+
+	state.Found = types.BoolValue(len(modules) > 0)
+	if len(modules) == 0 {
+		lookupNotFound(&resp.Diagnostics, state.AllowMissing, "module", fmt.Sprintf("%s/%s", state.Name.ValueString(), state.ProviderName.ValueString()))
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	module := modules[0].(*client.ModuleEntity)
+	state.ID = types.StringValue(module.ID)
+	state.Description = types.StringValue(module.Description)
+	state.Source = types.StringValue(module.Source)
+	state.Folder = types.StringPointerValue(module.Folder)
+	state.TagPrefix = types.StringPointerValue(module.TagPrefix)
+	state.RawAttributes = raw[module.ID]
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
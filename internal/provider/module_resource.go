@@ -1,15 +1,16 @@
 package provider
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
-	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
-	"io"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"net/http"
+	"reflect"
+	"sort"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 
@@ -23,26 +24,37 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ModuleResource{}
 var _ resource.ResourceWithImportState = &ModuleResource{}
+var _ resource.ResourceWithModifyPlan = &ModuleResource{}
 
 type ModuleResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client        *http.Client
+	endpoint      string
+	token         string
+	modules       *client.Crud[client.ModuleEntity]
+	organizations *organizationCache
+	airgap        *airgapPolicy
+	warnings      *warningPolicy
 }
 
 type ModuleResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	Name           types.String `tfsdk:"name"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	Description    types.String `tfsdk:"description"`
-	ProviderName   types.String `tfsdk:"provider_name"`
-	Source         types.String `tfsdk:"source"`
-	VcsId          types.String `tfsdk:"vcs_id"`
-	SshId          types.String `tfsdk:"ssh_id"`
-	TagPrefix      types.String `tfsdk:"tag_prefix"`
-	Folder         types.String `tfsdk:"folder"`
+	ID                  types.String     `tfsdk:"id"`
+	Name                types.String     `tfsdk:"name"`
+	OrganizationId      types.String     `tfsdk:"organization_id"`
+	Description         descriptionValue `tfsdk:"description"`
+	ProviderName        types.String     `tfsdk:"provider_name"`
+	Source              types.String     `tfsdk:"source"`
+	VcsId               types.String     `tfsdk:"vcs_id"`
+	SshId               types.String     `tfsdk:"ssh_id"`
+	TagPrefix           types.String     `tfsdk:"tag_prefix"`
+	Folder              types.String     `tfsdk:"folder"`
+	IgnoreServerChanges types.Set        `tfsdk:"ignore_server_changes"`
+	CheckConsumers      types.Bool       `tfsdk:"check_consumers"`
+	Force               types.Bool       `tfsdk:"force"`
+	LatestVersion       types.String     `tfsdk:"latest_version"`
 }
 
+var moduleServerManagedAttributes = []string{"name", "description", "provider_name", "source", "vcs_id", "ssh_id", "tag_prefix", "folder"}
+
 func NewModuleResource() resource.Resource {
 	return &ModuleResource{}
 }
@@ -70,34 +82,74 @@ func (r *ModuleResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
-				Description: "Module name",
+				Description: "Module name. Changing it changes the registry path of the module and forces a new module.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"description": schema.StringAttribute{
+				CustomType:  descriptionType{},
 				Required:    true,
 				Description: "Module description",
 			},
 			"provider_name": schema.StringAttribute{
 				Required:    true,
-				Description: "Module provider name. Example: azurerm, google, aws, etc",
+				Description: "Module provider name. Example: azurerm, google, aws, etc. Changing it changes the registry path of the module and forces a new module.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"source": schema.StringAttribute{
 				Required:    true,
 				Description: "Source repository for the module(git using https or ssh protocol)",
 			},
 			"vcs_id": schema.StringAttribute{
-				Optional:    true,
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 				Description: "VCS connection ID for private modules",
 			},
 			"ssh_id": schema.StringAttribute{
-				Optional:    true,
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 				Description: "Ssh connection ID for private modules",
 			},
 			"tag_prefix": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("folder")),
+				},
+				Description: "Prefix tag mono-repository modules. module/ will pick up any tag starting with 'module/*'. Requires `folder`.",
+			},
+			"ignore_server_changes": ignoreServerChangesSchema(moduleServerManagedAttributes),
+			"check_consumers": schema.BoolAttribute{
 				Optional:    true,
-				Description: "Prefix tag mono-repository modules. module/ will pick up any tag starting with 'module/*'",
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Refuse to delete the module while workspaces of the organization use its registry address `organization/name/provider` as source, default is `true`. Workspaces using the git repository of the module directly are not consumers of the module. When the workspaces cannot be checked the module is not deleted.",
 			},
-			"folder": schema.StringAttribute{
+			"force": schema.BoolAttribute{
 				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Delete the module even when `check_consumers` finds workspaces using it or cannot check them, default is `false`. Like any attribute read on destroy, it must be applied before the module is deleted.",
+			},
+			"latest_version": schema.StringAttribute{
+				Computed:    true,
+				Description: "Highest semver version of the module listed by the registry, as listed by the registry, refreshed on every read. Prereleases only count when the module has no release, null when the registry lists no version.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"folder": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 				Description: "Folder to look into for module files. Need to preprend a / and append a / to work properly.",
 			},
 		},
@@ -119,20 +171,14 @@ func (r *ModuleResource) Configure(ctx context.Context, req resource.ConfigureRe
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.organizations = providerData.Organizations
+	r.airgap = providerData.Airgap
+	r.warnings = providerData.Warnings
+	r.modules = client.NewCrud[client.ModuleEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/module")
 
 	tflog.Debug(ctx, "Configuring Module resource", map[string]any{"success": true})
 }
@@ -154,7 +200,7 @@ func (r *ModuleResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	if !plan.Folder.IsNull() {
-		tflog.Info(ctx, fmt.Sprintf("Module using folder path: %s", plan.VcsId.ValueString()))
+		tflog.Info(ctx, fmt.Sprintf("Module using folder path: %s", plan.Folder.ValueString()))
 		bodyRequest.Folder = plan.Folder.ValueStringPointer()
 	}
 
@@ -173,61 +219,21 @@ func (r *ModuleResource) Create(ctx context.Context, req resource.CreateRequest,
 		bodyRequest.Ssh = &client.SshEntity{ID: plan.SshId.ValueString()}
 	}
 
-	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
-		return
-	}
-
-	tflog.Info(ctx, fmt.Sprintf("Body Request: %s", out.String()))
-
-	moduleRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/module", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
-	moduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	moduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating team resource request: %s", err))
-		return
-	}
-
-	moduleResponse, err := r.client.Do(moduleRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing module resource request", fmt.Sprintf("Error executing team resource request: %s", err))
-		return
-	}
-
-	bodyResponse, err := io.ReadAll(moduleResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading module resource response")
-	}
-
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-
-	newModule := &client.ModuleEntity{}
-
-	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), newModule)
-
+	newModule, err := r.modules.Create(ctx, bodyRequest, plan.OrganizationId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error executing module resource request", apiErrorDetail(err, fmt.Sprintf("Error executing module resource request: %s", err)))
 		return
 	}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-
 	plan.ID = types.StringValue(newModule.ID)
 	plan.Name = types.StringValue(newModule.Name)
-	plan.Description = types.StringValue(newModule.Description)
+	plan.Description = newDescriptionValue(newModule.Description)
 	plan.ProviderName = types.StringValue(newModule.Provider)
 	plan.Source = types.StringValue(newModule.Source)
 
-	if newModule.Folder != nil {
-		plan.Folder = types.StringPointerValue(newModule.Folder)
-	}
-
-	if newModule.TagPrefix != nil {
-		plan.TagPrefix = types.StringPointerValue(newModule.TagPrefix)
-	}
+	plan.Folder = stringPointerFromAPI(newModule.Folder)
+	plan.TagPrefix = stringPointerFromAPI(newModule.TagPrefix)
+	r.readLatestVersion(ctx, &plan)
 
 	tflog.Info(ctx, "Module Resource Created", map[string]any{"success": true})
 
@@ -242,56 +248,12 @@ func (r *ModuleResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	moduleRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/module/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
-	moduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	moduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating team resource request: %s", err))
-		return
-	}
-
-	moduleResponse, err := r.client.Do(moduleRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing module resource request", fmt.Sprintf("Error executing team resource request: %s", err))
+	prior := state
+	if err := r.readModule(ctx, &state); err != nil {
+		resp.Diagnostics.AddError("Error executing module resource request", apiErrorDetail(err, fmt.Sprintf("Error executing module resource request: %s", err)))
 		return
 	}
-
-	bodyResponse, err := io.ReadAll(moduleResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading module resource response")
-	}
-	module := &client.ModuleEntity{}
-
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), module)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
-		return
-	}
-
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-
-	state.Name = types.StringValue(module.Name)
-	state.Description = types.StringValue(module.Description)
-	state.ProviderName = types.StringValue(module.Provider)
-	state.Source = types.StringValue(module.Source)
-
-	if module.Folder != nil {
-		state.Folder = types.StringPointerValue(module.Folder)
-	}
-
-	if module.TagPrefix != nil {
-		state.TagPrefix = types.StringPointerValue(module.TagPrefix)
-	}
-
-	if module.Vcs != nil {
-		state.VcsId = types.StringValue(module.Vcs.ID)
-	}
-
-	if module.Ssh != nil {
-		state.SshId = types.StringValue(module.Ssh.ID)
-	}
+	keepIgnoredServerChanges(ctx, state.IgnoreServerChanges, &prior, &state)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -315,14 +277,14 @@ func (r *ModuleResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	bodyRequest := &client.ModuleEntity{
 		ID:          state.ID.ValueString(),
-		Name:        plan.Name.ValueString(),
+		Name:        state.Name.ValueString(),
 		Description: plan.Description.ValueString(),
-		Provider:    plan.ProviderName.ValueString(),
+		Provider:    state.ProviderName.ValueString(),
 		Source:      plan.Source.ValueString(),
 	}
 
 	if !plan.Folder.IsNull() {
-		tflog.Info(ctx, fmt.Sprintf("Module using folder: %s", plan.VcsId.ValueString()))
+		tflog.Info(ctx, fmt.Sprintf("Module using folder: %s", plan.Folder.ValueString()))
 		bodyRequest.Folder = plan.Folder.ValueStringPointer()
 	}
 
@@ -341,101 +303,239 @@ func (r *ModuleResource) Update(ctx context.Context, req resource.UpdateRequest,
 		bodyRequest.Ssh = &client.SshEntity{ID: plan.SshId.ValueString()}
 	}
 
-	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
-
+	err := r.modules.Update(ctx, state.ID.ValueString(), bodyRequest, state.OrganizationId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
+		resp.Diagnostics.AddError("Error executing module resource request", apiErrorDetail(err, fmt.Sprintf("Error executing module resource request: %s", err)))
 		return
 	}
 
-	moduleRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/module/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
-	moduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	moduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
+	// Removing vcs_id or ssh_id must detach the relationship explicitly, an
+	// omitted relationship is left unchanged by the PATCH above.
+	if plan.VcsId.IsNull() && !state.VcsId.IsNull() {
+		if err := r.modules.PatchRelationship(ctx, state.ID.ValueString(), "vcs", nil, state.OrganizationId.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Error detaching module vcs connection", apiErrorDetail(err, fmt.Sprintf("Error detaching module vcs connection: %s", err)))
+			return
+		}
+	}
+
+	if plan.SshId.IsNull() && !state.SshId.IsNull() {
+		if err := r.modules.PatchRelationship(ctx, state.ID.ValueString(), "ssh", nil, state.OrganizationId.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Error detaching module ssh key", apiErrorDetail(err, fmt.Sprintf("Error detaching module ssh key: %s", err)))
+			return
+		}
+	}
+
+	module, err := r.modules.Get(ctx, state.ID.ValueString(), state.OrganizationId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating team resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing module resource request", apiErrorDetail(err, fmt.Sprintf("Error executing module resource request: %s", err)))
 		return
 	}
 
-	teamResponse, err := r.client.Do(moduleRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing module resource request", fmt.Sprintf("Error executing team resource request: %s", err))
+	plan.ID = types.StringValue(state.ID.ValueString())
+	plan.Name = types.StringValue(module.Name)
+	plan.Description = newDescriptionValue(module.Description)
+	plan.ProviderName = types.StringValue(module.Provider)
+	plan.Source = types.StringValue(module.Source)
+	plan.Folder = stringPointerFromAPI(module.Folder)
+	plan.TagPrefix = stringPointerFromAPI(module.TagPrefix)
+	r.readLatestVersion(ctx, &plan)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *ModuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ModuleResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	bodyResponse, err := io.ReadAll(teamResponse.Body)
+	if data.CheckConsumers.ValueBool() && !data.Force.ValueBool() {
+		consumers, err := r.moduleConsumers(ctx, data)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading module consumers", apiErrorDetail(err, fmt.Sprintf("Unable to check the workspaces using module %s, it is not deleted: %s. Set force = true to delete it without the check.", data.Name.ValueString(), err)))
+			return
+		}
+		if len(consumers) > 0 {
+			resp.Diagnostics.AddError(
+				"Module in use",
+				fmt.Sprintf("Module %s is used by workspaces %s, their next run would fail to initialize. "+
+					"Update them first, or set force = true to delete the module anyway.", data.Name.ValueString(), strings.Join(consumers, ", ")),
+			)
+			return
+		}
+	}
+
+	err := r.modules.Delete(ctx, data.ID.ValueString(), data.OrganizationId.ValueString())
 	if err != nil {
-		tflog.Error(ctx, "Error reading module resource response")
+		resp.Diagnostics.AddError("Error executing module resource request", apiErrorDetail(err, fmt.Sprintf("Error executing module resource request: %s", err)))
+		return
 	}
+}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
+func (r *ModuleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.airgap.warnExternalSource(ctx, req.Plan, "source", &resp.Diagnostics)
 
-	moduleRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/module/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
-	moduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	moduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating team resource request: %s", err))
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
 		return
 	}
 
-	teamResponse, err = r.client.Do(moduleRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing module resource request", fmt.Sprintf("Error executing team resource request: %s", err))
+	var plan, state ModuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	bodyResponse, err = io.ReadAll(teamResponse.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Error reading module resource response body", fmt.Sprintf("Error reading team resource response body: %s", err))
+	if plan.Name.IsUnknown() || plan.ProviderName.IsUnknown() {
+		return
 	}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+	if plan.Name.Equal(state.Name) && plan.ProviderName.Equal(state.ProviderName) {
+		return
+	}
 
-	module := &client.ModuleEntity{}
-	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), module)
+	organizationName := r.organizationName(state.OrganizationId.ValueString())
+	oldPath := moduleRegistryPath(organizationName, state.Name.ValueString(), state.ProviderName.ValueString())
+	newPath := moduleRegistryPath(organizationName, plan.Name.ValueString(), plan.ProviderName.ValueString())
 
+	r.warnings.add(
+		&resp.Diagnostics,
+		"Module registry path change",
+		fmt.Sprintf("Changing the module name or provider replaces the module and moves it from registry path %q to %q. "+
+			"Every configuration referencing the old registry path will fail to initialize until its source is updated.", oldPath, newPath),
+	)
+}
+
+// organizationName returns the organization name used in the registry path,
+// falling back to the organization id when it cannot be fetched.
+func (r *ModuleResource) organizationName(organizationId string) string {
+	name, err := cachedOrganizationName(r.organizations, r.client, r.endpoint, r.token, organizationId)
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
-		return
+		return organizationId
 	}
+	return name
+}
 
-	plan.ID = types.StringValue(state.ID.ValueString())
-	plan.Name = types.StringValue(module.Name)
-	plan.Description = types.StringValue(module.Description)
-	plan.ProviderName = types.StringValue(module.Provider)
-	plan.Source = types.StringValue(module.Source)
-	plan.TagPrefix = types.StringPointerValue(module.TagPrefix)
+// moduleConsumers returns the sorted names of the workspaces whose source is
+// the registry address of the module. Only registry addresses are matched,
+// whatever their host: a workspace using the git repository of the module,
+// or a module block of its configuration, is not a consumer. The
+// organization name is part of the address, failing to read it is an error
+// rather than a check that finds nothing.
+func (r *ModuleResource) moduleConsumers(ctx context.Context, module ModuleResourceModel) ([]string, error) {
+	organizationName, err := cachedOrganizationName(r.organizations, r.client, r.endpoint, r.token, module.OrganizationId.ValueString())
+	if err != nil {
+		return nil, fmt.Errorf("error reading the name of organization %s: %w", module.OrganizationId.ValueString(), err)
+	}
+
+	workspaces, err := fetchAllPages(ctx, r.client, r.token, fmt.Sprintf("%s/api/v1/organization/%s/workspace", r.endpoint, module.OrganizationId.ValueString()), reflect.TypeOf(new(client.WorkspaceEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("error listing the workspaces of organization %s: %w", module.OrganizationId.ValueString(), err)
+	}
+
+	registryPath := strings.ToLower(moduleRegistryPath(organizationName, module.Name.ValueString(), module.ProviderName.ValueString()))
 
-	if module.Folder != nil {
-		plan.Folder = types.StringPointerValue(module.Folder)
+	var consumers []string
+	for _, item := range workspaces {
+		workspace := item.(*client.WorkspaceEntity)
+		if !workspace.Deleted && moduleRegistryAddress(workspace.Source) == registryPath {
+			consumers = append(consumers, workspace.Name)
+		}
 	}
+	sort.Strings(consumers)
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	return consumers, nil
 }
 
-func (r *ModuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data ModuleResourceModel
+func moduleRegistryPath(organizationName string, name string, provider string) string {
+	return fmt.Sprintf("%s/%s/%s", organizationName, name, provider)
+}
 
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+func (r *ModuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
 
-	if resp.Diagnostics.HasError() {
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,ID', Got: %q", req.ID),
+		)
+		return
+	}
+
+	// Read the module right away so every attribute is in the imported state
+	// and the first plan does not depend on a refresh.
+	state := ModuleResourceModel{
+		ID:                  types.StringValue(idParts[1]),
+		OrganizationId:      types.StringValue(idParts[0]),
+		IgnoreServerChanges: types.SetNull(types.StringType),
+		CheckConsumers:      types.BoolValue(true),
+		Force:               types.BoolValue(false),
+	}
+
+	if err := r.readModule(ctx, &state); err != nil {
+		resp.Diagnostics.AddError("Error importing module resource", apiErrorDetail(err, fmt.Sprintf("Error importing module resource: %s", err)))
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/module/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
-	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// readModule maps the module returned by the API into the state.
+func (r *ModuleResource) readModule(ctx context.Context, state *ModuleResourceModel) error {
+	module, err := r.modules.Get(ctx, state.ID.ValueString(), state.OrganizationId.ValueString())
+	if err != nil {
+		return err
+	}
+
+	state.ID = types.StringValue(module.ID)
+	state.Name = types.StringValue(module.Name)
+	state.Description = newDescriptionValue(module.Description)
+	state.ProviderName = types.StringValue(module.Provider)
+	state.Source = types.StringValue(module.Source)
+
+	state.Folder = stringPointerFromAPI(module.Folder)
+	state.TagPrefix = stringPointerFromAPI(module.TagPrefix)
+	state.VcsId = types.StringNull()
+	state.SshId = types.StringNull()
+
+	if module.Vcs != nil {
+		state.VcsId = stringFromAPI(module.Vcs.ID)
+	}
+
+	if module.Ssh != nil {
+		state.SshId = stringFromAPI(module.Ssh.ID)
+	}
+
+	r.readLatestVersion(ctx, state)
+
+	return nil
+}
+
+// readLatestVersion sets latest_version from the registry. The registry is
+// not needed to manage the module, when it can not be read the value of
+// the state is kept.
+func (r *ModuleResource) readLatestVersion(ctx context.Context, state *ModuleResourceModel) {
+	if state.LatestVersion.IsUnknown() {
+		state.LatestVersion = types.StringNull()
+	}
+
+	organizationName, err := cachedOrganizationName(r.organizations, r.client, r.endpoint, r.token, state.OrganizationId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating team resource request: %s", err))
+		tflog.Warn(ctx, "Unable to read the module latest version", map[string]any{"module": state.ID.ValueString(), "error": err.Error()})
 		return
 	}
 
-	_, err = r.client.Do(reqOrg)
+	versions, err := listModuleVersions(r.client, r.token, moduleRegistryUrl(r.endpoint, organizationName, state.Name.ValueString(), state.ProviderName.ValueString()))
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing module resource request", fmt.Sprintf("Error executing team resource request: %s", err))
+		tflog.Warn(ctx, "Unable to read the module latest version", map[string]any{"module": state.ID.ValueString(), "error": err.Error()})
 		return
 	}
-}
 
-func (r *ModuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	state.LatestVersion = types.StringNull()
+	if latest := latestModuleVersion(versions, state.TagPrefix.ValueString()); latest != "" {
+		state.LatestVersion = types.StringValue(latest)
+	}
 }
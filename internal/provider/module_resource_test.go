@@ -0,0 +1,316 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newModuleAPI returns a fake API with the organization o1 named "platform".
+func newModuleAPI(t *testing.T) (*fakeAPI, *testProvider) {
+	api, server := newFakeAPI(t)
+	api.put("/api/v1/organization/o1", "organization", map[string]any{"name": "platform"})
+	return api, newTestProvider(t, server.URL, nil)
+}
+
+// moduleConfig returns a terrakube_module configuration of organization o1,
+// the attributes are added to the name and provider and may replace the
+// organization.
+func moduleConfig(terrakube *testProvider, name string, provider string, attributes map[string]tftypes.Value) tftypes.Value {
+	values := map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+		"name":            tftypes.NewValue(tftypes.String, name),
+		"provider_name":   tftypes.NewValue(tftypes.String, provider),
+		"description":     tftypes.NewValue(tftypes.String, "description"),
+		"source":          tftypes.NewValue(tftypes.String, "https://github.com/platform/terraform-aws-vpc.git"),
+	}
+	for attributeName, value := range attributes {
+		values[attributeName] = value
+	}
+	return terrakube.object("terrakube_module", values)
+}
+
+func TestModuleRenameReplaces(t *testing.T) {
+	t.Parallel()
+
+	_, terrakube := newModuleAPI(t)
+	state, diagnostics := terrakube.apply("terrakube_module", terrakube.null("terrakube_module"), moduleConfig(terrakube, "vpc", "aws", nil))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		config   tftypes.Value
+		replaced string
+		detail   string
+	}{
+		{"name", moduleConfig(terrakube, "network", "aws", nil), "name", `from registry path "platform/vpc/aws" to "platform/network/aws"`},
+		{"provider", moduleConfig(terrakube, "vpc", "google", nil), "provider_name", `from registry path "platform/vpc/aws" to "platform/vpc/google"`},
+	} {
+		plan := terrakube.plan("terrakube_module", state, test.config)
+		if err := diagnosticsError(plan.Diagnostics); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+
+		if len(plan.RequiresReplace) != 1 || !plan.RequiresReplace[0].Equal(tftypes.NewAttributePath().WithAttributeName(test.replaced)) {
+			t.Errorf("%s: expected %s to force a replacement, got %v", test.name, test.replaced, plan.RequiresReplace)
+		}
+
+		warned := false
+		for _, diagnostic := range plan.Diagnostics {
+			if diagnostic.Severity == tfprotov6.DiagnosticSeverityWarning && diagnostic.Summary == "Module registry path change" {
+				warned = strings.Contains(diagnostic.Detail, test.detail)
+			}
+		}
+		if !warned {
+			t.Errorf("%s: expected a registry path warning with %s, got %v", test.name, test.detail, plan.Diagnostics)
+		}
+	}
+
+	plan := terrakube.plan("terrakube_module", state, moduleConfig(terrakube, "vpc", "aws", map[string]tftypes.Value{
+		"description": tftypes.NewValue(tftypes.String, "changed"),
+	}))
+	if len(plan.RequiresReplace) != 0 || hasDiagnostic(plan.Diagnostics, tfprotov6.DiagnosticSeverityWarning, "Module registry path change") {
+		t.Errorf("a description change should update the module in place, got %v %v", plan.RequiresReplace, plan.Diagnostics)
+	}
+}
+
+func TestModuleImport(t *testing.T) {
+	t.Parallel()
+
+	api, terrakube := newModuleAPI(t)
+	api.put("/api/v1/organization/o1/module/m1", "module", map[string]any{"name": "vpc", "provider": "aws", "description": "network", "source": "https://github.com/platform/modules.git", "folder": "/vpc/", "tagPrefix": "vpc/"})
+
+	state, diagnostics := terrakube.importAndRead("terrakube_module", "o1,m1")
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for name, expected := range map[string]tftypes.Value{
+		"id":              tftypes.NewValue(tftypes.String, "m1"),
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+		"name":            tftypes.NewValue(tftypes.String, "vpc"),
+		"provider_name":   tftypes.NewValue(tftypes.String, "aws"),
+		"description":     tftypes.NewValue(tftypes.String, "network"),
+		"source":          tftypes.NewValue(tftypes.String, "https://github.com/platform/modules.git"),
+		"folder":          tftypes.NewValue(tftypes.String, "/vpc/"),
+		"tag_prefix":      tftypes.NewValue(tftypes.String, "vpc/"),
+		"check_consumers": tftypes.NewValue(tftypes.Bool, true),
+		"force":           tftypes.NewValue(tftypes.Bool, false),
+	} {
+		if value := attribute(t, state, name); !value.Equal(expected) {
+			t.Errorf("imported %s = %s, expected %s", name, value, expected)
+		}
+	}
+
+	if _, diagnostics := terrakube.importAndRead("terrakube_module", "o1,missing"); diagnosticsError(diagnostics) == nil {
+		t.Errorf("importing a missing module should fail")
+	}
+}
+
+func TestModuleIgnoreServerChanges(t *testing.T) {
+	t.Parallel()
+
+	api, terrakube := newModuleAPI(t)
+	config := moduleConfig(terrakube, "vpc", "aws", map[string]tftypes.Value{
+		"ignore_server_changes": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "description"),
+		}),
+	})
+
+	state, diagnostics := terrakube.apply("terrakube_module", terrakube.null("terrakube_module"), config)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	modulePath := "/api/v1/organization/o1/module/" + stringAttribute(t, state, "id")
+
+	attributes := api.attributes(modulePath)
+	api.put(modulePath, "module", map[string]any{
+		"name":        attributes["name"],
+		"provider":    attributes["provider"],
+		"description": "set by a controller",
+		"source":      "https://github.com/platform/terraform-aws-network.git",
+	})
+
+	state, diagnostics = terrakube.read("terrakube_module", state)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if description := stringAttribute(t, state, "description"); description != "description" {
+		t.Errorf("the ignored description should keep its prior value, got %s", description)
+	}
+	if source := stringAttribute(t, state, "source"); source != "https://github.com/platform/terraform-aws-network.git" {
+		t.Errorf("the source drift should be reported, got %s", source)
+	}
+
+	plan := terrakube.plan("terrakube_module", state, config)
+	if err := diagnosticsError(plan.Diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	planned := terrakube.value("terrakube_module", plan.PlannedState)
+	if source := stringAttribute(t, planned, "source"); source != "https://github.com/platform/terraform-aws-vpc.git" {
+		t.Errorf("the plan should revert the source, got %s", source)
+	}
+}
+
+func TestModuleDeleteConsumers(t *testing.T) {
+	t.Parallel()
+
+	force := map[string]tftypes.Value{"force": tftypes.NewValue(tftypes.Bool, true)}
+	for _, test := range []struct {
+		name         string
+		organization string
+		source       string
+		attributes   map[string]tftypes.Value
+		summary      string
+	}{
+		{"consumer", "o1", "registry.terrakube.example.com/platform/vpc/aws", nil, "Module in use"},
+		{"consumer of another case", "o1", "Registry.Terrakube.example.com/Platform/VPC/aws//modules/subnet?ref=1.0.0", nil, "Module in use"},
+		{"git consumer", "o1", "https://github.com/platform/terraform-aws-vpc.git", nil, ""},
+		{"other module", "o1", "registry.terrakube.example.com/platform/vpc/google", nil, ""},
+		{"forced", "o1", "registry.terrakube.example.com/platform/vpc/aws", force, ""},
+		{"unknown organization name", "o2", "", nil, "Error reading module consumers"},
+		{"forced without organization name", "o2", "", force, ""},
+	} {
+		api, terrakube := newModuleAPI(t)
+		if test.source != "" {
+			api.put("/api/v1/organization/o1/workspace/w1", "workspace", map[string]any{"name": "network", "source": test.source})
+		}
+		api.put("/api/v1/organization/o1/workspace/w2", "workspace", map[string]any{"name": "retired", "source": "registry.terrakube.example.com/platform/vpc/aws", "deleted": true})
+
+		attributes := map[string]tftypes.Value{"organization_id": tftypes.NewValue(tftypes.String, test.organization)}
+		for name, value := range test.attributes {
+			attributes[name] = value
+		}
+		state, diagnostics := terrakube.apply("terrakube_module", terrakube.null("terrakube_module"), moduleConfig(terrakube, "vpc", "aws", attributes))
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		modulePath := "/api/v1/organization/" + test.organization + "/module/" + stringAttribute(t, state, "id")
+
+		_, diagnostics = terrakube.apply("terrakube_module", state, terrakube.null("terrakube_module"))
+		deleted := api.attributes(modulePath) == nil
+		switch {
+		case test.summary == "" && (diagnosticsError(diagnostics) != nil || !deleted):
+			t.Errorf("%s: the module should be deleted, got %v", test.name, diagnostics)
+		case test.summary != "" && (!hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, test.summary) || deleted):
+			t.Errorf("%s: the delete should fail with %q, got %v", test.name, test.summary, diagnostics)
+		}
+	}
+}
+
+// TestModuleEmptyStrings imports a module whose optional strings Terrakube
+// returns empty, they must be null like in a configuration leaving them out,
+// and "" is rejected in the configuration.
+func TestModuleEmptyStrings(t *testing.T) {
+	t.Parallel()
+
+	api, terrakube := newModuleAPI(t)
+	api.put("/api/v1/organization/o1/module/m1", "module", map[string]any{"name": "vpc", "provider": "aws", "description": "description", "source": "https://github.com/platform/terraform-aws-vpc.git", "folder": "", "tagPrefix": ""})
+
+	state, diagnostics := terrakube.importAndRead("terrakube_module", "o1,m1")
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, name := range []string{"folder", "tag_prefix", "vcs_id", "ssh_id"} {
+		if value := attribute(t, state, name); !value.IsNull() {
+			t.Errorf("an empty %s should be null, got %s", name, value)
+		}
+	}
+	plan := terrakube.plan("terrakube_module", state, moduleConfig(terrakube, "vpc", "aws", nil))
+	if planned := terrakube.value("terrakube_module", plan.PlannedState); !planned.Equal(state) {
+		t.Errorf("a configuration without the optional strings should plan no change:\n%s\n%s", planned, state)
+	}
+
+	for _, name := range []string{"folder", "tag_prefix", "vcs_id", "ssh_id"} {
+		diagnostics := terrakube.validate("terrakube_module", moduleConfig(terrakube, "vpc", "aws", map[string]tftypes.Value{
+			name: tftypes.NewValue(tftypes.String, ""),
+		}))
+		if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, "Invalid Attribute Value Length") {
+			t.Errorf("an empty %s should be rejected, got %v", name, diagnostics)
+		}
+	}
+}
+
+// TestModuleDetachedVcs reads a module whose VCS connection was removed in
+// Terrakube, vcs_id must become null.
+func TestModuleDetachedVcs(t *testing.T) {
+	t.Parallel()
+
+	api, terrakube := newModuleAPI(t)
+	state, diagnostics := terrakube.apply("terrakube_module", terrakube.null("terrakube_module"), moduleConfig(terrakube, "vpc", "aws", map[string]tftypes.Value{
+		"vcs_id": tftypes.NewValue(tftypes.String, "v1"),
+	}))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if vcsId := stringAttribute(t, state, "vcs_id"); vcsId != "v1" {
+		t.Fatalf("unexpected vcs_id %s", vcsId)
+	}
+
+	modulePath := "/api/v1/organization/o1/module/" + stringAttribute(t, state, "id")
+	api.put(modulePath, "module", api.attributes(modulePath))
+	state, diagnostics = terrakube.read("terrakube_module", state)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if vcsId := attribute(t, state, "vcs_id"); !vcsId.IsNull() {
+		t.Errorf("a detached VCS connection should make vcs_id null, got %s", vcsId)
+	}
+}
+
+func TestModuleMonorepo(t *testing.T) {
+	t.Parallel()
+
+	api, terrakube := newModuleAPI(t)
+	monorepo := map[string]tftypes.Value{
+		"folder":     tftypes.NewValue(tftypes.String, "/vpc/"),
+		"tag_prefix": tftypes.NewValue(tftypes.String, "vpc/"),
+	}
+
+	state, diagnostics := terrakube.apply("terrakube_module", terrakube.null("terrakube_module"), moduleConfig(terrakube, "vpc", "aws", monorepo))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	modulePath := "/api/v1/organization/o1/module/" + stringAttribute(t, state, "id")
+	if attributes := api.attributes(modulePath); attributes["folder"] != "/vpc/" || attributes["tagPrefix"] != "vpc/" {
+		t.Errorf("the module should be created with its folder and tag prefix, got %v", attributes)
+	}
+
+	state, diagnostics = terrakube.read("terrakube_module", state)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for name, expected := range monorepo {
+		if value := attribute(t, state, name); !value.Equal(expected) {
+			t.Errorf("refreshed %s = %s, expected %s", name, value, expected)
+		}
+	}
+	plan := terrakube.plan("terrakube_module", state, moduleConfig(terrakube, "vpc", "aws", monorepo))
+	if err := diagnosticsError(plan.Diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if planned := terrakube.value("terrakube_module", plan.PlannedState); !planned.Equal(state) {
+		t.Errorf("the refreshed module should plan no change:\n%s\n%s", planned, state)
+	}
+
+	state, diagnostics = terrakube.apply("terrakube_module", state, moduleConfig(terrakube, "vpc", "aws", map[string]tftypes.Value{
+		"folder":     tftypes.NewValue(tftypes.String, "/network/vpc/"),
+		"tag_prefix": tftypes.NewValue(tftypes.String, "network-vpc/"),
+	}))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attributes := api.attributes(modulePath); attributes["folder"] != "/network/vpc/" || attributes["tagPrefix"] != "network-vpc/" {
+		t.Errorf("the new folder and tag prefix should be patched, got %v", attributes)
+	}
+
+	diagnostics = terrakube.validate("terrakube_module", moduleConfig(terrakube, "vpc", "aws", map[string]tftypes.Value{
+		"tag_prefix": tftypes.NewValue(tftypes.String, "vpc/"),
+	}))
+	if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, "Invalid Attribute Combination") {
+		t.Errorf("tag_prefix without folder should be rejected, got %v", diagnostics)
+	}
+}
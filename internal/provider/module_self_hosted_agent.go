@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -94,17 +93,7 @@ func (r *AgentResource) Configure(ctx context.Context, req resource.ConfigureReq
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -137,7 +126,7 @@ func (r *AgentResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	tflog.Info(ctx, fmt.Sprintf("Body Request: %s", out.String()))
 
-	agentRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/agent", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	agentRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/agent", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
 	agentRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	agentRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -163,7 +152,7 @@ func (r *AgentResource) Create(ctx context.Context, req resource.CreateRequest,
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), newAgent)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(agentResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, agentResponse.Status)))
 		return
 	}
 
@@ -211,7 +200,7 @@ func (r *AgentResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), agent)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(agentResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, agentResponse.Status)))
 		return
 	}
 
@@ -293,7 +282,7 @@ func (r *AgentResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	bodyResponse, err = io.ReadAll(agentResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading self hosted agent resource response body", fmt.Sprintf("Error reading self hosted agent resource response body: %s", err))
+		resp.Diagnostics.AddError("Error reading self hosted agent resource response body", responseErrorDetail(agentResponse, fmt.Sprintf("Error reading self hosted agent resource response body, error: %s, response status: %s", err, agentResponse.Status)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -302,7 +291,7 @@ func (r *AgentResource) Update(ctx context.Context, req resource.UpdateRequest,
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), module)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(agentResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, agentResponse.Status)))
 		return
 	}
 
@@ -323,18 +312,25 @@ func (r *AgentResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/agent/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	reqOrg, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/agent/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating self hosted agent resource request", fmt.Sprintf("Error creating self hosted agent resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(reqOrg)
+	deleteResponse, err := r.client.Do(reqOrg)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing self hosted agent resource request", fmt.Sprintf("Error executing self hosted agent resource request: %s", err))
 		return
 	}
+	defer deleteResponse.Body.Close()
+
+	if deleteResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(deleteResponse.Body)
+		resp.Diagnostics.AddError("Error executing self hosted agent resource request", responseErrorDetail(deleteResponse, fmt.Sprintf("Error executing self hosted agent resource request, response status: %s, response body: %s", deleteResponse.Status, bodyResponse)))
+		return
+	}
 }
 
 func (r *AgentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -0,0 +1,452 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ModuleVersionResource{}
+var _ resource.ResourceWithImportState = &ModuleVersionResource{}
+
+const (
+	moduleVersionTimeout   = 5 * time.Minute
+	moduleVersionFrequency = 10 * time.Second
+
+	moduleVersionPublished = "published"
+)
+
+type ModuleVersionResource struct {
+	client        *http.Client
+	endpoint      string
+	token         string
+	modules       *client.Crud[client.ModuleEntity]
+	organizations *organizationCache
+	warnings      *warningPolicy
+}
+
+type ModuleVersionResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	OrganizationId types.String `tfsdk:"organization_id"`
+	ModuleId       types.String `tfsdk:"module_id"`
+	Version        types.String `tfsdk:"version"`
+	Status         types.String `tfsdk:"status"`
+	DownloadUrl    types.String `tfsdk:"download_url"`
+}
+
+func NewModuleVersionResource() resource.Resource {
+	return &ModuleVersionResource{}
+}
+
+func (r *ModuleVersionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module_version"
+}
+
+func (r *ModuleVersionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Publish a version of a module in the Terrakube registry. " +
+			"The versions of a module are the tags of its repository, creating this resource waits until the registry lists the tag and asks the registry to package it. " +
+			"The registry has no API to remove a version, destroying the resource only removes it from the state.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Module version Id, the module id and the version separated by a comma",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"module_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube module id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Required:    true,
+				Description: "Git tag of the version, for example `v1.2.0`. A leading `v` is ignored when comparing with the versions of the registry, and so is the `tag_prefix` of a monorepo module, `networking/v1.2.0` and `v1.2.0` are the same version of a module with the `networking/` prefix.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Status of the version in the registry, `published` once the registry packaged it.",
+			},
+			"download_url": schema.StringAttribute{
+				Computed:    true,
+				Description: "URL returned by the registry to download the version, the value of its X-Terraform-Get header.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ModuleVersionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Module Version Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+	r.warnings = providerData.Warnings
+	r.modules = client.NewCrud[client.ModuleEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/module")
+	r.organizations = providerData.Organizations
+
+	tflog.Debug(ctx, "Configuring Module Version resource", map[string]any{"success": true})
+}
+
+func (r *ModuleVersionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ModuleVersionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registryUrl, tagPrefix, err := r.registryUrl(ctx, plan.OrganizationId.ValueString(), plan.ModuleId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading module", apiErrorDetail(err, fmt.Sprintf("Error reading module %s: %s", plan.ModuleId.ValueString(), err)))
+		return
+	}
+
+	var versions []string
+	var registryVersion string
+	err = waitFor(ctx, moduleVersionTimeout, moduleVersionFrequency, func() (bool, error) {
+		versions, err = r.listVersions(registryUrl)
+		if err != nil {
+			return false, err
+		}
+		tflog.Debug(ctx, "Waiting for module version", map[string]any{"module": plan.ModuleId.ValueString(), "version": plan.Version.ValueString(), "versions": versions})
+		var found bool
+		registryVersion, found = findModuleVersion(versions, plan.Version.ValueString(), tagPrefix)
+		return found, nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("version"),
+			"Module version not found",
+			fmt.Sprintf("The registry did not list version %s of module %s: %s. Versions found: %s. "+
+				"Check that the tag is pushed to the module repository and matches the tag_prefix and folder of the module, then run apply again.",
+				plan.Version.ValueString(), plan.ModuleId.ValueString(), err, strings.Join(versions, ", ")),
+		)
+		return
+	}
+
+	downloadUrl, err := r.download(registryUrl, registryVersion)
+	if err != nil {
+		resp.Diagnostics.AddError("Error publishing module version", apiErrorDetail(err, fmt.Sprintf("Error publishing version %s of module %s: %s", plan.Version.ValueString(), plan.ModuleId.ValueString(), err)))
+		return
+	}
+
+	plan.ID = types.StringValue(fmt.Sprintf("%s,%s", plan.ModuleId.ValueString(), plan.Version.ValueString()))
+	plan.Status = types.StringValue(moduleVersionPublished)
+	plan.DownloadUrl = types.StringValue(downloadUrl)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+
+	tflog.Info(ctx, "Module Version Resource Created", map[string]any{"success": true})
+}
+
+func (r *ModuleVersionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ModuleVersionResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registryUrl, tagPrefix, err := r.registryUrl(ctx, state.OrganizationId.ValueString(), state.ModuleId.ValueString())
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading module", apiErrorDetail(err, fmt.Sprintf("Error reading module %s: %s", state.ModuleId.ValueString(), err)))
+		return
+	}
+
+	versions, err := r.listVersions(registryUrl)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading module versions", apiErrorDetail(err, fmt.Sprintf("Error reading the versions of module %s: %s", state.ModuleId.ValueString(), err)))
+		return
+	}
+
+	// A tag deleted from the repository is no longer a version, removing it
+	// from the state plans to publish it again.
+	registryVersion, found := findModuleVersion(versions, state.Version.ValueString(), tagPrefix)
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if state.DownloadUrl.IsNull() {
+		downloadUrl, err := r.download(registryUrl, registryVersion)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading module version", apiErrorDetail(err, fmt.Sprintf("Error reading version %s of module %s: %s", state.Version.ValueString(), state.ModuleId.ValueString(), err)))
+			return
+		}
+		state.DownloadUrl = types.StringValue(downloadUrl)
+	}
+	state.ID = types.StringValue(fmt.Sprintf("%s,%s", state.ModuleId.ValueString(), state.Version.ValueString()))
+	state.Status = types.StringValue(moduleVersionPublished)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Module Version Resource reading", map[string]any{"success": true})
+}
+
+func (r *ModuleVersionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every configurable attribute forces a replacement, the computed values
+	// are kept from the state.
+	var plan ModuleVersionResourceModel
+	var state ModuleVersionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = state.ID
+	plan.Status = state.Status
+	plan.DownloadUrl = state.DownloadUrl
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *ModuleVersionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ModuleVersionResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.warnings.add(
+		&resp.Diagnostics,
+		"Module version not removed from the registry",
+		fmt.Sprintf("The Terrakube registry has no API to remove a module version, version %s of module %s is only removed from the state. "+
+			"Delete the tag from the module repository to remove it from the registry.", data.Version.ValueString(), data.ModuleId.ValueString()),
+	)
+
+	tflog.Info(ctx, "Module Version Resource deleted", map[string]any{"success": true})
+}
+
+func (r *ModuleVersionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,module_ID,version', Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("module_id"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("version"), idParts[2])...)
+}
+
+// registryUrl returns the registry URL of the module, under the module
+// registry protocol path of the Terrakube API, and the tag prefix of the
+// module.
+func (r *ModuleVersionResource) registryUrl(ctx context.Context, organizationId string, moduleId string) (string, string, error) {
+	module, err := r.modules.Get(ctx, moduleId, organizationId)
+	if err != nil {
+		return "", "", err
+	}
+
+	organizationName, err := cachedOrganizationName(r.organizations, r.client, r.endpoint, r.token, organizationId)
+	if err != nil {
+		return "", "", err
+	}
+
+	var tagPrefix string
+	if module.TagPrefix != nil {
+		tagPrefix = *module.TagPrefix
+	}
+
+	return moduleRegistryUrl(r.endpoint, organizationName, module.Name, module.Provider), tagPrefix, nil
+}
+
+// moduleRegistryUrl returns the URL of a module under the module registry
+// protocol path of the Terrakube API.
+func moduleRegistryUrl(endpoint string, organizationName string, name string, provider string) string {
+	return fmt.Sprintf("%s/terraform/modules/v1/%s/%s/%s", endpoint, url.PathEscape(organizationName), url.PathEscape(name), url.PathEscape(provider))
+}
+
+// listVersions returns the versions of the module listed by the registry.
+func (r *ModuleVersionResource) listVersions(registryUrl string) ([]string, error) {
+	return listModuleVersions(r.client, r.token, registryUrl)
+}
+
+// listModuleVersions returns the versions listed by the registry URL of a
+// module.
+func listModuleVersions(httpClient *http.Client, token string, registryUrl string) ([]string, error) {
+	response, err := moduleRegistryRequest(httpClient, token, registryUrl+"/versions")
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var versions struct {
+		Modules []struct {
+			Versions []struct {
+				Version string `json:"version"`
+			} `json:"versions"`
+		} `json:"modules"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("error reading versions: %w", err)
+	}
+
+	var result []string
+	for _, module := range versions.Modules {
+		for _, version := range module.Versions {
+			result = append(result, version.Version)
+		}
+	}
+
+	return result, nil
+}
+
+// download asks the registry for the download URL of a version it lists,
+// which packages the tag when the registry did not package it yet.
+func (r *ModuleVersionResource) download(registryUrl string, version string) (string, error) {
+	response, err := moduleRegistryRequest(r.client, r.token, fmt.Sprintf("%s/%s/download", registryUrl, url.PathEscape(version)))
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	downloadUrl := response.Header.Get("X-Terraform-Get")
+	if downloadUrl == "" {
+		return "", fmt.Errorf("the registry did not return a download URL")
+	}
+
+	// The registry protocol allows a download URL relative to the request.
+	base, err := url.Parse(registryUrl + "/")
+	if err != nil {
+		return downloadUrl, nil
+	}
+	resolved, err := base.Parse(downloadUrl)
+	if err != nil {
+		return downloadUrl, nil
+	}
+
+	return resolved.String(), nil
+}
+
+func moduleRegistryRequest(httpClient *http.Client, token string, requestUrl string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error executing request: %w", err)
+	}
+
+	if response.StatusCode >= 300 {
+		defer response.Body.Close()
+		body, _ := io.ReadAll(response.Body)
+		return nil, fmt.Errorf("status %s: %s", response.Status, string(body))
+	}
+
+	return response, nil
+}
+
+// findModuleVersion returns the version as listed by the registry, which
+// may strip the leading v of tags like v1.2.0. Modules of a monorepo list
+// the tags starting with their tag prefix, the version may be given with or
+// without the prefix, like networking/v1.2.0 or v1.2.0.
+func findModuleVersion(versions []string, version string, tagPrefix string) (string, bool) {
+	normalize := func(value string) string {
+		return strings.TrimPrefix(strings.TrimPrefix(value, tagPrefix), "v")
+	}
+
+	for _, candidate := range versions {
+		if normalize(candidate) == normalize(version) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// latestModuleVersion returns the highest semver version as listed by the
+// registry, prereleases only count when there is no release. The tag prefix
+// and leading v are ignored for the comparison, versions that are not
+// semver are skipped.
+func latestModuleVersion(versions []string, tagPrefix string) string {
+	var latest, latestRelease string
+	var latestVersion, latestReleaseVersion *version.Version
+
+	for _, candidate := range versions {
+		parsed, err := version.NewVersion(strings.TrimPrefix(strings.TrimPrefix(candidate, tagPrefix), "v"))
+		if err != nil {
+			continue
+		}
+
+		if latestVersion == nil || parsed.GreaterThan(latestVersion) {
+			latest, latestVersion = candidate, parsed
+		}
+		if parsed.Prerelease() == "" && (latestReleaseVersion == nil || parsed.GreaterThan(latestReleaseVersion)) {
+			latestRelease, latestReleaseVersion = candidate, parsed
+		}
+	}
+
+	if latestRelease != "" {
+		return latestRelease
+	}
+	return latest
+}
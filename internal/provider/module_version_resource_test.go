@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestFindModuleVersion(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		versions  []string
+		version   string
+		tagPrefix string
+		found     string
+	}{
+		{[]string{"1.0.0", "1.2.0"}, "v1.2.0", "", "1.2.0"},
+		{[]string{"v1.0.0", "v1.2.0"}, "1.2.0", "", "v1.2.0"},
+		{[]string{"1.0.0"}, "v1.2.0", "", ""},
+		{[]string{"vpc/v1.0.0", "vpc/v1.2.0"}, "v1.2.0", "vpc/", "vpc/v1.2.0"},
+		{[]string{"vpc/v1.2.0"}, "vpc/v1.2.0", "vpc/", "vpc/v1.2.0"},
+		{[]string{"vpc/v1.2.0"}, "vpc/1.2.0", "vpc/", "vpc/v1.2.0"},
+		{[]string{"1.2.0"}, "vpc/v1.2.0", "vpc/", "1.2.0"},
+		{[]string{"eks/v1.2.0"}, "v1.2.0", "vpc/", ""},
+		{[]string{"vpc/v1.2.0"}, "v1.2.0", "", ""},
+	} {
+		found, ok := findModuleVersion(test.versions, test.version, test.tagPrefix)
+		if found != test.found || ok != (test.found != "") {
+			t.Errorf("findModuleVersion(%v, %s, %q) = %s %t, expected %s", test.versions, test.version, test.tagPrefix, found, ok, test.found)
+		}
+	}
+}
+
+func TestModuleVersionTagPrefix(t *testing.T) {
+	t.Parallel()
+
+	api, terrakube := newModuleAPI(t)
+	api.put("/api/v1/organization/o1/module/m1", "module", map[string]any{"name": "vpc", "provider": "aws", "source": "https://github.com/platform/modules.git", "folder": "/vpc/", "tagPrefix": "vpc/"})
+	const registryPath = "/terraform/modules/v1/platform/vpc/aws/"
+	var downloads []string
+	api.handle = func(w http.ResponseWriter, r *http.Request) bool {
+		switch {
+		case r.URL.Path == registryPath+"versions":
+			json.NewEncoder(w).Encode(map[string]any{"modules": []any{map[string]any{"versions": []any{
+				map[string]any{"version": "vpc/v1.0.0"},
+				map[string]any{"version": "vpc/v1.2.0"},
+			}}}})
+		case strings.HasPrefix(r.URL.Path, registryPath) && strings.HasSuffix(r.URL.Path, "/download"):
+			downloads = append(downloads, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, registryPath), "/download"))
+			w.Header().Set("X-Terraform-Get", "/archives/vpc.tar.gz")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			return false
+		}
+		return true
+	}
+
+	config := terrakube.object("terrakube_module_version", map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+		"module_id":       tftypes.NewValue(tftypes.String, "m1"),
+		"version":         tftypes.NewValue(tftypes.String, "v1.2.0"),
+	})
+	state, diagnostics := terrakube.apply("terrakube_module_version", terrakube.null("terrakube_module_version"), config)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(downloads) != 1 || downloads[0] != "vpc/v1.2.0" {
+		t.Errorf("the prefixed registry version should be published, got %v", downloads)
+	}
+	if version := stringAttribute(t, state, "version"); version != "v1.2.0" {
+		t.Errorf("the configured version should be kept, got %s", version)
+	}
+
+	state, diagnostics = terrakube.read("terrakube_module_version", state)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if state.IsNull() {
+		t.Errorf("the prefixed version should still be found on refresh")
+	}
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"terraform-provider-terrakube/internal/client"
+	"time"
+
+	"github.com/google/jsonapi"
+)
+
+const organizationCacheTTL = 5 * time.Minute
+
+// organizationCache remembers the organization name and id pairs resolved
+// by the provider. Resources and data sources run concurrently, so every
+// access goes through the RWMutex. Entries expire after the TTL and are
+// invalidated when the provider renames or deletes the organization.
+type organizationCache struct {
+	mu     sync.RWMutex
+	ttl    time.Duration
+	byName map[string]organizationCacheEntry
+	byId   map[string]organizationCacheEntry
+}
+
+type organizationCacheEntry struct {
+	id            string
+	name          string
+	executionMode string
+	expires       time.Time
+}
+
+func newOrganizationCache(ttl time.Duration) *organizationCache {
+	return &organizationCache{
+		ttl:    ttl,
+		byName: map[string]organizationCacheEntry{},
+		byId:   map[string]organizationCacheEntry{},
+	}
+}
+
+// idByName returns the id of the organization, false when it is not cached
+// or expired.
+func (c *organizationCache) idByName(name string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.byName[name]
+	if !ok || time.Now().After(entry.expires) {
+		return "", false
+	}
+	return entry.id, true
+}
+
+// nameById returns the name of the organization, false when it is not
+// cached or expired.
+func (c *organizationCache) nameById(id string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.byId[id]
+	if !ok || time.Now().After(entry.expires) {
+		return "", false
+	}
+	return entry.name, true
+}
+
+// executionModeById returns the execution mode of the organization, false
+// when it is not cached or expired.
+func (c *organizationCache) executionModeById(id string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.byId[id]
+	if !ok || entry.executionMode == "" || time.Now().After(entry.expires) {
+		return "", false
+	}
+	return entry.executionMode, true
+}
+
+func (c *organizationCache) put(id string, name string) {
+	c.putWithExecutionMode(id, name, "")
+}
+
+// putWithExecutionMode caches the organization with its execution mode, an
+// empty mode is fetched again when needed.
+func (c *organizationCache) putWithExecutionMode(id string, name string, executionMode string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if previous, ok := c.byId[id]; ok {
+		delete(c.byName, previous.name)
+	}
+	// A name taken over by another organization, after a rename or a
+	// delete, must not resolve the old id anymore.
+	if previous, ok := c.byName[name]; ok && previous.id != id {
+		delete(c.byId, previous.id)
+	}
+
+	entry := organizationCacheEntry{id: id, name: name, executionMode: executionMode, expires: time.Now().Add(c.ttl)}
+	c.byName[name] = entry
+	c.byId[id] = entry
+}
+
+// invalidate removes the organization from the cache.
+func (c *organizationCache) invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.byId[id]; ok {
+		delete(c.byName, entry.name)
+		delete(c.byId, id)
+	}
+}
+
+// cachedOrganizationName returns the name of the organization, fetching it
+// when it is not cached.
+func cachedOrganizationName(organizations *organizationCache, httpClient *http.Client, endpoint string, token string, organizationId string) (string, error) {
+	if name, ok := organizations.nameById(organizationId); ok {
+		return name, nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s", endpoint, organizationId), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		body, _ := io.ReadAll(response.Body)
+		return "", fmt.Errorf("status %s: %s", response.Status, string(body))
+	}
+
+	organization := &client.OrganizationEntity{}
+	if err := jsonapi.UnmarshalPayload(response.Body, organization); err != nil {
+		return "", fmt.Errorf("error unmarshal payload response: %w", err)
+	}
+	if organization.Name == "" {
+		return "", fmt.Errorf("organization %s has no name", organizationId)
+	}
+
+	organizations.put(organization.ID, organization.Name)
+
+	return organization.Name, nil
+}
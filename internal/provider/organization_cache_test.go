@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"terraform-provider-terrakube/internal/client"
+	"testing"
+	"time"
+)
+
+func TestOrganizationCacheConcurrent(t *testing.T) {
+	t.Parallel()
+
+	cache := newOrganizationCache(time.Minute)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 50; worker++ {
+		worker := worker
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				id := fmt.Sprintf("o%d", (worker+i)%10)
+				name := fmt.Sprintf("organization-%d", (worker+i)%10)
+				switch i % 5 {
+				case 0:
+					cache.put(id, name)
+				case 1:
+					cache.putWithExecutionMode(id, name, "remote")
+				case 2:
+					if cached, ok := cache.idByName(name); ok && cached != id {
+						t.Errorf("name %s resolved to %s, expected %s", name, cached, id)
+					}
+				case 3:
+					if cached, ok := cache.nameById(id); ok && cached != name {
+						t.Errorf("id %s resolved to %s, expected %s", id, cached, name)
+					}
+					cache.executionModeById(id)
+				case 4:
+					cache.invalidate(id)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCachedOrganizationNameConcurrent(t *testing.T) {
+	t.Parallel()
+
+	var served atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served.Add(1)
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		fmt.Fprintf(w, `{"data":{"type":"organization","id":%q,"attributes":{"name":"name-%s"}}}`, id, id)
+	}))
+	defer server.Close()
+
+	metrics := client.NewMetrics()
+	httpClient := client.NewHttpClient(client.HttpClientOptions{Metrics: metrics})
+	cache := newOrganizationCache(time.Minute)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 50; worker++ {
+		worker := worker
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				id := fmt.Sprintf("o%d", (worker+i)%10)
+				name, err := cachedOrganizationName(cache, httpClient, server.URL, "token", id)
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+					return
+				}
+				if name != "name-"+id {
+					t.Errorf("organization %s resolved to %s", id, name)
+				}
+				if i%4 == 0 {
+					cache.invalidate(id)
+				}
+				metrics.Summary()
+			}
+		}()
+	}
+	wg.Wait()
+
+	summary := metrics.Summary()
+	if summary.TotalRequests != int(served.Load()) {
+		t.Errorf("metrics counted %d requests, the server answered %d", summary.TotalRequests, served.Load())
+	}
+	if len(summary.ErrorsByStatus) != 0 {
+		t.Errorf("unexpected errors: %v", summary.ErrorsByStatus)
+	}
+}
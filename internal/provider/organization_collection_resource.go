@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
 	"io"
@@ -98,17 +97,7 @@ func (r *CollectionResource) Configure(ctx context.Context, req resource.Configu
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -141,7 +130,7 @@ func (r *CollectionResource) Create(ctx context.Context, req resource.CreateRequ
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": strings.NewReader(out.String())})
 
-	collectionRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/collection", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	collectionRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/collection", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
 	collectionRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -165,7 +154,7 @@ func (r *CollectionResource) Create(ctx context.Context, req resource.CreateRequ
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), newCollection)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(collectionResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, collectionResponse.Status)))
 		return
 	}
 
@@ -213,7 +202,7 @@ func (r *CollectionResource) Read(ctx context.Context, req resource.ReadRequest,
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), collection)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(collectionResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, collectionResponse.Status)))
 		return
 	}
 
@@ -295,7 +284,7 @@ func (r *CollectionResource) Update(ctx context.Context, req resource.UpdateRequ
 
 	bodyResponse, err = io.ReadAll(collectionResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading collection resource response body", fmt.Sprintf("Error reading collection resource response body: %s", err))
+		resp.Diagnostics.AddError("Error reading collection resource response body", responseErrorDetail(collectionResponse, fmt.Sprintf("Error reading collection resource response body, error: %s, response status: %s", err, collectionResponse.Status)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -304,7 +293,7 @@ func (r *CollectionResource) Update(ctx context.Context, req resource.UpdateRequ
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), collection)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(collectionResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, collectionResponse.Status)))
 		return
 	}
 
@@ -326,18 +315,25 @@ func (r *CollectionResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	reqOrg, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating collection resource request", fmt.Sprintf("Error creating collection resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(reqOrg)
+	deleteResponse, err := r.client.Do(reqOrg)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing collection resource request", fmt.Sprintf("Error executing collection resource request: %s", err))
 		return
 	}
+	defer deleteResponse.Body.Close()
+
+	if deleteResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(deleteResponse.Body)
+		resp.Diagnostics.AddError("Error executing collection resource request", responseErrorDetail(deleteResponse, fmt.Sprintf("Error executing collection resource request, response status: %s, response body: %s", deleteResponse.Status, bodyResponse)))
+		return
+	}
 }
 
 func (r *CollectionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
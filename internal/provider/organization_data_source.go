@@ -2,36 +2,39 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
-	"io"
 	"net/http"
 	"reflect"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 
-	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
-	_ datasource.DataSource              = &OrganizationDataSource{}
-	_ datasource.DataSourceWithConfigure = &OrganizationDataSource{}
+	_ datasource.DataSource                     = &OrganizationDataSource{}
+	_ datasource.DataSourceWithConfigure        = &OrganizationDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &OrganizationDataSource{}
 )
 
 type OrganizationDataSourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Description   types.String `tfsdk:"description"`
+	ExecutionMode types.String `tfsdk:"execution_mode"`
+	AllowMissing  types.Bool   `tfsdk:"allow_missing"`
+	Found         types.Bool   `tfsdk:"found"`
 }
 
 type OrganizationDataSource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client        *http.Client
+	endpoint      string
+	token         string
+	organizations *organizationCache
 }
 
 func NewOrganizationDataSource() datasource.DataSource {
@@ -53,19 +56,10 @@ func (d *OrganizationDataSource) Configure(ctx context.Context, req datasource.C
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			d.client = &http.Client{Transport: customTransport}
-		} else {
-			d.client = &http.Client{}
-		}
-	} else {
-		d.client = &http.Client{}
-	}
+	d.client = providerData.HttpClient
 	d.endpoint = providerData.Endpoint
 	d.token = providerData.Token
+	d.organizations = providerData.Organizations
 
 	ctx = tflog.SetField(ctx, "endpoint", d.endpoint)
 	ctx = tflog.SetField(ctx, "token", d.token)
@@ -80,60 +74,84 @@ func (d *OrganizationDataSource) Metadata(_ context.Context, req datasource.Meta
 func (d *OrganizationDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
-				Computed:    true,
-				Description: "Organization Id",
-			},
-			"name": schema.StringAttribute{
-				Required:    true,
-				Description: "Organization Name",
-			},
+			"allow_missing": allowMissingSchema(),
+			"found":         foundSchema(),
+			"id":            idOrNameSchema("Organization Id"),
+			"name":          idOrNameSchema("Organization Name"),
 			"description": schema.StringAttribute{
 				Computed:    true,
 				Description: "Organization description information",
 			},
+			"execution_mode": schema.StringAttribute{
+				Computed:    true,
+				Description: "Default execution mode of the workspaces of the organization, `remote` or `local`",
+			},
 		},
 	}
 }
 
+func (d *OrganizationDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return idOrNameValidators()
+}
+
 func (d *OrganizationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state OrganizationDataSourceModel
 
-	req.Config.Get(ctx, &state)
-
-	reqOrg, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization?filter[organization]=name==%s", d.endpoint, state.Name.ValueString()), nil)
-	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
-	reqOrg.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		tflog.Error(ctx, "Error creating organization datasource request")
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	resOrg, err := d.client.Do(reqOrg)
+	body, err := lookupBody(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/organization", d.endpoint), "filter[organization]", state.ID, state.Name)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error executing organization datasource request, response status: %s, response body: %s, error: %s", resOrg.Status, resOrg.Body, err))
+		resp.Diagnostics.AddError("Error executing organization datasource request", apiErrorDetail(err, fmt.Sprintf("Error executing organization datasource request: %s", err)))
+		return
 	}
 
-	body, err := io.ReadAll(resOrg.Body)
+	items, err := unmarshalLookup(body, reflect.TypeOf(new(client.OrganizationEntity)))
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading organization response, response status: %s, response body: %s, error: %s", resOrg.Status, resOrg.Body, err))
+		resp.Diagnostics.AddError("Unable to unmarshal payload", fmt.Sprintf("Unable to unmarshal payload, response body: %s, error: %s", string(body), err))
+		return
 	}
 
-	var orgs []interface{}
-
-	orgs, err = jsonapi.UnmarshalManyPayload(strings.NewReader(string(body)), reflect.TypeOf(new(client.OrganizationEntity)))
+	// Deleted organizations are only disabled and keep their name.
+	var organizations []*client.OrganizationEntity
+	for _, item := range items {
+		if organization := item.(*client.OrganizationEntity); !organization.Disabled {
+			organizations = append(organizations, organization)
+		}
+	}
 
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to unmarshal payload", fmt.Sprintf("Unable to marshal payload, response status: %s, response body: %s, error: %s", resOrg.Status, resOrg.Body, err))
+	if len(organizations) > 1 {
+		ids := make([]string, 0, len(organizations))
+		for _, organization := range organizations {
+			ids = append(ids, organization.ID)
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Ambiguous organization name",
+			fmt.Sprintf("%d organizations are named %q: %s. Set id to the right one instead of name.", len(organizations), state.Name.ValueString(), strings.Join(ids, ", ")),
+		)
 		return
 	}
 
-	for _, organization := range orgs {
-		data, _ := organization.(*client.OrganizationEntity)
-		state.ID = types.StringValue(data.ID)
-		state.Name = types.StringValue(data.Name)
-		state.Description = types.StringValue(data.Description)
+	state.Found = types.BoolValue(len(organizations) == 1)
+	if len(organizations) == 0 {
+		lookupIdOrNameNotFound(&resp.Diagnostics, state.AllowMissing, "organization", state.ID, state.Name)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
 	}
 
+	organization := organizations[0]
+	state.ID = types.StringValue(organization.ID)
+	state.Name = types.StringValue(organization.Name)
+	state.Description = types.StringValue(organization.Description)
+	state.ExecutionMode = types.StringValue(organization.ExecutionMode)
+	d.organizations.put(organization.ID, organization.Name)
+
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
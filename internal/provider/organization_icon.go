@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// organizationIconMaxSize keeps the icon small enough to be stored in the
+// organization entity and shown by the UI without slowing it down.
+const organizationIconMaxSize = 256 * 1024
+
+var organizationIconTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp", "image/svg+xml"}
+
+type organizationIconEntity struct {
+	ID   string `jsonapi:"primary,organization"`
+	Icon string `jsonapi:"attr,icon"`
+}
+
+type organizationIcon struct {
+	dataUrl string
+	sha256  string
+}
+
+// readOrganizationIcon validates the image and returns it as a data url with
+// the checksum of the file, used to upload it only when it changes.
+func readOrganizationIcon(iconPath string) (*organizationIcon, error) {
+	content, err := os.ReadFile(iconPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read icon file: %w", err)
+	}
+
+	if len(content) > organizationIconMaxSize {
+		return nil, fmt.Errorf("icon %s is %d bytes, the maximum size is %d bytes", iconPath, len(content), organizationIconMaxSize)
+	}
+
+	contentType := http.DetectContentType(content)
+	if strings.EqualFold(filepath.Ext(iconPath), ".svg") {
+		contentType = "image/svg+xml"
+	}
+
+	supported := false
+	for _, iconType := range organizationIconTypes {
+		supported = supported || contentType == iconType
+	}
+	if !supported {
+		return nil, fmt.Errorf("icon %s has type %s, supported types are %s", iconPath, contentType, strings.Join(organizationIconTypes, ", "))
+	}
+
+	checksum := sha256.Sum256(content)
+
+	return &organizationIcon{
+		dataUrl: fmt.Sprintf("data:%s;base64,%s", contentType, base64.StdEncoding.EncodeToString(content)),
+		sha256:  hex.EncodeToString(checksum[:]),
+	}, nil
+}
+
+// uploadOrganizationIcon sets the icon of the organization. It returns false
+// when the server does not know the icon attribute, older Terrakube versions
+// reject it with a client error.
+func uploadOrganizationIcon(ctx context.Context, httpClient *http.Client, endpoint string, token string, organizationId string, icon string) (bool, error) {
+	var out = new(bytes.Buffer)
+	if err := jsonapi.MarshalPayload(out, &organizationIconEntity{ID: organizationId, Icon: icon}); err != nil {
+		return false, fmt.Errorf("unable to marshal payload: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s", endpoint, organizationId), out)
+	if err != nil {
+		return false, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return false, fmt.Errorf("error executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(response.Body)
+	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(body)})
+
+	switch {
+	case response.StatusCode < 300:
+		return true, nil
+	case response.StatusCode == http.StatusBadRequest:
+		return false, nil
+	default:
+		return false, fmt.Errorf("icon upload failed with status %s: %s", response.Status, string(body))
+	}
+}
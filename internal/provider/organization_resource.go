@@ -4,17 +4,20 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
-	"crypto/tls"
 	"fmt"
 	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -25,18 +28,23 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &OrganizationResource{}
 var _ resource.ResourceWithImportState = &OrganizationResource{}
+var _ resource.ResourceWithModifyPlan = &OrganizationResource{}
 
 type OrganizationResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client        *http.Client
+	endpoint      string
+	token         string
+	organizations *organizationCache
+	warnings      *warningPolicy
 }
 
 type OrganizationResourceModel struct {
-	ID            types.String `tfsdk:"id"`
-	Name          types.String `tfsdk:"name"`
-	Description   types.String `tfsdk:"description"`
-	ExecutionMode types.String `tfsdk:"execution_mode"`
+	ID            types.String     `tfsdk:"id"`
+	Name          types.String     `tfsdk:"name"`
+	Description   descriptionValue `tfsdk:"description"`
+	ExecutionMode types.String     `tfsdk:"execution_mode"`
+	IconPath      types.String     `tfsdk:"icon_path"`
+	IconSha256    types.String     `tfsdk:"icon_sha256"`
 }
 
 func NewOrganizationResource() resource.Resource {
@@ -64,12 +72,26 @@ func (r *OrganizationResource) Schema(ctx context.Context, req resource.SchemaRe
 				Description: "Organization name",
 			},
 			"description": schema.StringAttribute{
+				CustomType:  descriptionType{},
 				Required:    true,
 				Description: "Organization description",
 			},
 			"execution_mode": schema.StringAttribute{
-				Required:    true,
-				Description: "Select default execution mode for the organization (remote or local)",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("remote"),
+				Description: "Select default execution mode for the organization (remote or local), default is `remote`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("remote", "local"),
+				},
+			},
+			"icon_path": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Path of an image shown as the organization icon in the Terrakube UI (%s, at most %d KiB). The image is only uploaded when its content changes.", strings.Join(organizationIconTypes, ", "), organizationIconMaxSize/1024),
+			},
+			"icon_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA256 checksum of the uploaded icon",
 			},
 		},
 	}
@@ -90,20 +112,12 @@ func (r *OrganizationResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.warnings = providerData.Warnings
+	r.organizations = providerData.Organizations
 
 	tflog.Debug(ctx, "Configuring Organization resource", map[string]any{"success": true})
 }
@@ -131,7 +145,7 @@ func (r *OrganizationResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	organizationRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization", r.endpoint), strings.NewReader(out.String()))
+	organizationRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization", r.endpoint), strings.NewReader(out.String()))
 	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -149,6 +163,11 @@ func (r *OrganizationResource) Create(ctx context.Context, req resource.CreateRe
 	if err != nil {
 		tflog.Error(ctx, "Error reading organization resource response")
 	}
+
+	if organizationResponse.StatusCode != http.StatusCreated && organizationResponse.StatusCode != http.StatusOK {
+		resp.Diagnostics.AddError("Error creating organization", responseErrorDetail(organizationResponse, fmt.Sprintf("Organization %q was not created, status %s: %s", plan.Name.ValueString(), organizationResponse.Status, string(bodyResponse))))
+		return
+	}
 	newOrganization := &client.OrganizationEntity{}
 
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), newOrganization)
@@ -162,8 +181,13 @@ func (r *OrganizationResource) Create(ctx context.Context, req resource.CreateRe
 
 	plan.ID = types.StringValue(newOrganization.ID)
 	plan.Name = types.StringValue(newOrganization.Name)
-	plan.Description = types.StringValue(newOrganization.Description)
+	plan.Description = newDescriptionValue(newOrganization.Description)
 	plan.ExecutionMode = types.StringValue(newOrganization.ExecutionMode)
+	r.organizations.put(newOrganization.ID, newOrganization.Name)
+
+	if !plan.IconPath.IsNull() {
+		r.uploadIcon(ctx, plan.ID.ValueString(), plan.IconPath.ValueString(), &resp.Diagnostics)
+	}
 
 	tflog.Info(ctx, "Organization Resource Created", map[string]any{"success": true})
 
@@ -192,6 +216,13 @@ func (r *OrganizationResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
+	if organizationResponse.StatusCode == http.StatusNotFound {
+		tflog.Warn(ctx, "Organization not found, removing it from state", map[string]any{"id": state.ID.ValueString()})
+		r.organizations.invalidate(state.ID.ValueString())
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
 	bodyResponse, err := io.ReadAll(organizationResponse.Body)
 	if err != nil {
 		tflog.Error(ctx, "Error reading organization resource response")
@@ -206,9 +237,17 @@ func (r *OrganizationResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+	// Organizations are deleted by disabling them, a disabled organization
+	// is gone for Terraform.
+	if organization.Disabled {
+		tflog.Warn(ctx, "Organization disabled, removing it from state", map[string]any{"id": state.ID.ValueString()})
+		r.organizations.invalidate(state.ID.ValueString())
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
-	state.Description = types.StringValue(organization.Description)
+	state.Name = types.StringValue(organization.Name)
+	state.Description = newDescriptionValue(organization.Description)
 	state.ExecutionMode = types.StringValue(organization.ExecutionMode)
 	state.ID = types.StringValue(organization.ID)
 
@@ -299,8 +338,13 @@ func (r *OrganizationResource) Update(ctx context.Context, req resource.UpdateRe
 
 	plan.ID = types.StringValue(state.ID.ValueString())
 	plan.Name = types.StringValue(organization.Name)
-	plan.Description = types.StringValue(organization.Description)
+	plan.Description = newDescriptionValue(organization.Description)
 	plan.ExecutionMode = types.StringValue(organization.ExecutionMode)
+	r.organizations.put(state.ID.ValueString(), organization.Name)
+
+	if !plan.IconPath.IsNull() && !plan.IconSha256.Equal(state.IconSha256) {
+		r.uploadIcon(ctx, state.ID.ValueString(), plan.IconPath.ValueString(), &resp.Diagnostics)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -365,9 +409,59 @@ func (r *OrganizationResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
+	r.organizations.invalidate(data.ID.ValueString())
+
 	tflog.Info(ctx, "Delete Organization response code: "+strconv.Itoa(organizationResponse.StatusCode))
 }
 
+func (r *OrganizationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var iconPath types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("icon_path"), &iconPath)...)
+	if resp.Diagnostics.HasError() || iconPath.IsUnknown() {
+		return
+	}
+
+	if iconPath.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("icon_sha256"), types.StringNull())...)
+		return
+	}
+
+	icon, err := readOrganizationIcon(iconPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("icon_path"), "Invalid organization icon", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("icon_sha256"), icon.sha256)...)
+}
+
 func (r *OrganizationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+func (r *OrganizationResource) uploadIcon(ctx context.Context, organizationId string, iconPath string, diags *diag.Diagnostics) {
+	icon, err := readOrganizationIcon(iconPath)
+	if err != nil {
+		diags.AddAttributeError(path.Root("icon_path"), "Invalid organization icon", err.Error())
+		return
+	}
+
+	supported, err := uploadOrganizationIcon(ctx, r.client, r.endpoint, r.token, organizationId, icon.dataUrl)
+	if err != nil {
+		diags.AddAttributeError(path.Root("icon_path"), "Error uploading organization icon", apiErrorDetail(err, fmt.Sprintf("Error uploading organization icon: %s", err)))
+		return
+	}
+
+	if !supported {
+		r.warnings.addAttribute(
+			diags,
+			path.Root("icon_path"),
+			"Organization icon not supported",
+			"The Terrakube API rejected the icon attribute, this version does not support organization icons. The icon is skipped and uploaded again only when the file changes.",
+		)
+	}
+}
@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"sync"
+	"terraform-provider-terrakube/internal/client"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// organizationSummaryParallelism bounds the workspaces whose last job is
+// read at the same time.
+const organizationSummaryParallelism = 4
+
+var (
+	_ datasource.DataSource              = &OrganizationSummaryDataSource{}
+	_ datasource.DataSourceWithConfigure = &OrganizationSummaryDataSource{}
+)
+
+type OrganizationSummaryDataSourceModel struct {
+	OrganizationId        types.String `tfsdk:"organization_id"`
+	IncludeJobStatus      types.Bool   `tfsdk:"include_job_status"`
+	WorkspaceCount        types.Int64  `tfsdk:"workspace_count"`
+	FailingWorkspaceCount types.Int64  `tfsdk:"failing_workspace_count"`
+	ModuleCount           types.Int64  `tfsdk:"module_count"`
+	TeamCount             types.Int64  `tfsdk:"team_count"`
+	GeneratedAt           types.String `tfsdk:"generated_at"`
+}
+
+type OrganizationSummaryDataSource struct {
+	client    *http.Client
+	endpoint  string
+	token     string
+	summaries *organizationSummaryCache
+}
+
+func NewOrganizationSummaryDataSource() datasource.DataSource {
+	return &OrganizationSummaryDataSource{}
+}
+
+func (d *OrganizationSummaryDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Organization Summary Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+	d.summaries = providerData.OrganizationSummaries
+
+	tflog.Info(ctx, "Creating Organization Summary datasource")
+}
+
+func (d *OrganizationSummaryDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_organization_summary"
+}
+
+func (d *OrganizationSummaryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Count the workspaces, modules and teams of an organization. " +
+			"Listing the workspaces, modules and teams takes one request per 100 entries of each. " +
+			"With include_job_status every workspace also costs one request to read its last job, an organization with 500 workspaces takes about 510 requests. " +
+			"The summary is cached by the provider for 5 minutes, so reading it several times in a run costs the requests once.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+			},
+			"include_job_status": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Read the last job of every workspace to count the failing ones, default is `false`. This costs one request per workspace.",
+			},
+			"workspace_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of workspaces, deleted workspaces are not counted",
+			},
+			"failing_workspace_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of workspaces whose last job failed, null unless include_job_status is set",
+			},
+			"module_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of modules",
+			},
+			"team_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of teams",
+			},
+			"generated_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "RFC3339 timestamp of when the counts were read from the API",
+			},
+		},
+	}
+}
+
+func (d *OrganizationSummaryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state OrganizationSummaryDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organizationId := state.OrganizationId.ValueString()
+	includeJobStatus := state.IncludeJobStatus.ValueBool()
+
+	summary, ok := d.summaries.get(organizationId, includeJobStatus)
+	if !ok {
+		var err error
+		summary, err = d.summarize(ctx, organizationId, includeJobStatus)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading organization summary", apiErrorDetail(err, fmt.Sprintf("Error reading the summary of organization %s: %s", organizationId, err)))
+			return
+		}
+		d.summaries.put(organizationId, includeJobStatus, summary)
+	}
+
+	state.WorkspaceCount = types.Int64Value(summary.workspaces)
+	state.ModuleCount = types.Int64Value(summary.modules)
+	state.TeamCount = types.Int64Value(summary.teams)
+	state.FailingWorkspaceCount = types.Int64Null()
+	if includeJobStatus {
+		state.FailingWorkspaceCount = types.Int64Value(summary.failingWorkspaces)
+	}
+	state.GeneratedAt = types.StringValue(summary.generatedAt.UTC().Format(time.RFC3339))
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (d *OrganizationSummaryDataSource) summarize(ctx context.Context, organizationId string, includeJobStatus bool) (organizationSummary, error) {
+	summary := organizationSummary{generatedAt: time.Now()}
+	organizationUrl := fmt.Sprintf("%s/api/v1/organization/%s", d.endpoint, organizationId)
+
+	items, err := fetchAllPages(ctx, d.client, d.token, organizationUrl+"/workspace", reflect.TypeOf(new(client.WorkspaceEntity)))
+	if err != nil {
+		return summary, fmt.Errorf("listing workspaces: %w", err)
+	}
+	var workspaces []*client.WorkspaceEntity
+	for _, item := range items {
+		if workspace := item.(*client.WorkspaceEntity); !workspace.Deleted {
+			workspaces = append(workspaces, workspace)
+		}
+	}
+	summary.workspaces = int64(len(workspaces))
+
+	modules, err := fetchAllPages(ctx, d.client, d.token, organizationUrl+"/module", reflect.TypeOf(new(client.ModuleEntity)))
+	if err != nil {
+		return summary, fmt.Errorf("listing modules: %w", err)
+	}
+	summary.modules = int64(len(modules))
+
+	teams, err := fetchAllPages(ctx, d.client, d.token, organizationUrl+"/team", reflect.TypeOf(new(client.TeamEntity)))
+	if err != nil {
+		return summary, fmt.Errorf("listing teams: %w", err)
+	}
+	summary.teams = int64(len(teams))
+
+	if !includeJobStatus {
+		return summary, nil
+	}
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		errs  []error
+		slots = make(chan struct{}, organizationSummaryParallelism)
+	)
+
+	for _, item := range workspaces {
+		workspace := item
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			status, err := d.lastJobStatus(ctx, organizationUrl, workspace.ID)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("workspace %s (%s): %w", workspace.Name, workspace.ID, err))
+				return
+			}
+			if status == "failed" {
+				summary.failingWorkspaces++
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+		return summary, fmt.Errorf("reading the last job of %d of %d workspaces:\n%w", len(errs), len(workspaces), errors.Join(errs...))
+	}
+
+	tflog.Debug(ctx, "Organization summary read", map[string]any{"organization": organizationId, "workspaces": len(workspaces)})
+	return summary, nil
+}
+
+// lastJobStatus returns the status of the newest job of the workspace, empty
+// when the workspace never ran a job. Job ids are sequential, so the newest
+// job has the highest id.
+func (d *OrganizationSummaryDataSource) lastJobStatus(ctx context.Context, organizationUrl string, workspaceId string) (string, error) {
+	query := url.Values{}
+	query.Set("filter[job]", fmt.Sprintf("workspace.id=='%s'", workspaceId))
+	query.Set("sort", "-id")
+	query.Set("page[size]", "1")
+
+	jobs, err := fetchPage(ctx, d.client, d.token, organizationUrl+"/job?"+query.Encode(), reflect.TypeOf(new(client.JobEntity)))
+	if err != nil || len(jobs) == 0 {
+		return "", err
+	}
+	return jobs[0].(*client.JobEntity).Status, nil
+}
+
+type organizationSummary struct {
+	workspaces        int64
+	failingWorkspaces int64
+	modules           int64
+	teams             int64
+	generatedAt       time.Time
+}
+
+// organizationSummaryCache keeps the summaries read during the run, several
+// reports in a configuration read the organization once.
+type organizationSummaryCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	summaries map[string]organizationSummary
+}
+
+func newOrganizationSummaryCache(ttl time.Duration) *organizationSummaryCache {
+	return &organizationSummaryCache{ttl: ttl, summaries: map[string]organizationSummary{}}
+}
+
+// get returns a cached summary. A summary with the job status also answers
+// a read without it.
+func (c *organizationSummaryCache) get(organizationId string, includeJobStatus bool) (organizationSummary, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, withJobs := range []bool{true, includeJobStatus} {
+		summary, ok := c.summaries[organizationSummaryKey(organizationId, withJobs)]
+		if ok && time.Since(summary.generatedAt) < c.ttl {
+			return summary, true
+		}
+	}
+	return organizationSummary{}, false
+}
+
+func (c *organizationSummaryCache) put(organizationId string, includeJobStatus bool, summary organizationSummary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.summaries[organizationSummaryKey(organizationId, includeJobStatus)] = summary
+}
+
+func organizationSummaryKey(organizationId string, includeJobStatus bool) string {
+	return fmt.Sprintf("%s,%t", organizationId, includeJobStatus)
+}
@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -53,17 +52,7 @@ func (d *OrganizationTagDataSource) Configure(ctx context.Context, req datasourc
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			d.client = &http.Client{Transport: customTransport}
-		} else {
-			d.client = &http.Client{}
-		}
-	} else {
-		d.client = &http.Client{}
-	}
+	d.client = providerData.HttpClient
 	d.endpoint = providerData.Endpoint
 	d.token = providerData.Token
 
@@ -110,7 +99,8 @@ func (d *OrganizationTagDataSource) Read(ctx context.Context, req datasource.Rea
 
 	resOrgTag, err := d.client.Do(reqOrgTag)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error executing organization tag datasource request, error: %s, response status: %s", err, resOrgTag.Status))
+		resp.Diagnostics.AddError("Error executing organization tag datasource request", fmt.Sprintf("Error executing organization tag datasource request: %s", err))
+		return
 	}
 
 	body, err := io.ReadAll(resOrgTag.Body)
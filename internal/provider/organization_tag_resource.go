@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -84,17 +83,7 @@ func (r *OrganizationTagResource) Configure(ctx context.Context, req resource.Co
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -123,7 +112,7 @@ func (r *OrganizationTagResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
-	organizationTagRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/tag", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	organizationTagRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/tag", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
 	organizationTagRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationTagRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -133,7 +122,7 @@ func (r *OrganizationTagResource) Create(ctx context.Context, req resource.Creat
 
 	organizationTagResponse, err := r.client.Do(organizationTagRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request: %s", err))
 		return
 	}
 
@@ -146,7 +135,7 @@ func (r *OrganizationTagResource) Create(ctx context.Context, req resource.Creat
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), newOrganizationTag)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(organizationTagResponse, fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err)))
 		return
 	}
 
@@ -178,7 +167,7 @@ func (r *OrganizationTagResource) Read(ctx context.Context, req resource.ReadReq
 
 	organizationTagResponse, err := r.client.Do(organizationTagRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request: %s", err))
 		return
 	}
 
@@ -192,7 +181,7 @@ func (r *OrganizationTagResource) Read(ctx context.Context, req resource.ReadReq
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), organizationTag)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(organizationTagResponse, fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err)))
 		return
 	}
 
@@ -243,7 +232,7 @@ func (r *OrganizationTagResource) Update(ctx context.Context, req resource.Updat
 
 	organizationTagResponse, err := r.client.Do(organizationTagRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request: %s", err))
 		return
 	}
 
@@ -264,13 +253,13 @@ func (r *OrganizationTagResource) Update(ctx context.Context, req resource.Updat
 
 	organizationTagResponse, err = r.client.Do(organizationTagRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request: %s", err))
 		return
 	}
 
 	bodyResponse, err = io.ReadAll(organizationTagResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading organization tag resource response body", fmt.Sprintf("Error reading organization tag resource response body, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error reading organization tag resource response body", responseErrorDetail(organizationTagResponse, fmt.Sprintf("Error reading organization tag resource response body, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -299,7 +288,7 @@ func (r *OrganizationTagResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/tag/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	reqOrg, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/tag/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating organization tag resource request", fmt.Sprintf("Error creating organization tag resource request: %s", err))
@@ -307,8 +296,15 @@ func (r *OrganizationTagResource) Delete(ctx context.Context, req resource.Delet
 	}
 
 	organizationTagResponse, err := r.client.Do(reqOrg)
-	if err != nil || organizationTagResponse.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request: %s", err))
+		return
+	}
+	defer organizationTagResponse.Body.Close()
+
+	if organizationTagResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(organizationTagResponse.Body)
+		resp.Diagnostics.AddError("Error executing organization tag resource request", responseErrorDetail(organizationTagResponse, fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s", organizationTagResponse.Status, bodyResponse)))
 		return
 	}
 }
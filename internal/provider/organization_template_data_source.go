@@ -2,7 +2,7 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
@@ -24,9 +24,15 @@ var (
 )
 
 type OrganizationTemplateDataSourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	Name           types.String `tfsdk:"name"`
-	OrganizationId types.String `tfsdk:"organization_id"`
+	ID                types.String      `tfsdk:"id"`
+	Name              types.String      `tfsdk:"name"`
+	OrganizationId    types.String      `tfsdk:"organization_id"`
+	Content           types.String      `tfsdk:"content"`
+	Parameters        map[string]string `tfsdk:"parameters"`
+	Rendered          types.String      `tfsdk:"rendered"`
+	MissingParameters []string          `tfsdk:"missing_parameters"`
+	AllowMissing      types.Bool        `tfsdk:"allow_missing"`
+	Found             types.Bool        `tfsdk:"found"`
 }
 
 type OrganizationTemplateDataSource struct {
@@ -54,17 +60,7 @@ func (d *OrganizationTemplateDataSource) Configure(ctx context.Context, req data
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			d.client = &http.Client{Transport: customTransport}
-		} else {
-			d.client = &http.Client{}
-		}
-	} else {
-		d.client = &http.Client{}
-	}
+	d.client = providerData.HttpClient
 	d.endpoint = providerData.Endpoint
 	d.token = providerData.Token
 
@@ -81,6 +77,8 @@ func (d *OrganizationTemplateDataSource) Metadata(_ context.Context, req datasou
 func (d *OrganizationTemplateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"allow_missing": allowMissingSchema(),
+			"found":         foundSchema(),
 			"id": schema.StringAttribute{
 				Computed:    true,
 				Description: "Id",
@@ -93,6 +91,24 @@ func (d *OrganizationTemplateDataSource) Schema(_ context.Context, _ datasource.
 				Required:    true,
 				Description: "Organization ID",
 			},
+			"content": schema.StringAttribute{
+				Computed:    true,
+				Description: "The content of the template as plain YAML",
+			},
+			"parameters": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Values substituted for the `${name}` placeholders of the content in `rendered`. Names are made of letters, digits, `_`, `.` and `-`, and `$${` renders a literal `${`. Anything else between `${` and its matching `}`, like the `${A:-${B}}` expansions of shell scripts, is kept verbatim.",
+			},
+			"rendered": schema.StringAttribute{
+				Computed:    true,
+				Description: "The content with `parameters` substituted, null when `parameters` is not set. It previews the substitution for policy checks, the placeholders read by the scripts of the template as environment variables are only resolved by Terrakube when the job runs.",
+			},
+			"missing_parameters": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Sorted names of the placeholders of the content without a value in `parameters`, kept verbatim in `rendered`. Null when `parameters` is not set.",
+			},
 		},
 	}
 }
@@ -102,7 +118,9 @@ func (d *OrganizationTemplateDataSource) Read(ctx context.Context, req datasourc
 
 	req.Config.Get(ctx, &state)
 
-	apiUrl := fmt.Sprintf("%s/api/v1/organization/%s/template?filter[template]=name=='%s'", d.endpoint, state.OrganizationId.ValueString(), url.PathEscape(state.Name.ValueString()))
+	query := url.Values{}
+	query.Set("filter[template]", "name=="+rsqlString(state.Name.ValueString()))
+	apiUrl := fmt.Sprintf("%s/api/v1/organization/%s/template?%s", d.endpoint, state.OrganizationId.ValueString(), query.Encode())
 	reqTemplate, err := http.NewRequest(http.MethodGet, apiUrl, nil)
 	reqTemplate.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
 	reqTemplate.Header.Add("Content-Type", "application/vnd.api+json")
@@ -112,7 +130,8 @@ func (d *OrganizationTemplateDataSource) Read(ctx context.Context, req datasourc
 
 	resTemplate, err := d.client.Do(reqTemplate)
 	if err != nil {
-		tflog.Error(ctx, "Error executing organization template datasource request")
+		resp.Diagnostics.AddError("Error executing organization template datasource request", fmt.Sprintf("Error executing organization template datasource request: %s", err))
+		return
 	}
 
 	body, err := io.ReadAll(resTemplate.Body)
@@ -125,7 +144,7 @@ func (d *OrganizationTemplateDataSource) Read(ctx context.Context, req datasourc
 	templates, err = jsonapi.UnmarshalManyPayload(strings.NewReader(string(body)), reflect.TypeOf(new(client.OrganizationTemplateEntity)))
 
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to unmarshal payload", fmt.Sprintf("Unable to marshal, error: %s, response status %s, response body %s", err, resTemplate.Status, string(body)))
+		resp.Diagnostics.AddError("Unable to unmarshal payload", responseErrorDetail(resTemplate, fmt.Sprintf("Unable to marshal, error: %s, response status %s, response body %s", err, resTemplate.Status, string(body))))
 		return
 	}
 
@@ -133,6 +152,27 @@ func (d *OrganizationTemplateDataSource) Read(ctx context.Context, req datasourc
 		data, _ := template.(*client.OrganizationTemplateEntity)
 		state.ID = types.StringValue(data.ID)
 		state.Name = types.StringValue(data.Name)
+
+		content, err := base64.StdEncoding.DecodeString(data.Content)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to decode template content", fmt.Sprintf("Unable to decode the content of template %s: %s", data.ID, err))
+			return
+		}
+		state.Content = types.StringValue(string(content))
+
+		if state.Parameters != nil {
+			rendered, missing := renderTemplateParameters(string(content), state.Parameters)
+			state.Rendered = types.StringValue(rendered)
+			state.MissingParameters = missing
+		}
+	}
+
+	state.Found = types.BoolValue(len(templates) > 0)
+	if len(templates) == 0 {
+		lookupNotFound(&resp.Diagnostics, state.AllowMissing, "template", state.Name.ValueString())
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
 	diags := resp.State.Set(ctx, &state)
@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -26,6 +25,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &OrganizationTemplateResource{}
 var _ resource.ResourceWithImportState = &OrganizationTemplateResource{}
+var _ resource.ResourceWithValidateConfig = &OrganizationTemplateResource{}
 
 type OrganizationTemplateResource struct {
 	client   *http.Client
@@ -34,12 +34,13 @@ type OrganizationTemplateResource struct {
 }
 
 type OrganizationTemplateResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	Name           types.String `tfsdk:"name"`
-	Description    types.String `tfsdk:"description"`
-	Version        types.String `tfsdk:"version"`
-	Content        types.String `tfsdk:"content"`
+	ID             types.String         `tfsdk:"id"`
+	OrganizationId types.String         `tfsdk:"organization_id"`
+	Name           types.String         `tfsdk:"name"`
+	Description    types.String         `tfsdk:"description"`
+	Version        types.String         `tfsdk:"version"`
+	Content        templateContentValue `tfsdk:"content"`
+	SkipValidation types.Bool           `tfsdk:"skip_validation"`
 }
 
 func NewOrganizationTemplateResource() resource.Resource {
@@ -81,12 +82,38 @@ func (r *OrganizationTemplateResource) Schema(ctx context.Context, req resource.
 			},
 			"content": schema.StringAttribute{
 				Required:    true,
-				Description: "The content of the template",
+				CustomType:  templateContentType{},
+				Description: "The content of the template as plain YAML, the provider handles the base64 encoding used by the API. Differences only in line endings or trailing whitespace are not reported as changes.",
+			},
+			"skip_validation": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Do not check the flow against the steps, keys and runtimes known by the provider, default is `false`. Needed for templates using features of a newer Terrakube version.",
 			},
 		},
 	}
 }
 
+// ValidateConfig checks the structure of the flow so a broken template is
+// reported by plan instead of by the next job using it.
+func (r *OrganizationTemplateResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config OrganizationTemplateResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.SkipValidation.ValueBool() || config.SkipValidation.IsUnknown() {
+		return
+	}
+	if config.Content.IsNull() || config.Content.IsUnknown() {
+		return
+	}
+
+	for _, problem := range validateTemplateContent(config.Content.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Invalid template flow",
+			fmt.Sprintf("%s. Set skip_validation = true if the template uses features this provider does not know yet.", problem),
+		)
+	}
+}
+
 func (r *OrganizationTemplateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -102,17 +129,7 @@ func (r *OrganizationTemplateResource) Configure(ctx context.Context, req resour
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -144,7 +161,7 @@ func (r *OrganizationTemplateResource) Create(ctx context.Context, req resource.
 		return
 	}
 
-	organizationTemplateRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/template", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	organizationTemplateRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/template", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
 	organizationTemplateRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationTemplateRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -154,7 +171,7 @@ func (r *OrganizationTemplateResource) Create(ctx context.Context, req resource.
 
 	organizationTemplateResponse, err := r.client.Do(organizationTemplateRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request: %s", err))
 		return
 	}
 
@@ -167,7 +184,7 @@ func (r *OrganizationTemplateResource) Create(ctx context.Context, req resource.
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), organizationTemplate)
 	tflog.Info(ctx, string(bodyResponse))
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(organizationTemplateResponse, fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err)))
 		return
 	}
 
@@ -182,7 +199,7 @@ func (r *OrganizationTemplateResource) Create(ctx context.Context, req resource.
 		resp.Diagnostics.AddError("Error decoding the content from Base64.", fmt.Sprintf("Error decode the tcl: %s", err))
 		return
 	}
-	plan.Content = types.StringValue(string(contentDecoded))
+	plan.Content = newTemplateContentValue(string(contentDecoded))
 
 	tflog.Info(ctx, "Organization Template Resource Created", map[string]any{"success": true})
 
@@ -207,7 +224,13 @@ func (r *OrganizationTemplateResource) Read(ctx context.Context, req resource.Re
 
 	organizationTemplateResponse, err := r.client.Do(organizationTemplateRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request: %s", err))
+		return
+	}
+
+	if organizationTemplateResponse.StatusCode == http.StatusNotFound {
+		tflog.Warn(ctx, "Organization template not found, removing it from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
@@ -235,7 +258,7 @@ func (r *OrganizationTemplateResource) Read(ctx context.Context, req resource.Re
 		resp.Diagnostics.AddError("Error decoding the content from Base64.", fmt.Sprintf("Error decode the tcl: %s", err))
 		return
 	}
-	state.Content = types.StringValue(string(contentDecoded))
+	state.Content = newTemplateContentValue(string(contentDecoded))
 	state.ID = types.StringValue(organizationTemplate.ID)
 
 	// Set refreshed state
@@ -286,7 +309,7 @@ func (r *OrganizationTemplateResource) Update(ctx context.Context, req resource.
 
 	organizationTemplateResponse, err := r.client.Do(organizationTemplateRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request: %s", err))
 		return
 	}
 
@@ -307,13 +330,13 @@ func (r *OrganizationTemplateResource) Update(ctx context.Context, req resource.
 
 	organizationTemplateResponse, err = r.client.Do(organizationTemplateRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request: %s", err))
 		return
 	}
 
 	bodyResponse, err = io.ReadAll(organizationTemplateResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading organization template resource response body", fmt.Sprintf("Error reading organization template resource response body, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error reading organization template resource response body", responseErrorDetail(organizationTemplateResponse, fmt.Sprintf("Error reading organization template resource response body, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -335,7 +358,7 @@ func (r *OrganizationTemplateResource) Update(ctx context.Context, req resource.
 		resp.Diagnostics.AddError("Error decoding the content from Base64.", fmt.Sprintf("Error decode the tcl: %s", err))
 		return
 	}
-	plan.Content = types.StringValue(string(contentDecoded))
+	plan.Content = newTemplateContentValue(string(contentDecoded))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -350,7 +373,7 @@ func (r *OrganizationTemplateResource) Delete(ctx context.Context, req resource.
 		return
 	}
 
-	organizationTemplateRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/template/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	organizationTemplateRequest, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/template/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
 	organizationTemplateRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating organization template resource request", fmt.Sprintf("Error creating organization template resource request: %s", err))
@@ -358,8 +381,15 @@ func (r *OrganizationTemplateResource) Delete(ctx context.Context, req resource.
 	}
 
 	organizationTemplateResponse, err := r.client.Do(organizationTemplateRequest)
-	if err != nil || organizationTemplateResponse.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request: %s", err))
+		return
+	}
+	defer organizationTemplateResponse.Body.Close()
+
+	if organizationTemplateResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(organizationTemplateResponse.Body)
+		resp.Diagnostics.AddError("Error executing organization template resource request", responseErrorDetail(organizationTemplateResponse, fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s", organizationTemplateResponse.Status, bodyResponse)))
 		return
 	}
 }
@@ -3,17 +3,20 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 
 	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -25,22 +28,25 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &OrganizationVariableResource{}
 var _ resource.ResourceWithImportState = &OrganizationVariableResource{}
+var _ resource.ResourceWithModifyPlan = &OrganizationVariableResource{}
 
 type OrganizationVariableResource struct {
 	client   *http.Client
 	endpoint string
 	token    string
+	warnings *warningPolicy
 }
 
 type OrganizationVariableResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	Key            types.String `tfsdk:"key"`
-	Value          types.String `tfsdk:"value"`
-	Description    types.String `tfsdk:"description"`
-	Category       types.String `tfsdk:"category"`
-	Sensitive      types.Bool   `tfsdk:"sensitive"`
-	Hcl            types.Bool   `tfsdk:"hcl"`
+	ID              types.String `tfsdk:"id"`
+	OrganizationId  types.String `tfsdk:"organization_id"`
+	Key             types.String `tfsdk:"key"`
+	Value           types.String `tfsdk:"value"`
+	Description     types.String `tfsdk:"description"`
+	Category        types.String `tfsdk:"category"`
+	Sensitive       types.Bool   `tfsdk:"sensitive"`
+	Hcl             types.Bool   `tfsdk:"hcl"`
+	WarnOnOverrides types.Bool   `tfsdk:"warn_on_overrides"`
 }
 
 func NewOrganizationVariableResource() resource.Resource {
@@ -69,11 +75,15 @@ func (r *OrganizationVariableResource) Schema(ctx context.Context, req resource.
 			},
 			"key": schema.StringAttribute{
 				Required:    true,
-				Description: "Variable key",
+				Description: "Variable key. Changing it forces a new variable.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"value": schema.StringAttribute{
 				Required:    true,
-				Description: "Variable value",
+				Sensitive:   true,
+				Description: "Variable value, always hidden in plans because the API never returns the value of sensitive variables",
 			},
 			"description": schema.StringAttribute{
 				Required:    true,
@@ -81,7 +91,13 @@ func (r *OrganizationVariableResource) Schema(ctx context.Context, req resource.
 			},
 			"category": schema.StringAttribute{
 				Required:    true,
-				Description: "Variable category (ENV or TERRAFORM). ENV variables are injected in workspace environment at runtime.",
+				Description: "Variable category (ENV or TERRAFORM). ENV variables are injected in workspace environment at runtime. Changing it forces a new variable.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("ENV", "TERRAFORM"),
+				},
 			},
 			"sensitive": schema.BoolAttribute{
 				Required:    true,
@@ -91,6 +107,12 @@ func (r *OrganizationVariableResource) Schema(ctx context.Context, req resource.
 				Required:    true,
 				Description: "Parse this field as HashiCorp Configuration Language (HCL). This allows you to interpolate values at runtime.",
 			},
+			"warn_on_overrides": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Warn during plan when a collection of the organization defines the same key and category, since the collection value takes precedence over the variable in the workspaces using the collection. Reads the items of every collection, default is `false`.",
+			},
 		},
 	}
 }
@@ -110,20 +132,11 @@ func (r *OrganizationVariableResource) Configure(ctx context.Context, req resour
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.warnings = providerData.Warnings
 
 	tflog.Debug(ctx, "Configuring Organization Variable resource", map[string]any{"success": true})
 }
@@ -154,7 +167,7 @@ func (r *OrganizationVariableResource) Create(ctx context.Context, req resource.
 		return
 	}
 
-	organizationVarRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/globalvar", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	organizationVarRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/globalvar", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
 	organizationVarRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationVarRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -177,13 +190,13 @@ func (r *OrganizationVariableResource) Create(ctx context.Context, req resource.
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), organizationVariable)
 	tflog.Info(ctx, string(bodyResponse))
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(organizationVarResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, organizationVarResponse.Status)))
 		return
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
-	if *organizationVariable.Sensitive {
+	if organizationVariable.Sensitive != nil && *organizationVariable.Sensitive {
 		tflog.Info(ctx, "Variable value is not included in response, setting values the same as the plan for sensitive=true...")
 		plan.Value = types.StringValue(plan.Value.ValueString())
 	} else {
@@ -194,7 +207,7 @@ func (r *OrganizationVariableResource) Create(ctx context.Context, req resource.
 	plan.Key = types.StringValue(organizationVariable.Key)
 	plan.Description = types.StringValue(organizationVariable.Description)
 	plan.Category = types.StringValue(organizationVariable.Category)
-	plan.Sensitive = types.BoolValue(*organizationVariable.Sensitive)
+	plan.Sensitive = types.BoolValue(organizationVariable.Sensitive != nil && *organizationVariable.Sensitive)
 	plan.Hcl = types.BoolValue(organizationVariable.Hcl)
 	plan.ID = types.StringValue(organizationVariable.ID)
 
@@ -225,6 +238,12 @@ func (r *OrganizationVariableResource) Read(ctx context.Context, req resource.Re
 		return
 	}
 
+	if organizationVariableResponse.StatusCode == http.StatusNotFound {
+		tflog.Warn(ctx, "Organization variable not found, removing it from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
 	bodyResponse, err := io.ReadAll(organizationVariableResponse.Body)
 	if err != nil {
 		tflog.Error(ctx, "Error reading organization variable resource response")
@@ -235,13 +254,13 @@ func (r *OrganizationVariableResource) Read(ctx context.Context, req resource.Re
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), organizationVariable)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(organizationVariableResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, organizationVariableResponse.Status)))
 		return
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
-	if *organizationVariable.Sensitive {
+	if organizationVariable.Sensitive != nil && *organizationVariable.Sensitive {
 		tflog.Info(ctx, "Variable value is not included in response, setting values the same as the current state value")
 		state.Value = types.StringValue(state.Value.ValueString())
 	} else {
@@ -252,10 +271,14 @@ func (r *OrganizationVariableResource) Read(ctx context.Context, req resource.Re
 	state.Key = types.StringValue(organizationVariable.Key)
 	state.Description = types.StringValue(organizationVariable.Description)
 	state.Category = types.StringValue(organizationVariable.Category)
-	state.Sensitive = types.BoolValue(*organizationVariable.Sensitive)
+	state.Sensitive = types.BoolValue(organizationVariable.Sensitive != nil && *organizationVariable.Sensitive)
 	state.Hcl = types.BoolValue(organizationVariable.Hcl)
 	state.ID = types.StringValue(organizationVariable.ID)
 
+	if state.WarnOnOverrides.IsNull() {
+		state.WarnOnOverrides = types.BoolValue(false)
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -286,7 +309,7 @@ func (r *OrganizationVariableResource) Update(ctx context.Context, req resource.
 	}
 
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err := client.MarshalSparsePayload(out, bodyRequest, unchangedValue(plan.Value, state.Value)...)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
@@ -332,7 +355,7 @@ func (r *OrganizationVariableResource) Update(ctx context.Context, req resource.
 
 	bodyResponse, err = io.ReadAll(organizationVarResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading organization variable resource response body", fmt.Sprintf("Error reading organization variable resource response body: %s", err))
+		resp.Diagnostics.AddError("Error reading organization variable resource response body", responseErrorDetail(organizationVarResponse, fmt.Sprintf("Error reading organization variable resource response body, error: %s, response status: %s", err, organizationVarResponse.Status)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -341,14 +364,14 @@ func (r *OrganizationVariableResource) Update(ctx context.Context, req resource.
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), organizationVariable)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(organizationVarResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, organizationVarResponse.Status)))
 		return
 	}
 
 	plan.ID = types.StringValue(state.ID.ValueString())
 	plan.Key = types.StringValue(organizationVariable.Key)
 
-	if *organizationVariable.Sensitive {
+	if organizationVariable.Sensitive != nil && *organizationVariable.Sensitive {
 		tflog.Info(ctx, "Variable value is not included in response, setting values the same as the plan for sensitive=true...")
 		plan.Value = types.StringValue(plan.Value.ValueString())
 	} else {
@@ -358,7 +381,7 @@ func (r *OrganizationVariableResource) Update(ctx context.Context, req resource.
 
 	plan.Description = types.StringValue(organizationVariable.Description)
 	plan.Category = types.StringValue(organizationVariable.Category)
-	plan.Sensitive = types.BoolValue(*organizationVariable.Sensitive)
+	plan.Sensitive = types.BoolValue(organizationVariable.Sensitive != nil && *organizationVariable.Sensitive)
 	plan.Hcl = types.BoolValue(organizationVariable.Hcl)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -374,18 +397,66 @@ func (r *OrganizationVariableResource) Delete(ctx context.Context, req resource.
 		return
 	}
 
-	organizationVarRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/globalvar/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	organizationVarRequest, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/globalvar/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
 	organizationVarRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating organization variable resource request", fmt.Sprintf("Error creating organization variable resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(organizationVarRequest)
+	deleteResponse, err := r.client.Do(organizationVarRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing organization variable resource request", fmt.Sprintf("Error executing organization variable resource request: %s", err))
 		return
 	}
+	defer deleteResponse.Body.Close()
+
+	if deleteResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(deleteResponse.Body)
+		resp.Diagnostics.AddError("Error executing organization variable resource request", responseErrorDetail(deleteResponse, fmt.Sprintf("Error executing organization variable resource request, response status: %s, response body: %s", deleteResponse.Status, bodyResponse)))
+		return
+	}
+}
+
+func (r *OrganizationVariableResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan OrganizationVariableResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plan.WarnOnOverrides.ValueBool() {
+		return
+	}
+
+	if plan.OrganizationId.IsUnknown() || plan.Key.IsUnknown() || plan.Category.IsUnknown() {
+		return
+	}
+
+	items, err := listCollectionItems(ctx, r.client, r.endpoint, r.token, plan.OrganizationId.ValueString())
+	if err != nil {
+		r.warnings.add(&resp.Diagnostics, "Unable to check collection overrides", fmt.Sprintf("Unable to list the collection items of the organization: %s", err))
+		return
+	}
+
+	var collections []string
+	for _, item := range items {
+		if item.item.Key == plan.Key.ValueString() && item.item.Category == plan.Category.ValueString() {
+			collections = append(collections, fmt.Sprintf("%q (id %s, priority %d)", item.collection.Name, item.collection.ID, item.collection.Priority))
+		}
+	}
+	if len(collections) == 0 {
+		return
+	}
+	sort.Strings(collections)
+
+	r.warnings.addAttribute(
+		&resp.Diagnostics,
+		path.Root("key"),
+		"Organization variable overridden by collections",
+		fmt.Sprintf("The collections %s define %s variable %q, their value takes precedence over the organization variable in every workspace using them.",
+			strings.Join(collections, ", "), plan.Category.ValueString(), plan.Key.ValueString()),
+	)
 }
 
 func (r *OrganizationVariableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
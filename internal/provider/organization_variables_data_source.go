@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &OrganizationVariablesDataSource{}
+	_ datasource.DataSourceWithConfigure = &OrganizationVariablesDataSource{}
+)
+
+type OrganizationVariablesDataSourceModel struct {
+	OrganizationId types.String                        `tfsdk:"organization_id"`
+	Variables      []OrganizationVariableListItemModel `tfsdk:"variables"`
+}
+
+type OrganizationVariableListItemModel struct {
+	ID          types.String `tfsdk:"id"`
+	Key         types.String `tfsdk:"key"`
+	Value       types.String `tfsdk:"value"`
+	Description types.String `tfsdk:"description"`
+	Category    types.String `tfsdk:"category"`
+	Sensitive   types.Bool   `tfsdk:"sensitive"`
+	Hcl         types.Bool   `tfsdk:"hcl"`
+}
+
+type OrganizationVariablesDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewOrganizationVariablesDataSource() datasource.DataSource {
+	return &OrganizationVariablesDataSource{}
+}
+
+func (d *OrganizationVariablesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Organization Variables Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Organization Variables datasource")
+}
+
+func (d *OrganizationVariablesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_organization_variables"
+}
+
+func (d *OrganizationVariablesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the global variables of an organization sorted by key. Values of sensitive variables are never returned.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+			},
+			"variables": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Global variables defined in the organization",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Variable Id",
+						},
+						"key": schema.StringAttribute{
+							Computed:    true,
+							Description: "Variable key",
+						},
+						"value": schema.StringAttribute{
+							Computed:    true,
+							Sensitive:   true,
+							Description: "Variable value, null for sensitive variables",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Variable description",
+						},
+						"category": schema.StringAttribute{
+							Computed:    true,
+							Description: "Variable category (ENV or TERRAFORM)",
+						},
+						"sensitive": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the variable is sensitive",
+						},
+						"hcl": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the variable is parsed as HashiCorp Configuration Language (HCL)",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *OrganizationVariablesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state OrganizationVariablesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	variables, err := listOrganizationVariables(ctx, d.client, d.endpoint, d.token, state.OrganizationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading organization variables", apiErrorDetail(err, fmt.Sprintf("Error reading organization variables: %s", err)))
+		return
+	}
+
+	state.Variables = make([]OrganizationVariableListItemModel, 0, len(variables))
+	for _, variable := range variables {
+		sensitive := variable.Sensitive != nil && *variable.Sensitive
+		value := types.StringValue(variable.Value)
+		if sensitive {
+			value = types.StringNull()
+		}
+
+		state.Variables = append(state.Variables, OrganizationVariableListItemModel{
+			ID:          types.StringValue(variable.ID),
+			Key:         types.StringValue(variable.Key),
+			Value:       value,
+			Description: types.StringValue(variable.Description),
+			Category:    types.StringValue(variable.Category),
+			Sensitive:   types.BoolValue(sensitive),
+			Hcl:         types.BoolValue(variable.Hcl),
+		})
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// listOrganizationVariables returns every global variable of the
+// organization sorted by key and category.
+func listOrganizationVariables(ctx context.Context, httpClient *http.Client, endpoint string, token string, organizationId string) ([]*client.OrganizationVariableEntity, error) {
+	items, err := fetchAllPages(ctx, httpClient, token, fmt.Sprintf("%s/api/v1/organization/%s/globalvar", endpoint, organizationId), reflect.TypeOf(new(client.OrganizationVariableEntity)))
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make([]*client.OrganizationVariableEntity, 0, len(items))
+	for _, item := range items {
+		variables = append(variables, item.(*client.OrganizationVariableEntity))
+	}
+
+	sort.SliceStable(variables, func(i, j int) bool {
+		if variables[i].Key != variables[j].Key {
+			return variables[i].Key < variables[j].Key
+		}
+		return variables[i].Category < variables[j].Category
+	})
+
+	return variables, nil
+}
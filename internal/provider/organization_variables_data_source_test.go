@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestOrganizationVariablesDataSource(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.put("/api/v1/organization/o1/globalvar/v1", "globalvar", map[string]any{"key": "region", "value": "eu-west-1", "category": "TERRAFORM", "sensitive": false})
+	api.put("/api/v1/organization/o1/globalvar/v2", "globalvar", map[string]any{"key": "password", "value": "secret", "category": "ENV", "sensitive": true})
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	state, diagnostics := terrakube.readDataSource("terrakube_organization_variables", map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+	})
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var variables []tftypes.Value
+	if err := attribute(t, state, "variables").As(&variables); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(variables) != 2 {
+		t.Fatalf("expected 2 variables, got %d", len(variables))
+	}
+	if key := stringAttribute(t, variables[0], "key"); key != "password" {
+		t.Errorf("variables should be sorted by key, got %s first", key)
+	}
+	if !attribute(t, variables[0], "value").IsNull() {
+		t.Errorf("the value of a sensitive variable should be null")
+	}
+	if value := stringAttribute(t, variables[1], "value"); value != "eu-west-1" {
+		t.Errorf("unexpected value %q", value)
+	}
+}
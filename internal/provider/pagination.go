@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+)
+
+const listPageSize = client.ListPageSize
+
+// fetchAllPages walks a JSON:API collection endpoint page by page using the
+// Elide page[number]/page[size] parameters and returns every entity of the
+// given type. Iteration stops on the first page returning fewer entities
+// than the requested page size.
+func fetchAllPages(ctx context.Context, httpClient *http.Client, token string, collectionURL string, entityType reflect.Type) ([]interface{}, error) {
+	var all []interface{}
+
+	for page := 1; ; page++ {
+		pageURL, err := collectionPageURL(collectionURL, page)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := fetchPage(ctx, httpClient, token, pageURL, entityType)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, items...)
+
+		if len(items) < listPageSize {
+			return all, nil
+		}
+	}
+}
+
+// collectionPageURL returns the url of a page of listPageSize entities of a
+// collection endpoint, keeping the query of the collection url.
+func collectionPageURL(collectionURL string, page int) (string, error) {
+	pageURL, err := url.Parse(collectionURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid collection url %q: %w", collectionURL, err)
+	}
+
+	query := pageURL.Query()
+	query.Set("page[number]", strconv.Itoa(page))
+	query.Set("page[size]", strconv.Itoa(listPageSize))
+	pageURL.RawQuery = query.Encode()
+
+	return pageURL.String(), nil
+}
+
+// fetchPage returns the entities of a single request to a collection
+// endpoint, the url carries the page, sort and filter parameters.
+func fetchPage(ctx context.Context, httpClient *http.Client, token string, pageURL string, entityType reflect.Type) ([]interface{}, error) {
+	body, err := fetchPageBody(ctx, httpClient, token, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(body)), entityType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal payload, response body: %s, error: %w", string(body), err)
+	}
+
+	return items, nil
+}
+
+// fetchPageBody returns the undecoded body of a single request to a
+// collection endpoint.
+func fetchPageBody(ctx context.Context, httpClient *http.Client, token string, pageURL string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error executing request: %w", err)
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, client.NewStatusError(response, body)
+	}
+
+	return body, nil
+}
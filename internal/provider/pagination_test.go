@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"terraform-provider-terrakube/internal/client"
+	"testing"
+)
+
+func TestFetchAllPages(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	for i := 0; i < listPageSize+1; i++ {
+		api.put(fmt.Sprintf("/api/v1/organization/o1/team/t%03d", i), "team", map[string]any{"name": fmt.Sprintf("team-%d", i)})
+	}
+
+	teams, err := fetchAllPages(context.Background(), http.DefaultClient, "token", server.URL+"/api/v1/organization/o1/team", reflect.TypeOf(new(client.TeamEntity)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(teams) != listPageSize+1 || api.count("GET", "/api/v1/organization/o1/team") != 2 {
+		t.Errorf("expected %d teams in 2 pages, got %d teams", listPageSize+1, len(teams))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = fetchAllPages(ctx, http.DefaultClient, "token", server.URL+"/api/v1/organization/o1/team", reflect.TypeOf(new(client.TeamEntity)))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancellation of the context, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"terraform-provider-terrakube/internal/client"
+	"testing"
+
+	"github.com/google/jsonapi"
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const parallelApplyResources = 50
+
+// teamServer is an in memory team API of a single organization, its team
+// list is only filtered by name.
+type teamServer struct {
+	mu     sync.Mutex
+	teams  map[string]*client.TeamEntity
+	nextId int
+	served atomic.Int32
+}
+
+func (s *teamServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.served.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/organization/o1/team/")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/organization/o1/team":
+		team := new(client.TeamEntity)
+		if err := jsonapi.UnmarshalPayload(r.Body, team); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.nextId++
+		team.ID = fmt.Sprintf("team-%d", s.nextId)
+		s.teams[team.ID] = team
+		w.WriteHeader(http.StatusCreated)
+		jsonapi.MarshalPayload(w, team)
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/organization/o1/team":
+		_, name, _ := strings.Cut(r.URL.Query().Get("filter[team]"), "==")
+		teams := []*client.TeamEntity{}
+		for _, team := range s.teams {
+			if "'"+team.Name+"'" == name {
+				teams = append(teams, team)
+			}
+		}
+		jsonapi.MarshalPayload(w, teams)
+	case s.teams[id] == nil:
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodGet:
+		jsonapi.MarshalPayload(w, s.teams[id])
+	case r.Method == http.MethodPatch:
+		team := new(client.TeamEntity)
+		if err := jsonapi.UnmarshalPayload(r.Body, team); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.teams[id] = team
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(s.teams, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// objectValue returns an object of the given type with the given
+// attributes, every other attribute is null.
+func objectValue(objectType tftypes.Object, values map[string]tftypes.Value) tftypes.Value {
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range values {
+		attributes[name] = value
+	}
+	return tftypes.NewValue(objectType, attributes)
+}
+
+func dynamicValue(t *testing.T, objectType tftypes.Object, value tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+	dynamic, err := tfprotov6.NewDynamicValue(objectType, value)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return &dynamic
+}
+
+func diagnosticsError(diagnostics []*tfprotov6.Diagnostic) error {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			return fmt.Errorf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+	return nil
+}
+
+// TestParallelApply plans, applies, refreshes and destroys teams
+// concurrently through the provider server, the way Terraform does with
+// -parallelism, sharing one configured provider.
+func TestParallelApply(t *testing.T) {
+	ctx := context.Background()
+	api := &teamServer{teams: map[string]*client.TeamEntity{}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	terrakube := New("test")()
+	providerSchema := &fwprovider.SchemaResponse{}
+	terrakube.Schema(ctx, fwprovider.SchemaRequest{}, providerSchema)
+	providerType := providerSchema.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	teamSchema := &resource.SchemaResponse{}
+	NewTeamResource().Schema(ctx, resource.SchemaRequest{}, teamSchema)
+	teamType := teamSchema.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	providerServer := NewProtocol6WithRequestSummary(func() fwprovider.Provider { return terrakube })()
+	configured, err := providerServer.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: dynamicValue(t, providerType, objectValue(providerType, map[string]tftypes.Value{
+			"endpoint": tftypes.NewValue(tftypes.String, server.URL),
+			"token":    tftypes.NewValue(tftypes.String, "token"),
+		})),
+	})
+	if err == nil {
+		err = diagnosticsError(configured.Diagnostics)
+	}
+	if err != nil {
+		t.Fatalf("unexpected error configuring the provider: %s", err)
+	}
+
+	requestsBefore := requestMetrics.Summary().TotalRequests
+	servedBefore := api.served.Load()
+	nullState := dynamicValue(t, teamType, tftypes.NewValue(teamType, nil))
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallelApplyResources; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			name := fmt.Sprintf("team-name-%d", i)
+			config := dynamicValue(t, teamType, objectValue(teamType, map[string]tftypes.Value{
+				"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+				"name":            tftypes.NewValue(tftypes.String, name),
+				"manage_state":    tftypes.NewValue(tftypes.Bool, i%2 == 0),
+			}))
+
+			plan, err := providerServer.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+				TypeName:         "terrakube_team",
+				PriorState:       nullState,
+				ProposedNewState: config,
+				Config:           config,
+			})
+			if err == nil {
+				err = diagnosticsError(plan.Diagnostics)
+			}
+			if err != nil {
+				t.Errorf("%s: plan: %s", name, err)
+				return
+			}
+
+			applied, err := providerServer.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+				TypeName:     "terrakube_team",
+				PriorState:   nullState,
+				PlannedState: plan.PlannedState,
+				Config:       config,
+			})
+			if err == nil {
+				err = diagnosticsError(applied.Diagnostics)
+			}
+			if err != nil {
+				t.Errorf("%s: apply: %s", name, err)
+				return
+			}
+
+			read, err := providerServer.ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+				TypeName:     "terrakube_team",
+				CurrentState: applied.NewState,
+				Private:      applied.Private,
+			})
+			if err == nil {
+				err = diagnosticsError(read.Diagnostics)
+			}
+			if err != nil {
+				t.Errorf("%s: read: %s", name, err)
+				return
+			}
+
+			state, err := read.NewState.Unmarshal(teamType)
+			if err != nil {
+				t.Errorf("%s: %s", name, err)
+				return
+			}
+			var attributes map[string]tftypes.Value
+			var stateName string
+			var manageState bool
+			if err := state.As(&attributes); err != nil {
+				t.Errorf("%s: %s", name, err)
+				return
+			}
+			attributes["name"].As(&stateName)
+			attributes["manage_state"].As(&manageState)
+			if stateName != name || manageState != (i%2 == 0) {
+				t.Errorf("%s: unexpected state %v", name, state)
+			}
+
+			destroyed, err := providerServer.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+				TypeName:     "terrakube_team",
+				PriorState:   read.NewState,
+				PlannedState: nullState,
+				Config:       nullState,
+			})
+			if err == nil {
+				err = diagnosticsError(destroyed.Diagnostics)
+			}
+			if err != nil {
+				t.Errorf("%s: destroy: %s", name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if api.nextId != parallelApplyResources {
+		t.Errorf("expected %d teams to be created, got %d", parallelApplyResources, api.nextId)
+	}
+	if len(api.teams) != 0 {
+		t.Errorf("expected every team to be destroyed, %d left", len(api.teams))
+	}
+	if counted, served := requestMetrics.Summary().TotalRequests-requestsBefore, int(api.served.Load()-servedBefore); counted != served {
+		t.Errorf("metrics counted %d requests, the server answered %d", counted, served)
+	}
+}
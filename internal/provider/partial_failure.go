@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// itemFailures collects the errors of resources managing many API objects.
+// These resources apply every item they can and keep the applied items in
+// state, so the next apply only retries the failed ones instead of the whole
+// collection.
+type itemFailures struct {
+	kind   string
+	errors map[string]error
+}
+
+func newItemFailures(kind string) *itemFailures {
+	return &itemFailures{kind: kind, errors: map[string]error{}}
+}
+
+func (f *itemFailures) add(key string, err error) {
+	f.errors[key] = err
+}
+
+func (f *itemFailures) empty() bool {
+	return len(f.errors) == 0
+}
+
+// appendTo adds one error per failed item, sorted by key, pointing at the
+// item of the map attribute.
+func (f *itemFailures) appendTo(diags *diag.Diagnostics, attribute path.Path) {
+	keys := make([]string, 0, len(f.errors))
+	for key := range f.errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		diags.AddAttributeError(
+			attribute.AtMapKey(key),
+			fmt.Sprintf("Unable to apply %s %q", f.kind, key),
+			fmt.Sprintf("%s. The other items were applied and saved in state, the next apply retries the failed ones.", f.errors[key]),
+		)
+	}
+}
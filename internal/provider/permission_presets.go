@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// permissionPresets lists the manage_* attributes enabled by each preset.
+// The admin preset enables every permission of the resource.
+var permissionPresets = map[string][]string{
+	"read":  {"manage_state"},
+	"write": {"manage_state", "manage_job"},
+	"admin": nil,
+}
+
+var permissionPresetNames = []string{"read", "write", "admin"}
+
+// expandPermissionPreset returns the value of each permission attribute for
+// the preset.
+func expandPermissionPreset(preset string, attributes []string) map[string]bool {
+	values := make(map[string]bool, len(attributes))
+	for _, attribute := range attributes {
+		values[attribute] = preset == "admin"
+	}
+	for _, attribute := range permissionPresets[preset] {
+		if _, ok := values[attribute]; ok {
+			values[attribute] = true
+		}
+	}
+	return values
+}
+
+var _ resource.ConfigValidator = permissionsConfigValidator{}
+
+// permissionsConfigValidator rejects configurations granting no permission:
+// without a preset at least one of the attributes must be true, with a
+// preset none of them may be set.
+type permissionsConfigValidator struct {
+	attributes []string
+}
+
+func (v permissionsConfigValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v permissionsConfigValidator) MarkdownDescription(_ context.Context) string {
+	return fmt.Sprintf("Either preset or at least one of %s must be set to true", strings.Join(v.attributes, ", "))
+}
+
+func (v permissionsConfigValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var preset types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("preset"), &preset)...)
+	if resp.Diagnostics.HasError() || preset.IsUnknown() {
+		return
+	}
+
+	granted := false
+	for _, attribute := range v.attributes {
+		var value types.Bool
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(attribute), &value)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if value.IsUnknown() {
+			return
+		}
+
+		if !preset.IsNull() && !value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Conflicting permission configuration",
+				fmt.Sprintf("%s cannot be set together with preset %q.", attribute, preset.ValueString()),
+			)
+		}
+
+		granted = granted || value.ValueBool()
+	}
+
+	if preset.IsNull() && !granted {
+		resp.Diagnostics.AddError(
+			"No permission granted",
+			fmt.Sprintf("Set preset or at least one of %s to true, an entry without any permission has no effect.", strings.Join(v.attributes, ", ")),
+		)
+	}
+}
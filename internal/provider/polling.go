@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// waitFor calls check every interval until it reports done, returns an error
+// or the timeout expires.
+func waitFor(ctx context.Context, timeout time.Duration, interval time.Duration, check func() (bool, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s", timeout)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const cacheValidatorsKey = "cache_validators"
+
+// privateStateGetter and privateStateSetter match the private state of the
+// framework requests and responses.
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// readCacheValidators returns the validators saved by the last read, empty
+// validators when there are none or they cannot be decoded.
+func readCacheValidators(ctx context.Context, private privateStateGetter) client.CacheValidators {
+	var validators client.CacheValidators
+	if private == nil {
+		return validators
+	}
+
+	value, diags := private.GetKey(ctx, cacheValidatorsKey)
+	if diags.HasError() || len(value) == 0 {
+		return validators
+	}
+
+	_ = json.Unmarshal(value, &validators)
+	return validators
+}
+
+func writeCacheValidators(ctx context.Context, private privateStateSetter, validators client.CacheValidators) diag.Diagnostics {
+	if private == nil {
+		return nil
+	}
+
+	if validators == (client.CacheValidators{}) {
+		return private.SetKey(ctx, cacheValidatorsKey, nil)
+	}
+
+	value, err := json.Marshal(validators)
+	if err != nil {
+		return nil
+	}
+	return private.SetKey(ctx, cacheValidatorsKey, value)
+}
+
+// clearCacheValidators drops the validators of the last read. It is called
+// whenever the state is written without reading the object from Terrakube,
+// a 304 Not Modified must only ever keep a state that came from the server.
+func clearCacheValidators(ctx context.Context, private privateStateSetter) diag.Diagnostics {
+	return writeCacheValidators(ctx, private, client.CacheValidators{})
+}
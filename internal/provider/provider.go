@@ -5,8 +5,18 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
-
+	"reflect"
+	"sort"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -14,11 +24,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure TerrakubeProvider satisfies various provider interfaces.
 var _ provider.Provider = &TerrakubeProvider{}
+var _ provider.ProviderWithConfigValidators = &TerrakubeProvider{}
 
 // TerrakubeProvider defines the provider implementation.
 type TerrakubeProvider struct {
@@ -30,17 +42,64 @@ type TerrakubeProvider struct {
 
 // hashicupsProviderModel maps provider schema data to a Go type.
 type TerrakubeProviderModel struct {
-	Endpoint           types.String `tfsdk:"endpoint"`
-	Token              types.String `tfsdk:"token"`
-	InsecureHttpClient types.Bool   `tfsdk:"insecure_http_client"`
+	Endpoint               types.String `tfsdk:"endpoint"`
+	Token                  types.String `tfsdk:"token"`
+	InsecureHttpClient     types.Bool   `tfsdk:"insecure_http_client"`
+	FullPayloads           types.Bool   `tfsdk:"full_payloads"`
+	MetricsPath            types.String `tfsdk:"metrics_path"`
+	EnableBatching         types.Bool   `tfsdk:"enable_batching"`
+	DefaultTemplateNames   types.Map    `tfsdk:"default_template_names"`
+	ExpectedOrganization   types.String `tfsdk:"expected_organization_name"`
+	CACert                 types.String `tfsdk:"ca_cert"`
+	ClientCert             types.String `tfsdk:"client_cert"`
+	ClientKey              types.String `tfsdk:"client_key"`
+	InsecureHosts          types.List   `tfsdk:"insecure_hosts"`
+	Airgapped              types.Bool   `tfsdk:"airgapped"`
+	AllowedExternalHosts   types.List   `tfsdk:"allowed_external_hosts"`
+	ProtectedTeamNames     types.List   `tfsdk:"protected_team_names"`
+	WarningsAsErrors       types.Bool   `tfsdk:"warnings_as_errors"`
+	APIParallelism         types.Int64  `tfsdk:"api_parallelism"`
+	JobMetadata            types.Map    `tfsdk:"job_metadata"`
+	DisableDataSourceCache types.Bool   `tfsdk:"disable_datasource_cache"`
+	ConnectTimeout         types.String `tfsdk:"connect_timeout"`
+	ResponseHeaderTimeout  types.String `tfsdk:"response_header_timeout"`
+	IPProtocol             types.String `tfsdk:"ip_protocol"`
+}
+
+// ipNetworks maps ip_protocol to the network dialed by the http client,
+// auto and unset dial both address families.
+var ipNetworks = map[string]string{
+	"ipv4": "tcp4",
+	"ipv6": "tcp6",
 }
 
+// TerrakubeConnectionData is shared by every resource and data source, and
+// Terraform calls them concurrently (10 at a time by default). Fields are set
+// once in Configure and only read afterwards; the values holding mutable
+// state (HttpClient with its metrics, Batcher and Organizations) lock
+// internally. New shared caches or counters must do the same.
 type TerrakubeConnectionData struct {
-	Endpoint           string
-	Token              string
-	InsecureHttpClient bool
+	Endpoint              string
+	Token                 string
+	InsecureHttpClient    bool
+	HttpClient            *http.Client
+	Batcher               *client.Batcher
+	DefaultTemplateNames  map[string]string
+	Organizations         *organizationCache
+	OrganizationSummaries *organizationSummaryCache
+	Airgap                *airgapPolicy
+	ProtectedTeamNames    map[string]bool
+	Warnings              *warningPolicy
+	APIParallelism        int
+	JobMetadata           map[string]string
+	DataSourceCache       *dataSourceCache
 }
 
+// requestMetrics counts the API requests of the plugin process. It lives at
+// package level because it outlives the provider instances, the summary is
+// logged by requestSummaryServer.
+var requestMetrics = client.NewMetrics()
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &TerrakubeProvider{
@@ -67,12 +126,110 @@ func (p *TerrakubeProvider) Schema(ctx context.Context, req provider.SchemaReque
 			},
 			"insecure_http_client": schema.BoolAttribute{
 				Optional:    true,
-				Description: "Disable https certificate validation, default is `false`.",
+				Description: "Disable https certificate validation, default is `false`. Conflicts with `ca_cert`, `client_cert`, `client_key` and `insecure_hosts`.",
+			},
+			"ca_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded CA certificates trusted in addition to the system ones, for Terrakube instances using a private CA.",
+			},
+			"client_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded client certificate for mutual TLS, requires `client_key`.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM encoded private key of `client_cert`.",
+			},
+			"insecure_hosts": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Host names whose certificate is not verified, every other host is still verified.",
+			},
+			"connect_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum time to open a connection, including the TLS handshake, as a Go duration, default is `10s`. Requests failing to connect are retried whatever their method.",
+			},
+			"response_header_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum time to wait for the response headers once a request is sent, as a Go duration, default is `2m`. Only GET requests are retried after a timeout.",
+			},
+			"ip_protocol": schema.StringAttribute{
+				Optional:    true,
+				Description: "IP version used to connect to the API: `auto`, `ipv4` or `ipv6`, default is `auto`. Forcing one avoids the fallback delay on every new connection when the route of the other version is broken.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("auto", "ipv4", "ipv6"),
+				},
+			},
+			"expected_organization_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of an organization the token must be able to see. When set, the provider lists the organizations during configuration and fails if it is missing, which catches a token used with the endpoint of another Terrakube instance before any resource runs.",
+			},
+			"full_payloads": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Request complete JSON:API responses, default is `false`, which asks for sparse fieldsets limited to the fields the provider uses and no `included` side-loaded data. Only useful for debugging.",
+			},
+			"default_template_names": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Template names used by the `terrakube_default_template` data source, keyed by kind. Only needed when the templates created with new organizations were customized.",
+			},
+			"enable_batching": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Send the team updates of an apply as JSON:API atomic operations instead of one request per team, default is `false`. Requires a Terrakube API with atomic operations enabled.",
+			},
+			"airgapped": schema.BoolAttribute{
+				Optional:    true,
+				Description: "The Terrakube instance has no internet access, default is `false`. Modules and VCS workspaces whose repository is on github.com, gitlab.com or bitbucket.org are reported with a warning during plan since they would never sync.",
+			},
+			"allowed_external_hosts": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Public repository hosts reachable from an air-gapped instance, for example through a proxy. Only used with `airgapped`.",
+			},
+			"protected_team_names": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Names of teams that must not be deleted, like the owners team created with the organization. Terrakube does not flag such teams, a `terrakube_team` with one of these names is only deleted with `force_delete`.",
+			},
+			"warnings_as_errors": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Report the warnings of the resources, like version downgrades, variable conflicts or ignored changes, as errors so the plan or apply fails, default is `false`. Meant for strict CI pipelines. A warning raised while destroying a resource, like the module versions the registry cannot remove, keeps the resource in the state.",
+			},
+			"api_parallelism": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of delete requests sent at the same time by resources managing many objects, like `terrakube_workspace_variables` or a `terrakube_team` deleted with `force_delete`, default is `4`.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"disable_datasource_cache": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Read every data source from the API, default is `false`. By default the data sources with the same configuration are read once per plan or apply, so a `for_each` over identical inputs sends a single request. Disable the cache when a data source must see an object created earlier in the same apply.",
+			},
+			"job_metadata": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Metadata written to the comments of the jobs created by `terrakube_job`, one `key=value` line per entry sorted by key, to trace a job back to the Terraform run or CI pipeline that created it. The values are visible to every user of the workspace, do not put secrets in them. Values from the environment, like `TFC_RUN_ID` or the URL of the CI pipeline, are passed through input variables set with `TF_VAR_` environment variables.",
+			},
+			"metrics_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "File where a JSON summary of the API requests (`total_requests`, `retries`, `errors_by_status`) is written, can also be specified with environment variable `TERRAKUBE_METRICS_PATH`.",
 			},
 		},
 	}
 }
 
+func (p *TerrakubeProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		tlsConfigValidator{},
+		providervalidator.RequiredTogether(
+			path.MatchRoot("client_cert"),
+			path.MatchRoot("client_key"),
+		),
+	}
+}
+
 func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	tflog.Info(ctx, "Retrieving provider data from configuration")
 
@@ -111,6 +268,9 @@ func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.Configur
 	endpoint := os.Getenv("TERRAKUBE_ENDPOINT")
 	token := os.Getenv("TERRAKUBE_TOKEN")
 	insecureHttpClient := false
+	fullPayloads := false
+	metricsPath := os.Getenv("TERRAKUBE_METRICS_PATH")
+	enableBatching := false
 
 	if !config.Endpoint.IsNull() {
 		endpoint = config.Endpoint.ValueString()
@@ -124,6 +284,23 @@ func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.Configur
 		insecureHttpClient = config.InsecureHttpClient.ValueBool()
 	}
 
+	if !config.FullPayloads.IsNull() {
+		fullPayloads = config.FullPayloads.ValueBool()
+	}
+
+	if !config.EnableBatching.IsNull() {
+		enableBatching = config.EnableBatching.ValueBool()
+	}
+
+	defaultTemplateNames := map[string]string{}
+	if !config.DefaultTemplateNames.IsNull() {
+		resp.Diagnostics.Append(config.DefaultTemplateNames.ElementsAs(ctx, &defaultTemplateNames, false)...)
+	}
+
+	if !config.MetricsPath.IsNull() {
+		metricsPath = config.MetricsPath.ValueString()
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
@@ -151,11 +328,91 @@ func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
+	tlsOptions := client.TLSOptions{
+		CACertPEM:     config.CACert.ValueString(),
+		ClientCertPEM: config.ClientCert.ValueString(),
+		ClientKeyPEM:  config.ClientKey.ValueString(),
+	}
+	if !config.InsecureHosts.IsNull() {
+		resp.Diagnostics.Append(config.InsecureHosts.ElementsAs(ctx, &tlsOptions.InsecureHosts, false)...)
+	}
+
+	tlsConfig, err := client.NewTLSConfig(tlsOptions)
+	if err != nil {
+		attribute := path.Root("ca_cert")
+		var optionErr *client.TLSOptionError
+		if errors.As(err, &optionErr) {
+			attribute = path.Root(optionErr.Option)
+		}
+		resp.Diagnostics.AddAttributeError(attribute, "Invalid TLS configuration", err.Error())
+		return
+	}
+
+	connectTimeout := parseTimeout(config.ConnectTimeout, "connect_timeout", defaultConnectTimeout, &resp.Diagnostics)
+	responseHeaderTimeout := parseTimeout(config.ResponseHeaderTimeout, "response_header_timeout", defaultResponseHeaderTimeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	connection := new(TerrakubeConnectionData)
 
 	connection.Endpoint = endpoint
 	connection.Token = token
 	connection.InsecureHttpClient = insecureHttpClient
+	connection.DefaultTemplateNames = defaultTemplateNames
+	connection.Organizations = newOrganizationCache(organizationCacheTTL)
+	connection.OrganizationSummaries = newOrganizationSummaryCache(organizationCacheTTL)
+
+	var allowedExternalHosts []string
+	if !config.AllowedExternalHosts.IsNull() {
+		resp.Diagnostics.Append(config.AllowedExternalHosts.ElementsAs(ctx, &allowedExternalHosts, false)...)
+	}
+	connection.Warnings = newWarningPolicy(config.WarningsAsErrors.ValueBool())
+	connection.APIParallelism = defaultAPIParallelism
+	if !config.APIParallelism.IsNull() {
+		connection.APIParallelism = int(config.APIParallelism.ValueInt64())
+	}
+	connection.Airgap = newAirgapPolicy(config.Airgapped.ValueBool(), allowedExternalHosts, connection.Warnings)
+
+	if !config.DisableDataSourceCache.ValueBool() {
+		connection.DataSourceCache = newDataSourceCache()
+	}
+
+	connection.JobMetadata = map[string]string{}
+	if !config.JobMetadata.IsNull() {
+		resp.Diagnostics.Append(config.JobMetadata.ElementsAs(ctx, &connection.JobMetadata, false)...)
+	}
+
+	var protectedTeamNames []string
+	if !config.ProtectedTeamNames.IsNull() {
+		resp.Diagnostics.Append(config.ProtectedTeamNames.ElementsAs(ctx, &protectedTeamNames, false)...)
+	}
+	connection.ProtectedTeamNames = make(map[string]bool, len(protectedTeamNames))
+	for _, name := range protectedTeamNames {
+		connection.ProtectedTeamNames[name] = true
+	}
+	requestMetrics.SetPath(metricsPath)
+	connection.HttpClient = client.NewHttpClient(client.HttpClientOptions{
+		InsecureSkipVerify: insecureHttpClient,
+		TLSConfig:          tlsConfig,
+		FullPayloads:       fullPayloads,
+		Metrics:            requestMetrics,
+
+		ConnectTimeout:        connectTimeout,
+		ResponseHeaderTimeout: responseHeaderTimeout,
+		Network:               ipNetworks[config.IPProtocol.ValueString()],
+	})
+
+	if enableBatching {
+		connection.Batcher = client.NewBatcher(connection.HttpClient, endpoint, token)
+	}
+
+	if !config.ExpectedOrganization.IsNull() {
+		checkExpectedOrganization(ctx, connection, config.ExpectedOrganization.ValueString(), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	resp.DataSourceData = connection
 	resp.ResourceData = connection
@@ -170,6 +427,9 @@ func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.Configur
 func (p *TerrakubeProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewModuleResource,
+		NewModuleVersionResource,
+		NewRegistryProviderVersionResource,
+		NewRegistryProviderPlatformResource,
 		NewOrganizationResource,
 		NewOrganizationTemplateResource,
 		NewOrganizationTagResource,
@@ -179,6 +439,7 @@ func (p *TerrakubeProvider) Resources(ctx context.Context) []func() resource.Res
 		NewWorkspaceCliResource,
 		NewWorkspaceTagResource,
 		NewWorkspaceVariableResource,
+		NewWorkspaceVariablesResource,
 		NewWorkspaceVcsResource,
 		NewWorkspaceWebhookResource,
 		NewVcsResource,
@@ -189,15 +450,67 @@ func (p *TerrakubeProvider) Resources(ctx context.Context) []func() resource.Res
 		NewAgentResource,
 		NewWorkspaceAccessResource,
 		NewSshResource,
+		NewJobResource,
+		NewWorkspaceSshKeyResource,
+		NewWorkspaceRunTriggerResource,
+		NewActionResource,
+		NewWorkspaceAgentResource,
+		NewWorkspaceStateResource,
+		NewJobApprovalResource,
 	}
 }
 
 func (p *TerrakubeProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{
+	return withDataSourceCache([]func() datasource.DataSource{
 		NewOrganizationDataSource,
 		NewOrganizationTemplateDataSource,
 		NewOrganizationTagDataSource,
 		NewVcsDataSource,
 		NewSshDataSource,
+		NewSshKeysDataSource,
+		NewAgentsDataSource,
+		NewOrganizationVariablesDataSource,
+		NewWorkspaceSchedulesDataSource,
+		NewTeamTokenDataSource,
+		NewWorkspaceRemoteStateDataSource,
+		NewTeamsSnapshotDataSource,
+		NewDefaultTemplateDataSource,
+		NewCollectionsItemsDataSource,
+		NewOrganizationSummaryDataSource,
+		NewWorkspaceDataSource,
+		NewTeamDataSource,
+		NewModuleDataSource,
+		NewHealthDataSource,
+	})
+}
+
+// checkExpectedOrganization fails when the token cannot see the expected
+// organization, usually because it belongs to another Terrakube instance.
+func checkExpectedOrganization(ctx context.Context, connection *TerrakubeConnectionData, expected string, diags *diag.Diagnostics) {
+	items, err := fetchAllPages(ctx, connection.HttpClient, connection.Token, connection.Endpoint+"/api/v1/organization", reflect.TypeOf(new(client.OrganizationEntity)))
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("expected_organization_name"),
+			"Unable to list Terrakube organizations",
+			fmt.Sprintf("The organizations of %s could not be listed to check expected_organization_name: %s. Check that the token belongs to this endpoint.", connection.Endpoint, err),
+		)
+		return
 	}
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		organization := item.(*client.OrganizationEntity)
+		connection.Organizations.put(organization.ID, organization.Name)
+		if organization.Name == expected {
+			return
+		}
+		names = append(names, organization.Name)
+	}
+	sort.Strings(names)
+
+	diags.AddAttributeError(
+		path.Root("expected_organization_name"),
+		"Expected Terrakube organization not found",
+		fmt.Sprintf("The token does not see organization %q at %s, visible organizations: %q. The token and endpoint probably belong to different Terrakube instances.", expected, connection.Endpoint, names),
+	)
 }
@@ -1 +1,500 @@
 package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// fakeAPI is an in memory JSON:API server. Resources are kept as their
+// undecoded resource objects by path, a POST to a collection stores the
+// resource under the collection with a new id, GET, PATCH and DELETE work on
+// those paths and a GET of a collection lists its resources, filtered by the
+// equality RSQL filters and paged with page[number]/page[size].
+type fakeAPI struct {
+	mu        sync.Mutex
+	resources map[string]map[string]any
+	nextId    int
+	requests  []string
+
+	// handle, when set, answers the requests it returns true for instead of
+	// the fake.
+	handle func(w http.ResponseWriter, r *http.Request) bool
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *httptest.Server) {
+	t.Helper()
+	api := &fakeAPI{resources: map[string]map[string]any{}}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+// put stores a resource with the given attributes at path, the last segment
+// of the path is its id.
+func (a *fakeAPI) put(path string, resourceType string, attributes map[string]any) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.resources[path] = map[string]any{
+		"type":       resourceType,
+		"id":         path[strings.LastIndex(path, "/")+1:],
+		"attributes": attributes,
+	}
+}
+
+// attributes returns the attributes of the resource at path, nil when there
+// is none.
+func (a *fakeAPI) attributes(path string) map[string]any {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.resources[path] == nil {
+		return nil
+	}
+	return a.resources[path]["attributes"].(map[string]any)
+}
+
+// set changes one attribute of the resource at path, the way a change made
+// outside of Terraform does.
+func (a *fakeAPI) set(path string, name string, value any) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.resources[path]["attributes"].(map[string]any)[name] = value
+}
+
+// count returns how many requests with the method were made to paths with
+// the prefix.
+func (a *fakeAPI) count(method string, prefix string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	count := 0
+	for _, request := range a.requests {
+		if strings.HasPrefix(request, method+" "+prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	a.requests = append(a.requests, r.Method+" "+r.URL.Path)
+	handle := a.handle
+	a.mu.Unlock()
+
+	if handle != nil && handle(w, r) {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	path := r.URL.Path
+	resource := a.resources[path]
+	switch {
+	case r.Method == http.MethodPost:
+		var document struct {
+			Data map[string]any `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&document); err != nil || document.Data == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		a.nextId++
+		document.Data["id"] = fmt.Sprintf("id-%d", a.nextId)
+		if document.Data["attributes"] == nil {
+			document.Data["attributes"] = map[string]any{}
+		}
+		a.resources[path+"/"+document.Data["id"].(string)] = document.Data
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"data": document.Data})
+	case r.Method == http.MethodGet && resource != nil:
+		json.NewEncoder(w).Encode(map[string]any{"data": resource})
+	case r.Method == http.MethodGet:
+		json.NewEncoder(w).Encode(map[string]any{"data": a.list(path, r.URL.Query())})
+	case resource == nil:
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodPatch:
+		var document struct {
+			Data map[string]any `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&document); err != nil || document.Data == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if attributes, ok := document.Data["attributes"].(map[string]any); ok {
+			for name, value := range attributes {
+				resource["attributes"].(map[string]any)[name] = value
+			}
+		}
+		if relationships, ok := document.Data["relationships"]; ok {
+			resource["relationships"] = relationships
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(a.resources, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// list returns the resources directly under the collection path matching
+// the filters of the query, ordered by id.
+func (a *fakeAPI) list(collection string, query map[string][]string) []map[string]any {
+	var paths []string
+	for path := range a.resources {
+		rest, found := strings.CutPrefix(path, collection+"/")
+		if found && !strings.Contains(rest, "/") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	items := []map[string]any{}
+	for _, path := range paths {
+		if fakeMatches(a.resources[path], query) {
+			items = append(items, a.resources[path])
+		}
+	}
+
+	size, err := strconv.Atoi(first(query["page[size]"]))
+	if err != nil {
+		return items
+	}
+	number, _ := strconv.Atoi(first(query["page[number]"]))
+	start := min((max(number, 1)-1)*size, len(items))
+	return items[start:min(start+size, len(items))]
+}
+
+// fakeMatches checks the equality terms, like name=='a';key=='b', of every
+// filter of the query against the attributes of the resource.
+func fakeMatches(resource map[string]any, query map[string][]string) bool {
+	attributes := resource["attributes"].(map[string]any)
+	for key, values := range query {
+		if !strings.HasPrefix(key, "filter[") {
+			continue
+		}
+		for _, term := range strings.Split(first(values), ";") {
+			name, value, found := strings.Cut(term, "==")
+			if !found {
+				continue
+			}
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "'"), "'")
+			value = strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(value)
+			if fmt.Sprint(attributes[name]) != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// testProvider is a provider server configured against an endpoint, driven
+// the way Terraform drives it.
+type testProvider struct {
+	t       *testing.T
+	server  tfprotov6.ProviderServer
+	schemas *tfprotov6.GetProviderSchemaResponse
+}
+
+// newTestProvider configures a provider for the endpoint, the attributes
+// are added to the endpoint and token of the configuration.
+func newTestProvider(t *testing.T, endpoint string, attributes map[string]tftypes.Value) *testProvider {
+	t.Helper()
+	terrakube, diagnostics := configureTestProvider(t, endpoint, attributes)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error configuring the provider: %s", err)
+	}
+	return terrakube
+}
+
+// configureTestProvider is newTestProvider returning the diagnostics of the
+// configuration instead of failing on them.
+func configureTestProvider(t *testing.T, endpoint string, attributes map[string]tftypes.Value) (*testProvider, []*tfprotov6.Diagnostic) {
+	t.Helper()
+	ctx := context.Background()
+
+	server := NewProtocol6WithRequestSummary(func() fwprovider.Provider { return New("test")() })()
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	values := map[string]tftypes.Value{
+		"endpoint": tftypes.NewValue(tftypes.String, endpoint),
+		"token":    tftypes.NewValue(tftypes.String, "token"),
+	}
+	for name, value := range attributes {
+		values[name] = value
+	}
+	providerType := schemas.Provider.ValueType().(tftypes.Object)
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: dynamicValue(t, providerType, objectValue(providerType, values)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error configuring the provider: %s", err)
+	}
+
+	return &testProvider{t: t, server: server, schemas: schemas}, configured.Diagnostics
+}
+
+func (p *testProvider) resourceType(typeName string) tftypes.Object {
+	return p.schemas.ResourceSchemas[typeName].ValueType().(tftypes.Object)
+}
+
+// object returns a value of the resource type with the attributes, every
+// other attribute is null.
+func (p *testProvider) object(typeName string, attributes map[string]tftypes.Value) tftypes.Value {
+	return objectValue(p.resourceType(typeName), attributes)
+}
+
+func (p *testProvider) null(typeName string) tftypes.Value {
+	return tftypes.NewValue(p.resourceType(typeName), nil)
+}
+
+func (p *testProvider) dynamic(typeName string, value tftypes.Value) *tfprotov6.DynamicValue {
+	return dynamicValue(p.t, p.resourceType(typeName), value)
+}
+
+func (p *testProvider) value(typeName string, dynamic *tfprotov6.DynamicValue) tftypes.Value {
+	p.t.Helper()
+	if dynamic == nil {
+		return p.null(typeName)
+	}
+	value, err := dynamic.Unmarshal(p.resourceType(typeName))
+	if err != nil {
+		p.t.Fatalf("unexpected error: %s", err)
+	}
+	return value
+}
+
+// plan plans the change from prior to config.
+func (p *testProvider) plan(typeName string, prior tftypes.Value, config tftypes.Value) *tfprotov6.PlanResourceChangeResponse {
+	p.t.Helper()
+	response, err := p.server.PlanResourceChange(context.Background(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       p.dynamic(typeName, prior),
+		ProposedNewState: p.dynamic(typeName, p.proposedNewState(typeName, prior, config)),
+		Config:           p.dynamic(typeName, config),
+	})
+	if err != nil {
+		p.t.Fatalf("unexpected error: %s", err)
+	}
+	return response
+}
+
+// validate returns the diagnostics of the resource configuration
+// validation.
+func (p *testProvider) validate(typeName string, config tftypes.Value) []*tfprotov6.Diagnostic {
+	p.t.Helper()
+	response, err := p.server.ValidateResourceConfig(context.Background(), &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: typeName,
+		Config:   p.dynamic(typeName, config),
+	})
+	if err != nil {
+		p.t.Fatalf("unexpected error: %s", err)
+	}
+	return response.Diagnostics
+}
+
+// apply plans and applies the change from prior to config, a null config
+// destroys. It returns the new state and the diagnostics of the plan and
+// the apply.
+func (p *testProvider) apply(typeName string, prior tftypes.Value, config tftypes.Value) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+	planned := p.null(typeName)
+	if !config.IsNull() {
+		plan := p.plan(typeName, prior, config)
+		if diagnosticsError(plan.Diagnostics) != nil {
+			return prior, plan.Diagnostics
+		}
+		planned = p.value(typeName, plan.PlannedState)
+	}
+
+	response, err := p.server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     typeName,
+		PriorState:   p.dynamic(typeName, prior),
+		PlannedState: p.dynamic(typeName, planned),
+		Config:       p.dynamic(typeName, config),
+	})
+	if err != nil {
+		p.t.Fatalf("unexpected error: %s", err)
+	}
+	return p.value(typeName, response.NewState), response.Diagnostics
+}
+
+func (p *testProvider) read(typeName string, state tftypes.Value) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+	response, err := p.server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: p.dynamic(typeName, state),
+	})
+	if err != nil {
+		p.t.Fatalf("unexpected error: %s", err)
+	}
+	return p.value(typeName, response.NewState), response.Diagnostics
+}
+
+// importAndRead imports the id and reads the imported state, the way
+// terraform import does.
+func (p *testProvider) importAndRead(typeName string, id string) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+	response, err := p.server.ImportResourceState(context.Background(), &tfprotov6.ImportResourceStateRequest{
+		TypeName: typeName,
+		ID:       id,
+	})
+	if err != nil {
+		p.t.Fatalf("unexpected error: %s", err)
+	}
+	if diagnosticsError(response.Diagnostics) != nil || len(response.ImportedResources) != 1 {
+		return p.null(typeName), response.Diagnostics
+	}
+	return p.read(typeName, p.value(typeName, response.ImportedResources[0].State))
+}
+
+func (p *testProvider) dataSourceType(typeName string) tftypes.Object {
+	return p.schemas.DataSourceSchemas[typeName].ValueType().(tftypes.Object)
+}
+
+// readDataSource reads the data source with the attributes, every other
+// attribute is null.
+func (p *testProvider) readDataSource(typeName string, attributes map[string]tftypes.Value) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+	dataSourceType := p.dataSourceType(typeName)
+	response, err := p.server.ReadDataSource(context.Background(), &tfprotov6.ReadDataSourceRequest{
+		TypeName: typeName,
+		Config:   dynamicValue(p.t, dataSourceType, objectValue(dataSourceType, attributes)),
+	})
+	if err != nil {
+		p.t.Fatalf("unexpected error: %s", err)
+	}
+	if response.State == nil {
+		return tftypes.NewValue(dataSourceType, nil), response.Diagnostics
+	}
+	state, err := response.State.Unmarshal(dataSourceType)
+	if err != nil {
+		p.t.Fatalf("unexpected error: %s", err)
+	}
+	return state, response.Diagnostics
+}
+
+// proposedNewState keeps the prior value of the computed attributes the
+// config leaves null, the way Terraform does.
+func (p *testProvider) proposedNewState(typeName string, prior tftypes.Value, config tftypes.Value) tftypes.Value {
+	if prior.IsNull() || config.IsNull() {
+		return config
+	}
+	var priorAttributes, configAttributes map[string]tftypes.Value
+	if prior.As(&priorAttributes) != nil || config.As(&configAttributes) != nil {
+		return config
+	}
+	// As shares the attributes with config, the proposed state gets a copy.
+	proposed := make(map[string]tftypes.Value, len(configAttributes))
+	for name, value := range configAttributes {
+		proposed[name] = value
+	}
+	for _, schemaAttribute := range p.schemas.ResourceSchemas[typeName].Block.Attributes {
+		if schemaAttribute.Computed && configAttributes[schemaAttribute.Name].IsNull() {
+			proposed[schemaAttribute.Name] = priorAttributes[schemaAttribute.Name]
+		}
+	}
+	return tftypes.NewValue(config.Type(), proposed)
+}
+
+// attribute returns the attribute of an object value.
+func attribute(t *testing.T, object tftypes.Value, name string) tftypes.Value {
+	t.Helper()
+	var attributes map[string]tftypes.Value
+	if err := object.As(&attributes); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return attributes[name]
+}
+
+// stringAttribute returns a string attribute of an object value, empty when
+// it is null.
+func stringAttribute(t *testing.T, object tftypes.Value, name string) string {
+	t.Helper()
+	var value string
+	if err := attribute(t, object, name).As(&value); err != nil {
+		t.Fatalf("attribute %s: %s", name, err)
+	}
+	return value
+}
+
+// hasDiagnostic reports whether a diagnostic of the severity has the
+// summary.
+func hasDiagnostic(diagnostics []*tfprotov6.Diagnostic, severity tfprotov6.DiagnosticSeverity, summary string) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == severity && diagnostic.Summary == summary {
+			return true
+		}
+	}
+	return false
+}
+
+// TestExpectedOrganizationAliases configures two aliased providers against
+// two Terrakube instances, each checking its own expected organization.
+func TestExpectedOrganizationAliases(t *testing.T) {
+	t.Parallel()
+
+	primaryAPI, primaryServer := newFakeAPI(t)
+	primaryAPI.put("/api/v1/organization/o1", "organization", map[string]any{"name": "platform"})
+	sandboxAPI, sandboxServer := newFakeAPI(t)
+	sandboxAPI.put("/api/v1/organization/o2", "organization", map[string]any{"name": "sandbox"})
+	sandboxAPI.put("/api/v1/organization/o3", "organization", map[string]any{"name": "staging"})
+	expected := func(name string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{"expected_organization_name": tftypes.NewValue(tftypes.String, name)}
+	}
+
+	primary := newTestProvider(t, primaryServer.URL, expected("platform"))
+	sandbox := newTestProvider(t, sandboxServer.URL, expected("sandbox"))
+
+	_, diagnostics := configureTestProvider(t, sandboxServer.URL, expected("platform"))
+	if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, "Expected Terrakube organization not found") {
+		t.Fatalf("a token of another instance should be rejected, got %v", diagnostics)
+	}
+	if detail := diagnostics[0].Detail; !strings.Contains(detail, sandboxServer.URL) || !strings.Contains(detail, `["sandbox" "staging"]`) {
+		t.Errorf("the error should name the endpoint and the visible organizations: %s", detail)
+	}
+
+	for _, test := range []struct {
+		terrakube *testProvider
+		name      string
+		id        string
+	}{
+		{primary, "platform", "o1"},
+		{sandbox, "sandbox", "o2"},
+		{sandbox, "platform", ""},
+	} {
+		state, diagnostics := test.terrakube.readDataSource("terrakube_organization", map[string]tftypes.Value{
+			"name":          tftypes.NewValue(tftypes.String, test.name),
+			"allow_missing": tftypes.NewValue(tftypes.Bool, true),
+		})
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		if id := attribute(t, state, "id"); (test.id == "" && !id.IsNull()) || (test.id != "" && !id.Equal(tftypes.NewValue(tftypes.String, test.id))) {
+			t.Errorf("%s: expected the organization %q of the aliased endpoint, got %s", test.name, test.id, id)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// A short connect timeout fails fast on an unreachable endpoint so the
+// request is retried, while large listings of the registry may take a while
+// before the first byte of the response.
+const (
+	defaultConnectTimeout        = 10 * time.Second
+	defaultResponseHeaderTimeout = 2 * time.Minute
+)
+
+// parseTimeout returns the duration of a timeout attribute, or the default
+// when it is not set.
+func parseTimeout(value types.String, attribute string, defaultTimeout time.Duration, diags *diag.Diagnostics) time.Duration {
+	if value.IsNull() || value.IsUnknown() {
+		return defaultTimeout
+	}
+
+	timeout, err := time.ParseDuration(value.ValueString())
+	if err != nil || timeout <= 0 {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Invalid timeout",
+			fmt.Sprintf("%s must be a positive duration like \"30s\" or \"2m\", got %q.", attribute, value.ValueString()),
+		)
+		return 0
+	}
+
+	return timeout
+}
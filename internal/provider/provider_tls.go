@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ provider.ConfigValidator = tlsConfigValidator{}
+
+// tlsConfigValidator rejects the combinations of TLS settings whose result
+// would be unclear: insecure_http_client disables every verification, so a
+// custom CA, client certificates or per host exceptions cannot apply with it.
+type tlsConfigValidator struct{}
+
+func (v tlsConfigValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v tlsConfigValidator) MarkdownDescription(_ context.Context) string {
+	return "insecure_http_client cannot be combined with ca_cert, client_cert, client_key or insecure_hosts"
+}
+
+func (v tlsConfigValidator) ValidateProvider(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var insecure types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("insecure_http_client"), &insecure)...)
+	if resp.Diagnostics.HasError() || !insecure.ValueBool() {
+		return
+	}
+
+	certificateConflict := "insecure_http_client cannot be combined with client certificates, mutual TLS requires a verified server. Remove insecure_http_client, or add the server CA with ca_cert."
+	conflicts := map[string]string{
+		"ca_cert":     "insecure_http_client disables certificate verification, the custom CA would never be used. Remove insecure_http_client to verify the server with ca_cert.",
+		"client_cert": certificateConflict,
+		"client_key":  certificateConflict,
+	}
+
+	for _, attribute := range []string{"ca_cert", "client_cert", "client_key"} {
+		var value types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(attribute), &value)...)
+		if !value.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root(attribute), "Conflicting TLS configuration", conflicts[attribute])
+		}
+	}
+
+	var insecureHosts types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("insecure_hosts"), &insecureHosts)...)
+	if !insecureHosts.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("insecure_hosts"),
+			"Conflicting TLS configuration",
+			"insecure_http_client already disables verification for every host. Remove insecure_http_client to only skip verification for insecure_hosts.",
+		)
+	}
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestTLSConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := NewProtocol6WithRequestSummary(func() fwprovider.Provider { return New("test")() })()
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	providerType := schemas.Provider.ValueType().(tftypes.Object)
+
+	insecure := tftypes.NewValue(tftypes.Bool, true)
+	pem := tftypes.NewValue(tftypes.String, "-----BEGIN CERTIFICATE-----")
+	hosts := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "terrakube.internal")})
+	for _, test := range []struct {
+		name       string
+		attributes map[string]tftypes.Value
+		conflicts  []string
+	}{
+		{"insecure", map[string]tftypes.Value{"insecure_http_client": insecure}, nil},
+		{"ca_cert", map[string]tftypes.Value{"ca_cert": pem, "insecure_http_client": tftypes.NewValue(tftypes.Bool, false)}, nil},
+		{"mutual TLS", map[string]tftypes.Value{"ca_cert": pem, "client_cert": pem, "client_key": pem, "insecure_hosts": hosts}, nil},
+		{"unknown insecure", map[string]tftypes.Value{"insecure_http_client": tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue), "ca_cert": pem}, nil},
+		{"insecure and ca_cert", map[string]tftypes.Value{"insecure_http_client": insecure, "ca_cert": pem}, []string{"ca_cert"}},
+		{"insecure and client certificate", map[string]tftypes.Value{"insecure_http_client": insecure, "client_cert": pem, "client_key": pem}, []string{"client_cert", "client_key"}},
+		{"insecure and insecure_hosts", map[string]tftypes.Value{"insecure_http_client": insecure, "insecure_hosts": hosts}, []string{"insecure_hosts"}},
+	} {
+		response, err := server.ValidateProviderConfig(ctx, &tfprotov6.ValidateProviderConfigRequest{
+			Config: dynamicValue(t, providerType, objectValue(providerType, test.attributes)),
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+
+		var conflicts []string
+		for _, diagnostic := range response.Diagnostics {
+			if diagnostic.Summary != "Conflicting TLS configuration" || diagnostic.Attribute == nil {
+				t.Errorf("%s: unexpected diagnostic %s: %s", test.name, diagnostic.Summary, diagnostic.Detail)
+				continue
+			}
+			conflicts = append(conflicts, string(diagnostic.Attribute.Steps()[0].(tftypes.AttributeName)))
+		}
+		sort.Strings(conflicts)
+		if strings.Join(conflicts, ",") != strings.Join(test.conflicts, ",") {
+			t.Errorf("%s: expected conflicts on %v, got %v", test.name, test.conflicts, conflicts)
+		}
+	}
+
+	response, err := server.ValidateProviderConfig(ctx, &tfprotov6.ValidateProviderConfigRequest{
+		Config: dynamicValue(t, providerType, objectValue(providerType, map[string]tftypes.Value{"client_cert": pem})),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diagnosticsError(response.Diagnostics) == nil {
+		t.Errorf("client_cert without client_key should be rejected")
+	}
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func rawAttributesSchema() schema.MapAttribute {
+	return schema.MapAttribute{
+		Computed:    true,
+		ElementType: types.StringType,
+		Description: "Best effort copy of every attribute returned by the API, including the ones the provider does not know yet. " +
+			"Nested objects and lists are flattened to keys such as `parent.child` and `list.0`, numbers and booleans are converted to strings and null values are left out. " +
+			"The keys follow the API and may change between Terrakube versions, use the typed attributes whenever they exist.",
+	}
+}
+
+// rawAttributes returns the flattened attributes of every resource object of
+// a response, by id. The typed entities drop the fields the provider does
+// not know, so the body is decoded a second time here.
+func rawAttributes(body []byte) (map[string]map[string]string, error) {
+	var document struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, fmt.Errorf("unable to decode the attributes, response body: %s, error: %w", string(body), err)
+	}
+
+	result := map[string]map[string]string{}
+	primary := document.Data
+	if len(primary) == 0 {
+		return result, nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(primary), []byte("{")) {
+		primary = append(append([]byte("["), primary...), ']')
+	}
+
+	var resources []struct {
+		ID         string          `json:"id"`
+		Attributes json.RawMessage `json:"attributes"`
+	}
+	if err := json.Unmarshal(primary, &resources); err != nil {
+		return nil, fmt.Errorf("unable to decode the attributes, response body: %s, error: %w", string(body), err)
+	}
+
+	for _, data := range resources {
+		attributes := map[string]string{}
+		if len(data.Attributes) > 0 {
+			decoder := json.NewDecoder(bytes.NewReader(data.Attributes))
+			decoder.UseNumber()
+
+			var value interface{}
+			if err := decoder.Decode(&value); err != nil {
+				return nil, fmt.Errorf("unable to decode the attributes of %s: %w", data.ID, err)
+			}
+			flattenRawAttribute("", value, attributes)
+		}
+		result[data.ID] = attributes
+	}
+
+	return result, nil
+}
+
+// flattenRawAttribute adds value to attributes under key, objects and lists
+// are walked so every leaf gets its own dotted key.
+func flattenRawAttribute(key string, value interface{}, attributes map[string]string) {
+	join := func(child string) string {
+		if key == "" {
+			return child
+		}
+		return key + "." + child
+	}
+
+	switch v := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for child, item := range v {
+			flattenRawAttribute(join(child), item, attributes)
+		}
+	case []interface{}:
+		for index, item := range v {
+			flattenRawAttribute(join(strconv.Itoa(index)), item, attributes)
+		}
+	case string:
+		attributes[key] = v
+	case bool:
+		attributes[key] = strconv.FormatBool(v)
+	case json.Number:
+		attributes[key] = v.String()
+	default:
+		attributes[key] = fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestRawAttributes(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name       string
+		body       string
+		attributes map[string]map[string]string
+	}{
+		{
+			"single resource",
+			`{"data":{"type":"team","id":"t1","attributes":{"name":"platform","manageJob":true,"limit":12345678901234567890,"ratio":0.5,"owner":null}}}`,
+			map[string]map[string]string{"t1": {"name": "platform", "manageJob": "true", "limit": "12345678901234567890", "ratio": "0.5"}},
+		},
+		{
+			"collection",
+			`{"data":[{"type":"team","id":"t1","attributes":{"name":"a"}},{"type":"team","id":"t2","attributes":{"name":"b"}}]}`,
+			map[string]map[string]string{"t1": {"name": "a"}, "t2": {"name": "b"}},
+		},
+		{
+			"nested values",
+			`{"data":{"type":"module","id":"m1","attributes":{"registry":{"path":"platform/vpc/aws","tags":["v1","v2"]},"versions":[{"version":"1.0.0"}],"empty":{}}}}`,
+			map[string]map[string]string{"m1": {"registry.path": "platform/vpc/aws", "registry.tags.0": "v1", "registry.tags.1": "v2", "versions.0.version": "1.0.0"}},
+		},
+		{
+			"no attributes",
+			`{"data":{"type":"team","id":"t1"}}`,
+			map[string]map[string]string{"t1": {}},
+		},
+		{
+			"no data",
+			`{"data":[]}`,
+			map[string]map[string]string{},
+		},
+		{
+			"null data",
+			`{"meta":{}}`,
+			map[string]map[string]string{},
+		},
+	} {
+		attributes, err := rawAttributes([]byte(test.body))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(attributes, test.attributes) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.attributes, attributes)
+		}
+	}
+
+	for _, body := range []string{`<html>`, `{"data":"team"}`} {
+		if _, err := rawAttributes([]byte(body)); err == nil {
+			t.Errorf("decoding %s should fail", body)
+		}
+	}
+}
+
+func TestRawAttributesDataSources(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.put("/api/v1/organization/o1/team/t1", "team", map[string]any{"name": "platform", "manageJob": true, "manageRunners": true})
+	api.put("/api/v1/organization/o1/module/m1", "module", map[string]any{"name": "vpc", "provider": "aws", "source": "https://github.com/platform/vpc.git", "downloadQuota": map[string]any{"daily": 10}})
+	var mu sync.Mutex
+	var queries []string
+	api.handle = func(w http.ResponseWriter, r *http.Request) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		queries = append(queries, r.URL.RawQuery)
+		return false
+	}
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	for _, test := range []struct {
+		typeName   string
+		config     map[string]tftypes.Value
+		attributes map[string]string
+	}{
+		{
+			"terrakube_team",
+			map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "platform")},
+			map[string]string{"name": "platform", "manageJob": "true", "manageRunners": "true"},
+		},
+		{
+			"terrakube_module",
+			map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "vpc"), "provider_name": tftypes.NewValue(tftypes.String, "aws")},
+			map[string]string{"name": "vpc", "provider": "aws", "source": "https://github.com/platform/vpc.git", "downloadQuota.daily": "10"},
+		},
+	} {
+		test.config["organization_id"] = tftypes.NewValue(tftypes.String, "o1")
+		state, diagnostics := terrakube.readDataSource(test.typeName, test.config)
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.typeName, err)
+		}
+
+		var raw map[string]tftypes.Value
+		if err := attribute(t, state, "raw_attributes").As(&raw); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.typeName, err)
+		}
+		attributes := map[string]string{}
+		for key, value := range raw {
+			var text string
+			value.As(&text)
+			attributes[key] = text
+		}
+		if !reflect.DeepEqual(attributes, test.attributes) {
+			t.Errorf("%s: expected the raw attributes %v, got %v", test.typeName, test.attributes, attributes)
+		}
+	}
+
+	// The unknown attributes are only returned without sparse fieldsets.
+	mu.Lock()
+	defer mu.Unlock()
+	for _, query := range queries {
+		values, _ := url.ParseQuery(query)
+		for key := range values {
+			if strings.HasPrefix(key, "fields[") {
+				t.Errorf("the data sources should ask for every attribute, got the query %s", query)
+			}
+		}
+	}
+}
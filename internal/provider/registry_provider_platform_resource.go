@@ -0,0 +1,339 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RegistryProviderPlatformResource{}
+var _ resource.ResourceWithImportState = &RegistryProviderPlatformResource{}
+var _ resource.ResourceWithValidateConfig = &RegistryProviderPlatformResource{}
+
+// providerPlatforms lists the architectures Terraform publishes providers
+// for on each operating system.
+var providerPlatforms = map[string][]string{
+	"darwin":  {"amd64", "arm64"},
+	"freebsd": {"386", "amd64", "arm"},
+	"linux":   {"386", "amd64", "arm", "arm64"},
+	"openbsd": {"386", "amd64"},
+	"solaris": {"amd64"},
+	"windows": {"386", "amd64", "arm64"},
+}
+
+type RegistryProviderPlatformResource struct {
+	client          *http.Client
+	endpoint        string
+	token           string
+	implementations *client.Crud[client.RegistryProviderImplementationEntity]
+}
+
+type RegistryProviderPlatformResourceModel struct {
+	ID                  types.String `tfsdk:"id"`
+	OrganizationId      types.String `tfsdk:"organization_id"`
+	ProviderId          types.String `tfsdk:"provider_id"`
+	VersionId           types.String `tfsdk:"version_id"`
+	Os                  types.String `tfsdk:"os"`
+	Arch                types.String `tfsdk:"arch"`
+	Filename            types.String `tfsdk:"filename"`
+	DownloadUrl         types.String `tfsdk:"download_url"`
+	Shasum              types.String `tfsdk:"shasum"`
+	ShasumsUrl          types.String `tfsdk:"shasums_url"`
+	ShasumsSignatureUrl types.String `tfsdk:"shasums_signature_url"`
+	KeyId               types.String `tfsdk:"key_id"`
+	AsciiArmor          types.String `tfsdk:"ascii_armor"`
+}
+
+func NewRegistryProviderPlatformResource() resource.Resource {
+	return &RegistryProviderPlatformResource{}
+}
+
+func (r *RegistryProviderPlatformResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_provider_platform"
+}
+
+func (r *RegistryProviderPlatformResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Declare the binary of a private registry provider version for one platform, with the checksums and GPG key Terraform uses to verify it.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Provider platform Id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"provider_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Id of the registry provider",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Id of the provider version",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"os": schema.StringAttribute{
+				Required:    true,
+				Description: "Operating system of the binary, for example `linux`. Changing it forces a new platform.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"arch": schema.StringAttribute{
+				Required:    true,
+				Description: "Architecture of the binary, for example `amd64`. Changing it forces a new platform.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"filename": schema.StringAttribute{
+				Required:    true,
+				Description: "File name of the binary archive, for example `terraform-provider-random_3.6.0_linux_amd64.zip`.",
+			},
+			"download_url": schema.StringAttribute{
+				Required:    true,
+				Description: "URL of the binary archive.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://.+$`), "must be an http or https URL"),
+				},
+			},
+			"shasum": schema.StringAttribute{
+				Required:    true,
+				Description: "SHA256 checksum of the binary archive, as 64 hexadecimal characters.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-fA-F]{64}$`), "must be a SHA256 checksum"),
+				},
+			},
+			"shasums_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "URL of the SHA256SUMS file of the version.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://.+$`), "must be an http or https URL"),
+				},
+			},
+			"shasums_signature_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "URL of the GPG signature of the SHA256SUMS file.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://.+$`), "must be an http or https URL"),
+				},
+			},
+			"key_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Id of the GPG key that signed the SHA256SUMS file, for example `51852D87348FFC4C`.",
+			},
+			"ascii_armor": schema.StringAttribute{
+				Optional:    true,
+				Description: "ASCII armored public GPG key that signed the SHA256SUMS file.",
+			},
+		},
+	}
+}
+
+func (r *RegistryProviderPlatformResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config RegistryProviderPlatformResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Os.IsUnknown() || config.Arch.IsUnknown() {
+		return
+	}
+
+	architectures, ok := providerPlatforms[config.Os.ValueString()]
+	if !ok {
+		systems := make([]string, 0, len(providerPlatforms))
+		for system := range providerPlatforms {
+			systems = append(systems, system)
+		}
+		sort.Strings(systems)
+		resp.Diagnostics.AddAttributeError(path.Root("os"), "Unknown operating system", fmt.Sprintf("os must be one of %s, got %q.", strings.Join(systems, ", "), config.Os.ValueString()))
+		return
+	}
+
+	if !containsString(architectures, config.Arch.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("arch"), "Unknown architecture", fmt.Sprintf("arch must be one of %s for %s, got %q.", strings.Join(architectures, ", "), config.Os.ValueString(), config.Arch.ValueString()))
+	}
+}
+
+func (r *RegistryProviderPlatformResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Registry Provider Platform Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+	r.implementations = client.NewCrud[client.RegistryProviderImplementationEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/provider/%s/version/%s/implementation")
+
+	tflog.Debug(ctx, "Configuring Registry Provider Platform resource", map[string]any{"success": true})
+}
+
+func (r *RegistryProviderPlatformResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RegistryProviderPlatformResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	implementation, err := r.implementations.Create(ctx, providerImplementationEntity(plan), plan.OrganizationId.ValueString(), plan.ProviderId.ValueString(), plan.VersionId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing registry provider platform resource request", apiErrorDetail(err, fmt.Sprintf("Error executing registry provider platform resource request: %s", err)))
+		return
+	}
+
+	plan.ID = types.StringValue(implementation.ID)
+
+	tflog.Info(ctx, "Registry Provider Platform Resource Created", map[string]any{"success": true})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *RegistryProviderPlatformResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state RegistryProviderPlatformResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	implementation, err := r.implementations.Get(ctx, state.ID.ValueString(), state.OrganizationId.ValueString(), state.ProviderId.ValueString(), state.VersionId.ValueString())
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing registry provider platform resource request", apiErrorDetail(err, fmt.Sprintf("Error executing registry provider platform resource request: %s", err)))
+		return
+	}
+
+	state.ID = types.StringValue(implementation.ID)
+	state.Os = types.StringValue(implementation.Os)
+	state.Arch = types.StringValue(implementation.Arch)
+	state.Filename = types.StringValue(implementation.Filename)
+	state.DownloadUrl = types.StringValue(implementation.DownloadUrl)
+	state.Shasum = types.StringValue(implementation.Shasum)
+	state.ShasumsUrl = stringFromAPI(implementation.ShasumsUrl)
+	state.ShasumsSignatureUrl = stringFromAPI(implementation.ShasumsSignatureUrl)
+	state.KeyId = stringFromAPI(implementation.KeyId)
+	state.AsciiArmor = stringFromAPI(implementation.AsciiArmor)
+
+	// Set refreshed state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Registry Provider Platform Resource reading", map[string]any{"success": true})
+}
+
+func (r *RegistryProviderPlatformResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan RegistryProviderPlatformResourceModel
+	var state RegistryProviderPlatformResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	bodyRequest := providerImplementationEntity(plan)
+	bodyRequest.ID = state.ID.ValueString()
+
+	err := r.implementations.Update(ctx, state.ID.ValueString(), bodyRequest, state.OrganizationId.ValueString(), state.ProviderId.ValueString(), state.VersionId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing registry provider platform resource request", apiErrorDetail(err, fmt.Sprintf("Error executing registry provider platform resource request: %s", err)))
+		return
+	}
+
+	plan.ID = state.ID
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *RegistryProviderPlatformResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RegistryProviderPlatformResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only this implementation is deleted, the version and its other
+	// platforms are kept.
+	err := r.implementations.Delete(ctx, data.ID.ValueString(), data.OrganizationId.ValueString(), data.ProviderId.ValueString(), data.VersionId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing registry provider platform resource request", apiErrorDetail(err, fmt.Sprintf("Error executing registry provider platform resource request: %s", err)))
+		return
+	}
+}
+
+func (r *RegistryProviderPlatformResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 4 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" || idParts[3] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,provider_ID,version_ID,ID', Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("provider_id"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("version_id"), idParts[2])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[3])...)
+}
+
+func providerImplementationEntity(plan RegistryProviderPlatformResourceModel) *client.RegistryProviderImplementationEntity {
+	return &client.RegistryProviderImplementationEntity{
+		Os:                  plan.Os.ValueString(),
+		Arch:                plan.Arch.ValueString(),
+		Filename:            plan.Filename.ValueString(),
+		DownloadUrl:         plan.DownloadUrl.ValueString(),
+		Shasum:              plan.Shasum.ValueString(),
+		ShasumsUrl:          plan.ShasumsUrl.ValueString(),
+		ShasumsSignatureUrl: plan.ShasumsSignatureUrl.ValueString(),
+		KeyId:               plan.KeyId.ValueString(),
+		AsciiArmor:          plan.AsciiArmor.ValueString(),
+	}
+}
@@ -0,0 +1,277 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RegistryProviderVersionResource{}
+var _ resource.ResourceWithImportState = &RegistryProviderVersionResource{}
+
+type RegistryProviderVersionResource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+	versions *client.Crud[client.RegistryProviderVersionEntity]
+}
+
+type RegistryProviderVersionResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	OrganizationId types.String `tfsdk:"organization_id"`
+	ProviderId     types.String `tfsdk:"provider_id"`
+	VersionNumber  types.String `tfsdk:"version_number"`
+	Protocols      types.List   `tfsdk:"protocols"`
+}
+
+func NewRegistryProviderVersionResource() resource.Resource {
+	return &RegistryProviderVersionResource{}
+}
+
+func (r *RegistryProviderVersionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_provider_version"
+}
+
+func (r *RegistryProviderVersionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Declare a version of a provider published in the private registry of an organization. " +
+			"The binaries of the version and their signing key are declared for each platform.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Provider version Id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"provider_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Id of the registry provider",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version_number": schema.StringAttribute{
+				Required:    true,
+				Description: "Version number without a leading v, for example `1.2.0`. Changing it forces a new version.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`), "must be a semantic version like 1.2.0"),
+				},
+			},
+			"protocols": schema.ListAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "Plugin protocol versions supported by the version, for example `[\"5.0\"]`.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.RegexMatches(regexp.MustCompile(`^\d+(\.\d+)?$`), "must be a protocol version like 5.0")),
+				},
+			},
+		},
+	}
+}
+
+func (r *RegistryProviderVersionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Registry Provider Version Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+	r.versions = client.NewCrud[client.RegistryProviderVersionEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/provider/%s/version")
+
+	tflog.Debug(ctx, "Configuring Registry Provider Version resource", map[string]any{"success": true})
+}
+
+func (r *RegistryProviderVersionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RegistryProviderVersionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	protocols, diags := providerProtocols(ctx, plan.Protocols)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	bodyRequest := &client.RegistryProviderVersionEntity{
+		VersionNumber: plan.VersionNumber.ValueString(),
+		Protocols:     protocols,
+		Provider:      &client.RegistryProviderEntity{ID: plan.ProviderId.ValueString()},
+	}
+
+	version, err := r.versions.Create(ctx, bodyRequest, plan.OrganizationId.ValueString(), plan.ProviderId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing registry provider version resource request", apiErrorDetail(err, fmt.Sprintf("Error executing registry provider version resource request: %s", err)))
+		return
+	}
+
+	plan.ID = types.StringValue(version.ID)
+	plan.VersionNumber = types.StringValue(version.VersionNumber)
+	plan.Protocols, diags = providerProtocolsValue(version.Protocols)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Info(ctx, "Registry Provider Version Resource Created", map[string]any{"success": true})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *RegistryProviderVersionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state RegistryProviderVersionResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, err := r.versions.Get(ctx, state.ID.ValueString(), state.OrganizationId.ValueString(), state.ProviderId.ValueString())
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing registry provider version resource request", apiErrorDetail(err, fmt.Sprintf("Error executing registry provider version resource request: %s", err)))
+		return
+	}
+
+	var diags diag.Diagnostics
+	state.ID = types.StringValue(version.ID)
+	state.VersionNumber = types.StringValue(version.VersionNumber)
+	state.Protocols, diags = providerProtocolsValue(version.Protocols)
+	resp.Diagnostics.Append(diags...)
+
+	// Set refreshed state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Registry Provider Version Resource reading", map[string]any{"success": true})
+}
+
+func (r *RegistryProviderVersionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan RegistryProviderVersionResourceModel
+	var state RegistryProviderVersionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	protocols, diags := providerProtocols(ctx, plan.Protocols)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	bodyRequest := &client.RegistryProviderVersionEntity{
+		ID:            state.ID.ValueString(),
+		VersionNumber: plan.VersionNumber.ValueString(),
+		Protocols:     protocols,
+	}
+
+	err := r.versions.Update(ctx, state.ID.ValueString(), bodyRequest, state.OrganizationId.ValueString(), state.ProviderId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing registry provider version resource request", apiErrorDetail(err, fmt.Sprintf("Error executing registry provider version resource request: %s", err)))
+		return
+	}
+
+	plan.ID = state.ID
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *RegistryProviderVersionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RegistryProviderVersionResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.versions.Delete(ctx, data.ID.ValueString(), data.OrganizationId.ValueString(), data.ProviderId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing registry provider version resource request", apiErrorDetail(err, fmt.Sprintf("Error executing registry provider version resource request: %s", err)))
+		return
+	}
+}
+
+func (r *RegistryProviderVersionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,provider_ID,ID', Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("provider_id"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[2])...)
+}
+
+// providerProtocols returns the protocols as stored by Terrakube, a comma
+// separated string.
+func providerProtocols(ctx context.Context, protocols types.List) (string, diag.Diagnostics) {
+	var values []string
+	diags := protocols.ElementsAs(ctx, &values, false)
+	return strings.Join(values, ","), diags
+}
+
+func providerProtocolsValue(protocols string) (types.List, diag.Diagnostics) {
+	var values []attr.Value
+	for _, protocol := range strings.Split(protocols, ",") {
+		if protocol = strings.TrimSpace(protocol); protocol != "" {
+			values = append(values, types.StringValue(protocol))
+		}
+	}
+	return types.ListValue(types.StringType, values)
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// requestSummaryServer logs the request summary of the plugin process at
+// the end of the calls that reach the API. Terraform stops the plugin
+// without a last call and the plugin logs are no longer forwarded once the
+// server stopped, so the last summary logged is the one of the whole run.
+type requestSummaryServer struct {
+	tfprotov6.ProviderServer
+
+	mu     sync.Mutex
+	logged int
+}
+
+// NewProtocol6WithRequestSummary returns the protocol 6 server of the
+// provider for tf6server.Serve, logging the request summary.
+func NewProtocol6WithRequestSummary(p func() provider.Provider) func() tfprotov6.ProviderServer {
+	return func() tfprotov6.ProviderServer {
+		return &requestSummaryServer{ProviderServer: providerserver.NewProtocol6(p())()}
+	}
+}
+
+// logRequestSummary logs the summary with tflog and writes it to
+// metrics_path when requests were sent since the last summary.
+func (s *requestSummaryServer) logRequestSummary(ctx context.Context) {
+	summary := requestMetrics.Summary()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if summary.TotalRequests == s.logged {
+		return
+	}
+	s.logged = summary.TotalRequests
+
+	tflog.Info(ctx, "Terrakube provider request summary", map[string]any{
+		"total_requests":   summary.TotalRequests,
+		"retries":          summary.Retries,
+		"errors_by_status": summary.ErrorsByStatus,
+	})
+
+	if err := requestMetrics.WriteFile(); err != nil {
+		tflog.Warn(ctx, "Unable to write Terrakube provider request summary", map[string]any{"error": err.Error()})
+	}
+}
+
+func (s *requestSummaryServer) ConfigureProvider(ctx context.Context, req *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	defer s.logRequestSummary(ctx)
+	return s.ProviderServer.ConfigureProvider(ctx, req)
+}
+
+func (s *requestSummaryServer) ReadResource(ctx context.Context, req *tfprotov6.ReadResourceRequest) (*tfprotov6.ReadResourceResponse, error) {
+	defer s.logRequestSummary(ctx)
+	return s.ProviderServer.ReadResource(ctx, req)
+}
+
+func (s *requestSummaryServer) PlanResourceChange(ctx context.Context, req *tfprotov6.PlanResourceChangeRequest) (*tfprotov6.PlanResourceChangeResponse, error) {
+	defer s.logRequestSummary(ctx)
+	return s.ProviderServer.PlanResourceChange(ctx, req)
+}
+
+func (s *requestSummaryServer) ApplyResourceChange(ctx context.Context, req *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	defer s.logRequestSummary(ctx)
+	return s.ProviderServer.ApplyResourceChange(ctx, req)
+}
+
+func (s *requestSummaryServer) ImportResourceState(ctx context.Context, req *tfprotov6.ImportResourceStateRequest) (*tfprotov6.ImportResourceStateResponse, error) {
+	defer s.logRequestSummary(ctx)
+	return s.ProviderServer.ImportResourceState(ctx, req)
+}
+
+func (s *requestSummaryServer) ReadDataSource(ctx context.Context, req *tfprotov6.ReadDataSourceRequest) (*tfprotov6.ReadDataSourceResponse, error) {
+	defer s.logRequestSummary(ctx)
+	return s.ProviderServer.ReadDataSource(ctx, req)
+}
+
+// MoveResourceState is forwarded explicitly, tf6server only calls it on
+// servers implementing ResourceServerWithMoveResourceState.
+func (s *requestSummaryServer) MoveResourceState(ctx context.Context, req *tfprotov6.MoveResourceStateRequest) (*tfprotov6.MoveResourceStateResponse, error) {
+	return s.ProviderServer.(tfprotov6.ResourceServerWithMoveResourceState).MoveResourceState(ctx, req)
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"terraform-provider-terrakube/internal/client"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// TestRequestSummaryWritesMetrics is not parallel, metrics_path sets the
+// path of the metrics shared by the whole process.
+func TestRequestSummaryWritesMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	t.Cleanup(func() { requestMetrics.SetPath("") })
+
+	_, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, map[string]tftypes.Value{
+		"metrics_path": tftypes.NewValue(tftypes.String, path),
+	})
+	os.Remove(path)
+
+	_, diagnostics := terrakube.readDataSource("terrakube_organization_variables", map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+	})
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("the metrics file was not written after the read: %s", err)
+	}
+	var summary client.MetricsSummary
+	if err := json.Unmarshal(content, &summary); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if summary.TotalRequests != requestMetrics.Summary().TotalRequests {
+		t.Errorf("the metrics file has %d requests, expected %d", summary.TotalRequests, requestMetrics.Summary().TotalRequests)
+	}
+}
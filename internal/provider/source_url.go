@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"net/url"
+	"strings"
+)
+
+// sourceHost extracts the host of a repository url. Besides urls with a
+// scheme it understands the scp-like syntax "git@github.com:org/repo.git",
+// go-getter forced getters like "git::https://..." and bare
+// "github.com/org/repo" sources.
+func sourceHost(source string) string {
+	source = strings.TrimSpace(source)
+	if getter, rest, found := strings.Cut(source, "::"); found && !strings.ContainsAny(getter, "/:@") {
+		source = rest
+	}
+
+	if strings.Contains(source, "://") {
+		parsed, err := url.Parse(source)
+		if err != nil {
+			return ""
+		}
+		return normalizeHost(parsed.Hostname())
+	}
+
+	// scp-like syntax, the host ends at the first colon, before any slash.
+	host := source
+	if at := strings.Index(host, "@"); at >= 0 && at < strings.IndexAny(host+"/", "/") {
+		host = host[at+1:]
+	}
+	if end := strings.IndexAny(host, ":/"); end >= 0 {
+		host = host[:end]
+	}
+
+	return normalizeHost(host)
+}
+
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+	return strings.TrimPrefix(host, "www.")
+}
+
+// moduleRegistryAddress returns the lowercase "organization/name/provider"
+// of a registry module source such as
+// "registry.example.com/org/vpc/aws//modules/subnet?ref=1.0.0", and an empty
+// string for any other source, a git repository url in particular.
+func moduleRegistryAddress(source string) string {
+	source = strings.TrimSpace(source)
+	if strings.Contains(source, "::") || strings.Contains(source, "://") || strings.Contains(source, "@") {
+		return ""
+	}
+
+	source, _, _ = strings.Cut(source, "?")
+	source, _, _ = strings.Cut(source, "//")
+	segments := strings.Split(strings.Trim(source, "/"), "/")
+	if len(segments) != 4 || !strings.ContainsAny(segments[0], ".:") {
+		return ""
+	}
+	for _, segment := range segments[1:] {
+		if segment == "" {
+			return ""
+		}
+	}
+
+	return strings.ToLower(strings.Join(segments[1:], "/"))
+}
@@ -0,0 +1,24 @@
+package provider
+
+import "testing"
+
+func TestModuleRegistryAddress(t *testing.T) {
+	t.Parallel()
+
+	for source, expected := range map[string]string{
+		"registry.example.com/Org/vpc/aws":                           "org/vpc/aws",
+		"registry.example.com/org/vpc/aws//modules/subnet?ref=1.0.0": "org/vpc/aws",
+		"localhost:8075/org/vpc/aws":                                 "org/vpc/aws",
+		"https://github.com/org/vpc.git":                             "",
+		"git@github.com:org/vpc.git":                                 "",
+		"git::https://github.com/org/vpc//modules/subnet":            "",
+		"github.com/org/vpc":                                         "",
+		"github.com/org/vpc/aws-extra/more":                          "",
+		"org/vpc/aws":                                                "",
+		"":                                                           "",
+	} {
+		if address := moduleRegistryAddress(source); address != expected {
+			t.Errorf("moduleRegistryAddress(%q) = %q, expected %q", source, address, expected)
+		}
+	}
+}
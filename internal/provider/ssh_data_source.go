@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -53,17 +52,7 @@ func (d *SshDataSource) Configure(ctx context.Context, req datasource.ConfigureR
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			d.client = &http.Client{Transport: customTransport}
-		} else {
-			d.client = &http.Client{}
-		}
-	} else {
-		d.client = &http.Client{}
-	}
+	d.client = providerData.HttpClient
 	d.endpoint = providerData.Endpoint
 	d.token = providerData.Token
 
@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// sshKeyTypes maps the public key algorithms to the ssh_type values Terrakube
+// accepts.
+var sshKeyTypes = map[string]string{
+	ssh.KeyAlgoRSA:     "rsa",
+	ssh.KeyAlgoED25519: "ed25519",
+}
+
+// sshPrivateKeyFingerprint returns the SHA256 fingerprint of the public half
+// of a PEM encoded private key, in the SHA256:... format printed by
+// ssh-keygen -l, with the ssh_type of the key.
+func sshPrivateKeyFingerprint(privateKey string) (string, string, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to parse the private key, only unencrypted rsa and ed25519 keys in PEM or OpenSSH format are supported: %w", err)
+	}
+
+	publicKey := signer.PublicKey()
+	sshType, ok := sshKeyTypes[publicKey.Type()]
+	if !ok {
+		return "", "", fmt.Errorf("the private key is a %s key, only rsa and ed25519 keys are supported", publicKey.Type())
+	}
+
+	return ssh.FingerprintSHA256(publicKey), sshType, nil
+}
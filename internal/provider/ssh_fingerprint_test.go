@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// The keys of testdata were generated for these tests only, the expected
+// fingerprints are the ones printed by ssh-keygen -l.
+const (
+	rsaKeyFingerprint     = "SHA256:CGFHQAs6kx13g/cRnPfNkOGFX4jfmWxr/Cz4jIpYHbI"
+	ed25519KeyFingerprint = "SHA256:0cL6/VWeRNq9D7rdKCcB1V/bjgsYJJYe+TMCgCI+09A"
+)
+
+func readTestKey(t *testing.T, name string) string {
+	t.Helper()
+	key, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return string(key)
+}
+
+func TestSshPrivateKeyFingerprint(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		key         string
+		fingerprint string
+		sshType     string
+		err         string
+	}{
+		{"ssh_rsa.key", rsaKeyFingerprint, "rsa", ""},
+		{"ssh_ed25519.key", ed25519KeyFingerprint, "ed25519", ""},
+		{"ssh_ecdsa.key", "", "", "only rsa and ed25519 keys are supported"},
+	} {
+		fingerprint, sshType, err := sshPrivateKeyFingerprint(readTestKey(t, test.key))
+		switch {
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: expected the error %q, got %v", test.key, test.err, err)
+		case test.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %s", test.key, err)
+		case fingerprint != test.fingerprint || sshType != test.sshType:
+			t.Errorf("%s: expected %s %s, got %s %s", test.key, test.fingerprint, test.sshType, fingerprint, sshType)
+		}
+	}
+
+	if _, _, err := sshPrivateKeyFingerprint("not a key"); err == nil || !strings.Contains(err.Error(), "unable to parse the private key") {
+		t.Errorf("an invalid key should fail to parse, got %v", err)
+	}
+}
+
+func TestSshFingerprintPlan(t *testing.T) {
+	t.Parallel()
+
+	_, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+	rsaKey := tftypes.NewValue(tftypes.String, readTestKey(t, "ssh_rsa.key"))
+	ed25519Key := tftypes.NewValue(tftypes.String, readTestKey(t, "ssh_ed25519.key"))
+	config := func(privateKey tftypes.Value, sshType string) tftypes.Value {
+		return terrakube.object("terrakube_ssh", map[string]tftypes.Value{
+			"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+			"name":            tftypes.NewValue(tftypes.String, "deploy"),
+			"private_key":     privateKey,
+			"ssh_type":        tftypes.NewValue(tftypes.String, sshType),
+		})
+	}
+
+	plan := terrakube.plan("terrakube_ssh", terrakube.null("terrakube_ssh"), config(ed25519Key, "ed25519"))
+	if err := diagnosticsError(plan.Diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fingerprint := stringAttribute(t, terrakube.value("terrakube_ssh", plan.PlannedState), "fingerprint"); fingerprint != ed25519KeyFingerprint {
+		t.Errorf("expected the planned fingerprint %s, got %s", ed25519KeyFingerprint, fingerprint)
+	}
+	if len(plan.Diagnostics) != 0 {
+		t.Errorf("a matching ssh_type should not warn, got %v", plan.Diagnostics)
+	}
+
+	plan = terrakube.plan("terrakube_ssh", terrakube.null("terrakube_ssh"), config(rsaKey, "ed25519"))
+	if !hasDiagnostic(plan.Diagnostics, tfprotov6.DiagnosticSeverityWarning, "SSH key type mismatch") {
+		t.Errorf("an rsa key with ssh_type ed25519 should warn, got %v", plan.Diagnostics)
+	}
+
+	plan = terrakube.plan("terrakube_ssh", terrakube.null("terrakube_ssh"), config(tftypes.NewValue(tftypes.String, readTestKey(t, "ssh_ecdsa.key")), "rsa"))
+	if !hasDiagnostic(plan.Diagnostics, tfprotov6.DiagnosticSeverityError, "Invalid SSH private key") {
+		t.Errorf("an ecdsa key should be rejected, got %v", plan.Diagnostics)
+	}
+
+	// A fingerprint refreshed from a replaced key replaces the resource.
+	for _, test := range []struct {
+		stored  string
+		replace bool
+	}{
+		{rsaKeyFingerprint, false},
+		{ed25519KeyFingerprint, true},
+	} {
+		state := terrakube.object("terrakube_ssh", map[string]tftypes.Value{
+			"id":              tftypes.NewValue(tftypes.String, "s1"),
+			"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+			"name":            tftypes.NewValue(tftypes.String, "deploy"),
+			"private_key":     rsaKey,
+			"ssh_type":        tftypes.NewValue(tftypes.String, "rsa"),
+			"fingerprint":     tftypes.NewValue(tftypes.String, test.stored),
+		})
+		plan := terrakube.plan("terrakube_ssh", state, config(rsaKey, "rsa"))
+		if err := diagnosticsError(plan.Diagnostics); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		replaced := false
+		for _, attributePath := range plan.RequiresReplace {
+			if attributePath.Steps()[0] == tftypes.AttributeName("fingerprint") {
+				replaced = true
+			}
+		}
+		if replaced != test.replace {
+			t.Errorf("stored fingerprint %s: expected a replacement %t, got %v", test.stored, test.replace, plan.RequiresReplace)
+		}
+	}
+}
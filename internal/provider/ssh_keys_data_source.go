@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &SshKeysDataSource{}
+	_ datasource.DataSourceWithConfigure = &SshKeysDataSource{}
+)
+
+type SshKeysDataSourceModel struct {
+	OrganizationId types.String          `tfsdk:"organization_id"`
+	SshKeys        []SshKeyListItemModel `tfsdk:"ssh_keys"`
+}
+
+type SshKeyListItemModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	SshType     types.String `tfsdk:"ssh_type"`
+}
+
+type SshKeysDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewSshKeysDataSource() datasource.DataSource {
+	return &SshKeysDataSource{}
+}
+
+func (d *SshKeysDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Ssh Keys Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Ssh Keys datasource")
+}
+
+func (d *SshKeysDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ssh_keys"
+}
+
+func (d *SshKeysDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List all the ssh keys of an organization sorted by name. Private keys are never returned.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+			},
+			"ssh_keys": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Ssh keys defined in the organization",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Ssh Id",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Ssh Name",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Ssh description information",
+						},
+						"ssh_type": schema.StringAttribute{
+							Computed:    true,
+							Description: "Ssh key type (rsa or ed25519)",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *SshKeysDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state SshKeysDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sshList, err := fetchAllPages(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/organization/%s/ssh", d.endpoint, state.OrganizationId.ValueString()), reflect.TypeOf(new(client.SshEntity)))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading ssh keys", apiErrorDetail(err, fmt.Sprintf("Error reading ssh keys: %s", err)))
+		return
+	}
+
+	state.SshKeys = make([]SshKeyListItemModel, 0, len(sshList))
+	for _, ssh := range sshList {
+		data, _ := ssh.(*client.SshEntity)
+		state.SshKeys = append(state.SshKeys, SshKeyListItemModel{
+			ID:          types.StringValue(data.ID),
+			Name:        types.StringValue(data.Name),
+			Description: types.StringValue(data.Description),
+			SshType:     types.StringValue(data.SshType),
+		})
+	}
+
+	sort.SliceStable(state.SshKeys, func(i, j int) bool {
+		if state.SshKeys[i].Name.ValueString() != state.SshKeys[j].Name.ValueString() {
+			return state.SshKeys[i].Name.ValueString() < state.SshKeys[j].Name.ValueString()
+		}
+		return state.SshKeys[i].ID.ValueString() < state.SshKeys[j].ID.ValueString()
+	})
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -26,11 +25,13 @@ import (
 
 var _ resource.Resource = &SshResource{}
 var _ resource.ResourceWithImportState = &SshResource{}
+var _ resource.ResourceWithModifyPlan = &SshResource{}
 
 type SshResource struct {
 	client   *http.Client
 	endpoint string
 	token    string
+	warnings *warningPolicy
 }
 
 type SshResourceModel struct {
@@ -40,6 +41,7 @@ type SshResourceModel struct {
 	Description    types.String `tfsdk:"description"`
 	PrivateKey     types.String `tfsdk:"private_key"`
 	SshType        types.String `tfsdk:"ssh_type"`
+	Fingerprint    types.String `tfsdk:"fingerprint"`
 }
 
 func NewSshResource() resource.Resource {
@@ -79,7 +81,17 @@ func (r *SshResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 			"private_key": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				Description: "SSH Key content",
+				Description: "SSH Key content. The API never returns it, changing it replaces the key. After an import the first apply sends the configured key without replacing it.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							// An imported key has no private key in state.
+							resp.RequiresReplace = !req.StateValue.IsNull()
+						},
+						"Changing the private key replaces the SSH key.",
+						"Changing the private key replaces the SSH key.",
+					),
+				},
 			},
 			"ssh_type": schema.StringAttribute{
 				Optional:    true,
@@ -90,6 +102,11 @@ func (r *SshResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 					stringvalidator.OneOf("rsa", "ed25519"),
 				},
 			},
+			"fingerprint": schema.StringAttribute{
+				Computed: true,
+				Description: "SHA256 fingerprint of the public half of `private_key`, as printed by `ssh-keygen -l`. " +
+					"When the API returns the key material and its fingerprint differs, the key was replaced outside of Terraform and is planned for replacement.",
+			},
 		},
 	}
 }
@@ -108,20 +125,11 @@ func (r *SshResource) Configure(ctx context.Context, req resource.ConfigureReque
 
 		return
 	}
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.warnings = providerData.Warnings
 
 	tflog.Debug(ctx, "Configuring Ssh Key resource", map[string]any{"success": true})
 
@@ -148,7 +156,7 @@ func (r *SshResource) Create(ctx context.Context, req resource.CreateRequest, re
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
 		return
 	}
-	sshRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/ssh", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	sshRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/ssh", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
 	sshRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	sshRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -168,7 +176,7 @@ func (r *SshResource) Create(ctx context.Context, req resource.CreateRequest, re
 	newSshKey := &client.SshEntity{}
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), newSshKey)
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(sshResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, sshResponse.Status)))
 		return
 	}
 
@@ -176,9 +184,9 @@ func (r *SshResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	plan.ID = types.StringValue(newSshKey.ID)
 	plan.Name = types.StringValue(newSshKey.Name)
-	plan.PrivateKey = types.StringValue(plan.PrivateKey.ValueString())
 	plan.SshType = types.StringValue(newSshKey.SshType)
 	plan.Description = types.StringValue(newSshKey.Description)
+	plan.Fingerprint = sshFingerprintValue(plan.PrivateKey)
 	tflog.Info(ctx, "Ssh Key Resource Created", map[string]any{"success": true})
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -207,6 +215,12 @@ func (r *SshResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
+	if sshResponse.StatusCode == http.StatusNotFound {
+		tflog.Warn(ctx, "Ssh key not found, removing it from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
 	bodyResponse, err := io.ReadAll(sshResponse.Body)
 	if err != nil {
 		tflog.Error(ctx, "Error reading ssh key resource response")
@@ -217,17 +231,28 @@ func (r *SshResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), sshKey)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(sshResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, sshResponse.Status)))
 		return
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 	state.Name = types.StringValue(sshKey.Name)
-	state.PrivateKey = types.StringValue(state.PrivateKey.ValueString()) //value is not inside the response getting the value from the current state
 	state.SshType = types.StringValue(sshKey.SshType)
 	state.Description = types.StringValue(sshKey.Description)
 	state.ID = types.StringValue(sshKey.ID)
 
+	// The private key is left out of the sparse fieldsets and normally not
+	// returned with full_payloads either, when it is the fingerprint of the
+	// key stored in Terrakube replaces the one computed at apply time.
+	if sshKey.PrivateKey != "" {
+		fingerprint, _, err := sshPrivateKeyFingerprint(sshKey.PrivateKey)
+		if err != nil {
+			tflog.Warn(ctx, "Unable to compute the fingerprint of the ssh key returned by the API", map[string]any{"error": err.Error()})
+		} else {
+			state.Fingerprint = types.StringValue(fingerprint)
+		}
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -256,8 +281,15 @@ func (r *SshResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		PrivateKey:  plan.PrivateKey.ValueString(),
 		SshType:     plan.SshType.ValueString(),
 	}
+	// The private key is only sent when it changed, which after an import
+	// is the first apply, so the key is never overwritten with itself.
+	var omit []string
+	if plan.PrivateKey.Equal(state.PrivateKey) {
+		omit = append(omit, "privateKey")
+	}
+
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err := client.MarshalSparsePayload(out, bodyRequest, omit...)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
@@ -299,7 +331,7 @@ func (r *SshResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	bodyResponse, err = io.ReadAll(sshResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading ssh key resource response body", fmt.Sprintf("Error reading ssh key resource response body: %s", err))
+		resp.Diagnostics.AddError("Error reading ssh key resource response body", responseErrorDetail(sshResponse, fmt.Sprintf("Error reading ssh key resource response body, error: %s, response status: %s", err, sshResponse.Status)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -308,15 +340,15 @@ func (r *SshResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), ssh)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(sshResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, sshResponse.Status)))
 		return
 	}
 
 	plan.ID = types.StringValue(state.ID.ValueString())
 	plan.Name = types.StringValue(ssh.Name)
 	plan.Description = types.StringValue(ssh.Description)
-	plan.PrivateKey = types.StringValue(plan.PrivateKey.ValueString())
 	plan.SshType = types.StringValue(ssh.SshType)
+	plan.Fingerprint = sshFingerprintValue(plan.PrivateKey)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 
@@ -332,18 +364,71 @@ func (r *SshResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/ssh/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	reqOrg, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/ssh/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating ssh key resource request", fmt.Sprintf("Error creating ssh key resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(reqOrg)
+	deleteResponse, err := r.client.Do(reqOrg)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing ssh key resource request", fmt.Sprintf("Error executing ssh key resource request: %s", err))
 		return
 	}
+	defer deleteResponse.Body.Close()
+
+	if deleteResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(deleteResponse.Body)
+		resp.Diagnostics.AddError("Error executing ssh key resource request", responseErrorDetail(deleteResponse, fmt.Sprintf("Error executing ssh key resource request, response status: %s, response body: %s", deleteResponse.Status, bodyResponse)))
+		return
+	}
+}
+
+// ModifyPlan plans the fingerprint of the configured key, a fingerprint
+// refreshed from the API that differs from it replaces the key.
+func (r *SshResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan SshResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case plan.PrivateKey.IsUnknown():
+		plan.Fingerprint = types.StringUnknown()
+	case plan.PrivateKey.IsNull():
+		plan.Fingerprint = types.StringNull()
+	default:
+		fingerprint, sshType, err := sshPrivateKeyFingerprint(plan.PrivateKey.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("private_key"), "Invalid SSH private key", err.Error())
+			return
+		}
+		if !plan.SshType.IsUnknown() && plan.SshType.ValueString() != sshType {
+			r.warnings.addAttribute(&resp.Diagnostics, path.Root("ssh_type"), "SSH key type mismatch",
+				fmt.Sprintf("private_key is an %s key but ssh_type is %q, Terrakube names the key file after ssh_type.", sshType, plan.SshType.ValueString()))
+		}
+		plan.Fingerprint = types.StringValue(fingerprint)
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state SshResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if !state.Fingerprint.IsNull() && !plan.Fingerprint.IsUnknown() && !plan.Fingerprint.Equal(state.Fingerprint) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("fingerprint"))
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
 }
 
 func (r *SshResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -360,3 +445,16 @@ func (r *SshResource) ImportState(ctx context.Context, req resource.ImportStateR
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
 }
+
+// sshFingerprintValue is the fingerprint stored after an apply, the private
+// key was already parsed when the plan was made.
+func sshFingerprintValue(privateKey types.String) types.String {
+	if privateKey.IsNull() || privateKey.IsUnknown() {
+		return types.StringNull()
+	}
+	fingerprint, _, err := sshPrivateKeyFingerprint(privateKey.ValueString())
+	if err != nil {
+		return types.StringNull()
+	}
+	return types.StringValue(fingerprint)
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource                     = &TeamDataSource{}
+	_ datasource.DataSourceWithConfigure        = &TeamDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &TeamDataSource{}
+)
+
+type TeamDataSourceModel struct {
+	ID               types.String      `tfsdk:"id"`
+	OrganizationId   types.String      `tfsdk:"organization_id"`
+	Name             types.String      `tfsdk:"name"`
+	ManageState      types.Bool        `tfsdk:"manage_state"`
+	ManageWorkspace  types.Bool        `tfsdk:"manage_workspace"`
+	ManageModule     types.Bool        `tfsdk:"manage_module"`
+	ManageProvider   types.Bool        `tfsdk:"manage_provider"`
+	ManageVcs        types.Bool        `tfsdk:"manage_vcs"`
+	ManageTemplate   types.Bool        `tfsdk:"manage_template"`
+	ManageJob        types.Bool        `tfsdk:"manage_job"`
+	ManageCollection types.Bool        `tfsdk:"manage_collection"`
+	RawAttributes    map[string]string `tfsdk:"raw_attributes"`
+	AllowMissing     types.Bool        `tfsdk:"allow_missing"`
+	Found            types.Bool        `tfsdk:"found"`
+}
+
+type TeamDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewTeamDataSource() datasource.DataSource {
+	return &TeamDataSource{}
+}
+
+func (d *TeamDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Team Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Team datasource")
+}
+
+func (d *TeamDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_team"
+}
+
+func (d *TeamDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Find a team by id or name and read its permissions.",
+		Attributes: map[string]schema.Attribute{
+			"allow_missing": allowMissingSchema(),
+			"found":         foundSchema(),
+			"id":            idOrNameSchema("Team Id"),
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+			},
+			"name": idOrNameSchema("Team name"),
+			"manage_state": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Allow to manage Terraform/OpenTofu state",
+			},
+			"manage_workspace": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Allow to manage workspaces",
+			},
+			"manage_module": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Allow to manage modules",
+			},
+			"manage_provider": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Allow to manage providers",
+			},
+			"manage_vcs": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Allow to manage vcs connections",
+			},
+			"manage_template": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Allow to manage templates",
+			},
+			"manage_job": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Allow to manage and trigger jobs",
+			},
+			"manage_collection": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Allow to manage variables collection",
+			},
+			"raw_attributes": rawAttributesSchema(),
+		},
+	}
+}
+
+func (d *TeamDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return idOrNameValidators()
+}
+
+func (d *TeamDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state TeamDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The raw attributes need every attribute the server knows.
+	body, err := lookupBody(client.WithFullPayloads(ctx), d.client, d.token, fmt.Sprintf("%s/api/v1/organization/%s/team", d.endpoint, state.OrganizationId.ValueString()), "filter[team]", state.ID, state.Name)
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing team datasource request", apiErrorDetail(err, fmt.Sprintf("Error executing team datasource request: %s", err)))
+		return
+	}
+
+	teams, err := unmarshalLookup(body, reflect.TypeOf(new(client.TeamEntity)))
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to unmarshal payload", fmt.Sprintf("Unable to unmarshal payload, response body: %s, error: %s", string(body), err))
+		return
+	}
+
+	raw, err := rawAttributes(body)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read raw attributes", err.Error())
+		return
+	}
+
+	state.Found = types.BoolValue(len(teams) > 0)
+	if len(teams) == 0 {
+		lookupIdOrNameNotFound(&resp.Diagnostics, state.AllowMissing, "team", state.ID, state.Name)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	team := teams[0].(*client.TeamEntity)
+	state.ID = types.StringValue(team.ID)
+	state.Name = types.StringValue(team.Name)
+	state.ManageState = types.BoolValue(team.ManageState)
+	state.ManageWorkspace = types.BoolValue(team.ManageWorkspace)
+	state.ManageModule = types.BoolValue(team.ManageModule)
+	state.ManageProvider = types.BoolValue(team.ManageProvider)
+	state.ManageVcs = types.BoolValue(team.ManageVcs)
+	state.ManageTemplate = types.BoolValue(team.ManageTemplate)
+	state.ManageJob = types.BoolValue(team.ManageJob)
+	state.ManageCollection = types.BoolValue(team.ManageCollection)
+	state.RawAttributes = raw[team.ID]
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -1,16 +1,16 @@
 package provider
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 
-	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -25,27 +25,42 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &TeamResource{}
 var _ resource.ResourceWithImportState = &TeamResource{}
+var _ resource.ResourceWithModifyPlan = &TeamResource{}
 
 type TeamResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client         *http.Client
+	endpoint       string
+	token          string
+	teams          *client.Crud[client.TeamEntity]
+	accesses       *client.Crud[client.WorkspaceAccessEntity]
+	protectedTeams map[string]bool
+	warnings       *warningPolicy
+	parallelism    int
 }
 
 type TeamResourceModel struct {
-	ID               types.String `tfsdk:"id"`
-	Name             types.String `tfsdk:"name"`
-	OrganizationId   types.String `tfsdk:"organization_id"`
-	ManageState      types.Bool   `tfsdk:"manage_state"`
-	ManageWorkspace  types.Bool   `tfsdk:"manage_workspace"`
-	ManageModule     types.Bool   `tfsdk:"manage_module"`
-	ManageProvider   types.Bool   `tfsdk:"manage_provider"`
-	ManageVcs        types.Bool   `tfsdk:"manage_vcs"`
-	ManageTemplate   types.Bool   `tfsdk:"manage_template"`
-	ManageJob        types.Bool   `tfsdk:"manage_job"`
-	ManageCollection types.Bool   `tfsdk:"manage_collection"`
+	ID                  types.String `tfsdk:"id"`
+	Name                types.String `tfsdk:"name"`
+	OrganizationId      types.String `tfsdk:"organization_id"`
+	ManageState         types.Bool   `tfsdk:"manage_state"`
+	ManageWorkspace     types.Bool   `tfsdk:"manage_workspace"`
+	ManageModule        types.Bool   `tfsdk:"manage_module"`
+	ManageProvider      types.Bool   `tfsdk:"manage_provider"`
+	ManageVcs           types.Bool   `tfsdk:"manage_vcs"`
+	ManageTemplate      types.Bool   `tfsdk:"manage_template"`
+	ManageJob           types.Bool   `tfsdk:"manage_job"`
+	ManageCollection    types.Bool   `tfsdk:"manage_collection"`
+	ObserveOnly         types.Bool   `tfsdk:"observe_only"`
+	IgnoreServerChanges types.Set    `tfsdk:"ignore_server_changes"`
+	Protected           types.Bool   `tfsdk:"protected"`
+	ForceDelete         types.Bool   `tfsdk:"force_delete"`
+	Authoritative       types.Bool   `tfsdk:"authoritative"`
 }
 
+var teamServerManagedAttributes = []string{"name", "manage_state", "manage_workspace", "manage_module", "manage_provider", "manage_vcs", "manage_template", "manage_job", "manage_collection"}
+
+var teamPermissions = []string{"manage_state", "manage_workspace", "manage_module", "manage_provider", "manage_vcs", "manage_template", "manage_job", "manage_collection"}
+
 func NewTeamResource() resource.Resource {
 	return &TeamResource{}
 }
@@ -125,6 +140,33 @@ func (r *TeamResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
 			},
+			"ignore_server_changes": ignoreServerChangesSchema(teamServerManagedAttributes),
+			"observe_only": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "Report drift on the team without ever changing it in Terrakube, default is `false`. " +
+					"When enabled, updates and deletes are skipped with a warning and the state follows the plan, " +
+					"creating a new team is rejected, so the team must be imported.",
+			},
+			"protected": schema.BoolAttribute{
+				Computed:    true,
+				Description: "The team name is listed in the `protected_team_names` of the provider. Protected teams are not deleted without `force_delete` and removing one of their permissions is reported with a warning.",
+			},
+			"force_delete": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Allow to delete the team even when it is protected, and revoke the access granted to the team on the workspaces of the organization before deleting it, default is `false`. Like any attribute read on destroy, it must be applied before the team is deleted.",
+			},
+			"authoritative": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+				Description: "Enforce every permission of the team, default is `true`. " +
+					"When `false` the permissions set to `true` in the configuration are granted and their removal outside of Terraform is reported as drift, " +
+					"the other permissions are never removed and their changes outside of Terraform are ignored, for teams whose permissions are also granted by another controller.",
+			},
 		},
 	}
 }
@@ -144,24 +186,65 @@ func (r *TeamResource) Configure(ctx context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.teams = client.NewCrud[client.TeamEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/team")
+	r.teams.Batcher = providerData.Batcher
+	r.teams.FindCreated = r.findTeam
+	r.accesses = client.NewCrud[client.WorkspaceAccessEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/workspace/%s/access")
+	r.protectedTeams = providerData.ProtectedTeamNames
+	r.warnings = providerData.Warnings
+	r.parallelism = providerData.APIParallelism
 
 	tflog.Debug(ctx, "Configuring Team resource", map[string]any{"success": true})
 }
 
+func (r *TeamResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		var state TeamResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() || !r.protectedTeams[state.Name.ValueString()] || state.ForceDelete.ValueBool() || state.ObserveOnly.ValueBool() {
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Protected team",
+			fmt.Sprintf("Team %q is listed in protected_team_names and cannot be deleted. Set force_delete to true and apply it before destroying the team.", state.Name.ValueString()),
+		)
+		return
+	}
+
+	var plan TeamResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Name.IsUnknown() {
+		return
+	}
+
+	plan.Protected = r.protected(plan.Name.ValueString())
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("protected"), plan.Protected)...)
+
+	// A non-authoritative team never loses a permission.
+	if req.State.Raw.IsNull() || !plan.Protected.ValueBool() || !teamAuthoritative(&plan) {
+		return
+	}
+
+	for _, permission := range teamPermissions {
+		var prior, planned types.Bool
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(permission), &prior)...)
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(permission), &planned)...)
+		if prior.ValueBool() && !planned.IsUnknown() && !planned.ValueBool() {
+			r.warnings.addAttribute(
+				&resp.Diagnostics,
+				path.Root(permission),
+				"Permission removed from a protected team",
+				fmt.Sprintf("Team %q is listed in protected_team_names, applying this plan removes its %s permission.", plan.Name.ValueString(), permission),
+			)
+		}
+	}
+}
+
 func (r *TeamResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan TeamResourceModel
 
@@ -171,6 +254,15 @@ func (r *TeamResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
+	if plan.ObserveOnly.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("observe_only"),
+			"Team is in observe only mode",
+			fmt.Sprintf("Team %q cannot be created while observe_only is true. Import the existing team with 'organization_ID,ID' or set observe_only to false.", plan.Name.ValueString()),
+		)
+		return
+	}
+
 	bodyRequest := &client.TeamEntity{
 		Name:             plan.Name.ValueString(),
 		ManageState:      plan.ManageState.ValueBool(),
@@ -183,43 +275,20 @@ func (r *TeamResource) Create(ctx context.Context, req resource.CreateRequest, r
 		ManageCollection: plan.ManageCollection.ValueBool(),
 	}
 
-	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
-		return
-	}
-
-	teamRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/team", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
-	teamRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	teamRequest.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating team resource request", fmt.Sprintf("Error creating team resource request: %s", err))
-		return
-	}
-
-	teamResponse, err := r.client.Do(teamRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing team resource request", fmt.Sprintf("Error executing team resource request: %s", err))
+	newTeam, err := r.teams.Create(ctx, bodyRequest, plan.OrganizationId.ValueString())
+	var lost *client.LostCreateError
+	if errors.As(err, &lost) {
+		resp.Diagnostics.AddError(
+			"Team create response lost",
+			fmt.Sprintf("The response of the create of team %q was lost and the teams %s with this name were created meanwhile. Import the team created by this apply with 'organization_ID,ID'.", plan.Name.ValueString(), strings.Join(lost.IDs, ", ")),
+		)
 		return
 	}
-
-	bodyResponse, err := io.ReadAll(teamResponse.Body)
 	if err != nil {
-		tflog.Error(ctx, "Error reading team resource response")
-	}
-	newTeam := &client.TeamEntity{}
-
-	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), newTeam)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error executing team resource request", apiErrorDetail(err, fmt.Sprintf("Error executing team resource request: %s", err)))
 		return
 	}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-
 	plan.ID = types.StringValue(newTeam.ID)
 	plan.Name = types.StringValue(newTeam.Name)
 	plan.ManageState = types.BoolValue(newTeam.ManageState)
@@ -230,6 +299,7 @@ func (r *TeamResource) Create(ctx context.Context, req resource.CreateRequest, r
 	plan.ManageTemplate = types.BoolValue(newTeam.ManageTemplate)
 	plan.ManageJob = types.BoolValue(newTeam.ManageJob)
 	plan.ManageCollection = types.BoolValue(newTeam.ManageCollection)
+	plan.Protected = r.protected(plan.Name.ValueString())
 
 	tflog.Info(ctx, "Team Resource Created", map[string]any{"success": true})
 
@@ -244,45 +314,24 @@ func (r *TeamResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	teamRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/team/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
-	teamRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	teamRequest.Header.Add("Content-Type", "application/vnd.api+json")
+	team, validators, modified, err := r.teams.GetIfModified(ctx, state.ID.ValueString(), readCacheValidators(ctx, req.Private), state.OrganizationId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating team resource request", fmt.Sprintf("Error creating team resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing team resource request", apiErrorDetail(err, fmt.Sprintf("Error executing team resource request: %s", err)))
 		return
 	}
+	resp.Diagnostics.Append(writeCacheValidators(ctx, resp.Private, validators)...)
 
-	teamResponse, err := r.client.Do(teamRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing team resource request", fmt.Sprintf("Error executing team resource request: %s", err))
-		return
+	// A team that did not change since the last read keeps its state, the
+	// ignored changes and undeclared permissions are applied on both paths.
+	prior := state
+	if modified {
+		setTeamState(&state, team)
 	}
-
-	bodyResponse, err := io.ReadAll(teamResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading team resource response")
+	keepIgnoredServerChanges(ctx, state.IgnoreServerChanges, &prior, &state)
+	if !teamAuthoritative(&prior) {
+		keepUndeclaredPermissions(&prior, &state)
 	}
-	team := &client.TeamEntity{}
-
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), team)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
-		return
-	}
-
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-
-	state.Name = types.StringValue(team.Name)
-	state.ManageState = types.BoolValue(team.ManageState)
-	state.ManageWorkspace = types.BoolValue(team.ManageWorkspace)
-	state.ManageModule = types.BoolValue(team.ManageModule)
-	state.ManageVcs = types.BoolValue(team.ManageVcs)
-	state.ManageProvider = types.BoolValue(team.ManageProvider)
-	state.ManageTemplate = types.BoolValue(team.ManageTemplate)
-	state.ManageJob = types.BoolValue(team.ManageJob)
-	state.ManageCollection = types.BoolValue(team.ManageCollection)
+	state.Protected = r.protected(state.Name.ValueString())
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -304,6 +353,22 @@ func (r *TeamResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
+	// The state written below is the plan or a merge with it, the next read
+	// must not be answered with 304 Not Modified.
+	resp.Diagnostics.Append(clearCacheValidators(ctx, resp.Private)...)
+
+	if plan.ObserveOnly.ValueBool() {
+		r.warnings.add(
+			&resp.Diagnostics,
+			"Team update skipped",
+			fmt.Sprintf("Team %q is in observe only mode, the changes have not been sent to Terrakube.", state.Name.ValueString()),
+		)
+		plan.ID = state.ID
+		plan.Protected = r.protected(plan.Name.ValueString())
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
 	bodyRequest := &client.TeamEntity{
 		ManageState:      plan.ManageState.ValueBool(),
 		ManageWorkspace:  plan.ManageWorkspace.ValueBool(),
@@ -317,64 +382,34 @@ func (r *TeamResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		Name:             state.Name.ValueString(),
 	}
 
-	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
-		return
-	}
-
-	teamRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/team/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
-	teamRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	teamRequest.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating team resource request", fmt.Sprintf("Error creating team resource request: %s", err))
-		return
-	}
-
-	teamResponse, err := r.client.Do(teamRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing team resource request", fmt.Sprintf("Error executing team resource request: %s", err))
-		return
-	}
-
-	bodyResponse, err := io.ReadAll(teamResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading team resource response")
-	}
-
-	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
+	// The permissions granted in Terrakube are kept, the plan only adds the
+	// ones set to true.
+	if !teamAuthoritative(&plan) {
+		current, err := r.teams.Get(ctx, state.ID.ValueString(), state.OrganizationId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error executing team resource request", apiErrorDetail(err, fmt.Sprintf("Error executing team resource request: %s", err)))
+			return
+		}
 
-	teamRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/team/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
-	teamRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	teamRequest.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating team resource request", fmt.Sprintf("Error creating team resource request: %s", err))
-		return
+		permissions := teamEntityPermissions(bodyRequest)
+		for i, granted := range teamEntityPermissions(current) {
+			*permissions[i] = *permissions[i] || *granted
+		}
 	}
 
-	teamResponse, err = r.client.Do(teamRequest)
+	err := r.teams.Update(ctx, state.ID.ValueString(), bodyRequest, state.OrganizationId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing team resource request", fmt.Sprintf("Error executing team resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing team resource request", apiErrorDetail(err, fmt.Sprintf("Error executing team resource request: %s", err)))
 		return
 	}
 
-	bodyResponse, err = io.ReadAll(teamResponse.Body)
+	team, err := r.teams.Get(ctx, state.ID.ValueString(), state.OrganizationId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading team resource response body", fmt.Sprintf("Error reading team resource response body: %s", err))
-	}
-
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-
-	team := &client.TeamEntity{}
-	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), team)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error executing team resource request", apiErrorDetail(err, fmt.Sprintf("Error executing team resource request: %s", err)))
 		return
 	}
 
+	planned := plan
 	plan.ID = types.StringValue(state.ID.ValueString())
 	plan.Name = types.StringValue(team.Name)
 	plan.ManageState = types.BoolValue(team.ManageState)
@@ -385,6 +420,10 @@ func (r *TeamResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	plan.ManageTemplate = types.BoolValue(team.ManageTemplate)
 	plan.ManageJob = types.BoolValue(team.ManageJob)
 	plan.ManageCollection = types.BoolValue(team.ManageCollection)
+	plan.Protected = r.protected(plan.Name.ValueString())
+	if !teamAuthoritative(&planned) {
+		keepUndeclaredPermissions(&planned, &plan)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -399,16 +438,33 @@ func (r *TeamResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/team/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
-	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating team resource request", fmt.Sprintf("Error creating team resource request: %s", err))
+	if data.ObserveOnly.ValueBool() {
+		r.warnings.add(
+			&resp.Diagnostics,
+			"Team delete skipped",
+			fmt.Sprintf("Team %q is in observe only mode, it has been removed from the Terraform state but still exists in Terrakube.", data.Name.ValueString()),
+		)
 		return
 	}
 
-	_, err = r.client.Do(reqOrg)
+	if r.protectedTeams[data.Name.ValueString()] && !data.ForceDelete.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Protected team",
+			fmt.Sprintf("Team %q is listed in protected_team_names and cannot be deleted. Set force_delete to true and apply it before destroying the team.", data.Name.ValueString()),
+		)
+		return
+	}
+
+	if data.ForceDelete.ValueBool() {
+		if err := r.revokeWorkspaceAccess(ctx, data.OrganizationId.ValueString(), data.Name.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Error revoking team workspace access", apiErrorDetail(err, fmt.Sprintf("Unable to revoke the workspace access of team %q, it is not deleted: %s", data.Name.ValueString(), err)))
+			return
+		}
+	}
+
+	err := r.teams.Delete(ctx, data.ID.ValueString(), data.OrganizationId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing team resource request", fmt.Sprintf("Error executing team resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing team resource request", apiErrorDetail(err, fmt.Sprintf("Error executing team resource request: %s", err)))
 		return
 	}
 }
@@ -424,6 +480,174 @@ func (r *TeamResource) ImportState(ctx context.Context, req resource.ImportState
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+	// Read the team right away so every attribute is in the imported state
+	// and the first plan does not depend on a refresh.
+	state := TeamResourceModel{
+		ID:                  types.StringValue(idParts[1]),
+		OrganizationId:      types.StringValue(idParts[0]),
+		IgnoreServerChanges: types.SetNull(types.StringType),
+	}
+
+	if err := r.readTeam(ctx, &state); err != nil {
+		resp.Diagnostics.AddError("Error importing team resource", apiErrorDetail(err, fmt.Sprintf("Error importing team resource: %s", err)))
+		return
+	}
+	state.Protected = r.protected(state.Name.ValueString())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// readTeam maps the team returned by the API into the state.
+func (r *TeamResource) readTeam(ctx context.Context, state *TeamResourceModel) error {
+	team, err := r.teams.Get(ctx, state.ID.ValueString(), state.OrganizationId.ValueString())
+	if err != nil {
+		return err
+	}
+
+	setTeamState(state, team)
+	return nil
+}
+
+func setTeamState(state *TeamResourceModel, team *client.TeamEntity) {
+	state.ID = types.StringValue(team.ID)
+	state.Name = types.StringValue(team.Name)
+	state.ManageState = types.BoolValue(team.ManageState)
+	state.ManageWorkspace = types.BoolValue(team.ManageWorkspace)
+	state.ManageModule = types.BoolValue(team.ManageModule)
+	state.ManageVcs = types.BoolValue(team.ManageVcs)
+	state.ManageProvider = types.BoolValue(team.ManageProvider)
+	state.ManageTemplate = types.BoolValue(team.ManageTemplate)
+	state.ManageJob = types.BoolValue(team.ManageJob)
+	state.ManageCollection = types.BoolValue(team.ManageCollection)
+
+	if state.ObserveOnly.IsNull() {
+		state.ObserveOnly = types.BoolValue(false)
+	}
+	if state.ForceDelete.IsNull() {
+		state.ForceDelete = types.BoolValue(false)
+	}
+	if state.Authoritative.IsNull() {
+		state.Authoritative = types.BoolValue(true)
+	}
+}
+
+// teamAuthoritative reports whether every permission of the team is
+// enforced, the states written before the attribute existed are.
+func teamAuthoritative(model *TeamResourceModel) bool {
+	return model.Authoritative.IsNull() || model.Authoritative.ValueBool()
+}
+
+// keepUndeclaredPermissions keeps in the state the permissions that are not
+// set to true in declared, so a non-authoritative team only tracks the
+// permissions it grants.
+func keepUndeclaredPermissions(declared *TeamResourceModel, state *TeamResourceModel) {
+	values := teamModelPermissions(state)
+	for i, value := range teamModelPermissions(declared) {
+		if !value.ValueBool() {
+			*values[i] = *value
+		}
+	}
+}
+
+// teamModelPermissions returns the permissions of the model in the order of
+// teamPermissions.
+func teamModelPermissions(model *TeamResourceModel) []*types.Bool {
+	return []*types.Bool{
+		&model.ManageState,
+		&model.ManageWorkspace,
+		&model.ManageModule,
+		&model.ManageProvider,
+		&model.ManageVcs,
+		&model.ManageTemplate,
+		&model.ManageJob,
+		&model.ManageCollection,
+	}
+}
+
+// teamEntityPermissions returns the permissions of the team in the order of
+// teamPermissions.
+func teamEntityPermissions(team *client.TeamEntity) []*bool {
+	return []*bool{
+		&team.ManageState,
+		&team.ManageWorkspace,
+		&team.ManageModule,
+		&team.ManageProvider,
+		&team.ManageVcs,
+		&team.ManageTemplate,
+		&team.ManageJob,
+		&team.ManageCollection,
+	}
+}
+
+// findTeam returns by id the teams of the organization named like team.
+func (r *TeamResource) findTeam(ctx context.Context, team *client.TeamEntity, parentIds ...string) (map[string]*client.TeamEntity, error) {
+	query := url.Values{}
+	query.Set("filter[team]", "name=="+rsqlString(team.Name))
+	items, err := fetchAllPages(ctx, r.client, r.token, r.teams.CollectionURL(parentIds...)+"?"+query.Encode(), reflect.TypeOf(new(client.TeamEntity)))
+	if err != nil {
+		return nil, err
+	}
+
+	teams := map[string]*client.TeamEntity{}
+	for _, item := range items {
+		teams[item.(*client.TeamEntity).ID] = item.(*client.TeamEntity)
+	}
+	return teams, nil
+}
+
+// revokeWorkspaceAccess deletes the access granted to the team on every
+// workspace of the organization, Terrakube keeps them when the team is
+// deleted. The errors of all the failed deletes are returned.
+func (r *TeamResource) revokeWorkspaceAccess(ctx context.Context, organizationId string, name string) error {
+	query := url.Values{}
+	query.Set("filter[access]", "name=="+rsqlString(name))
+
+	errs, err := deletePages(ctx, r.parallelism, func(ctx context.Context, page int) ([]string, bool, error) {
+		pageURL, err := collectionPageURL(fmt.Sprintf("%s/api/v1/organization/%s/workspace", r.endpoint, organizationId), page)
+		if err != nil {
+			return nil, false, err
+		}
+		workspaces, err := fetchPage(ctx, r.client, r.token, pageURL, reflect.TypeOf(new(client.WorkspaceEntity)))
+		if err != nil {
+			return nil, false, err
+		}
+
+		var keys []string
+		for _, item := range workspaces {
+			workspaceId := item.(*client.WorkspaceEntity).ID
+			accesses, err := fetchAllPages(ctx, r.client, r.token, r.accesses.CollectionURL(organizationId, workspaceId)+"?"+query.Encode(), reflect.TypeOf(new(client.WorkspaceAccessEntity)))
+			if err != nil {
+				return nil, false, err
+			}
+			for _, access := range accesses {
+				keys = append(keys, workspaceId+"/"+access.(*client.WorkspaceAccessEntity).ID)
+			}
+		}
+		return keys, len(workspaces) == listPageSize, nil
+	}, func(ctx context.Context, key string) error {
+		workspaceId, accessId, _ := strings.Cut(key, "/")
+		return r.accesses.Delete(ctx, accessId, organizationId, workspaceId)
+	})
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	failures := make([]error, 0, len(keys))
+	for _, key := range keys {
+		workspaceId, accessId, _ := strings.Cut(key, "/")
+		failures = append(failures, fmt.Errorf("access %s of workspace %s: %w", accessId, workspaceId, errs[key]))
+	}
+	return errors.Join(failures...)
+}
+
+// protected reports whether the team name is listed in the
+// protected_team_names of the provider.
+func (r *TeamResource) protected(name string) types.Bool {
+	return types.BoolValue(r.protectedTeams[name])
 }
@@ -0,0 +1,413 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const teamCollectionPath = "/api/v1/organization/o1/team"
+
+// teamConfig returns a terrakube_team configuration of organization o1, the
+// attributes are added to the name.
+func teamConfig(terrakube *testProvider, name string, attributes map[string]tftypes.Value) tftypes.Value {
+	values := map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+		"name":            tftypes.NewValue(tftypes.String, name),
+	}
+	for attributeName, value := range attributes {
+		values[attributeName] = value
+	}
+	return terrakube.object("terrakube_team", values)
+}
+
+func TestTeamObserveOnly(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+	observeOnly := map[string]tftypes.Value{"observe_only": tftypes.NewValue(tftypes.Bool, true)}
+
+	_, diagnostics := terrakube.apply("terrakube_team", terrakube.null("terrakube_team"), teamConfig(terrakube, "observed", observeOnly))
+	if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, "Team is in observe only mode") {
+		t.Errorf("create in observe only mode should fail, got %v", diagnostics)
+	}
+	if count := api.count("POST", teamCollectionPath); count != 0 {
+		t.Errorf("create in observe only mode sent %d POST", count)
+	}
+
+	state, diagnostics := terrakube.apply("terrakube_team", terrakube.null("terrakube_team"), teamConfig(terrakube, "observed", nil))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	teamPath := teamCollectionPath + "/" + stringAttribute(t, state, "id")
+
+	state, diagnostics = terrakube.apply("terrakube_team", state, teamConfig(terrakube, "observed", map[string]tftypes.Value{
+		"observe_only": tftypes.NewValue(tftypes.Bool, true),
+		"manage_state": tftypes.NewValue(tftypes.Bool, true),
+	}))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityWarning, "Team update skipped") {
+		t.Errorf("update in observe only mode should warn, got %v", diagnostics)
+	}
+	if count := api.count("PATCH", teamPath); count != 0 {
+		t.Errorf("update in observe only mode sent %d PATCH", count)
+	}
+	if manageState := api.attributes(teamPath)["manageState"]; manageState != false {
+		t.Errorf("update in observe only mode changed the team: manageState %v", manageState)
+	}
+	if !attribute(t, state, "manage_state").Equal(tftypes.NewValue(tftypes.Bool, true)) {
+		t.Errorf("update in observe only mode should keep the plan in the state")
+	}
+
+	_, diagnostics = terrakube.apply("terrakube_team", state, terrakube.null("terrakube_team"))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityWarning, "Team delete skipped") {
+		t.Errorf("delete in observe only mode should warn, got %v", diagnostics)
+	}
+	if count := api.count("DELETE", teamPath); count != 0 || api.attributes(teamPath) == nil {
+		t.Errorf("delete in observe only mode removed the team")
+	}
+}
+
+func TestTeamImport(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.put(teamCollectionPath+"/t1", "team", map[string]any{"name": "platform", "manageWorkspace": true, "manageJob": true})
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	state, diagnostics := terrakube.importAndRead("terrakube_team", "o1,t1")
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for name, expected := range map[string]tftypes.Value{
+		"id":               tftypes.NewValue(tftypes.String, "t1"),
+		"organization_id":  tftypes.NewValue(tftypes.String, "o1"),
+		"name":             tftypes.NewValue(tftypes.String, "platform"),
+		"manage_workspace": tftypes.NewValue(tftypes.Bool, true),
+		"manage_job":       tftypes.NewValue(tftypes.Bool, true),
+		"manage_state":     tftypes.NewValue(tftypes.Bool, false),
+	} {
+		if value := attribute(t, state, name); !value.Equal(expected) {
+			t.Errorf("imported %s = %s, expected %s", name, value, expected)
+		}
+	}
+
+	plan := terrakube.plan("terrakube_team", state, teamConfig(terrakube, "platform", map[string]tftypes.Value{
+		"manage_workspace": tftypes.NewValue(tftypes.Bool, true),
+		"manage_job":       tftypes.NewValue(tftypes.Bool, true),
+	}))
+	if err := diagnosticsError(plan.Diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if planned := terrakube.value("terrakube_team", plan.PlannedState); !planned.Equal(state) {
+		t.Errorf("the configuration matching the team should plan no change:\n%s\n%s", planned, state)
+	}
+
+	for _, id := range []string{"o1,missing", "t1"} {
+		if _, diagnostics := terrakube.importAndRead("terrakube_team", id); diagnosticsError(diagnostics) == nil {
+			t.Errorf("importing %s should fail", id)
+		}
+	}
+}
+
+func TestTeamIgnoreServerChanges(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+	ignored := tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "name"),
+		tftypes.NewValue(tftypes.String, "manage_job"),
+	})
+	config := teamConfig(terrakube, "platform", map[string]tftypes.Value{
+		"manage_state":          tftypes.NewValue(tftypes.Bool, true),
+		"ignore_server_changes": ignored,
+	})
+
+	state, diagnostics := terrakube.apply("terrakube_team", terrakube.null("terrakube_team"), config)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	teamPath := teamCollectionPath + "/" + stringAttribute(t, state, "id")
+
+	// A controller renames the team and grants two permissions.
+	api.put(teamPath, "team", map[string]any{"name": "platform-renamed", "manageState": true, "manageJob": true, "manageWorkspace": true})
+
+	state, diagnostics = terrakube.read("terrakube_team", state)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for name, expected := range map[string]tftypes.Value{
+		"name":             tftypes.NewValue(tftypes.String, "platform"),
+		"manage_job":       tftypes.NewValue(tftypes.Bool, false),
+		"manage_workspace": tftypes.NewValue(tftypes.Bool, true),
+	} {
+		if value := attribute(t, state, name); !value.Equal(expected) {
+			t.Errorf("refreshed %s = %s, expected %s", name, value, expected)
+		}
+	}
+
+	// Changes made in the configuration are still applied.
+	state, diagnostics = terrakube.apply("terrakube_team", state, teamConfig(terrakube, "platform", map[string]tftypes.Value{
+		"manage_state":          tftypes.NewValue(tftypes.Bool, true),
+		"manage_job":            tftypes.NewValue(tftypes.Bool, true),
+		"ignore_server_changes": ignored,
+	}))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attributes := api.attributes(teamPath); attributes["manageJob"] != true || attributes["manageWorkspace"] != false {
+		t.Errorf("the configuration was not applied: %v", attributes)
+	}
+	if !attribute(t, state, "manage_job").Equal(tftypes.NewValue(tftypes.Bool, true)) {
+		t.Errorf("the applied manage_job should be in the state")
+	}
+}
+
+func TestTeamProtected(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	protectedNames := map[string]tftypes.Value{
+		"protected_team_names": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "owners")}),
+	}
+	terrakube := newTestProvider(t, server.URL, protectedNames)
+	granted := map[string]tftypes.Value{"manage_workspace": tftypes.NewValue(tftypes.Bool, true)}
+
+	state, diagnostics := terrakube.apply("terrakube_team", terrakube.null("terrakube_team"), teamConfig(terrakube, "owners", granted))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !attribute(t, state, "protected").Equal(tftypes.NewValue(tftypes.Bool, true)) {
+		t.Errorf("a team listed in protected_team_names should be protected")
+	}
+	teamPath := teamCollectionPath + "/" + stringAttribute(t, state, "id")
+
+	// Removing a permission is allowed with a warning.
+	plan := terrakube.plan("terrakube_team", state, teamConfig(terrakube, "owners", nil))
+	if err := diagnosticsError(plan.Diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !hasDiagnostic(plan.Diagnostics, tfprotov6.DiagnosticSeverityWarning, "Permission removed from a protected team") {
+		t.Errorf("removing a permission of a protected team should warn, got %v", plan.Diagnostics)
+	}
+
+	strictAttributes := map[string]tftypes.Value{"warnings_as_errors": tftypes.NewValue(tftypes.Bool, true)}
+	for name, value := range protectedNames {
+		strictAttributes[name] = value
+	}
+	strict := newTestProvider(t, server.URL, strictAttributes)
+	if plan := strict.plan("terrakube_team", state, teamConfig(strict, "owners", nil)); !hasDiagnostic(plan.Diagnostics, tfprotov6.DiagnosticSeverityError, "Permission removed from a protected team") {
+		t.Errorf("with warnings_as_errors removing a permission should fail, got %v", plan.Diagnostics)
+	}
+
+	state, diagnostics = terrakube.apply("terrakube_team", state, teamConfig(terrakube, "owners", nil))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if manageWorkspace := api.attributes(teamPath)["manageWorkspace"]; manageWorkspace != false {
+		t.Errorf("the update of a protected team should be applied, manageWorkspace %v", manageWorkspace)
+	}
+
+	// Destroying needs force_delete, checked by the plan and the apply.
+	if plan := terrakube.plan("terrakube_team", state, terrakube.null("terrakube_team")); !hasDiagnostic(plan.Diagnostics, tfprotov6.DiagnosticSeverityError, "Protected team") {
+		t.Errorf("the destroy plan of a protected team should fail, got %v", plan.Diagnostics)
+	}
+	if _, diagnostics := terrakube.apply("terrakube_team", state, terrakube.null("terrakube_team")); !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, "Protected team") {
+		t.Errorf("deleting a protected team should fail, got %v", diagnostics)
+	}
+	if count := api.count("DELETE", teamPath); count != 0 || api.attributes(teamPath) == nil {
+		t.Errorf("the protected team was deleted")
+	}
+
+	state, diagnostics = terrakube.apply("terrakube_team", state, teamConfig(terrakube, "owners", map[string]tftypes.Value{
+		"force_delete": tftypes.NewValue(tftypes.Bool, true),
+	}))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if plan := terrakube.plan("terrakube_team", state, terrakube.null("terrakube_team")); diagnosticsError(plan.Diagnostics) != nil {
+		t.Errorf("the destroy plan with force_delete should pass, got %v", plan.Diagnostics)
+	}
+	if _, diagnostics := terrakube.apply("terrakube_team", state, terrakube.null("terrakube_team")); diagnosticsError(diagnostics) != nil {
+		t.Fatalf("unexpected error: %s", diagnosticsError(diagnostics))
+	}
+	if count := api.count("DELETE", teamPath); count != 1 {
+		t.Errorf("the team with force_delete should be deleted, got %d DELETE", count)
+	}
+
+	// Other teams are not protected.
+	state, diagnostics = terrakube.apply("terrakube_team", terrakube.null("terrakube_team"), teamConfig(terrakube, "developers", granted))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, diagnostics := terrakube.apply("terrakube_team", state, terrakube.null("terrakube_team")); diagnosticsError(diagnostics) != nil {
+		t.Errorf("deleting a team that is not protected should pass, got %v", diagnostics)
+	}
+}
+
+func TestTeamForceDeleteRevokesAccess(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	// More workspaces than a page, the grants are on the first and last.
+	for i := 1; i <= listPageSize+1; i++ {
+		api.put(fmt.Sprintf("/api/v1/organization/o1/workspace/w%03d", i), "workspace", map[string]any{"name": fmt.Sprintf("workspace-%d", i)})
+	}
+	api.put("/api/v1/organization/o1/workspace/w001/access/a1", "access", map[string]any{"name": "platform", "manageJob": true})
+	api.put("/api/v1/organization/o1/workspace/w001/access/a2", "access", map[string]any{"name": "developers", "manageJob": true})
+	api.put(fmt.Sprintf("/api/v1/organization/o1/workspace/w%03d/access/a3", listPageSize+1), "access", map[string]any{"name": "platform", "manageState": true})
+	terrakube := newTestProvider(t, server.URL, nil)
+	forceDelete := map[string]tftypes.Value{"force_delete": tftypes.NewValue(tftypes.Bool, true)}
+
+	state, diagnostics := terrakube.apply("terrakube_team", terrakube.null("terrakube_team"), teamConfig(terrakube, "platform", forceDelete))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	teamPath := teamCollectionPath + "/" + stringAttribute(t, state, "id")
+
+	// A grant that cannot be deleted keeps the team.
+	api.handle = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodDelete && strings.HasSuffix(r.URL.Path, "/access/a3") {
+			w.WriteHeader(http.StatusForbidden)
+			return true
+		}
+		return false
+	}
+	_, diagnostics = terrakube.apply("terrakube_team", state, terrakube.null("terrakube_team"))
+	if !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, "Error revoking team workspace access") {
+		t.Fatalf("a failed revoke should fail the delete, got %v", diagnostics)
+	}
+	if detail := diagnostics[0].Detail; !strings.Contains(detail, fmt.Sprintf("access a3 of workspace w%03d", listPageSize+1)) {
+		t.Errorf("the detail should name the failed access, got %s", detail)
+	}
+	if count := api.count("DELETE", teamPath); count != 0 {
+		t.Errorf("the team should be kept when an access is not revoked")
+	}
+
+	api.handle = nil
+	_, diagnostics = terrakube.apply("terrakube_team", state, terrakube.null("terrakube_team"))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if api.attributes("/api/v1/organization/o1/workspace/w001/access/a1") != nil || api.attributes(fmt.Sprintf("/api/v1/organization/o1/workspace/w%03d/access/a3", listPageSize+1)) != nil {
+		t.Errorf("the access of the team on every page of workspaces should be revoked")
+	}
+	if api.attributes("/api/v1/organization/o1/workspace/w001/access/a2") == nil {
+		t.Errorf("the access of other teams should be kept")
+	}
+	if api.attributes(teamPath) != nil {
+		t.Errorf("the team should be deleted")
+	}
+
+	// Without force_delete the access is left alone.
+	state, diagnostics = terrakube.apply("terrakube_team", terrakube.null("terrakube_team"), teamConfig(terrakube, "developers", nil))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, diagnostics := terrakube.apply("terrakube_team", state, terrakube.null("terrakube_team")); diagnosticsError(diagnostics) != nil {
+		t.Fatalf("unexpected error: %s", diagnosticsError(diagnostics))
+	}
+	if api.attributes("/api/v1/organization/o1/workspace/w001/access/a2") == nil {
+		t.Errorf("a team deleted without force_delete should not revoke its access")
+	}
+}
+
+func TestKeepUndeclaredPermissions(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		declared types.Bool
+		state    types.Bool
+		expected types.Bool
+	}{
+		{"declared true granted", types.BoolValue(true), types.BoolValue(true), types.BoolValue(true)},
+		{"declared true revoked", types.BoolValue(true), types.BoolValue(false), types.BoolValue(false)},
+		{"declared false granted", types.BoolValue(false), types.BoolValue(true), types.BoolValue(false)},
+		{"declared false revoked", types.BoolValue(false), types.BoolValue(false), types.BoolValue(false)},
+		{"declared null granted", types.BoolNull(), types.BoolValue(true), types.BoolNull()},
+	} {
+		declared := TeamResourceModel{ManageJob: test.declared, ManageState: types.BoolValue(true)}
+		state := TeamResourceModel{ManageJob: test.state, ManageState: types.BoolValue(true)}
+		keepUndeclaredPermissions(&declared, &state)
+		if !state.ManageJob.Equal(test.expected) {
+			t.Errorf("%s: expected manage_job %s, got %s", test.name, test.expected, state.ManageJob)
+		}
+		if !state.ManageState.Equal(types.BoolValue(true)) {
+			t.Errorf("%s: the other permissions should not change, got manage_state %s", test.name, state.ManageState)
+		}
+	}
+}
+
+func TestTeamAuthoritative(t *testing.T) {
+	t.Parallel()
+
+	for _, authoritative := range []bool{true, false} {
+		api, server := newFakeAPI(t)
+		terrakube := newTestProvider(t, server.URL, nil)
+		attributes := map[string]tftypes.Value{"manage_state": tftypes.NewValue(tftypes.Bool, true)}
+		if !authoritative {
+			attributes["authoritative"] = tftypes.NewValue(tftypes.Bool, false)
+		}
+
+		state, diagnostics := terrakube.apply("terrakube_team", terrakube.null("terrakube_team"), teamConfig(terrakube, "platform", attributes))
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		teamPath := teamCollectionPath + "/" + stringAttribute(t, state, "id")
+
+		// Another controller grants a permission that is not declared.
+		api.set(teamPath, "manageJob", true)
+
+		state, diagnostics = terrakube.read("terrakube_team", state)
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if manageJob := attribute(t, state, "manage_job"); !manageJob.Equal(tftypes.NewValue(tftypes.Bool, authoritative)) {
+			t.Errorf("authoritative %t: unexpected refreshed manage_job %s", authoritative, manageJob)
+		}
+		plan := terrakube.plan("terrakube_team", state, teamConfig(terrakube, "platform", attributes))
+		if err := diagnosticsError(plan.Diagnostics); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if changed := !terrakube.value("terrakube_team", plan.PlannedState).Equal(state); changed != authoritative {
+			t.Errorf("authoritative %t: expected a planned change %t, got %t", authoritative, authoritative, changed)
+		}
+
+		// Granting a declared permission keeps the undeclared one only when
+		// the team is not authoritative.
+		attributes["manage_workspace"] = tftypes.NewValue(tftypes.Bool, true)
+		state, diagnostics = terrakube.apply("terrakube_team", state, teamConfig(terrakube, "platform", attributes))
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if teamAttributes := api.attributes(teamPath); teamAttributes["manageWorkspace"] != true || teamAttributes["manageJob"] != !authoritative {
+			t.Errorf("authoritative %t: unexpected permissions %v", authoritative, teamAttributes)
+		}
+		if manageJob := attribute(t, state, "manage_job"); !manageJob.Equal(tftypes.NewValue(tftypes.Bool, false)) {
+			t.Errorf("authoritative %t: the state should keep the declared manage_job, got %s", authoritative, manageJob)
+		}
+
+		// Revoking a declared permission is drift in both modes.
+		api.set(teamPath, "manageState", false)
+		state, diagnostics = terrakube.read("terrakube_team", state)
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if manageState := attribute(t, state, "manage_state"); !manageState.Equal(tftypes.NewValue(tftypes.Bool, false)) {
+			t.Errorf("authoritative %t: a revoked manage_state should be refreshed, got %s", authoritative, manageState)
+		}
+	}
+}
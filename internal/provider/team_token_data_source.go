@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource                     = &TeamTokenDataSource{}
+	_ datasource.DataSourceWithConfigure        = &TeamTokenDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &TeamTokenDataSource{}
+)
+
+type TeamTokenDataSourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	TeamName         types.String `tfsdk:"team_name"`
+	Description      types.String `tfsdk:"description"`
+	DescriptionRegex types.String `tfsdk:"description_regex"`
+	CreatedDate      types.String `tfsdk:"created_date"`
+	ExpiresAt        types.String `tfsdk:"expires_at"`
+}
+
+type TeamTokenDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewTeamTokenDataSource() datasource.DataSource {
+	return &TeamTokenDataSource{}
+}
+
+func (d *TeamTokenDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Team Token Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Team Token datasource")
+}
+
+func (d *TeamTokenDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_team_token"
+}
+
+func (d *TeamTokenDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Find an existing team token by description, for example to revoke tokens created outside of Terraform. The token value is never returned.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Team Token Id",
+			},
+			"team_name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the team who owns the token.",
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "Exact description of the token. Conflicts with `description_regex`.",
+			},
+			"description_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression matching the description of the token. Conflicts with `description`.",
+			},
+			"created_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "Creation date of the token",
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Expiration date of the token in RFC 3339 format, null when the creation date is unknown",
+			},
+		},
+	}
+}
+
+func (d *TeamTokenDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("description"),
+			path.MatchRoot("description_regex"),
+		),
+	}
+}
+
+func (d *TeamTokenDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state TeamTokenDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	match := func(description string) bool { return description == state.Description.ValueString() }
+	if !state.DescriptionRegex.IsNull() {
+		pattern, err := regexp.Compile(state.DescriptionRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("description_regex"), "Invalid description regular expression", err.Error())
+			return
+		}
+		match = pattern.MatchString
+	}
+
+	tokens, err := listTeamTokens(ctx, d.client, d.endpoint, d.token)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading team tokens", apiErrorDetail(err, fmt.Sprintf("Error reading team tokens: %s", err)))
+		return
+	}
+
+	var found []client.TeamTokenEntity
+	for _, teamToken := range tokens {
+		if teamToken.Group == state.TeamName.ValueString() && match(teamToken.Description) {
+			found = append(found, teamToken)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		resp.Diagnostics.AddError("Team token not found", fmt.Sprintf("No token of team %q matches the description.", state.TeamName.ValueString()))
+		return
+	case 1:
+	default:
+		candidates := make([]string, 0, len(found))
+		for _, teamToken := range found {
+			candidates = append(candidates, fmt.Sprintf("%s (%q, created %s)", teamToken.ID, teamToken.Description, teamToken.CreatedDate))
+		}
+		resp.Diagnostics.AddError(
+			"Ambiguous team token description",
+			fmt.Sprintf("%d tokens of team %q match the description, use a more specific one: %s", len(found), state.TeamName.ValueString(), strings.Join(candidates, ", ")),
+		)
+		return
+	}
+
+	teamToken := found[0]
+	state.ID = types.StringValue(teamToken.ID)
+	state.CreatedDate = types.StringValue(teamToken.CreatedDate)
+	state.ExpiresAt = types.StringNull()
+
+	if created, err := time.Parse(time.RFC3339, teamToken.CreatedDate); err == nil {
+		validity := time.Duration(teamToken.Days)*24*time.Hour + time.Duration(teamToken.Hours)*time.Hour + time.Duration(teamToken.Minutes)*time.Minute
+		state.ExpiresAt = types.StringValue(created.Add(validity).Format(time.RFC3339))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// listTeamTokens returns the tokens of every team the caller belongs to,
+// the API never includes their value.
+func listTeamTokens(ctx context.Context, httpClient *http.Client, endpoint string, token string) ([]client.TeamTokenEntity, error) {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/access-token/v1/teams", endpoint), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	tflog.Debug(ctx, "Body Response", map[string]any{"bodyResponse": string(body)})
+
+	if response.StatusCode >= 300 {
+		return nil, fmt.Errorf("status %s: %s", response.Status, string(body))
+	}
+
+	var tokens []client.TeamTokenEntity
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, fmt.Errorf("error unmarshal payload response: %w", err)
+	}
+
+	return tokens, nil
+}
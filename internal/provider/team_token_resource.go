@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -121,17 +120,7 @@ func (r *TeamTokenResource) Configure(ctx context.Context, req resource.Configur
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -162,7 +151,7 @@ func (r *TeamTokenResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	teamTokenRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/access-token/v1/teams", r.endpoint), strings.NewReader(string(bodyJson)))
+	teamTokenRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/access-token/v1/teams", r.endpoint), strings.NewReader(string(bodyJson)))
 	teamTokenRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	teamTokenRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -185,7 +174,7 @@ func (r *TeamTokenResource) Create(ctx context.Context, req resource.CreateReque
 	err = json.Unmarshal(bodyResponse, newTeamToken)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, teamTokenResponse.Status))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(teamTokenResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, teamTokenResponse.Status)))
 		return
 	}
 
@@ -235,7 +224,7 @@ func (r *TeamTokenResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	err = json.Unmarshal(bodyResponse, teamTokens)
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, error: %s, response status %s", err, teamTokenResponse.Status))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(teamTokenResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status %s", err, teamTokenResponse.Status)))
 		return
 	}
 
@@ -278,7 +267,7 @@ func (r *TeamTokenResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	reqToken, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/access-token/v1/teams/%s", r.endpoint, data.ID.ValueString()), nil)
+	reqToken, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/access-token/v1/teams/%s", r.endpoint, data.ID.ValueString()), nil)
 	reqToken.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting team token resource request", fmt.Sprintf("Error deleting team token resource request: %s", err))
@@ -286,8 +275,15 @@ func (r *TeamTokenResource) Delete(ctx context.Context, req resource.DeleteReque
 	}
 
 	resToken, err := r.client.Do(reqToken)
-	if err != nil || resToken.StatusCode != http.StatusAccepted {
-		resp.Diagnostics.AddError("Error deleting team token", fmt.Sprintf("Error deleting team token, error: %s, response status: %s, response body: %s", err, resToken.Status, resToken.Body))
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting team token", fmt.Sprintf("Error deleting team token: %s", err))
+		return
+	}
+	defer resToken.Body.Close()
+
+	if resToken.StatusCode != http.StatusAccepted {
+		bodyResponse, _ := io.ReadAll(resToken.Body)
+		resp.Diagnostics.AddError("Error deleting team token", responseErrorDetail(resToken, fmt.Sprintf("Error deleting team token, response status: %s, response body: %s", resToken.Status, bodyResponse)))
 		return
 	}
 }
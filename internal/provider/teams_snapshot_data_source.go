@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &TeamsSnapshotDataSource{}
+	_ datasource.DataSourceWithConfigure = &TeamsSnapshotDataSource{}
+)
+
+type TeamsSnapshotDataSourceModel struct {
+	OrganizationId types.String `tfsdk:"organization_id"`
+	Json           types.String `tfsdk:"json"`
+	Sha256         types.String `tfsdk:"sha256"`
+}
+
+type TeamsSnapshotDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+// The snapshot types fix the field order of the JSON document, organizations
+// and teams are sorted by name and then id so the same permissions always
+// produce the same bytes.
+type teamsSnapshot struct {
+	Organizations []teamsSnapshotOrganization `json:"organizations"`
+}
+
+type teamsSnapshotOrganization struct {
+	ID    string              `json:"id"`
+	Name  string              `json:"name"`
+	Teams []teamsSnapshotTeam `json:"teams"`
+}
+
+type teamsSnapshotTeam struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	ManageCollection bool   `json:"manage_collection"`
+	ManageJob        bool   `json:"manage_job"`
+	ManageModule     bool   `json:"manage_module"`
+	ManageProvider   bool   `json:"manage_provider"`
+	ManageState      bool   `json:"manage_state"`
+	ManageTemplate   bool   `json:"manage_template"`
+	ManageVcs        bool   `json:"manage_vcs"`
+	ManageWorkspace  bool   `json:"manage_workspace"`
+}
+
+func NewTeamsSnapshotDataSource() datasource.DataSource {
+	return &TeamsSnapshotDataSource{}
+}
+
+func (d *TeamsSnapshotDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Teams Snapshot Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Teams Snapshot datasource")
+}
+
+func (d *TeamsSnapshotDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_teams_snapshot"
+}
+
+func (d *TeamsSnapshotDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Export the teams and their permissions as a canonical JSON document, to back them up with a local_file and compare with a previous backup.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only export the teams of this organization, by default the teams of every organization visible to the token are exported.",
+			},
+			"json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The snapshot, organizations and teams are sorted by name so the document only changes when the teams or their permissions change.",
+			},
+			"sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA256 checksum of `json`, to compare with the checksum of a previous snapshot.",
+			},
+		},
+	}
+}
+
+func (d *TeamsSnapshotDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state TeamsSnapshotDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var organizations []*client.OrganizationEntity
+	if state.OrganizationId.IsNull() {
+		items, err := fetchAllPages(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/organization", d.endpoint), reflect.TypeOf(new(client.OrganizationEntity)))
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading organizations", apiErrorDetail(err, fmt.Sprintf("Error reading organizations: %s", err)))
+			return
+		}
+		for _, item := range items {
+			if organization := item.(*client.OrganizationEntity); !organization.Disabled {
+				organizations = append(organizations, organization)
+			}
+		}
+	} else {
+		organization, err := client.NewCrud[client.OrganizationEntity](d.client, d.endpoint, d.token, "/api/v1/organization").Get(ctx, state.OrganizationId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading organization", apiErrorDetail(err, fmt.Sprintf("Error reading organization %s: %s", state.OrganizationId.ValueString(), err)))
+			return
+		}
+		organizations = append(organizations, organization)
+	}
+
+	snapshot := teamsSnapshot{Organizations: []teamsSnapshotOrganization{}}
+	for _, organization := range organizations {
+		items, err := fetchAllPages(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/organization/%s/team", d.endpoint, organization.ID), reflect.TypeOf(new(client.TeamEntity)))
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading teams", apiErrorDetail(err, fmt.Sprintf("Error reading the teams of organization %s: %s", organization.Name, err)))
+			return
+		}
+
+		teams := make([]teamsSnapshotTeam, 0, len(items))
+		for _, item := range items {
+			team := item.(*client.TeamEntity)
+			teams = append(teams, teamsSnapshotTeam{
+				ID:               team.ID,
+				Name:             team.Name,
+				ManageCollection: team.ManageCollection,
+				ManageJob:        team.ManageJob,
+				ManageModule:     team.ManageModule,
+				ManageProvider:   team.ManageProvider,
+				ManageState:      team.ManageState,
+				ManageTemplate:   team.ManageTemplate,
+				ManageVcs:        team.ManageVcs,
+				ManageWorkspace:  team.ManageWorkspace,
+			})
+		}
+		sort.Slice(teams, func(i, j int) bool {
+			if teams[i].Name != teams[j].Name {
+				return teams[i].Name < teams[j].Name
+			}
+			return teams[i].ID < teams[j].ID
+		})
+
+		snapshot.Organizations = append(snapshot.Organizations, teamsSnapshotOrganization{
+			ID:    organization.ID,
+			Name:  organization.Name,
+			Teams: teams,
+		})
+	}
+	sort.Slice(snapshot.Organizations, func(i, j int) bool {
+		if snapshot.Organizations[i].Name != snapshot.Organizations[j].Name {
+			return snapshot.Organizations[i].Name < snapshot.Organizations[j].Name
+		}
+		return snapshot.Organizations[i].ID < snapshot.Organizations[j].ID
+	})
+
+	document, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating teams snapshot", fmt.Sprintf("Error creating teams snapshot: %s", err))
+		return
+	}
+	document = append(document, '\n')
+	checksum := sha256.Sum256(document)
+
+	state.Json = types.StringValue(string(document))
+	state.Sha256 = types.StringValue(hex.EncodeToString(checksum[:]))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const expectedTeamsSnapshot = `{
+  "organizations": [
+    {
+      "id": "o2",
+      "name": "platform",
+      "teams": [
+        {
+          "id": "t3",
+          "name": "operators",
+          "manage_collection": false,
+          "manage_job": true,
+          "manage_module": false,
+          "manage_provider": false,
+          "manage_state": true,
+          "manage_template": false,
+          "manage_vcs": false,
+          "manage_workspace": false
+        }
+      ]
+    },
+    {
+      "id": "o1",
+      "name": "sandbox",
+      "teams": [
+        {
+          "id": "t2",
+          "name": "alpha",
+          "manage_collection": false,
+          "manage_job": false,
+          "manage_module": true,
+          "manage_provider": false,
+          "manage_state": false,
+          "manage_template": false,
+          "manage_vcs": false,
+          "manage_workspace": false
+        },
+        {
+          "id": "t1",
+          "name": "zeta",
+          "manage_collection": true,
+          "manage_job": false,
+          "manage_module": false,
+          "manage_provider": false,
+          "manage_state": false,
+          "manage_template": false,
+          "manage_vcs": false,
+          "manage_workspace": true
+        }
+      ]
+    }
+  ]
+}
+`
+
+func TestTeamsSnapshotStable(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.put("/api/v1/organization/o1", "organization", map[string]any{"name": "sandbox"})
+	api.put("/api/v1/organization/o2", "organization", map[string]any{"name": "platform"})
+	api.put("/api/v1/organization/o3", "organization", map[string]any{"name": "archived", "disabled": true})
+	api.put("/api/v1/organization/o1/team/t1", "team", map[string]any{"name": "zeta", "manageWorkspace": true, "manageCollection": true})
+	api.put("/api/v1/organization/o1/team/t2", "team", map[string]any{"name": "alpha", "manageModule": true})
+	api.put("/api/v1/organization/o2/team/t3", "team", map[string]any{"name": "operators", "manageState": true, "manageJob": true})
+	api.put("/api/v1/organization/o3/team/t4", "team", map[string]any{"name": "legacy", "manageState": true})
+	// Every read must build the snapshot again, not return the cached one.
+	terrakube := newTestProvider(t, server.URL, map[string]tftypes.Value{"disable_datasource_cache": tftypes.NewValue(tftypes.Bool, true)})
+
+	snapshot := func() (string, string) {
+		state, diagnostics := terrakube.readDataSource("terrakube_teams_snapshot", nil)
+		if err := diagnosticsError(diagnostics); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return stringAttribute(t, state, "json"), stringAttribute(t, state, "sha256")
+	}
+
+	document, checksum := snapshot()
+	if document != expectedTeamsSnapshot {
+		t.Errorf("unexpected snapshot:\n%s", document)
+	}
+	if sum := sha256.Sum256([]byte(document)); checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("sha256 %s does not match the document", checksum)
+	}
+
+	for i := 0; i < 3; i++ {
+		if again, againChecksum := snapshot(); again != document || againChecksum != checksum {
+			t.Fatalf("the snapshot changed without any team change:\n%s", again)
+		}
+	}
+
+	api.set("/api/v1/organization/o1/team/t2", "manageVcs", true)
+	if _, changed := snapshot(); changed == checksum {
+		t.Errorf("a permission change should change the checksum")
+	}
+
+	state, diagnostics := terrakube.readDataSource("terrakube_teams_snapshot", map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o2"),
+	})
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if document := stringAttribute(t, state, "json"); !strings.Contains(document, `"name": "platform"`) || strings.Contains(document, `"name": "sandbox"`) {
+		t.Errorf("unexpected snapshot of one organization:\n%s", document)
+	}
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Terrakube stores the template flow base64 encoded and the UI saves it with
+// its own line endings and trailing spaces. templateContentType treats two
+// flows as equal when they only differ in that whitespace, indentation is
+// kept since it is meaningful in YAML. The code of a terrakube_action uses
+// the same type.
+var (
+	_ basetypes.StringTypable                    = templateContentType{}
+	_ basetypes.StringValuableWithSemanticEquals = templateContentValue{}
+)
+
+type templateContentType struct {
+	basetypes.StringType
+}
+
+func (t templateContentType) Equal(o attr.Type) bool {
+	other, ok := o.(templateContentType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t templateContentType) String() string {
+	return "templateContentType"
+}
+
+func (t templateContentType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return templateContentValue{StringValue: in}, nil
+}
+
+func (t templateContentType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return templateContentValue{StringValue: stringValue}, nil
+}
+
+func (t templateContentType) ValueType(ctx context.Context) attr.Value {
+	return templateContentValue{}
+}
+
+type templateContentValue struct {
+	basetypes.StringValue
+}
+
+func newTemplateContentValue(value string) templateContentValue {
+	return templateContentValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func (v templateContentValue) Equal(o attr.Value) bool {
+	other, ok := o.(templateContentValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v templateContentValue) Type(ctx context.Context) attr.Type {
+	return templateContentType{}
+}
+
+func (v templateContentValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(templateContentValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	return normalizeTemplateContent(v.ValueString()) == normalizeTemplateContent(newValue.ValueString()), diags
+}
+
+// normalizeTemplateContent converts CRLF line endings, removes the trailing
+// spaces of every line and the blank lines at the start and end of the flow.
+func normalizeTemplateContent(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
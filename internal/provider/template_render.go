@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"sort"
+	"strings"
+)
+
+// renderTemplateParameters replaces the ${name} placeholders of a template
+// with the parameters. The rules:
+//
+//   - name is made of letters, digits, '_', '.' and '-', and a value is
+//     inserted as is, it is not scanned for placeholders again.
+//   - a placeholder without parameter is kept verbatim and its name returned
+//     in missing, sorted and without duplicates.
+//   - $${ is an escaped ${ and renders as ${.
+//   - anything else between ${ and its matching }, like the nested
+//     ${A:-${B}} expansions of shell scripts, is kept verbatim with the
+//     placeholders inside it.
+//   - a ${ without matching } is kept verbatim up to the end.
+func renderTemplateParameters(content string, parameters map[string]string) (string, []string) {
+	var rendered strings.Builder
+	missing := map[string]bool{}
+
+	for i := 0; i < len(content); {
+		if strings.HasPrefix(content[i:], "$${") {
+			rendered.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(content[i:], "${") {
+			rendered.WriteByte(content[i])
+			i++
+			continue
+		}
+
+		end := matchingBrace(content, i+2)
+		if end < 0 {
+			rendered.WriteString(content[i:])
+			break
+		}
+
+		name := content[i+2 : end]
+		value, ok := parameters[name]
+		switch {
+		case !isTemplateParameterName(name):
+			rendered.WriteString(content[i : end+1])
+		case ok:
+			rendered.WriteString(value)
+		default:
+			missing[name] = true
+			rendered.WriteString(content[i : end+1])
+		}
+		i = end + 1
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return rendered.String(), names
+}
+
+// matchingBrace returns the index of the } closing the brace opened right
+// before start, or -1.
+func matchingBrace(content string, start int) int {
+	depth := 1
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isTemplateParameterName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '.', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestRenderTemplateParameters(t *testing.T) {
+	t.Parallel()
+
+	parameters := map[string]string{"region": "eu-west-1", "team.name": "platform", "LEVEL-1": "debug", "loop": "${region}", "empty": ""}
+
+	for _, test := range []struct {
+		name     string
+		content  string
+		rendered string
+		missing  []string
+	}{
+		{"no placeholder", "flow: []", "flow: []", []string{}},
+		{"placeholders", "region: ${region}\nteam: ${team.name}\nlevel: ${LEVEL-1}", "region: eu-west-1\nteam: platform\nlevel: debug", []string{}},
+		{"repeated placeholder", "${region}/${region}", "eu-west-1/eu-west-1", []string{}},
+		{"empty value", "value: '${empty}'", "value: ''", []string{}},
+		{"value not rendered again", "${loop}", "${region}", []string{}},
+		{"missing parameters", "${zone} ${region} ${account} ${zone}", "${zone} eu-west-1 ${account} ${zone}", []string{"account", "zone"}},
+		{"escaped placeholder", "$${region} ${region}", "${region} eu-west-1", []string{}},
+		{"escaped missing placeholder", "$${zone}", "${zone}", []string{}},
+		{"shell expansion", "echo ${REGION:-${region}}", "echo ${REGION:-${region}}", []string{}},
+		{"shell expression", "echo ${#region} ${region// /}", "echo ${#region} ${region// /}", []string{}},
+		{"empty placeholder", "${}", "${}", []string{}},
+		{"unclosed placeholder", "${region} ${region", "eu-west-1 ${region", []string{}},
+		{"dollar without brace", "cost: $region $5", "cost: $region $5", []string{}},
+	} {
+		rendered, missing := renderTemplateParameters(test.content, parameters)
+		if rendered != test.rendered {
+			t.Errorf("%s: expected the content %q, got %q", test.name, test.rendered, rendered)
+		}
+		if !reflect.DeepEqual(missing, test.missing) {
+			t.Errorf("%s: expected the missing parameters %v, got %v", test.name, test.missing, missing)
+		}
+	}
+}
+
+func TestOrganizationTemplateRendered(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.put("/api/v1/organization/o1/template/tp1", "template", map[string]any{
+		"name": "deploy",
+		"tcl":  base64.StdEncoding.EncodeToString([]byte("script: deploy ${region} ${zone}")),
+	})
+	terrakube := newTestProvider(t, server.URL, map[string]tftypes.Value{
+		"disable_datasource_cache": tftypes.NewValue(tftypes.Bool, true),
+	})
+	config := map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+		"name":            tftypes.NewValue(tftypes.String, "deploy"),
+	}
+
+	state, diagnostics := terrakube.readDataSource("terrakube_organization_template", config)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if rendered, missing := attribute(t, state, "rendered"), attribute(t, state, "missing_parameters"); !rendered.IsNull() || !missing.IsNull() {
+		t.Errorf("without parameters rendered and missing_parameters should be null, got %s %s", rendered, missing)
+	}
+
+	config["parameters"] = tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+		"region": tftypes.NewValue(tftypes.String, "eu-west-1"),
+	})
+	state, diagnostics = terrakube.readDataSource("terrakube_organization_template", config)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if rendered := stringAttribute(t, state, "rendered"); rendered != "script: deploy eu-west-1 ${zone}" {
+		t.Errorf("unexpected rendered content %q", rendered)
+	}
+	expected := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "zone")})
+	if missing := attribute(t, state, "missing_parameters"); !missing.Equal(expected) {
+		t.Errorf("expected the missing parameters %s, got %s", expected, missing)
+	}
+}
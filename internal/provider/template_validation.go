@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The flow schema of the Terrakube executor, templates using newer keys set
+// skip_validation.
+var (
+	templateFlowKeys = map[string]bool{
+		"type": true, "name": true, "step": true, "team": true, "ignoreError": true,
+		"commands": true, "inputsEnv": true, "inputsTerraform": true, "importComands": true, "templates": true,
+	}
+	templateCommandKeys = map[string]bool{
+		"runtime": true, "priority": true, "before": true, "after": true, "script": true, "verbose": true,
+	}
+	templateFlowTypes = []string{
+		"approval", "customScripts", "disableWorkspace", "scheduleTemplates",
+		"terraformApply", "terraformDestroy", "terraformPlan", "terraformPlanDestroy",
+	}
+	templateRuntimes = []string{"BASH", "GROOVY"}
+)
+
+// templateProblem is an error in the template at a YAML path like
+// flow[1].commands[0].runtime.
+type templateProblem struct {
+	path    string
+	line    int
+	message string
+}
+
+func (p templateProblem) String() string {
+	if p.path == "" {
+		return fmt.Sprintf("line %d: %s", p.line, p.message)
+	}
+	return fmt.Sprintf("%s (line %d): %s", p.path, p.line, p.message)
+}
+
+// validateTemplateContent checks the structure of a template flow and
+// returns every problem found, in document order.
+func validateTemplateContent(content string) []templateProblem {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return []templateProblem{{line: 1, message: err.Error()}}
+	}
+	if len(document.Content) == 0 {
+		return []templateProblem{{line: 1, message: "the template is empty"}}
+	}
+
+	v := &templateValidator{}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		v.add("", root, "the template must be a mapping with a flow key")
+		return v.problems
+	}
+
+	flow := v.mappingValue(root, "", map[string]bool{"flow": true})["flow"]
+	if flow == nil {
+		v.add("", root, "missing the flow key")
+		return v.problems
+	}
+	if flow.Kind != yaml.SequenceNode || len(flow.Content) == 0 {
+		v.add("flow", flow, "must be a non empty list of steps")
+		return v.problems
+	}
+
+	steps := map[int]string{}
+	for i, item := range flow.Content {
+		v.flowItem(fmt.Sprintf("flow[%d]", i), item, steps)
+	}
+
+	return v.problems
+}
+
+type templateValidator struct {
+	problems []templateProblem
+}
+
+func (v *templateValidator) add(path string, node *yaml.Node, format string, args ...any) {
+	v.problems = append(v.problems, templateProblem{path: path, line: node.Line, message: fmt.Sprintf(format, args...)})
+}
+
+// mappingValue returns the values of a mapping node by key, reporting the
+// keys that are not known.
+func (v *templateValidator) mappingValue(node *yaml.Node, path string, known map[string]bool) map[string]*yaml.Node {
+	values := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !known[key.Value] {
+			v.add(joinTemplatePath(path, key.Value), key, "unknown key %q, expected one of %s", key.Value, strings.Join(sortedKeys(known), ", "))
+			continue
+		}
+		values[key.Value] = node.Content[i+1]
+	}
+	return values
+}
+
+func (v *templateValidator) flowItem(path string, node *yaml.Node, steps map[int]string) {
+	if node.Kind != yaml.MappingNode {
+		v.add(path, node, "each step must be a mapping")
+		return
+	}
+
+	values := v.mappingValue(node, path, templateFlowKeys)
+
+	if flowType := values["type"]; flowType == nil {
+		v.add(path, node, "missing the type key")
+	} else if !containsString(templateFlowTypes, flowType.Value) {
+		v.add(path+".type", flowType, "unknown type %q, expected one of %s", flowType.Value, strings.Join(templateFlowTypes, ", "))
+	} else if flowType.Value == "approval" && values["team"] == nil {
+		v.add(path, node, "approval steps need the team allowed to approve them")
+	}
+
+	if name := values["name"]; name == nil || strings.TrimSpace(name.Value) == "" {
+		v.add(path, node, "missing the name key")
+	}
+
+	if step := values["step"]; step == nil {
+		v.add(path, node, "missing the step key")
+	} else if number, err := strconv.Atoi(step.Value); err != nil || step.Kind != yaml.ScalarNode {
+		v.add(path+".step", step, "must be an integer, got %q", step.Value)
+	} else if previous, ok := steps[number]; ok {
+		v.add(path+".step", step, "step %d is already used by %s", number, previous)
+	} else {
+		steps[number] = path
+	}
+
+	if commands := values["commands"]; commands != nil {
+		if commands.Kind != yaml.SequenceNode {
+			v.add(path+".commands", commands, "must be a list")
+			return
+		}
+		for i, command := range commands.Content {
+			v.command(fmt.Sprintf("%s.commands[%d]", path, i), command)
+		}
+	}
+}
+
+func (v *templateValidator) command(path string, node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		v.add(path, node, "each command must be a mapping")
+		return
+	}
+
+	values := v.mappingValue(node, path, templateCommandKeys)
+
+	if runtime := values["runtime"]; runtime == nil {
+		v.add(path, node, "missing the runtime key")
+	} else if !containsString(templateRuntimes, runtime.Value) {
+		v.add(path+".runtime", runtime, "unknown runtime %q, expected one of %s", runtime.Value, strings.Join(templateRuntimes, ", "))
+	}
+
+	if script := values["script"]; script == nil {
+		v.add(path, node, "missing the script key")
+	}
+}
+
+func joinTemplatePath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
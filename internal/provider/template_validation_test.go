@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const validTemplateFlow = `flow:
+  - type: terraformPlan
+    name: Plan
+    step: 100
+    commands:
+      - runtime: BASH
+        priority: 100
+        before: true
+        script: echo plan
+  - type: approval
+    name: Approve
+    step: 200
+    team: platform
+  - type: terraformApply
+    name: Apply
+    step: 300
+`
+
+func TestValidateTemplateContent(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		content  string
+		problems []string
+	}{
+		{"valid flow", validTemplateFlow, nil},
+		{"empty", "", []string{"line 1: the template is empty"}},
+		{"not yaml", "flow: [", []string{"line 1: yaml: line 1: did not find expected node content"}},
+		{"not a mapping", "- flow", []string{"line 1: the template must be a mapping with a flow key"}},
+		{"missing flow", "steps: []\n", []string{
+			`steps (line 1): unknown key "steps", expected one of flow`,
+			"line 1: missing the flow key",
+		}},
+		{"empty flow", "flow: []\n", []string{"flow (line 1): must be a non empty list of steps"}},
+		{"step not a mapping", "flow:\n  - plan\n", []string{"flow[0] (line 2): each step must be a mapping"}},
+		{"unknown flow key", "flow:\n  - type: terraformPlan\n    name: Plan\n    step: 100\n    retries: 2\n", []string{
+			`flow[0].retries (line 5): unknown key "retries", expected one of commands, ignoreError, importComands, inputsEnv, inputsTerraform, name, step, team, templates, type`,
+		}},
+		{"missing keys", "flow:\n  - ignoreError: true\n", []string{
+			"flow[0] (line 2): missing the type key",
+			"flow[0] (line 2): missing the name key",
+			"flow[0] (line 2): missing the step key",
+		}},
+		{"unknown type", "flow:\n  - type: terraformValidate\n    name: Validate\n    step: 100\n", []string{
+			`flow[0].type (line 2): unknown type "terraformValidate", expected one of ` + strings.Join(templateFlowTypes, ", "),
+		}},
+		{"approval without team", "flow:\n  - type: approval\n    name: Approve\n    step: 100\n", []string{
+			"flow[0] (line 2): approval steps need the team allowed to approve them",
+		}},
+		{"step not an integer", "flow:\n  - type: terraformPlan\n    name: Plan\n    step: first\n", []string{
+			`flow[0].step (line 4): must be an integer, got "first"`,
+		}},
+		{"duplicate step", "flow:\n  - type: terraformPlan\n    name: Plan\n    step: 100\n  - type: terraformApply\n    name: Apply\n    step: 100\n", []string{
+			"flow[1].step (line 7): step 100 is already used by flow[0]",
+		}},
+		{"commands not a list", "flow:\n  - type: terraformPlan\n    name: Plan\n    step: 100\n    commands: echo\n", []string{
+			"flow[0].commands (line 5): must be a list",
+		}},
+		{"command not a mapping", "flow:\n  - type: terraformPlan\n    name: Plan\n    step: 100\n    commands:\n      - echo\n", []string{
+			"flow[0].commands[0] (line 6): each command must be a mapping",
+		}},
+		{"command problems", "flow:\n  - type: terraformPlan\n    name: Plan\n    step: 100\n    commands:\n      - runtime: PYTHON\n        shell: sh\n", []string{
+			`flow[0].commands[0].shell (line 7): unknown key "shell", expected one of after, before, priority, runtime, script, verbose`,
+			`flow[0].commands[0].runtime (line 6): unknown runtime "PYTHON", expected one of BASH, GROOVY`,
+			"flow[0].commands[0] (line 6): missing the script key",
+		}},
+		{"command without runtime", "flow:\n  - type: terraformPlan\n    name: Plan\n    step: 100\n    commands:\n      - script: echo\n", []string{
+			"flow[0].commands[0] (line 6): missing the runtime key",
+		}},
+	} {
+		var problems []string
+		for _, problem := range validateTemplateContent(test.content) {
+			problems = append(problems, problem.String())
+		}
+		if strings.Join(problems, "\n") != strings.Join(test.problems, "\n") {
+			t.Errorf("%s: expected the problems\n%s\ngot\n%s", test.name, strings.Join(test.problems, "\n"), strings.Join(problems, "\n"))
+		}
+	}
+}
+
+func TestTemplateValidationConfig(t *testing.T) {
+	t.Parallel()
+
+	_, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+	brokenFlow := tftypes.NewValue(tftypes.String, "flow:\n  - type: terraformValidate\n    name: Validate\n    step: 100\n")
+	skip := tftypes.NewValue(tftypes.Bool, true)
+
+	for _, test := range []struct {
+		name     string
+		typeName string
+		config   map[string]tftypes.Value
+		invalid  bool
+	}{
+		{"valid template", "terrakube_organization_template", map[string]tftypes.Value{"content": tftypes.NewValue(tftypes.String, validTemplateFlow)}, false},
+		{"broken template", "terrakube_organization_template", map[string]tftypes.Value{"content": brokenFlow}, true},
+		{"skipped template", "terrakube_organization_template", map[string]tftypes.Value{"content": brokenFlow, "skip_validation": skip}, false},
+		{"unknown template", "terrakube_organization_template", map[string]tftypes.Value{"content": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)}, false},
+		{"valid inline flow", "terrakube_job", map[string]tftypes.Value{"inline_template_tcl": tftypes.NewValue(tftypes.String, validTemplateFlow)}, false},
+		{"broken inline flow", "terrakube_job", map[string]tftypes.Value{"inline_template_tcl": brokenFlow}, true},
+		{"skipped inline flow", "terrakube_job", map[string]tftypes.Value{"inline_template_tcl": brokenFlow, "skip_validation": skip}, false},
+	} {
+		values := map[string]tftypes.Value{
+			"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+		}
+		switch test.typeName {
+		case "terrakube_organization_template":
+			values["name"] = tftypes.NewValue(tftypes.String, "drift")
+		case "terrakube_job":
+			values["workspace_id"] = tftypes.NewValue(tftypes.String, "w1")
+		}
+		for name, value := range test.config {
+			values[name] = value
+		}
+
+		diagnostics := terrakube.validate(test.typeName, terrakube.object(test.typeName, values))
+		switch {
+		case test.invalid && !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, "Invalid template flow"):
+			t.Errorf("%s: expected the error %q, got %v", test.name, "Invalid template flow", diagnostics)
+		case !test.invalid && diagnosticsError(diagnostics) != nil:
+			t.Errorf("%s: unexpected error: %s", test.name, diagnosticsError(diagnostics))
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestVariableReplacement(t *testing.T) {
+	t.Parallel()
+
+	_, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	for _, typeName := range []string{"terrakube_workspace_variable", "terrakube_organization_variable", "terrakube_collection_item"} {
+		config := func(attributes map[string]tftypes.Value) tftypes.Value {
+			values := map[string]tftypes.Value{
+				"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+				"key":             tftypes.NewValue(tftypes.String, "region"),
+				"value":           tftypes.NewValue(tftypes.String, "eu-west-1"),
+				"description":     tftypes.NewValue(tftypes.String, "description"),
+				"category":        tftypes.NewValue(tftypes.String, "TERRAFORM"),
+				"sensitive":       tftypes.NewValue(tftypes.Bool, false),
+				"hcl":             tftypes.NewValue(tftypes.Bool, false),
+			}
+			switch typeName {
+			case "terrakube_workspace_variable":
+				values["workspace_id"] = tftypes.NewValue(tftypes.String, "w1")
+			case "terrakube_collection_item":
+				values["collection_id"] = tftypes.NewValue(tftypes.String, "c1")
+			}
+			for name, value := range attributes {
+				values[name] = value
+			}
+			return terrakube.object(typeName, values)
+		}
+		state := config(map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "v1")})
+
+		for _, test := range []struct {
+			name    string
+			changed map[string]tftypes.Value
+			replace string
+		}{
+			{"key", map[string]tftypes.Value{"key": tftypes.NewValue(tftypes.String, "zone")}, "key"},
+			{"category", map[string]tftypes.Value{"category": tftypes.NewValue(tftypes.String, "ENV")}, "category"},
+			{"value", map[string]tftypes.Value{"value": tftypes.NewValue(tftypes.String, "us-east-1")}, ""},
+			{"description", map[string]tftypes.Value{"description": tftypes.NewValue(tftypes.String, "region of the stack")}, ""},
+		} {
+			plan := terrakube.plan(typeName, state, config(test.changed))
+			if err := diagnosticsError(plan.Diagnostics); err != nil {
+				t.Fatalf("%s %s: unexpected error: %s", typeName, test.name, err)
+			}
+
+			var replaced []string
+			for _, attributePath := range plan.RequiresReplace {
+				replaced = append(replaced, string(attributePath.Steps()[0].(tftypes.AttributeName)))
+			}
+			switch {
+			case test.replace == "" && len(replaced) != 0:
+				t.Errorf("%s: changing %s should update in place, got a replacement by %v", typeName, test.name, replaced)
+			case test.replace != "" && (len(replaced) != 1 || replaced[0] != test.replace):
+				t.Errorf("%s: changing %s should force a replacement by %s, got %v", typeName, test.name, test.replace, replaced)
+			}
+		}
+	}
+}
+
+func TestWorkspaceVariablesCategoryChange(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+	itemType := terrakube.resourceType("terrakube_workspace_variables").AttributeTypes["variables"].(tftypes.Map).ElementType.(tftypes.Object)
+	config := func(category string) tftypes.Value {
+		return terrakube.object("terrakube_workspace_variables", map[string]tftypes.Value{
+			"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+			"workspace_id":    tftypes.NewValue(tftypes.String, "w1"),
+			"variables": tftypes.NewValue(tftypes.Map{ElementType: itemType}, map[string]tftypes.Value{
+				"region": objectValue(itemType, map[string]tftypes.Value{
+					"value":    tftypes.NewValue(tftypes.String, "eu-west-1"),
+					"category": tftypes.NewValue(tftypes.String, category),
+				}),
+			}),
+		})
+	}
+	variableId := func(state tftypes.Value) string {
+		var variables map[string]tftypes.Value
+		var item map[string]tftypes.Value
+		var id string
+		if err := attribute(t, state, "variables").As(&variables); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if err := variables["region"].As(&item); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if err := item["id"].As(&id); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return id
+	}
+
+	state, diagnostics := terrakube.apply("terrakube_workspace_variables", terrakube.null("terrakube_workspace_variables"), config("TERRAFORM"))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	previousId := variableId(state)
+
+	state, diagnostics = terrakube.apply("terrakube_workspace_variables", state, config("ENV"))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	variablePath := "/api/v1/organization/o1/workspace/w1/variable"
+	if count := api.count("DELETE", variablePath+"/"+previousId); count != 1 {
+		t.Errorf("the variable should be deleted once, got %d DELETE", count)
+	}
+	if count := api.count("PATCH", variablePath); count != 0 {
+		t.Errorf("the category change should not be patched, got %d PATCH", count)
+	}
+	newId := variableId(state)
+	if newId == previousId {
+		t.Errorf("the variable should be created again, it kept the id %s", newId)
+	}
+	if category := api.attributes(variablePath + "/" + newId)["category"]; category != "ENV" {
+		t.Errorf("the new variable should be created in ENV, got %v", category)
+	}
+}
@@ -2,16 +2,11 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
 	"reflect"
-	"strings"
 	"terraform-provider-terrakube/internal/client"
 
-	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -19,8 +14,9 @@ import (
 )
 
 var (
-	_ datasource.DataSource              = &VcsDataSource{}
-	_ datasource.DataSourceWithConfigure = &VcsDataSource{}
+	_ datasource.DataSource                     = &VcsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &VcsDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &VcsDataSource{}
 )
 
 type VcsDataSourceModel struct {
@@ -32,6 +28,9 @@ type VcsDataSourceModel struct {
 	Endpoint       types.String `tfsdk:"endpoint"`
 	ApiUrl         types.String `tfsdk:"api_url"`
 	Status         types.String `tfsdk:"status"`
+	CallbackUrl    types.String `tfsdk:"callback_url"`
+	AllowMissing   types.Bool   `tfsdk:"allow_missing"`
+	Found          types.Bool   `tfsdk:"found"`
 }
 
 type VcsDataSource struct {
@@ -59,17 +58,7 @@ func (d *VcsDataSource) Configure(ctx context.Context, req datasource.ConfigureR
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			d.client = &http.Client{Transport: customTransport}
-		} else {
-			d.client = &http.Client{}
-		}
-	} else {
-		d.client = &http.Client{}
-	}
+	d.client = providerData.HttpClient
 
 	d.endpoint = providerData.Endpoint
 	d.token = providerData.Token
@@ -84,18 +73,14 @@ func (d *VcsDataSource) Metadata(_ context.Context, req datasource.MetadataReque
 func (d *VcsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
-				Computed:    true,
-				Description: "Vcs Id",
-			},
+			"allow_missing": allowMissingSchema(),
+			"found":         foundSchema(),
+			"id":            idOrNameSchema("Vcs Id"),
 			"organization_id": schema.StringAttribute{
 				Required:    true,
 				Description: "Terrakube organization id",
 			},
-			"name": schema.StringAttribute{
-				Required:    true,
-				Description: "Vcs Name",
-			},
+			"name": idOrNameSchema("Vcs Name"),
 			"description": schema.StringAttribute{
 				Computed:    true,
 				Description: "Vcs description information",
@@ -116,54 +101,55 @@ func (d *VcsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, re
 				Computed:    true,
 				Description: "The status of the Vcs provider",
 			},
+			"callback_url": schema.StringAttribute{
+				Computed:    true,
+				Description: "The callback URL of the Vcs provider, used as the redirect URL of the OAuth application",
+			},
 		},
 	}
 }
 
+func (d *VcsDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return idOrNameValidators()
+}
+
 func (d *VcsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state VcsDataSourceModel
 
 	req.Config.Get(ctx, &state)
 
-	apiURL := fmt.Sprintf("%s/api/v1/organization/%s/vcs?filter[vcs]=name=='%s'", d.endpoint, state.OrganizationId.ValueString(), url.PathEscape(state.Name.ValueString()))
-	requestVcs, err := http.NewRequest(http.MethodGet, apiURL, nil)
-	requestVcs.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
-	requestVcs.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating ssh request", fmt.Sprintf("Error creating team resource request: %s", err))
-		return
-	}
-
-	responseVcs, err := d.client.Do(requestVcs)
+	body, err := lookupBody(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/organization/%s/vcs", d.endpoint, state.OrganizationId.ValueString()), "filter[vcs]", state.ID, state.Name)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing ssh request", fmt.Sprintf("Error executing ssh request: %s", err))
+		resp.Diagnostics.AddError("Error executing vcs datasource request", apiErrorDetail(err, fmt.Sprintf("Error executing vcs datasource request: %s", err)))
 		return
 	}
 
-	bodyResponse, err := io.ReadAll(responseVcs.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Error reading ssh response body", fmt.Sprintf("Error reading team resource response body: %s", err))
-	}
-
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-
-	var vcss []interface{}
-
-	vcss, err = jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), reflect.TypeOf(new(client.VcsEntity)))
+	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(body)})
 
+	vcss, err := unmarshalLookup(body, reflect.TypeOf(new(client.VcsEntity)))
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to unmarshal payload", fmt.Sprintf("Unable to unmarshal payload, error: %s, response status %s, response body %s", err, responseVcs.Status, string(bodyResponse)))
+		resp.Diagnostics.AddError("Unable to unmarshal payload", fmt.Sprintf("Unable to unmarshal payload, response body: %s, error: %s", string(body), err))
 		return
 	}
 
 	for _, vcs := range vcss {
 		data, _ := vcs.(*client.VcsEntity)
 		state.ID = types.StringValue(data.ID)
+		state.Name = types.StringValue(data.Name)
 		state.Description = types.StringValue(data.Description)
 		state.ClientId = types.StringValue(data.ClientId)
 		state.Endpoint = types.StringValue(data.Endpoint)
 		state.ApiUrl = types.StringValue(data.ApiUrl)
 		state.Status = types.StringValue(data.Status)
+		state.CallbackUrl = types.StringValue(vcsCallbackUrl(d.endpoint, data.ID))
+	}
+
+	state.Found = types.BoolValue(len(vcss) > 0)
+	if len(vcss) == 0 {
+		lookupIdOrNameNotFound(&resp.Diagnostics, state.AllowMissing, "VCS connection", state.ID, state.Name)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
 	diags := resp.State.Set(ctx, &state)
@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -34,22 +33,25 @@ type VcsResource struct {
 	client   *http.Client
 	endpoint string
 	token    string
+	warnings *warningPolicy
 }
 
 type VcsResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	Name           types.String `tfsdk:"name"`
-	Description    types.String `tfsdk:"description"`
-	VcsType        types.String `tfsdk:"vcs_type"`
-	ConnectionType types.String `tfsdk:"connection_type"`
-	ClientId       types.String `tfsdk:"client_id"`
-	ClientSecret   types.String `tfsdk:"client_secret"`
-	PrivateKey     types.String `tfsdk:"private_key"`
-	Endpoint       types.String `tfsdk:"endpoint"`
-	ApiUrl         types.String `tfsdk:"api_url"`
-	Status         types.String `tfsdk:"status"`
-	ConnectUrl     types.String `tfsdk:"connect_url"`
+	ID                    types.String `tfsdk:"id"`
+	OrganizationId        types.String `tfsdk:"organization_id"`
+	Name                  types.String `tfsdk:"name"`
+	Description           types.String `tfsdk:"description"`
+	VcsType               types.String `tfsdk:"vcs_type"`
+	ConnectionType        types.String `tfsdk:"connection_type"`
+	ClientId              types.String `tfsdk:"client_id"`
+	ClientSecret          types.String `tfsdk:"client_secret"`
+	ClientSecretWoVersion types.Int64  `tfsdk:"client_secret_wo_version"`
+	PrivateKey            types.String `tfsdk:"private_key"`
+	Endpoint              types.String `tfsdk:"endpoint"`
+	ApiUrl                types.String `tfsdk:"api_url"`
+	Status                types.String `tfsdk:"status"`
+	ConnectUrl            types.String `tfsdk:"connect_url"`
+	CallbackUrl           types.String `tfsdk:"callback_url"`
 }
 
 func NewVcsResource() resource.Resource {
@@ -108,25 +110,28 @@ func (r *VcsResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 				Description: "The client ID or GitHub Application ID for the VCS connection",
 			},
 			"client_secret": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "The secret of the VCS connection. Terrakube never returns it, so it is sent when the connection is created and afterwards only when `client_secret_wo_version` changes. " +
+					"It can be left unset for an imported connection whose secret is already set in Terrakube.",
+			},
+			"client_secret_wo_version": schema.Int64Attribute{
 				Optional:    true,
-				Sensitive:   true,
-				Description: "The secret of the VCS connection",
-				Validators: []validator.String{
-					stringvalidator.AtLeastOneOf(path.MatchRelative().AtParent().AtName("client_secret"), path.MatchRelative().AtParent().AtName("private_key")),
-				},
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
+				Description: "Version of `client_secret`, change it to send a rotated secret to Terrakube. The OAuth link of the connection is kept.",
 			},
 			"private_key": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
 				Description: "The private key in PKCS8 format of the VCS connection. Please use command `openssl pkcs8 -topk8 -inform PEM -inform pem -outform pem -in github_rsa_private_key.pem -out private_key.pem -nocrypt` to convert the private key to PKCS8 format form Github default RSA.",
-				Validators: []validator.String{
-					stringvalidator.AtLeastOneOf(path.MatchRelative().AtParent().AtName("client_secret"), path.MatchRelative().AtParent().AtName("private_key")),
-				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							// An imported connection has no private key in state.
+							resp.RequiresReplace = !req.StateValue.IsNull()
+						},
+						"Changing the private key replaces the VCS connection.",
+						"Changing the private key replaces the VCS connection.",
+					),
 				},
 			},
 			"endpoint": schema.StringAttribute{
@@ -146,6 +151,10 @@ func (r *VcsResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://.*$`), "The endpoint must be a valid URL"),
 				},
 			},
+			"callback_url": schema.StringAttribute{
+				Computed:    true,
+				Description: "The callback URL of the VCS connection, use it as the redirect URL of the OAuth application. It is known once the connection is created.",
+			},
 			"connect_url": schema.StringAttribute{
 				Computed:    true,
 				Description: "The connect URL of the VCS connection, after adding the VCS connection, please logon to this URL to connect.",
@@ -177,20 +186,11 @@ func (r *VcsResource) Configure(ctx context.Context, req resource.ConfigureReque
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.warnings = providerData.Warnings
 
 	tflog.Debug(ctx, "Configuring Organization Variable resource", map[string]any{"success": true})
 }
@@ -224,7 +224,7 @@ func (r *VcsResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
-	vcsRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/vcs", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	vcsRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/vcs", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
 	vcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	vcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -234,7 +234,7 @@ func (r *VcsResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	vcsResponse, err := r.client.Do(vcsRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, response status %s, response body %s, error: %s", vcsResponse.Status, vcsResponse.Request.Body, err))
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request: %s", err))
 		return
 	}
 
@@ -247,7 +247,7 @@ func (r *VcsResource) Create(ctx context.Context, req resource.CreateRequest, re
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), vcs)
 	tflog.Info(ctx, string(bodyResponse))
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, vcsResponse.Status))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(vcsResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, vcsResponse.Status)))
 		return
 	}
 
@@ -255,7 +255,9 @@ func (r *VcsResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	plan.ID = types.StringValue(vcs.ID)
 	plan.Name = types.StringValue(vcs.Name)
-	plan.Description = types.StringValue(vcs.Description)
+	if vcs.Description != "" || !plan.Description.IsNull() {
+		plan.Description = types.StringValue(vcs.Description)
+	}
 	plan.VcsType = types.StringValue(vcs.VcsType)
 	plan.ClientId = types.StringValue(vcs.ClientId)
 	plan.Endpoint = types.StringValue(vcs.Endpoint)
@@ -270,6 +272,7 @@ func (r *VcsResource) Create(ctx context.Context, req resource.CreateRequest, re
 	plan.ConnectUrl = types.StringValue(plan.ConnectUrl.ValueString())
 	plan.Status = types.StringValue(vcs.Status)
 	plan.ConnectionType = types.StringValue(vcs.ConnectionType)
+	plan.CallbackUrl = types.StringValue(vcsCallbackUrl(r.endpoint, vcs.ID))
 
 	if vcs.Status == "PENDING" {
 		tflog.Warn(ctx, fmt.Sprintf("VCS connection is pending, please logon to %s to connect. Check doc here %s", plan.ConnectUrl, helpers.GetVCSProviderDoc()))
@@ -297,7 +300,7 @@ func (r *VcsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 
 	vcsResponse, err := r.client.Do(vcsRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, response status %s, response body %s, error: %s", vcsResponse.Status, vcsResponse.Request.Body, err))
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request: %s", err))
 		return
 	}
 
@@ -311,21 +314,28 @@ func (r *VcsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), vcs)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, vcsResponse.Status))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(vcsResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, vcsResponse.Status)))
 		return
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
+	// Secrets are never returned, the state keeps the configured ones and an
+	// imported connection has none.
 	state.ID = types.StringValue(vcs.ID)
 	state.Name = types.StringValue(vcs.Name)
-	state.Description = types.StringValue(vcs.Description)
+	if vcs.Description != "" || !state.Description.IsNull() {
+		state.Description = types.StringValue(vcs.Description)
+	}
 	state.VcsType = types.StringValue(vcs.VcsType)
 	state.ConnectionType = types.StringValue(vcs.ConnectionType)
 	state.ClientId = types.StringValue(vcs.ClientId)
 	state.Endpoint = types.StringValue(vcs.Endpoint)
 	state.ApiUrl = types.StringValue(vcs.ApiUrl)
 	state.Status = types.StringValue(vcs.Status)
+	state.CallbackUrl = types.StringValue(vcsCallbackUrl(r.endpoint, vcs.ID))
+	_, _, connectUrl := GetEndpointAndApiUrl(vcs.VcsType, vcs.ClientId, vcs.Endpoint)
+	state.ConnectUrl = types.StringValue(connectUrl)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -364,9 +374,18 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		ApiUrl:         plan.ApiUrl.ValueString(),
 		Status:         plan.Status.ValueString(),
 	}
+	// Sending the secrets again on every update would clear them for an
+	// imported connection, they are only sent when rotated.
+	var omit []string
+	if plan.ClientSecret.IsNull() || plan.ClientSecretWoVersion.Equal(state.ClientSecretWoVersion) {
+		omit = append(omit, "clientSecret")
+	}
+	if plan.PrivateKey.IsNull() || plan.PrivateKey.Equal(state.PrivateKey) {
+		omit = append(omit, "privateKey")
+	}
 
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err := client.MarshalSparsePayload(out, bodyRequest, omit...)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
@@ -385,7 +404,7 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	vcsResponse, err := r.client.Do(vcsRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, response status %s, response body %s, error: %s", vcsResponse.Status, vcsResponse.Request.Body, err))
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request: %s", err))
 		return
 	}
 
@@ -406,13 +425,13 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	vcsResponse, err = r.client.Do(vcsRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, response status %s, response body %s, error: %s", vcsResponse.Status, vcsResponse.Request.Body, err))
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request: %s", err))
 		return
 	}
 
 	bodyResponse, err = io.ReadAll(vcsResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading VCS resource response body", fmt.Sprintf("Error reading VCS resource response body, error: %s, response status %s", err, vcsResponse.Status))
+		resp.Diagnostics.AddError("Error reading VCS resource response body", responseErrorDetail(vcsResponse, fmt.Sprintf("Error reading VCS resource response body, error: %s, response status %s", err, vcsResponse.Status)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -420,13 +439,15 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), vcs)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, vcsResponse.Status))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(vcsResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, vcsResponse.Status)))
 		return
 	}
 
 	plan.ID = types.StringValue(state.ID.ValueString())
 	plan.Name = types.StringValue(vcs.Name)
-	plan.Description = types.StringValue(vcs.Description)
+	if vcs.Description != "" || !plan.Description.IsNull() {
+		plan.Description = types.StringValue(vcs.Description)
+	}
 	plan.ConnectionType = types.StringValue(vcs.ConnectionType)
 	plan.VcsType = types.StringValue(vcs.VcsType)
 	plan.ClientId = types.StringValue(vcs.ClientId)
@@ -442,6 +463,7 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	plan.ApiUrl = types.StringValue(vcs.ApiUrl)
 	plan.Status = types.StringValue(vcs.Status)
 	plan.ConnectUrl = types.StringValue(plan.ConnectUrl.ValueString())
+	plan.CallbackUrl = types.StringValue(vcsCallbackUrl(r.endpoint, state.ID.ValueString()))
 
 	if vcs.Status == "PENDING" {
 		tflog.Warn(ctx, fmt.Sprintf("VCS connection is pending, please logon to %s to connect. Check doc here %s", plan.ConnectUrl, helpers.GetVCSProviderDoc()))
@@ -460,7 +482,7 @@ func (r *VcsResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
-	vcsRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/vcs/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	vcsRequest, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/vcs/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
 	vcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating VCS resource request", fmt.Sprintf("Error creating VCS resource request: %s", err))
@@ -468,8 +490,15 @@ func (r *VcsResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	}
 
 	vcsResponse, err := r.client.Do(vcsRequest)
-	if err != nil || vcsResponse.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, response status %s, response body %s, error: %s", vcsResponse.Status, vcsResponse.Request.Body, err))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request: %s", err))
+		return
+	}
+	defer vcsResponse.Body.Close()
+
+	if vcsResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(vcsResponse.Body)
+		resp.Diagnostics.AddError("Error executing VCS resource request", responseErrorDetail(vcsResponse, fmt.Sprintf("Error executing VCS resource request, response status: %s, response body: %s", vcsResponse.Status, bodyResponse)))
 		return
 	}
 }
@@ -540,6 +569,22 @@ func (r VcsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequ
 		var state VcsResourceModel
 		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 		plan.Status = types.StringValue(state.Status.ValueString())
+		plan.CallbackUrl = state.CallbackUrl
+
+		if !plan.ClientSecret.IsNull() && !plan.ClientSecret.Equal(state.ClientSecret) && plan.ClientSecretWoVersion.Equal(state.ClientSecretWoVersion) {
+			r.warnings.addAttribute(
+				&resp.Diagnostics,
+				path.Root("client_secret"),
+				"VCS client secret not sent",
+				"The client_secret changed but client_secret_wo_version did not, so the new secret is only stored in the state. Change client_secret_wo_version to send it to Terrakube.",
+			)
+		}
+	} else if plan.ClientSecret.IsNull() && plan.PrivateKey.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_secret"),
+			"Missing VCS connection credentials",
+			"Creating a VCS connection requires client_secret or private_key. They can only be left unset for an imported connection.",
+		)
 	}
 
 	if resp.Diagnostics.HasError() {
@@ -560,3 +605,10 @@ func (r VcsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequ
 
 	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
 }
+
+// vcsCallbackUrl returns the URL the VCS provider redirects to once the
+// OAuth application is authorized. Terrakube builds it from the API endpoint
+// and the connection id.
+func vcsCallbackUrl(endpoint string, vcsId string) string {
+	return fmt.Sprintf("%s/callback/v1/vcs/%s", strings.TrimSuffix(endpoint, "/"), vcsId)
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// warningsAsErrorsNote ends the detail of a warning reported as an error, so
+// the user knows why a warning stopped the run.
+const warningsAsErrorsNote = "\n\nReported as an error because warnings_as_errors is set in the provider configuration."
+
+// warningPolicy adds the warnings of the resources to the diagnostics. With
+// warnings_as_errors they are added as errors instead, so a strict pipeline
+// fails on any downgrade, conflict or ignored change the provider reports.
+// Log messages are not affected. A nil policy adds plain warnings.
+type warningPolicy struct {
+	asErrors bool
+}
+
+func newWarningPolicy(asErrors bool) *warningPolicy {
+	return &warningPolicy{asErrors: asErrors}
+}
+
+func (p *warningPolicy) add(diags *diag.Diagnostics, summary string, detail string) {
+	if p != nil && p.asErrors {
+		diags.AddError(summary, detail+warningsAsErrorsNote)
+		return
+	}
+	diags.AddWarning(summary, detail)
+}
+
+func (p *warningPolicy) addAttribute(diags *diag.Diagnostics, attribute path.Path, summary string, detail string) {
+	if p != nil && p.asErrors {
+		diags.AddAttributeError(attribute, summary, detail+warningsAsErrorsNote)
+		return
+	}
+	diags.AddAttributeWarning(attribute, summary, detail)
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// workspaceAccessExpiresAt is the JSON:API attribute of the expiry, Terrakube
+// versions without it reject payloads that contain it.
+const workspaceAccessExpiresAt = "expiresAt"
+
+var _ validator.String = rfc3339Validator{}
+
+// rfc3339Validator accepts timestamps like 2025-06-30T18:00:00Z.
+type rfc3339Validator struct{}
+
+func (v rfc3339Validator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v rfc3339Validator) MarkdownDescription(_ context.Context) string {
+	return "value must be an RFC3339 timestamp like 2025-06-30T18:00:00Z"
+}
+
+func (v rfc3339Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid timestamp", fmt.Sprintf("%s, got %q: %s", v.Description(ctx), req.ConfigValue.ValueString(), err))
+	}
+}
+
+// planWorkspaceAccessExpiry rejects a new grant that is already expired and
+// warns about an existing grant that expired, since Terrakube keeps expired
+// grants until they are removed.
+func planWorkspaceAccessExpiry(ctx context.Context, now time.Time, warnings *warningPolicy, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var expiresAt types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("expires_at"), &expiresAt)...)
+	if resp.Diagnostics.HasError() || expiresAt.IsNull() || expiresAt.IsUnknown() {
+		return
+	}
+
+	expiry, err := time.Parse(time.RFC3339, expiresAt.ValueString())
+	if err != nil || expiry.After(now) {
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("expires_at"),
+			"Workspace access already expired",
+			fmt.Sprintf("expires_at %s is in the past, a new grant must expire in the future.", expiresAt.ValueString()),
+		)
+		return
+	}
+
+	warnings.addAttribute(
+		&resp.Diagnostics,
+		path.Root("expires_at"),
+		"Workspace access expired",
+		fmt.Sprintf("The grant expired at %s. Remove the resource, or extend expires_at if the access is still needed.", expiresAt.ValueString()),
+	)
+}
+
+// workspaceAccessExpiryValue returns expires_at after a read. The prior value
+// is kept when it is the same instant in another format, so only an expiry
+// changed in the UI is reported as drift.
+func workspaceAccessExpiryValue(prior types.String, expiresAt *string) types.String {
+	if expiresAt == nil || *expiresAt == "" {
+		return types.StringNull()
+	}
+
+	expiry, ok := parseApiTime(*expiresAt)
+	if !ok {
+		if !prior.IsNull() {
+			return prior
+		}
+		return types.StringValue(*expiresAt)
+	}
+
+	if priorExpiry, err := time.Parse(time.RFC3339, prior.ValueString()); err == nil && priorExpiry.Equal(expiry) {
+		return prior
+	}
+
+	return types.StringValue(expiry.UTC().Format(time.RFC3339))
+}
+
+// apiTimeLayouts are the formats of dates returned by Elide, which drops
+// the seconds when they are zero.
+var apiTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05.000Z0700", "2006-01-02T15:04Z0700"}
+
+func parseApiTime(value string) (time.Time, bool) {
+	for _, layout := range apiTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// hasPayloadAttribute reports whether the JSON:API document returns the
+// attribute, Elide returns every attribute of the entity even when null.
+func hasPayloadAttribute(body []byte, name string) bool {
+	var payload struct {
+		Data struct {
+			Attributes map[string]json.RawMessage `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return false
+	}
+
+	_, ok := payload.Data.Attributes[name]
+	return ok
+}
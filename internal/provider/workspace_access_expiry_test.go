@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// workspaceAccessExpiryPlan returns the plan of a grant of the team
+// "platform" expiring at expiresAt, null when it is empty.
+func workspaceAccessExpiryPlan(t *testing.T, r *WorkspaceAccessResource, expiresAt string) tfsdk.Plan {
+	t.Helper()
+
+	var schemaResponse resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &schemaResponse)
+	objectType := schemaResponse.Schema.Type().TerraformType(context.Background()).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	values["organization_id"] = tftypes.NewValue(tftypes.String, "o1")
+	values["workspace_id"] = tftypes.NewValue(tftypes.String, "w1")
+	values["name"] = tftypes.NewValue(tftypes.String, "platform")
+	values["manage_job"] = tftypes.NewValue(tftypes.Bool, true)
+	if expiresAt != "" {
+		values["expires_at"] = tftypes.NewValue(tftypes.String, expiresAt)
+	}
+
+	return tfsdk.Plan{Schema: schemaResponse.Schema, Raw: tftypes.NewValue(objectType, values)}
+}
+
+func TestWorkspaceAccessExpiryPlan(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		name      string
+		expiresAt string
+		existing  bool
+		strict    bool
+		severity  diag.Severity
+		summary   string
+	}{
+		{"future grant", "2026-06-01T13:00:00Z", false, false, 0, ""},
+		{"expired grant", "2026-06-01T11:00:00Z", false, false, diag.SeverityError, "Workspace access already expired"},
+		{"grant expiring now", "2026-06-01T12:00:00Z", false, false, diag.SeverityError, "Workspace access already expired"},
+		{"grant expiring now in another zone", "2026-06-01T14:00:00+02:00", false, false, diag.SeverityError, "Workspace access already expired"},
+		{"existing future grant", "2026-06-02T00:00:00Z", true, false, 0, ""},
+		{"existing expired grant", "2026-05-31T00:00:00Z", true, false, diag.SeverityWarning, "Workspace access expired"},
+		{"existing expired grant with warnings as errors", "2026-05-31T00:00:00Z", true, true, diag.SeverityError, "Workspace access expired"},
+		{"grant without expiry", "", false, false, 0, ""},
+		{"unparsable expiry", "yesterday", false, false, 0, ""},
+	} {
+		r := &WorkspaceAccessResource{now: func() time.Time { return now }, warnings: newWarningPolicy(test.strict)}
+		plan := workspaceAccessExpiryPlan(t, r, test.expiresAt)
+		state := tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)}
+		if test.existing {
+			state.Raw = plan.Raw
+		}
+
+		response := resource.ModifyPlanResponse{Plan: plan}
+		r.ModifyPlan(context.Background(), resource.ModifyPlanRequest{Plan: plan, State: state}, &response)
+
+		switch {
+		case test.summary == "" && len(response.Diagnostics) != 0:
+			t.Errorf("%s: unexpected diagnostics: %v", test.name, response.Diagnostics)
+		case test.summary != "" && (len(response.Diagnostics) != 1 || response.Diagnostics[0].Severity() != test.severity || response.Diagnostics[0].Summary() != test.summary):
+			t.Errorf("%s: expected the %s %q, got %v", test.name, test.severity, test.summary, response.Diagnostics)
+		}
+	}
+}
@@ -3,21 +3,24 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"io"
 	"net/http"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -25,25 +28,35 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &WorkspaceAccessResource{}
 var _ resource.ResourceWithImportState = &WorkspaceAccessResource{}
+var _ resource.ResourceWithConfigValidators = &WorkspaceAccessResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceAccessResource{}
+
+var workspaceAccessPermissions = []string{"manage_state", "manage_job", "manage_workspace"}
 
 type WorkspaceAccessResource struct {
 	client   *http.Client
 	endpoint string
 	token    string
+	teams    *client.Crud[client.TeamEntity]
+	now      func() time.Time
+	warnings *warningPolicy
 }
 
 type WorkspaceAccessResourceModel struct {
 	ID              types.String `tfsdk:"id"`
 	Name            types.String `tfsdk:"name"`
+	TeamId          types.String `tfsdk:"team_id"`
 	OrganizationId  types.String `tfsdk:"organization_id"`
 	WorkspaceId     types.String `tfsdk:"workspace_id"`
 	ManageState     types.Bool   `tfsdk:"manage_state"`
 	ManageWorkspace types.Bool   `tfsdk:"manage_workspace"`
 	ManageJob       types.Bool   `tfsdk:"manage_job"`
+	Preset          types.String `tfsdk:"preset"`
+	ExpiresAt       types.String `tfsdk:"expires_at"`
 }
 
 func NewWorkspaceAccessResource() resource.Resource {
-	return &WorkspaceAccessResource{}
+	return &WorkspaceAccessResource{now: time.Now}
 }
 
 func (r *WorkspaceAccessResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -65,16 +78,33 @@ func (r *WorkspaceAccessResource) Schema(ctx context.Context, req resource.Schem
 			"organization_id": schema.StringAttribute{
 				Required:    true,
 				Description: "Terrakube organization id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"workspace_id": schema.StringAttribute{
 				Required:    true,
 				Description: "Terrakube workspace id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"name": schema.StringAttribute{
-				Required:    true,
-				Description: "Team name",
+				Optional:    true,
+				Computed:    true,
+				Description: "Team name. Conflicts with `team_id`.",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("name"), path.MatchRoot("team_id")),
+				},
+			},
+			"team_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Terrakube team id, the access is granted to the name of the team. Conflicts with `name`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"manage_state": schema.BoolAttribute{
@@ -95,10 +125,52 @@ func (r *WorkspaceAccessResource) Schema(ctx context.Context, req resource.Schem
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
 			},
+			"preset": schema.StringAttribute{
+				Optional:    true,
+				Description: "Permission preset replacing the manage_* attributes: `read` allows to manage state, `write` also allows to manage jobs and `admin` grants every permission.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(permissionPresetNames...),
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				Optional: true,
+				Description: "RFC3339 timestamp after which the team loses the access, for example `2025-06-30T18:00:00Z`. It must be in the future when the grant is created, " +
+					"an expired grant is reported with a warning until it is removed. Requires a Terrakube version with access expiry, otherwise it is not sent and a warning is reported.",
+				Validators: []validator.String{
+					rfc3339Validator{},
+				},
+			},
 		},
 	}
 }
 
+func (r *WorkspaceAccessResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		permissionsConfigValidator{attributes: workspaceAccessPermissions},
+	}
+}
+
+func (r *WorkspaceAccessResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planWorkspaceAccessExpiry(ctx, r.now(), r.warnings, req, resp)
+
+	var plan WorkspaceAccessResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Preset.IsNull() || plan.Preset.IsUnknown() {
+		return
+	}
+
+	permissions := expandPermissionPreset(plan.Preset.ValueString(), workspaceAccessPermissions)
+	plan.ManageState = types.BoolValue(permissions["manage_state"])
+	plan.ManageJob = types.BoolValue(permissions["manage_job"])
+	plan.ManageWorkspace = types.BoolValue(permissions["manage_workspace"])
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
 func (r *WorkspaceAccessResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -114,20 +186,12 @@ func (r *WorkspaceAccessResource) Configure(ctx context.Context, req resource.Co
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.warnings = providerData.Warnings
+	r.teams = client.NewCrud[client.TeamEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/team")
 
 	tflog.Debug(ctx, "Configuring Workspace Access resource", map[string]any{"success": true})
 }
@@ -141,39 +205,37 @@ func (r *WorkspaceAccessResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
+	if !plan.TeamId.IsNull() {
+		team, err := r.teams.Get(ctx, plan.TeamId.ValueString(), plan.OrganizationId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("team_id"), "Error reading team", apiErrorDetail(err, fmt.Sprintf("Error reading team %s: %s", plan.TeamId.ValueString(), err)))
+			return
+		}
+		plan.Name = types.StringValue(team.Name)
+	}
+
 	bodyRequest := &client.WorkspaceAccessEntity{
 		ManageState:     plan.ManageState.ValueBool(),
 		ManageWorkspace: plan.ManageWorkspace.ValueBool(),
 		ManageJob:       plan.ManageJob.ValueBool(),
 		Name:            plan.Name.ValueString(),
+		ExpiresAt:       plan.ExpiresAt.ValueStringPointer(),
 	}
 
-	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
-		return
+	var omit []string
+	if plan.ExpiresAt.IsNull() {
+		omit = append(omit, workspaceAccessExpiresAt)
 	}
 
-	workspaceAccessRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/access", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
-	workspaceAccessRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	workspaceAccessRequest.Header.Add("Content-Type", "application/vnd.api+json")
+	bodyResponse, unsupported, err := r.sendAccess(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/access", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), bodyRequest, omit)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating workspace access resource request", fmt.Sprintf("Error creating workspace access resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing workspace access resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace access resource request: %s", err)))
 		return
 	}
-
-	workspaceAccessResponse, err := r.client.Do(workspaceAccessRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace access resource request", fmt.Sprintf("Error executing workspace access resource request: %s", err))
-		return
+	if unsupported {
+		r.addExpiryUnsupportedWarning(&resp.Diagnostics)
 	}
 
-	bodyResponse, err := io.ReadAll(workspaceAccessResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading workspace access resource response")
-	}
 	workspaceAccess := &client.WorkspaceAccessEntity{}
 
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspaceAccess)
@@ -187,7 +249,11 @@ func (r *WorkspaceAccessResource) Create(ctx context.Context, req resource.Creat
 	plan.ManageState = types.BoolValue(workspaceAccess.ManageState)
 	plan.ManageWorkspace = types.BoolValue(workspaceAccess.ManageWorkspace)
 	plan.ManageJob = types.BoolValue(workspaceAccess.ManageJob)
+	plan.Name = types.StringValue(workspaceAccess.Name)
 	plan.ID = types.StringValue(workspaceAccess.ID)
+	if hasPayloadAttribute(bodyResponse, workspaceAccessExpiresAt) {
+		plan.ExpiresAt = workspaceAccessExpiryValue(plan.ExpiresAt, workspaceAccess.ExpiresAt)
+	}
 
 	tflog.Info(ctx, "workspace access Created", map[string]any{"success": true})
 
@@ -215,14 +281,24 @@ func (r *WorkspaceAccessResource) Read(ctx context.Context, req resource.ReadReq
 		resp.Diagnostics.AddError("Error executing workspace access resource request", fmt.Sprintf("Error executing workspace access resource request: %s", err))
 		return
 	}
+	defer workspaceAccessResponse.Body.Close()
+
+	// The access is removed with the team or the workspace.
+	if workspaceAccessResponse.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
 	bodyResponse, err := io.ReadAll(workspaceAccessResponse.Body)
 	if err != nil {
 		tflog.Error(ctx, "Error reading workspace access resource response")
 	}
+	if workspaceAccessResponse.StatusCode != http.StatusOK {
+		resp.Diagnostics.AddError("Error executing workspace access resource request", responseErrorDetail(workspaceAccessResponse, fmt.Sprintf("Error executing workspace access resource request, response status %s, response body: %s", workspaceAccessResponse.Status, string(bodyResponse))))
+		return
+	}
 	workspaceAccess := &client.WorkspaceAccessEntity{}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspaceAccess)
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -236,6 +312,11 @@ func (r *WorkspaceAccessResource) Read(ctx context.Context, req resource.ReadReq
 	state.ManageJob = types.BoolValue(workspaceAccess.ManageJob)
 	state.Name = types.StringValue(workspaceAccess.Name)
 	state.ID = types.StringValue(workspaceAccess.ID)
+	// Without expiry support the configured value is kept, the warning is
+	// reported when it is sent.
+	if hasPayloadAttribute(bodyResponse, workspaceAccessExpiresAt) {
+		state.ExpiresAt = workspaceAccessExpiryValue(state.ExpiresAt, workspaceAccess.ExpiresAt)
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -257,44 +338,36 @@ func (r *WorkspaceAccessResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
+	// A grant by team_id keeps the team name resolved when it was created.
+	if plan.Name.IsUnknown() {
+		plan.Name = state.Name
+	}
+
 	bodyRequest := &client.WorkspaceAccessEntity{
 		ManageState:     plan.ManageState.ValueBool(),
 		ManageWorkspace: plan.ManageWorkspace.ValueBool(),
 		ManageJob:       plan.ManageJob.ValueBool(),
 		Name:            plan.Name.ValueString(),
 		ID:              state.ID.ValueString(),
+		ExpiresAt:       plan.ExpiresAt.ValueStringPointer(),
 	}
 
-	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
-		return
+	// A removed expiry is sent as null, an expiry never set is not sent.
+	var omit []string
+	if plan.ExpiresAt.IsNull() && state.ExpiresAt.IsNull() {
+		omit = append(omit, workspaceAccessExpiresAt)
 	}
 
-	workspaceAccessReq, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/access/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
-	workspaceAccessReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	workspaceAccessReq.Header.Add("Content-Type", "application/vnd.api+json")
+	_, unsupported, err := r.sendAccess(ctx, http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/access/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), bodyRequest, omit)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating Workspace access resource request", fmt.Sprintf("Error creating Workspace access resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing Workspace access resource request", apiErrorDetail(err, fmt.Sprintf("Error executing Workspace access resource request: %s", err)))
 		return
 	}
-
-	workspaceAccessResponse, err := r.client.Do(workspaceAccessReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing Workspace access resource request", fmt.Sprintf("Error executing Workspace access resource request: %s", err))
-		return
-	}
-
-	bodyResponse, err := io.ReadAll(workspaceAccessResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading Workspace access resource response")
+	if unsupported {
+		r.addExpiryUnsupportedWarning(&resp.Diagnostics)
 	}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
-
-	workspaceAccessReq, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/access/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
+	workspaceAccessReq, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/access/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
 	workspaceAccessReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceAccessReq.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -302,13 +375,13 @@ func (r *WorkspaceAccessResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
-	workspaceAccessResponse, err = r.client.Do(workspaceAccessReq)
+	workspaceAccessResponse, err := r.client.Do(workspaceAccessReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing Workspace access resource request", fmt.Sprintf("Error executing Workspace access resource request: %s", err))
 		return
 	}
 
-	bodyResponse, err = io.ReadAll(workspaceAccessResponse.Body)
+	bodyResponse, err := io.ReadAll(workspaceAccessResponse.Body)
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 	if err != nil {
 		resp.Diagnostics.AddError("Error reading Workspace access resource response body", fmt.Sprintf("Error reading Workspace access resource response body: %s", err))
@@ -323,10 +396,13 @@ func (r *WorkspaceAccessResource) Update(ctx context.Context, req resource.Updat
 	}
 
 	plan.ID = types.StringValue(state.ID.ValueString())
-	state.ManageState = types.BoolValue(workspaceAccess.ManageState)
-	state.ManageWorkspace = types.BoolValue(workspaceAccess.ManageWorkspace)
-	state.ManageJob = types.BoolValue(workspaceAccess.ManageJob)
-	state.Name = types.StringValue(workspaceAccess.Name)
+	plan.ManageState = types.BoolValue(workspaceAccess.ManageState)
+	plan.ManageWorkspace = types.BoolValue(workspaceAccess.ManageWorkspace)
+	plan.ManageJob = types.BoolValue(workspaceAccess.ManageJob)
+	plan.Name = types.StringValue(workspaceAccess.Name)
+	if hasPayloadAttribute(bodyResponse, workspaceAccessExpiresAt) {
+		plan.ExpiresAt = workspaceAccessExpiryValue(plan.ExpiresAt, workspaceAccess.ExpiresAt)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -341,18 +417,25 @@ func (r *WorkspaceAccessResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/access/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
+	workspaceRequest, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/access/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
 	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating Workspace access resource request", fmt.Sprintf("Error creating Workspace access resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(workspaceRequest)
+	workspaceResponse, err := r.client.Do(workspaceRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing Workspace access resource request", fmt.Sprintf("Error executing Workspace access resource request: %s", err))
 		return
 	}
+	defer workspaceResponse.Body.Close()
+
+	if workspaceResponse.StatusCode >= 300 && workspaceResponse.StatusCode != http.StatusNotFound {
+		bodyResponse, _ := io.ReadAll(workspaceResponse.Body)
+		resp.Diagnostics.AddError("Error executing Workspace access resource request", responseErrorDetail(workspaceResponse, fmt.Sprintf("Error executing Workspace access resource request, response status %s, response body: %s", workspaceResponse.Status, string(bodyResponse))))
+		return
+	}
 }
 
 func (r *WorkspaceAccessResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -361,7 +444,7 @@ func (r *WorkspaceAccessResource) ImportState(ctx context.Context, req resource.
 	if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
 		resp.Diagnostics.AddError(
 			"Unexpected Import Identifier",
-			fmt.Sprintf("Expected import identifier with format: 'organization_ID,workspace_ID, ID', Got: %q", req.ID),
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,workspace_ID,ID', Got: %q", req.ID),
 		)
 		return
 	}
@@ -370,3 +453,65 @@ func (r *WorkspaceAccessResource) ImportState(ctx context.Context, req resource.
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), idParts[1])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[2])...)
 }
+
+// sendAccess sends the access and returns the response body. When Terrakube
+// rejects the expiry attribute the access is sent again without it and
+// unsupported is true.
+func (r *WorkspaceAccessResource) sendAccess(ctx context.Context, method string, url string, entity *client.WorkspaceAccessEntity, omit []string) ([]byte, bool, error) {
+	body, status, err := r.send(method, url, entity, omit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	unsupported := false
+	if status == http.StatusBadRequest && strings.Contains(string(body), workspaceAccessExpiresAt) && !containsString(omit, workspaceAccessExpiresAt) {
+		tflog.Warn(ctx, "Terrakube rejected the access expiry, sending the access without it", map[string]any{"response": string(body)})
+		unsupported = true
+		body, status, err = r.send(method, url, entity, append(omit, workspaceAccessExpiresAt))
+		if err != nil {
+			return nil, unsupported, err
+		}
+	}
+
+	if status >= 300 {
+		return nil, unsupported, fmt.Errorf("response status %d, response body: %s", status, string(body))
+	}
+
+	return body, unsupported, nil
+}
+
+func (r *WorkspaceAccessResource) send(method string, url string, entity *client.WorkspaceAccessEntity, omit []string) ([]byte, int, error) {
+	var out = new(bytes.Buffer)
+	if err := client.MarshalSparsePayload(out, entity, omit...); err != nil {
+		return nil, 0, fmt.Errorf("unable to marshal payload: %w", err)
+	}
+
+	request, err := http.NewRequest(method, url, out)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, response.StatusCode, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	return body, response.StatusCode, nil
+}
+
+func (r *WorkspaceAccessResource) addExpiryUnsupportedWarning(diags *diag.Diagnostics) {
+	r.warnings.addAttribute(
+		diags,
+		path.Root("expires_at"),
+		"Workspace access expiry not supported",
+		"This Terrakube version has no access expiry, the grant was saved without expires_at and does not expire. Remove it manually when the access is no longer needed.",
+	)
+}
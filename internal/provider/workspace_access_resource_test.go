@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// workspaceAccessConfig returns a terrakube_workspace_access configuration
+// of the team "platform" on the workspace w1 of organization o1.
+func workspaceAccessConfig(terrakube *testProvider, attributes map[string]tftypes.Value) tftypes.Value {
+	values := map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+		"workspace_id":    tftypes.NewValue(tftypes.String, "w1"),
+		"name":            tftypes.NewValue(tftypes.String, "platform"),
+	}
+	for attributeName, value := range attributes {
+		values[attributeName] = value
+	}
+	return terrakube.object("terrakube_workspace_access", values)
+}
+
+func TestExpandPermissionPreset(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		preset      string
+		permissions map[string]bool
+	}{
+		{"read", map[string]bool{"manage_state": true, "manage_job": false, "manage_workspace": false}},
+		{"write", map[string]bool{"manage_state": true, "manage_job": true, "manage_workspace": false}},
+		{"admin", map[string]bool{"manage_state": true, "manage_job": true, "manage_workspace": true}},
+	} {
+		if permissions := expandPermissionPreset(test.preset, workspaceAccessPermissions); !reflect.DeepEqual(permissions, test.permissions) {
+			t.Errorf("expandPermissionPreset(%s) = %v, expected %v", test.preset, permissions, test.permissions)
+		}
+	}
+}
+
+func TestWorkspaceAccessPermissionsValidation(t *testing.T) {
+	t.Parallel()
+
+	_, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+	granted := tftypes.NewValue(tftypes.Bool, true)
+	denied := tftypes.NewValue(tftypes.Bool, false)
+	preset := tftypes.NewValue(tftypes.String, "write")
+
+	for _, test := range []struct {
+		name       string
+		attributes map[string]tftypes.Value
+		summary    string
+	}{
+		{"nothing set", nil, "No permission granted"},
+		{"every permission false", map[string]tftypes.Value{"manage_state": denied, "manage_job": denied, "manage_workspace": denied}, "No permission granted"},
+		{"preset and permission", map[string]tftypes.Value{"preset": preset, "manage_job": denied}, "Conflicting permission configuration"},
+		{"preset", map[string]tftypes.Value{"preset": preset}, ""},
+		{"one permission", map[string]tftypes.Value{"manage_state": denied, "manage_job": granted}, ""},
+		{"unknown permission", map[string]tftypes.Value{"manage_job": tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue)}, ""},
+	} {
+		diagnostics := terrakube.validate("terrakube_workspace_access", workspaceAccessConfig(terrakube, test.attributes))
+		switch {
+		case test.summary == "" && diagnosticsError(diagnostics) != nil:
+			t.Errorf("%s: unexpected error: %s", test.name, diagnosticsError(diagnostics))
+		case test.summary != "" && !hasDiagnostic(diagnostics, tfprotov6.DiagnosticSeverityError, test.summary):
+			t.Errorf("%s: expected the error %q, got %v", test.name, test.summary, diagnostics)
+		}
+	}
+}
+
+func TestWorkspaceAccessPresetPlan(t *testing.T) {
+	t.Parallel()
+
+	_, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	plan := terrakube.plan("terrakube_workspace_access", terrakube.null("terrakube_workspace_access"), workspaceAccessConfig(terrakube, map[string]tftypes.Value{
+		"preset": tftypes.NewValue(tftypes.String, "write"),
+	}))
+	if err := diagnosticsError(plan.Diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	planned := terrakube.value("terrakube_workspace_access", plan.PlannedState)
+	for name, expected := range map[string]bool{"manage_state": true, "manage_job": true, "manage_workspace": false} {
+		if value := attribute(t, planned, name); !value.Equal(tftypes.NewValue(tftypes.Bool, expected)) {
+			t.Errorf("preset write planned %s = %s, expected %t", name, value, expected)
+		}
+	}
+}
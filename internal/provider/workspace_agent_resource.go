@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &WorkspaceAgentResource{}
+var _ resource.ResourceWithImportState = &WorkspaceAgentResource{}
+
+type WorkspaceAgentResource struct {
+	client     *http.Client
+	endpoint   string
+	token      string
+	workspaces *client.Crud[client.WorkspaceEntity]
+	agents     *client.Crud[client.AgentEntity]
+}
+
+type WorkspaceAgentResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	OrganizationId types.String `tfsdk:"organization_id"`
+	WorkspaceId    types.String `tfsdk:"workspace_id"`
+	AgentId        types.String `tfsdk:"agent_id"`
+}
+
+func NewWorkspaceAgentResource() resource.Resource {
+	return &WorkspaceAgentResource{}
+}
+
+func (r *WorkspaceAgentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_agent"
+}
+
+func (r *WorkspaceAgentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Run the jobs of a workspace on a self hosted agent of the organization. " +
+			"Destroying the resource detaches the agent, the workspace then runs on the default executor.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Id of the association, the workspace id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube workspace id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"agent_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Id of the self hosted agent running the jobs of the workspace",
+			},
+		},
+	}
+}
+
+func (r *WorkspaceAgentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Workspace Agent Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+	r.workspaces = client.NewCrud[client.WorkspaceEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/workspace")
+	r.agents = client.NewCrud[client.AgentEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/agent")
+
+	tflog.Debug(ctx, "Configuring Workspace Agent resource", map[string]any{"success": true})
+}
+
+func (r *WorkspaceAgentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan WorkspaceAgentResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.agentExists(ctx, plan, &resp.Diagnostics) {
+		return
+	}
+
+	agentId := plan.AgentId.ValueString()
+	err := r.workspaces.PatchRelationship(ctx, plan.WorkspaceId.ValueString(), "agent", &agentId, plan.OrganizationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace agent resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace agent resource request: %s", err)))
+		return
+	}
+
+	plan.ID = plan.WorkspaceId
+
+	tflog.Info(ctx, "Workspace Agent Resource Created", map[string]any{"success": true})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *WorkspaceAgentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state WorkspaceAgentResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace, err := r.workspaces.Get(ctx, state.WorkspaceId.ValueString(), state.OrganizationId.ValueString())
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace agent resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace agent resource request: %s", err)))
+		return
+	}
+
+	// An agent detached in the UI plans to assign it again, an agent swapped
+	// in the UI is reported as a change of agent_id.
+	if workspace.Deleted || workspace.Agent == nil || workspace.Agent.ID == "" {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.ID = types.StringValue(workspace.ID)
+	state.AgentId = types.StringValue(workspace.Agent.ID)
+
+	// Set refreshed state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Workspace Agent Resource reading", map[string]any{"success": true})
+}
+
+func (r *WorkspaceAgentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan WorkspaceAgentResourceModel
+	var state WorkspaceAgentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.agentExists(ctx, plan, &resp.Diagnostics) {
+		return
+	}
+
+	agentId := plan.AgentId.ValueString()
+	err := r.workspaces.PatchRelationship(ctx, state.WorkspaceId.ValueString(), "agent", &agentId, state.OrganizationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace agent resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace agent resource request: %s", err)))
+		return
+	}
+
+	plan.ID = state.ID
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *WorkspaceAgentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data WorkspaceAgentResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.workspaces.PatchRelationship(ctx, data.WorkspaceId.ValueString(), "agent", nil, data.OrganizationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace agent resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace agent resource request: %s", err)))
+		return
+	}
+}
+
+func (r *WorkspaceAgentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,workspace_ID', Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+}
+
+// agentExists checks the agent before it is assigned, the API would
+// otherwise answer with a generic error for an unknown relationship id.
+func (r *WorkspaceAgentResource) agentExists(ctx context.Context, plan WorkspaceAgentResourceModel, diags *diag.Diagnostics) bool {
+	_, err := r.agents.Get(ctx, plan.AgentId.ValueString(), plan.OrganizationId.ValueString())
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		diags.AddAttributeError(
+			path.Root("agent_id"),
+			"Agent not found",
+			fmt.Sprintf("No self hosted agent with id %s exists in organization %s.", plan.AgentId.ValueString(), plan.OrganizationId.ValueString()),
+		)
+		return false
+	}
+	if err != nil {
+		diags.AddError("Error executing workspace agent resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace agent resource request: %s", err)))
+		return false
+	}
+	return true
+}
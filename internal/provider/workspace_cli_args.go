@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	cliArgsPlanVariable  = "TF_CLI_ARGS_plan"
+	cliArgsApplyVariable = "TF_CLI_ARGS_apply"
+)
+
+// WorkspaceCliArgsModel maps the cli_args attribute of the workspace
+// resources. Each list is stored in Terrakube as a TF_CLI_ARGS_<command>
+// environment variable of the workspace.
+type WorkspaceCliArgsModel struct {
+	Plan  types.List `tfsdk:"plan"`
+	Apply types.List `tfsdk:"apply"`
+}
+
+func workspaceCliArgsSchema() schema.SingleNestedAttribute {
+	argsValidators := []validator.List{
+		listvalidator.ValueStringsAre(
+			stringvalidator.RegexMatches(regexp.MustCompile(`^[^\r\n]*$`), "must not contain newline characters"),
+		),
+	}
+
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Description: "Extra arguments for the terraform commands executed by the workspace. " +
+			"They are stored as the TF_CLI_ARGS_plan and TF_CLI_ARGS_apply environment variables of the workspace, quoted so values can contain spaces and quotes.",
+		Attributes: map[string]schema.Attribute{
+			"plan": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arguments added to terraform plan, for example `-parallelism=20`.",
+				Validators:  argsValidators,
+			},
+			"apply": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arguments added to terraform apply.",
+				Validators:  argsValidators,
+			},
+		},
+	}
+}
+
+// syncWorkspaceCliArgs creates, updates or deletes the TF_CLI_ARGS variables
+// of the workspace so they match the desired cli_args.
+func syncWorkspaceCliArgs(ctx context.Context, variables *client.Crud[client.WorkspaceVariableEntity], organizationId string, workspaceId string, desired *WorkspaceCliArgsModel) error {
+	existing, err := variables.List(ctx, organizationId, workspaceId)
+	if err != nil {
+		return err
+	}
+
+	var plan, apply types.List
+	if desired != nil {
+		plan, apply = desired.Plan, desired.Apply
+	}
+
+	for key, values := range map[string]types.List{cliArgsPlanVariable: plan, cliArgsApplyVariable: apply} {
+		var args []string
+		if !values.IsNull() && !values.IsUnknown() {
+			if diags := values.ElementsAs(ctx, &args, false); diags.HasError() {
+				return fmt.Errorf("unable to read the %s arguments: %s", key, diags.Errors()[0].Detail())
+			}
+		}
+
+		current := findEnvVariable(existing, key)
+
+		switch {
+		case len(args) == 0 && current != nil:
+			err = variables.Delete(ctx, current.ID, organizationId, workspaceId)
+		case len(args) == 0:
+			continue
+		case current == nil:
+			_, err = variables.Create(ctx, &client.WorkspaceVariableEntity{
+				Key:         key,
+				Value:       quoteCliArgs(args),
+				Description: "Managed by the cli_args attribute of the Terrakube provider",
+				Category:    "ENV",
+			}, organizationId, workspaceId)
+		case current.Value != quoteCliArgs(args):
+			current.Value = quoteCliArgs(args)
+			err = variables.Update(ctx, current.ID, current, organizationId, workspaceId)
+		}
+
+		if err != nil {
+			return fmt.Errorf("unable to update workspace variable %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// readWorkspaceCliArgs rebuilds cli_args from the workspace variables. Lists
+// configured as empty stay empty when the variable does not exist.
+func readWorkspaceCliArgs(ctx context.Context, variables *client.Crud[client.WorkspaceVariableEntity], organizationId string, workspaceId string, prior *WorkspaceCliArgsModel) (*WorkspaceCliArgsModel, error) {
+	existing, err := variables.List(ctx, organizationId, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	read := func(key string, prior types.List) (types.List, error) {
+		current := findEnvVariable(existing, key)
+		if current == nil {
+			if !prior.IsNull() && len(prior.Elements()) == 0 {
+				return prior, nil
+			}
+			return types.ListNull(types.StringType), nil
+		}
+
+		args, err := splitCliArgs(current.Value)
+		if err != nil {
+			return types.ListNull(types.StringType), fmt.Errorf("unable to parse workspace variable %s: %w", key, err)
+		}
+
+		list, diags := types.ListValueFrom(ctx, types.StringType, args)
+		if diags.HasError() {
+			return types.ListNull(types.StringType), fmt.Errorf("unable to read workspace variable %s: %s", key, diags.Errors()[0].Detail())
+		}
+		return list, nil
+	}
+
+	result := &WorkspaceCliArgsModel{}
+	if result.Plan, err = read(cliArgsPlanVariable, prior.Plan); err != nil {
+		return nil, err
+	}
+	if result.Apply, err = read(cliArgsApplyVariable, prior.Apply); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func findEnvVariable(variables []*client.WorkspaceVariableEntity, key string) *client.WorkspaceVariableEntity {
+	for _, variable := range variables {
+		if variable.Key == key && variable.Category == "ENV" {
+			return variable
+		}
+	}
+	return nil
+}
+
+// quoteCliArgs joins the arguments with spaces, single quoting the ones
+// terraform would otherwise split or unescape.
+func quoteCliArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t'\"\\$`") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// splitCliArgs splits a TF_CLI_ARGS value into arguments following the shell
+// quoting rules used by terraform: single quotes, double quotes and
+// backslash escapes.
+func splitCliArgs(value string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		case c == '\'':
+			inArg = true
+			end := strings.IndexByte(value[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in %q", value)
+			}
+			current.WriteString(value[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			inArg = true
+			closed := false
+			for i++; i < len(value); i++ {
+				if value[i] == '\\' && i+1 < len(value) && strings.IndexByte("\"\\$`", value[i+1]) >= 0 {
+					i++
+				} else if value[i] == '"' {
+					closed = true
+					break
+				}
+				current.WriteByte(value[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated double quote in %q", value)
+			}
+		case c == '\\' && i+1 < len(value):
+			inArg = true
+			i++
+			current.WriteByte(value[i])
+		default:
+			inArg = true
+			current.WriteByte(c)
+		}
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"terraform-provider-terrakube/internal/client"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCliArgsQuoting(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		args   []string
+		quoted string
+	}{
+		{"plain", []string{"-parallelism=20", "-refresh=false"}, "-parallelism=20 -refresh=false"},
+		{"space in value", []string{"-var=name=my workspace"}, "'-var=name=my workspace'"},
+		{"equals in value", []string{"-var=filter=a=b", "-target=module.a"}, "-var=filter=a=b -target=module.a"},
+		{"single quote", []string{"-var=owner=o'brien"}, `'-var=owner=o'\''brien'`},
+		{"double quote", []string{`-var=tags={"env":"prod"}`}, `'-var=tags={"env":"prod"}'`},
+		{"shell characters", []string{"-var=cost=$5", `-var=path=C:\tmp`}, `'-var=cost=$5' '-var=path=C:\tmp'`},
+		{"empty argument", []string{"-var=empty=", ""}, "-var=empty= ''"},
+	} {
+		if quoted := quoteCliArgs(test.args); quoted != test.quoted {
+			t.Errorf("%s: quoteCliArgs(%q) = %s, expected %s", test.name, test.args, quoted, test.quoted)
+		}
+
+		split, err := splitCliArgs(test.quoted)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(split, test.args) {
+			t.Errorf("%s: splitCliArgs(%s) = %q, expected %q", test.name, test.quoted, split, test.args)
+		}
+	}
+}
+
+func TestSplitCliArgs(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		value string
+		args  []string
+		err   bool
+	}{
+		{`-var="name=my workspace"`, []string{"-var=name=my workspace"}, false},
+		{`-var="say=\"hi\""  -lock=false`, []string{`-var=say="hi"`, "-lock=false"}, false},
+		{`-var=name=my\ workspace`, []string{"-var=name=my workspace"}, false},
+		{`-var='a=b=c'`, []string{"-var=a=b=c"}, false},
+		{"\t-input=false\t", []string{"-input=false"}, false},
+		{"", nil, false},
+		{`-var='unterminated`, nil, true},
+		{`-var="unterminated`, nil, true},
+	} {
+		args, err := splitCliArgs(test.value)
+		if (err != nil) != test.err {
+			t.Errorf("splitCliArgs(%s): unexpected error %v", test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(args, test.args) {
+			t.Errorf("splitCliArgs(%s) = %q, expected %q", test.value, args, test.args)
+		}
+	}
+}
+
+// TestWorkspaceCliArgsPaged keeps the TF_CLI_ARGS variables after the first
+// page of the workspace variables, they must still be found and updated
+// instead of created again.
+func TestWorkspaceCliArgsPaged(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	collectionPath := "/api/v1/organization/o1/workspace/w1/variable"
+	for i := 0; i < listPageSize; i++ {
+		api.put(fmt.Sprintf("%s/a%03d", collectionPath, i), "variable", map[string]any{"key": fmt.Sprintf("VAR_%d", i), "value": "", "category": "ENV"})
+	}
+	api.put(collectionPath+"/z001", "variable", map[string]any{"key": cliArgsPlanVariable, "value": "'-var=name=my workspace'", "category": "ENV"})
+
+	ctx := context.Background()
+	variables := client.NewCrud[client.WorkspaceVariableEntity](http.DefaultClient, server.URL, "token", "/api/v1/organization/%s/workspace/%s/variable")
+
+	read, err := readWorkspaceCliArgs(ctx, variables, "o1", "w1", &WorkspaceCliArgsModel{Plan: types.ListNull(types.StringType), Apply: types.ListNull(types.StringType)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected, _ := types.ListValueFrom(ctx, types.StringType, []string{"-var=name=my workspace"})
+	if !read.Plan.Equal(expected) || !read.Apply.IsNull() {
+		t.Errorf("unexpected cli_args %v", read)
+	}
+
+	plan, _ := types.ListValueFrom(ctx, types.StringType, []string{"-parallelism=20"})
+	if err := syncWorkspaceCliArgs(ctx, variables, "o1", "w1", &WorkspaceCliArgsModel{Plan: plan, Apply: types.ListNull(types.StringType)}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count := api.count("POST", collectionPath); count != 0 {
+		t.Errorf("the variable on the second page was created again, %d POST", count)
+	}
+	if value := api.attributes(collectionPath + "/z001")["value"]; value != "-parallelism=20" {
+		t.Errorf("unexpected value %v", value)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -25,21 +24,31 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &WorkspaceCliResource{}
 var _ resource.ResourceWithImportState = &WorkspaceCliResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceCliResource{}
 
 type WorkspaceCliResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client        *http.Client
+	endpoint      string
+	token         string
+	variables     *client.Crud[client.WorkspaceVariableEntity]
+	organizations *organizationCache
+	warnings      *warningPolicy
 }
 
 type WorkspaceCliResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	Name           types.String `tfsdk:"name"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	Description    types.String `tfsdk:"description"`
-	IaCType        types.String `tfsdk:"iac_type"`
-	IaCVersion     types.String `tfsdk:"iac_version"`
-	ExecutionMode  types.String `tfsdk:"execution_mode"`
+	ID                     types.String           `tfsdk:"id"`
+	Name                   types.String           `tfsdk:"name"`
+	OrganizationId         types.String           `tfsdk:"organization_id"`
+	Description            descriptionValue       `tfsdk:"description"`
+	IaCType                types.String           `tfsdk:"iac_type"`
+	IaCVersion             types.String           `tfsdk:"iac_version"`
+	ExecutionMode          types.String           `tfsdk:"execution_mode"`
+	EffectiveExecutionMode types.String           `tfsdk:"effective_execution_mode"`
+	CliArgs                *WorkspaceCliArgsModel `tfsdk:"cli_args"`
+	ProviderMirrorUrl      types.String           `tfsdk:"provider_mirror_url"`
+	InitialStateFile       types.String           `tfsdk:"initial_state_file"`
+	AllowDowngrade         types.Bool             `tfsdk:"allow_version_downgrade"`
+	Slug                   types.String           `tfsdk:"slug"`
 }
 
 func NewWorkspaceCliResource() resource.Resource {
@@ -73,13 +82,20 @@ func (r *WorkspaceCliResource) Schema(ctx context.Context, req resource.SchemaRe
 				Description: "Workspace CLI name",
 			},
 			"description": schema.StringAttribute{
+				CustomType:  descriptionType{},
 				Required:    true,
 				Description: "Workspace CLI description",
 			},
+			"allow_version_downgrade": allowVersionDowngradeSchema(),
+			"slug":                    workspaceSlugSchema(),
+			"cli_args":                workspaceCliArgsSchema(),
+			"provider_mirror_url":     workspaceProviderMirrorSchema(),
+			"initial_state_file":      initialStateFileSchema(),
 			"execution_mode": schema.StringAttribute{
-				Required:    true,
-				Description: "Workspace CLI execution mode (remote or local). Remote execution will require setting up executor.",
+				Optional:    true,
+				Description: "Workspace CLI execution mode (remote or local). Remote execution will require setting up executor. When not set the workspace uses the execution mode of the organization.",
 			},
+			"effective_execution_mode": effectiveExecutionModeSchema(),
 			"iac_type": schema.StringAttribute{
 				Required:    true,
 				Description: "Workspace CLI IaC type (Supported values terraform or tofu)",
@@ -107,20 +123,13 @@ func (r *WorkspaceCliResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.warnings = providerData.Warnings
+	r.variables = client.NewCrud[client.WorkspaceVariableEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/workspace/%s/variable")
+	r.organizations = providerData.Organizations
 
 	tflog.Debug(ctx, "Configuring Workspace CLI resource", map[string]any{"success": true})
 }
@@ -134,6 +143,12 @@ func (r *WorkspaceCliResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
+	executionMode, err := requestedExecutionMode(plan.ExecutionMode, r.organizationExecutionMode(plan.OrganizationId.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading organization execution mode", apiErrorDetail(err, fmt.Sprintf("Error reading the execution mode of organization %s: %s", plan.OrganizationId.ValueString(), err)))
+		return
+	}
+
 	bodyRequest := &client.WorkspaceEntity{
 		Name:          plan.Name.ValueString(),
 		Description:   plan.Description.ValueString(),
@@ -141,18 +156,18 @@ func (r *WorkspaceCliResource) Create(ctx context.Context, req resource.CreateRe
 		Branch:        "remote-content",
 		IaCType:       plan.IaCType.ValueString(),
 		IaCVersion:    plan.IaCVersion.ValueString(),
-		ExecutionMode: plan.ExecutionMode.ValueString(),
+		ExecutionMode: executionMode,
 	}
 
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err = jsonapi.MarshalPayload(out, bodyRequest)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
 		return
 	}
 
-	workspaceCliRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	workspaceCliRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
 	workspaceCliRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceCliRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -184,13 +199,32 @@ func (r *WorkspaceCliResource) Create(ctx context.Context, req resource.CreateRe
 
 	plan.ID = types.StringValue(newWorkspaceCli.ID)
 	plan.Name = types.StringValue(newWorkspaceCli.Name)
-	plan.Description = types.StringValue(newWorkspaceCli.Description)
+	plan.Description = newDescriptionValue(newWorkspaceCli.Description)
 	plan.IaCType = types.StringValue(newWorkspaceCli.IaCType)
 	plan.IaCVersion = types.StringValue(newWorkspaceCli.IaCVersion)
-	plan.ExecutionMode = types.StringValue(newWorkspaceCli.ExecutionMode)
+	plan.ExecutionMode, plan.EffectiveExecutionMode = appliedExecutionMode(plan.ExecutionMode, newWorkspaceCli.ExecutionMode)
+	plan.Slug = types.StringValue(workspaceSlug(plan.Name.ValueString(), plan.ID.ValueString()))
 
 	tflog.Info(ctx, "Workspace Cli Resource Created", map[string]any{"success": true})
 
+	if !plan.InitialStateFile.IsNull() {
+		if err := uploadInitialState(ctx, r.client, r.endpoint, r.token, plan.ID.ValueString(), plan.InitialStateFile.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("initial_state_file"), "Error uploading initial state", apiErrorDetail(err, fmt.Sprintf("Error uploading initial state %s: %s", plan.InitialStateFile.ValueString(), err)))
+		}
+	}
+
+	if plan.CliArgs != nil {
+		if err := syncWorkspaceCliArgs(ctx, r.variables, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.CliArgs); err != nil {
+			resp.Diagnostics.AddError("Error setting workspace cli arguments", apiErrorDetail(err, fmt.Sprintf("Error setting workspace cli arguments: %s", err)))
+		}
+	}
+
+	if !plan.ProviderMirrorUrl.IsNull() {
+		if err := syncWorkspaceProviderMirror(ctx, r.variables, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.ProviderMirrorUrl); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("provider_mirror_url"), "Error setting workspace provider mirror", apiErrorDetail(err, fmt.Sprintf("Error setting workspace provider mirror: %s", err)))
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -202,42 +236,61 @@ func (r *WorkspaceCliResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
-	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	workspaceRequest.Header.Add("Content-Type", "application/vnd.api+json")
+	body, validators, notModified, err := client.ConditionalGet(ctx, r.client, r.token, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), readCacheValidators(ctx, req.Private))
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating workspace cli resource request", fmt.Sprintf("Error creating workspace cli resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing workspace cli resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace cli resource request: %s", err)))
 		return
 	}
+	resp.Diagnostics.Append(writeCacheValidators(ctx, resp.Private, validators)...)
+
+	workspaceMode := storedExecutionMode(state.ExecutionMode, state.EffectiveExecutionMode)
+
+	// A workspace that did not change since the last read keeps its state,
+	// only the cli arguments stored as variables are read again.
+	if !notModified {
+		workspace := &client.WorkspaceEntity{}
+		if err := jsonapi.UnmarshalPayload(bytes.NewReader(body), workspace); err != nil {
+			resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+			return
+		}
+
+		state.Name = types.StringValue(workspace.Name)
+		state.Description = newDescriptionValue(workspace.Description)
+		workspaceMode = workspace.ExecutionMode
+		state.IaCType = types.StringValue(workspace.IaCType)
+		state.IaCVersion = types.StringValue(workspace.IaCVersion)
+		state.ID = types.StringValue(workspace.ID)
+	}
 
-	workspaceResponse, err := r.client.Do(workspaceRequest)
+	state.ExecutionMode, state.EffectiveExecutionMode, err = resolveExecutionMode(state.ExecutionMode, workspaceMode, r.organizationExecutionMode(state.OrganizationId.ValueString()))
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace cli resource request", fmt.Sprintf("Error executing workspace cli resource request: %s", err))
+		resp.Diagnostics.AddError("Error reading organization execution mode", apiErrorDetail(err, fmt.Sprintf("Error reading the execution mode of organization %s: %s", state.OrganizationId.ValueString(), err)))
 		return
 	}
 
-	bodyResponse, err := io.ReadAll(workspaceResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading workspace cli resource response")
+	if state.AllowDowngrade.IsNull() {
+		state.AllowDowngrade = types.BoolValue(false)
 	}
-	workspace := &client.WorkspaceEntity{}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspace)
+	state.Slug = types.StringValue(workspaceSlug(state.Name.ValueString(), state.ID.ValueString()))
 
-	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
-		return
+	if state.CliArgs != nil {
+		cliArgs, err := readWorkspaceCliArgs(ctx, r.variables, state.OrganizationId.ValueString(), state.ID.ValueString(), state.CliArgs)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading workspace cli arguments", apiErrorDetail(err, fmt.Sprintf("Error reading workspace cli arguments: %s", err)))
+			return
+		}
+		state.CliArgs = cliArgs
 	}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-
-	state.Name = types.StringValue(workspace.Name)
-	state.Description = types.StringValue(workspace.Description)
-	state.ExecutionMode = types.StringValue(workspace.ExecutionMode)
-	state.IaCType = types.StringValue(workspace.IaCType)
-	state.IaCVersion = types.StringValue(workspace.IaCVersion)
-	state.ID = types.StringValue(workspace.ID)
+	if !state.ProviderMirrorUrl.IsNull() {
+		mirrorUrl, err := readWorkspaceProviderMirror(ctx, r.variables, state.OrganizationId.ValueString(), state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading workspace provider mirror", apiErrorDetail(err, fmt.Sprintf("Error reading workspace provider mirror: %s", err)))
+			return
+		}
+		state.ProviderMirrorUrl = mirrorUrl
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -259,10 +312,19 @@ func (r *WorkspaceCliResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
+	// The validators of the last read do not describe the updated workspace.
+	resp.Diagnostics.Append(clearCacheValidators(ctx, resp.Private)...)
+
+	executionMode, err := requestedExecutionMode(plan.ExecutionMode, r.organizationExecutionMode(plan.OrganizationId.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading organization execution mode", apiErrorDetail(err, fmt.Sprintf("Error reading the execution mode of organization %s: %s", plan.OrganizationId.ValueString(), err)))
+		return
+	}
+
 	bodyRequest := &client.WorkspaceEntity{
 		IaCVersion:    plan.IaCVersion.ValueString(),
 		IaCType:       plan.IaCType.ValueString(),
-		ExecutionMode: plan.ExecutionMode.ValueString(),
+		ExecutionMode: executionMode,
 		Description:   plan.Description.ValueString(),
 		Source:        "empty",
 		Branch:        "remote-content",
@@ -271,7 +333,7 @@ func (r *WorkspaceCliResource) Update(ctx context.Context, req resource.UpdateRe
 	}
 
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err = jsonapi.MarshalPayload(out, bodyRequest)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
@@ -330,10 +392,32 @@ func (r *WorkspaceCliResource) Update(ctx context.Context, req resource.UpdateRe
 
 	plan.ID = types.StringValue(state.ID.ValueString())
 	plan.Name = types.StringValue(workspace.Name)
-	plan.Description = types.StringValue(workspace.Description)
+	plan.Description = newDescriptionValue(workspace.Description)
 	plan.IaCType = types.StringValue(workspace.IaCType)
 	plan.IaCVersion = types.StringValue(workspace.IaCVersion)
-	plan.ExecutionMode = types.StringValue(workspace.ExecutionMode)
+	plan.ExecutionMode, plan.EffectiveExecutionMode = appliedExecutionMode(plan.ExecutionMode, workspace.ExecutionMode)
+	plan.Slug = types.StringValue(workspaceSlug(plan.Name.ValueString(), plan.ID.ValueString()))
+
+	if !plan.InitialStateFile.Equal(state.InitialStateFile) {
+		r.warnings.addAttribute(
+			&resp.Diagnostics,
+			path.Root("initial_state_file"),
+			"Initial state file ignored",
+			"initial_state_file is only uploaded when the workspace is created, the new value has not been uploaded.",
+		)
+	}
+
+	if plan.CliArgs != nil || state.CliArgs != nil {
+		if err := syncWorkspaceCliArgs(ctx, r.variables, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.CliArgs); err != nil {
+			resp.Diagnostics.AddError("Error setting workspace cli arguments", apiErrorDetail(err, fmt.Sprintf("Error setting workspace cli arguments: %s", err)))
+		}
+	}
+
+	if !plan.ProviderMirrorUrl.IsNull() || !state.ProviderMirrorUrl.IsNull() {
+		if err := syncWorkspaceProviderMirror(ctx, r.variables, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.ProviderMirrorUrl); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("provider_mirror_url"), "Error setting workspace provider mirror", apiErrorDetail(err, fmt.Sprintf("Error setting workspace provider mirror: %s", err)))
+		}
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -373,7 +457,7 @@ func (r *WorkspaceCliResource) Delete(ctx context.Context, req resource.DeleteRe
 		Branch:        "remote-content",
 		IaCType:       data.IaCType.ValueString(),
 		IaCVersion:    data.IaCVersion.ValueString(),
-		ExecutionMode: data.ExecutionMode.ValueString(),
+		ExecutionMode: storedExecutionMode(data.ExecutionMode, data.EffectiveExecutionMode),
 		Deleted:       true,
 	}
 
@@ -406,6 +490,12 @@ func (r *WorkspaceCliResource) Delete(ctx context.Context, req resource.DeleteRe
 
 }
 
+func (r *WorkspaceCliResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	warnOnIaCVersionDowngrade(ctx, r.warnings, req, resp)
+	planWorkspaceSlug(ctx, req, resp)
+	planEffectiveExecutionMode(ctx, req, resp)
+}
+
 func (r *WorkspaceCliResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	idParts := strings.Split(req.ID, ",")
 
@@ -419,4 +509,15 @@ func (r *WorkspaceCliResource) ImportState(ctx context.Context, req resource.Imp
 
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	addWorkspaceImportReport(ctx, &resp.Diagnostics, r.client, r.endpoint, r.token, idParts[0], idParts[1])
+}
+
+func (r *WorkspaceCliResource) organizationExecutionMode(organizationId string) func() (string, error) {
+	return func() (string, error) {
+		return organizationExecutionMode(r.organizations, r.client, r.endpoint, r.token, organizationId)
+	}
 }
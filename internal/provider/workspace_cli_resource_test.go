@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// workspaceCliConfig returns a terrakube_workspace_cli configuration named
+// "network" in organization o1, the attributes are added to the required
+// ones.
+func workspaceCliConfig(terrakube *testProvider, attributes map[string]tftypes.Value) tftypes.Value {
+	values := map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+		"name":            tftypes.NewValue(tftypes.String, "network"),
+		"description":     tftypes.NewValue(tftypes.String, "description"),
+		"iac_type":        tftypes.NewValue(tftypes.String, "terraform"),
+		"iac_version":     tftypes.NewValue(tftypes.String, "1.5.7"),
+	}
+	for attributeName, value := range attributes {
+		values[attributeName] = value
+	}
+	return terrakube.object("terrakube_workspace_cli", values)
+}
+
+// workspaceCliState returns the state of the workspace w1 for the
+// configuration, as written after an apply.
+func workspaceCliState(terrakube *testProvider, attributes map[string]tftypes.Value) tftypes.Value {
+	values := map[string]tftypes.Value{
+		"id":                      tftypes.NewValue(tftypes.String, "w1"),
+		"allow_version_downgrade": tftypes.NewValue(tftypes.Bool, false),
+	}
+	for attributeName, value := range attributes {
+		values[attributeName] = value
+	}
+	return workspaceCliConfig(terrakube, values)
+}
@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource                     = &WorkspaceDataSource{}
+	_ datasource.DataSourceWithConfigure        = &WorkspaceDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &WorkspaceDataSource{}
+)
+
+type WorkspaceDataSourceModel struct {
+	ID             types.String                   `tfsdk:"id"`
+	OrganizationId types.String                   `tfsdk:"organization_id"`
+	Name           types.String                   `tfsdk:"name"`
+	Description    types.String                   `tfsdk:"description"`
+	Collections    []WorkspaceCollectionListModel `tfsdk:"collections"`
+	AllowMissing   types.Bool                     `tfsdk:"allow_missing"`
+	Found          types.Bool                     `tfsdk:"found"`
+}
+
+type WorkspaceCollectionListModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Priority    types.Int64  `tfsdk:"priority"`
+	Description types.String `tfsdk:"description"`
+}
+
+type WorkspaceDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewWorkspaceDataSource() datasource.DataSource {
+	return &WorkspaceDataSource{}
+}
+
+func (d *WorkspaceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Workspace Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Workspace datasource")
+}
+
+func (d *WorkspaceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace"
+}
+
+func (d *WorkspaceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Find a workspace by id or name, with the collections attached to it in the order their variables take precedence.",
+		Attributes: map[string]schema.Attribute{
+			"allow_missing": allowMissingSchema(),
+			"found":         foundSchema(),
+			"id":            idOrNameSchema("Workspace Id"),
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+			},
+			"name": idOrNameSchema("Workspace name"),
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "Workspace description",
+			},
+			"collections": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Collections attached to the workspace, sorted by priority descending. When two collections define the same key the first one wins. Empty when the workspace has no collection.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Collection Id",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Collection name",
+						},
+						"priority": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Collection priority",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Collection description",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *WorkspaceDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return idOrNameValidators()
+}
+
+func (d *WorkspaceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state WorkspaceDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organizationUrl := fmt.Sprintf("%s/api/v1/organization/%s", d.endpoint, state.OrganizationId.ValueString())
+
+	body, err := lookupBody(ctx, d.client, d.token, organizationUrl+"/workspace", "filter[workspace]", state.ID, state.Name)
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace datasource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace datasource request: %s", err)))
+		return
+	}
+
+	items, err := unmarshalLookup(body, reflect.TypeOf(new(client.WorkspaceEntity)))
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to unmarshal payload", fmt.Sprintf("Unable to unmarshal payload, response body: %s, error: %s", string(body), err))
+		return
+	}
+
+	var workspace *client.WorkspaceEntity
+	for _, item := range items {
+		if candidate := item.(*client.WorkspaceEntity); !candidate.Deleted {
+			workspace = candidate
+		}
+	}
+
+	state.Found = types.BoolValue(workspace != nil)
+	if workspace == nil {
+		lookupIdOrNameNotFound(&resp.Diagnostics, state.AllowMissing, "workspace", state.ID, state.Name)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	state.ID = types.StringValue(workspace.ID)
+	state.Name = types.StringValue(workspace.Name)
+	state.Description = types.StringValue(workspace.Description)
+
+	// The references only hold the collection id, the collections are listed
+	// once for their name and priority instead of one request per reference.
+	query := url.Values{}
+	query.Set("filter[reference]", fmt.Sprintf("workspace.id=='%s'", workspace.ID))
+	references, err := fetchAllPages(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/reference?%s", d.endpoint, query.Encode()), reflect.TypeOf(new(client.CollectionReferenceEntity)))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading workspace collections", apiErrorDetail(err, fmt.Sprintf("Error listing the collection references of workspace %s: %s", workspace.ID, err)))
+		return
+	}
+
+	collections := map[string]*client.CollectionEntity{}
+	if len(references) > 0 {
+		items, err := fetchAllPages(ctx, d.client, d.token, organizationUrl+"/collection", reflect.TypeOf(new(client.CollectionEntity)))
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading workspace collections", apiErrorDetail(err, fmt.Sprintf("Error listing the collections of the organization: %s", err)))
+			return
+		}
+		for _, item := range items {
+			collection := item.(*client.CollectionEntity)
+			collections[collection.ID] = collection
+		}
+	}
+
+	state.Collections = []WorkspaceCollectionListModel{}
+	for _, item := range references {
+		reference := item.(*client.CollectionReferenceEntity)
+		if reference.Collection == nil {
+			continue
+		}
+		collection, ok := collections[reference.Collection.ID]
+		if !ok {
+			continue
+		}
+		state.Collections = append(state.Collections, WorkspaceCollectionListModel{
+			ID:          types.StringValue(collection.ID),
+			Name:        types.StringValue(collection.Name),
+			Priority:    types.Int64Value(int64(collection.Priority)),
+			Description: types.StringValue(collection.Description),
+		})
+	}
+
+	sort.SliceStable(state.Collections, func(i, j int) bool {
+		a, b := state.Collections[i], state.Collections[j]
+		if a.Priority.ValueInt64() != b.Priority.ValueInt64() {
+			return a.Priority.ValueInt64() > b.Priority.ValueInt64()
+		}
+		return a.Name.ValueString() < b.Name.ValueString()
+	})
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
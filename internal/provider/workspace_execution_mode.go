@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func effectiveExecutionModeSchema() schema.StringAttribute {
+	return schema.StringAttribute{
+		Computed:    true,
+		Description: "Execution mode used by the workspace: `execution_mode` when set, otherwise the execution mode of the organization.",
+	}
+}
+
+// organizationExecutionMode returns the execution mode of the organization.
+// It is cached with the organization name, so a refresh of many workspaces
+// reads each organization once.
+func organizationExecutionMode(organizations *organizationCache, httpClient *http.Client, endpoint string, token string, organizationId string) (string, error) {
+	if executionMode, ok := organizations.executionModeById(organizationId); ok {
+		return executionMode, nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s", endpoint, organizationId), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		body, _ := io.ReadAll(response.Body)
+		return "", fmt.Errorf("status %s: %s", response.Status, string(body))
+	}
+
+	organization := &client.OrganizationEntity{}
+	if err := jsonapi.UnmarshalPayload(response.Body, organization); err != nil {
+		return "", fmt.Errorf("error unmarshal payload response: %w", err)
+	}
+
+	executionMode := organization.ExecutionMode
+	if executionMode == "" {
+		executionMode = "remote"
+	}
+	organizations.putWithExecutionMode(organization.ID, organization.Name, executionMode)
+
+	return executionMode, nil
+}
+
+// resolveExecutionMode returns the execution_mode and effective_execution_mode
+// of a workspace whose stored mode is workspaceMode. A workspace configured
+// without execution_mode keeps it null while it uses the mode of the
+// organization, once they differ the stored mode is reported as a change.
+func resolveExecutionMode(configured types.String, workspaceMode string, organizationMode func() (string, error)) (types.String, types.String, error) {
+	if !configured.IsNull() {
+		return types.StringValue(workspaceMode), types.StringValue(workspaceMode), nil
+	}
+
+	inherited, err := organizationMode()
+	if err != nil {
+		return configured, types.StringNull(), err
+	}
+
+	if workspaceMode == "" {
+		return types.StringNull(), types.StringValue(inherited), nil
+	}
+	if workspaceMode != inherited {
+		return types.StringValue(workspaceMode), types.StringValue(workspaceMode), nil
+	}
+	return types.StringNull(), types.StringValue(workspaceMode), nil
+}
+
+// planEffectiveExecutionMode sets effective_execution_mode in the plan when
+// it is known: the configured execution mode, or the prior value while the
+// workspace keeps inheriting it.
+func planEffectiveExecutionMode(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planned types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("execution_mode"), &planned)...)
+	if resp.Diagnostics.HasError() || planned.IsUnknown() {
+		return
+	}
+
+	if !planned.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_execution_mode"), planned)...)
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var prior, effective types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("execution_mode"), &prior)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("effective_execution_mode"), &effective)...)
+	if resp.Diagnostics.HasError() || !prior.IsNull() || effective.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_execution_mode"), effective)...)
+}
+
+// requestedExecutionMode returns the execution mode sent to the API, the mode
+// of the organization when execution_mode is not configured.
+func requestedExecutionMode(configured types.String, organizationMode func() (string, error)) (string, error) {
+	if !configured.IsNull() {
+		return configured.ValueString(), nil
+	}
+	return organizationMode()
+}
+
+// appliedExecutionMode returns the execution_mode and effective_execution_mode
+// of a created or updated workspace, execution_mode stays null when it is not
+// configured.
+func appliedExecutionMode(configured types.String, workspaceMode string) (types.String, types.String) {
+	if configured.IsNull() {
+		return configured, types.StringValue(workspaceMode)
+	}
+	return types.StringValue(workspaceMode), types.StringValue(workspaceMode)
+}
+
+// storedExecutionMode returns the execution mode of the workspace saved in
+// the state, states written before effective_execution_mode only have
+// execution_mode.
+func storedExecutionMode(raw types.String, effective types.String) string {
+	if effective.IsNull() {
+		return raw.ValueString()
+	}
+	return effective.ValueString()
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestResolveExecutionMode(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name          string
+		configured    types.String
+		workspaceMode string
+		executionMode types.String
+		effective     types.String
+	}{
+		{"configured", types.StringValue("local"), "local", types.StringValue("local"), types.StringValue("local")},
+		{"configured and changed", types.StringValue("local"), "remote", types.StringValue("remote"), types.StringValue("remote")},
+		{"inherited", types.StringNull(), "remote", types.StringNull(), types.StringValue("remote")},
+		{"inherited without mode", types.StringNull(), "", types.StringNull(), types.StringValue("remote")},
+		{"inherited and changed", types.StringNull(), "local", types.StringValue("local"), types.StringValue("local")},
+	} {
+		executionMode, effective, err := resolveExecutionMode(test.configured, test.workspaceMode, func() (string, error) { return "remote", nil })
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+		if !executionMode.Equal(test.executionMode) || !effective.Equal(test.effective) {
+			t.Errorf("%s: expected %s %s, got %s %s", test.name, test.executionMode, test.effective, executionMode, effective)
+		}
+	}
+
+	failed := errors.New("organization not readable")
+	if _, _, err := resolveExecutionMode(types.StringNull(), "remote", func() (string, error) { return "", failed }); err != failed {
+		t.Errorf("expected the organization error, got %v", err)
+	}
+	if _, _, err := resolveExecutionMode(types.StringValue("local"), "local", func() (string, error) { return "", failed }); err != nil {
+		t.Errorf("a configured mode should not read the organization, got %v", err)
+	}
+}
+
+func TestWorkspaceInheritedExecutionMode(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.put("/api/v1/organization/o1", "organization", map[string]any{"name": "simple", "executionMode": "local"})
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	state, diagnostics := terrakube.apply("terrakube_workspace_cli", terrakube.null("terrakube_workspace_cli"), workspaceCliConfig(terrakube, nil))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	workspacePath := "/api/v1/organization/o1/workspace/" + stringAttribute(t, state, "id")
+	if executionMode := api.attributes(workspacePath)["executionMode"]; executionMode != "local" {
+		t.Errorf("the workspace should be created with the organization mode, got %v", executionMode)
+	}
+	if value := attribute(t, state, "execution_mode"); !value.IsNull() {
+		t.Errorf("created execution_mode = %s, expected null", value)
+	}
+	if value := stringAttribute(t, state, "effective_execution_mode"); value != "local" {
+		t.Errorf("created effective_execution_mode = %s, expected local", value)
+	}
+
+	state, diagnostics = terrakube.read("terrakube_workspace_cli", state)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value := attribute(t, state, "execution_mode"); !value.IsNull() {
+		t.Errorf("refreshed execution_mode = %s, expected null", value)
+	}
+	if reads := api.count("GET", "/api/v1/organization/o1") - api.count("GET", "/api/v1/organization/o1/"); reads != 1 {
+		t.Errorf("the organization mode should be cached, it was read %d times", reads)
+	}
+
+	// The workspace no longer follows the organization.
+	api.set(workspacePath, "executionMode", "remote")
+	state, diagnostics = terrakube.read("terrakube_workspace_cli", state)
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value := attribute(t, state, "execution_mode"); !value.Equal(tftypes.NewValue(tftypes.String, "remote")) {
+		t.Errorf("the changed execution_mode should be reported, got %s", value)
+	}
+
+	plan := terrakube.plan("terrakube_workspace_cli", state, workspaceCliConfig(terrakube, nil))
+	if err := diagnosticsError(plan.Diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value := attribute(t, terrakube.value("terrakube_workspace_cli", plan.PlannedState), "execution_mode"); !value.IsNull() {
+		t.Errorf("the plan should return the workspace to the organization mode, got %s", value)
+	}
+}
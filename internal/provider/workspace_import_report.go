@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// addWorkspaceImportReport warns about what else exists on an imported
+// workspace. The import does not fail when the report can not be built.
+func addWorkspaceImportReport(ctx context.Context, diags *diag.Diagnostics, httpClient *http.Client, endpoint string, token string, organizationId string, workspaceId string) {
+	report, err := workspaceImportReport(ctx, httpClient, endpoint, token, organizationId, workspaceId)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to build the workspace import report", map[string]any{"workspace": workspaceId, "error": err.Error()})
+		return
+	}
+	if report == "" {
+		return
+	}
+
+	// Informational only, it is not turned into an error by
+	// warnings_as_errors.
+	diags.AddWarning("Workspace objects not imported", report)
+}
+
+// workspaceImportReport lists the variables, access grants and schedules of
+// an imported workspace with the terraform import command of each one, an
+// empty report when the workspace has none.
+func workspaceImportReport(ctx context.Context, httpClient *http.Client, endpoint string, token string, organizationId string, workspaceId string) (string, error) {
+	workspaceUrl := fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", endpoint, organizationId, workspaceId)
+
+	variables, err := fetchAllPages(ctx, httpClient, token, workspaceUrl+"/variable", reflect.TypeOf(new(client.WorkspaceVariableEntity)))
+	if err != nil {
+		return "", fmt.Errorf("error listing the variables: %w", err)
+	}
+	access, err := fetchAllPages(ctx, httpClient, token, workspaceUrl+"/access", reflect.TypeOf(new(client.WorkspaceAccessEntity)))
+	if err != nil {
+		return "", fmt.Errorf("error listing the access grants: %w", err)
+	}
+	schedules, err := fetchAllPages(ctx, httpClient, token, fmt.Sprintf("%s/api/v1/workspace/%s/schedule", endpoint, workspaceId), reflect.TypeOf(new(client.WorkspaceScheduleEntity)))
+	if err != nil {
+		return "", fmt.Errorf("error listing the schedules: %w", err)
+	}
+
+	if len(variables)+len(access)+len(schedules) == 0 {
+		return "", nil
+	}
+
+	names := importNames{}
+	var commands []string
+	importCommand := func(resourceType string, name string, id string) string {
+		return fmt.Sprintf("terraform import %s.%s %s,%s,%s", resourceType, names.unique(name), organizationId, workspaceId, id)
+	}
+
+	sort.SliceStable(variables, func(i, j int) bool {
+		a, b := variables[i].(*client.WorkspaceVariableEntity), variables[j].(*client.WorkspaceVariableEntity)
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Key < b.Key
+	})
+	for _, item := range variables {
+		variable := item.(*client.WorkspaceVariableEntity)
+		prefix := "tf"
+		if variable.Category == "ENV" {
+			prefix = "env"
+		}
+		commands = append(commands, importCommand("terrakube_workspace_variable", prefix+"_"+variable.Key, variable.ID))
+	}
+
+	sort.SliceStable(access, func(i, j int) bool {
+		return access[i].(*client.WorkspaceAccessEntity).Name < access[j].(*client.WorkspaceAccessEntity).Name
+	})
+	for _, item := range access {
+		grant := item.(*client.WorkspaceAccessEntity)
+		commands = append(commands, importCommand("terrakube_workspace_access", grant.Name, grant.ID))
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].(*client.WorkspaceScheduleEntity).Schedule < schedules[j].(*client.WorkspaceScheduleEntity).Schedule
+	})
+	for _, item := range schedules {
+		schedule := item.(*client.WorkspaceScheduleEntity)
+		commands = append(commands, importCommand("terrakube_workspace_schedule", "schedule", schedule.ID))
+	}
+
+	return fmt.Sprintf("Workspace %s has %s, %s and %s that the import does not manage. Import them with:\n\n%s",
+		workspaceId,
+		pluralize(len(variables), "variable", "variables"),
+		pluralize(len(access), "access grant", "access grants"),
+		pluralize(len(schedules), "schedule", "schedules"),
+		strings.Join(commands, "\n"),
+	), nil
+}
+
+func pluralize(count int, singular string, plural string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+	return fmt.Sprintf("%d %s", count, plural)
+}
+
+// importNames turns keys and team names into resource names, the names
+// taken twice get a numeric suffix.
+type importNames map[string]int
+
+func (n importNames) unique(value string) string {
+	var name strings.Builder
+	for _, c := range strings.ToLower(value) {
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' {
+			name.WriteRune(c)
+		} else {
+			name.WriteRune('_')
+		}
+	}
+
+	result := name.String()
+	if result == "" || result[0] >= '0' && result[0] <= '9' {
+		result = "_" + result
+	}
+
+	n[result]++
+	if n[result] > 1 {
+		return fmt.Sprintf("%s_%d", result, n[result])
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	providerMirrorVariable    = "TF_CLI_CONFIG_FILE"
+	providerMirrorDescription = "Managed by the provider_mirror_url attribute of the Terrakube provider"
+)
+
+var providerMirrorUrlPattern = regexp.MustCompile(`network_mirror\s*\{\s*url\s*=\s*"([^"]*)"`)
+
+func workspaceProviderMirrorSchema() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional: true,
+		Description: "URL of a provider network mirror used for every provider of the workspace, for example `https://mirror.example.com/providers/`. " +
+			"The provider stores the matching CLI configuration as the TF_CLI_CONFIG_FILE environment variable of the workspace and deletes it when the attribute is removed. " +
+			"A TF_CLI_CONFIG_FILE variable created outside of the provider is never changed.",
+		Validators: []validator.String{
+			stringvalidator.RegexMatches(regexp.MustCompile(`^https://[^\s"]+/$`), "must be an https URL ending with a slash, as required by terraform network mirrors"),
+		},
+	}
+}
+
+// providerMirrorCliConfig returns the CLI configuration installing every
+// provider from the mirror.
+func providerMirrorCliConfig(mirrorUrl string) string {
+	return fmt.Sprintf("provider_installation {\n  network_mirror {\n    url = %q\n  }\n}\n", mirrorUrl)
+}
+
+// syncWorkspaceProviderMirror creates, updates or deletes the CLI
+// configuration variable of the workspace so it matches the mirror url. Only
+// the variable created by the provider, recognized by its description, is
+// changed or deleted.
+func syncWorkspaceProviderMirror(ctx context.Context, variables *client.Crud[client.WorkspaceVariableEntity], organizationId string, workspaceId string, mirrorUrl types.String) error {
+	existing, err := variables.List(ctx, organizationId, workspaceId)
+	if err != nil {
+		return err
+	}
+
+	current := findEnvVariable(existing, providerMirrorVariable)
+	if current != nil && current.Description != providerMirrorDescription {
+		if mirrorUrl.IsNull() {
+			return nil
+		}
+		return fmt.Errorf("the workspace already has a %s variable not managed by the provider, delete it to use provider_mirror_url", providerMirrorVariable)
+	}
+
+	switch {
+	case mirrorUrl.IsNull() && current != nil:
+		err = variables.Delete(ctx, current.ID, organizationId, workspaceId)
+	case mirrorUrl.IsNull():
+		return nil
+	case current == nil:
+		_, err = variables.Create(ctx, &client.WorkspaceVariableEntity{
+			Key:         providerMirrorVariable,
+			Value:       providerMirrorCliConfig(mirrorUrl.ValueString()),
+			Description: providerMirrorDescription,
+			Category:    "ENV",
+		}, organizationId, workspaceId)
+	case current.Value != providerMirrorCliConfig(mirrorUrl.ValueString()):
+		current.Value = providerMirrorCliConfig(mirrorUrl.ValueString())
+		err = variables.Update(ctx, current.ID, current, organizationId, workspaceId)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to update workspace variable %s: %w", providerMirrorVariable, err)
+	}
+
+	return nil
+}
+
+// readWorkspaceProviderMirror returns the mirror url of the variable managed
+// by the provider, null when it was deleted or changed outside of Terraform.
+func readWorkspaceProviderMirror(ctx context.Context, variables *client.Crud[client.WorkspaceVariableEntity], organizationId string, workspaceId string) (types.String, error) {
+	existing, err := variables.List(ctx, organizationId, workspaceId)
+	if err != nil {
+		return types.StringNull(), err
+	}
+
+	current := findEnvVariable(existing, providerMirrorVariable)
+	if current == nil || current.Description != providerMirrorDescription {
+		return types.StringNull(), nil
+	}
+
+	match := providerMirrorUrlPattern.FindStringSubmatch(current.Value)
+	if match == nil {
+		return types.StringNull(), nil
+	}
+
+	return types.StringValue(match[1]), nil
+}
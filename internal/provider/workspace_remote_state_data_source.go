@@ -0,0 +1,348 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"reflect"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &WorkspaceRemoteStateDataSource{}
+	_ datasource.DataSourceWithConfigure = &WorkspaceRemoteStateDataSource{}
+)
+
+type WorkspaceRemoteStateDataSourceModel struct {
+	Organization     types.String  `tfsdk:"organization"`
+	Workspace        types.String  `tfsdk:"workspace"`
+	OrganizationId   types.String  `tfsdk:"organization_id"`
+	WorkspaceId      types.String  `tfsdk:"workspace_id"`
+	Outputs          types.Dynamic `tfsdk:"outputs"`
+	SensitiveOutputs types.Dynamic `tfsdk:"sensitive_outputs"`
+}
+
+type WorkspaceRemoteStateDataSource struct {
+	client        *http.Client
+	endpoint      string
+	token         string
+	organizations *organizationCache
+}
+
+// remoteStateOutput is an output of the state file, the type is not used
+// since the value is converted from its JSON representation.
+type remoteStateOutput struct {
+	Value     any  `json:"value"`
+	Sensitive bool `json:"sensitive"`
+}
+
+func NewWorkspaceRemoteStateDataSource() datasource.DataSource {
+	return &WorkspaceRemoteStateDataSource{}
+}
+
+func (d *WorkspaceRemoteStateDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Workspace Remote State Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+	d.organizations = providerData.Organizations
+
+	tflog.Info(ctx, "Creating Workspace Remote State datasource")
+}
+
+func (d *WorkspaceRemoteStateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_remote_state"
+}
+
+func (d *WorkspaceRemoteStateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Read the outputs of the current state of a workspace, found by organization and workspace name. " +
+			"Terraform marks a whole attribute as sensitive, so sensitive outputs are returned apart in `sensitive_outputs`.",
+		Attributes: map[string]schema.Attribute{
+			"organization": schema.StringAttribute{
+				Required:    true,
+				Description: "Organization name",
+			},
+			"workspace": schema.StringAttribute{
+				Required:    true,
+				Description: "Workspace name",
+			},
+			"organization_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Organization Id",
+			},
+			"workspace_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Workspace Id",
+			},
+			"outputs": schema.DynamicAttribute{
+				Computed:    true,
+				Description: "Object with the outputs of the workspace that are not sensitive",
+			},
+			"sensitive_outputs": schema.DynamicAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Object with the sensitive outputs of the workspace",
+			},
+		},
+	}
+}
+
+func (d *WorkspaceRemoteStateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state WorkspaceRemoteStateDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organizationId, err := d.organizationId(ctx, state.Organization.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("organization"), "Error reading organization", apiErrorDetail(err, err.Error()))
+		return
+	}
+
+	workspaceId, err := d.workspaceId(ctx, organizationId, state.Workspace.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("workspace"), "Error reading workspace", apiErrorDetail(err, err.Error()))
+		return
+	}
+
+	outputs, err := d.stateOutputs(ctx, workspaceId)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading workspace state", apiErrorDetail(err, fmt.Sprintf("Error reading the state of workspace %q: %s", state.Workspace.ValueString(), err)))
+		return
+	}
+	if outputs == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("workspace"),
+			"Workspace has no state",
+			fmt.Sprintf("Workspace %q of organization %q has no state yet, its outputs are available after a successful apply.", state.Workspace.ValueString(), state.Organization.ValueString()),
+		)
+		return
+	}
+
+	public := map[string]any{}
+	sensitive := map[string]any{}
+	for name, output := range outputs {
+		if output.Sensitive {
+			sensitive[name] = output.Value
+		} else {
+			public[name] = output.Value
+		}
+	}
+
+	publicValue, err := jsonAttrValue(public)
+	if err != nil {
+		resp.Diagnostics.AddError("Error converting workspace outputs", err.Error())
+		return
+	}
+	sensitiveValue, err := jsonAttrValue(sensitive)
+	if err != nil {
+		resp.Diagnostics.AddError("Error converting workspace outputs", err.Error())
+		return
+	}
+
+	state.OrganizationId = types.StringValue(organizationId)
+	state.WorkspaceId = types.StringValue(workspaceId)
+	state.Outputs = types.DynamicValue(publicValue)
+	state.SensitiveOutputs = types.DynamicValue(sensitiveValue)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *WorkspaceRemoteStateDataSource) organizationId(ctx context.Context, name string) (string, error) {
+	if id, ok := d.organizations.idByName(name); ok {
+		return id, nil
+	}
+
+	query := url.Values{}
+	query.Set("filter[organization]", "name=="+rsqlString(name))
+	organizations, err := fetchAllPages(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/organization?%s", d.endpoint, query.Encode()), reflect.TypeOf(new(client.OrganizationEntity)))
+	if err != nil {
+		return "", err
+	}
+
+	for _, item := range organizations {
+		organization := item.(*client.OrganizationEntity)
+		if organization.Name == name {
+			d.organizations.put(organization.ID, organization.Name)
+			return organization.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("no organization named %q was found", name)
+}
+
+func (d *WorkspaceRemoteStateDataSource) workspaceId(ctx context.Context, organizationId string, name string) (string, error) {
+	query := url.Values{}
+	query.Set("filter[workspace]", "name=="+rsqlString(name))
+	workspaces, err := fetchAllPages(ctx, d.client, d.token, fmt.Sprintf("%s/api/v1/organization/%s/workspace?%s", d.endpoint, organizationId, query.Encode()), reflect.TypeOf(new(client.WorkspaceEntity)))
+	if err != nil {
+		return "", err
+	}
+
+	for _, item := range workspaces {
+		workspace := item.(*client.WorkspaceEntity)
+		if workspace.Name == name && !workspace.Deleted {
+			return workspace.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("no workspace named %q was found", name)
+}
+
+// stateOutputs downloads the current state of the workspace through the
+// remote backend API and returns its outputs, nil when there is no state.
+func (d *WorkspaceRemoteStateDataSource) stateOutputs(ctx context.Context, workspaceId string) (map[string]remoteStateOutput, error) {
+	body, status, err := d.get(fmt.Sprintf("%s/remote/tfe/v2/workspaces/%s/current-state-version", d.endpoint, workspaceId))
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+
+	var stateVersion struct {
+		Data struct {
+			Attributes struct {
+				DownloadUrl string `json:"hosted-state-download-url"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &stateVersion); err != nil {
+		return nil, fmt.Errorf("error unmarshal state version: %w", err)
+	}
+	if stateVersion.Data.Attributes.DownloadUrl == "" {
+		return nil, nil
+	}
+
+	tflog.Debug(ctx, "Downloading workspace state", map[string]any{"workspaceId": workspaceId})
+
+	body, status, err = d.get(stateVersion.Data.Attributes.DownloadUrl)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+
+	var stateFile struct {
+		Outputs map[string]remoteStateOutput `json:"outputs"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&stateFile); err != nil {
+		return nil, fmt.Errorf("error reading state file: %w", err)
+	}
+	if stateFile.Outputs == nil {
+		stateFile.Outputs = map[string]remoteStateOutput{}
+	}
+
+	return stateFile.Outputs, nil
+}
+
+// get returns the body of a successful or not found response, any other
+// status is an error.
+func (d *WorkspaceRemoteStateDataSource) get(requestUrl string) ([]byte, int, error) {
+	request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if response.StatusCode >= 300 && response.StatusCode != http.StatusNotFound {
+		return nil, response.StatusCode, fmt.Errorf("status %s: %s", response.Status, string(body))
+	}
+
+	return body, response.StatusCode, nil
+}
+
+// jsonAttrValue converts a value decoded with json.Decoder.UseNumber, lists
+// become tuples and maps objects so every element keeps its own type.
+func jsonAttrValue(value any) (attr.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return types.StringNull(), nil
+	case string:
+		return types.StringValue(v), nil
+	case bool:
+		return types.BoolValue(v), nil
+	case json.Number:
+		number, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s: %w", v, err)
+		}
+		return types.NumberValue(number), nil
+	case []any:
+		elementTypes := make([]attr.Type, 0, len(v))
+		elements := make([]attr.Value, 0, len(v))
+		for _, item := range v {
+			element, err := jsonAttrValue(item)
+			if err != nil {
+				return nil, err
+			}
+			elementTypes = append(elementTypes, element.Type(context.Background()))
+			elements = append(elements, element)
+		}
+		tuple, diags := types.TupleValue(elementTypes, elements)
+		if diags.HasError() {
+			return nil, fmt.Errorf("unable to convert list: %v", diags)
+		}
+		return tuple, nil
+	case map[string]any:
+		attributeTypes := make(map[string]attr.Type, len(v))
+		attributes := make(map[string]attr.Value, len(v))
+		for key, item := range v {
+			attribute, err := jsonAttrValue(item)
+			if err != nil {
+				return nil, err
+			}
+			attributeTypes[key] = attribute.Type(context.Background())
+			attributes[key] = attribute
+		}
+		object, diags := types.ObjectValue(attributeTypes, attributes)
+		if diags.HasError() {
+			return nil, fmt.Errorf("unable to convert object: %v", diags)
+		}
+		return object, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
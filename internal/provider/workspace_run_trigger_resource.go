@@ -0,0 +1,414 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &WorkspaceRunTriggerResource{}
+var _ resource.ResourceWithImportState = &WorkspaceRunTriggerResource{}
+
+// Terrakube has no run trigger object, the trigger is a template whose last
+// step queues the downstream job with a token stored in the source workspace.
+// The description of the template records the downstream job so the trigger
+// can be imported.
+const (
+	runTriggerDescription = "Queue template %s in workspace %s after a successful apply"
+	runTriggerTokenPrefix = "TERRAKUBE_RUN_TRIGGER_TOKEN_"
+)
+
+const runTriggerTemplate = `flow:
+  - type: "terraformPlan"
+    name: "Plan"
+    step: 100
+  - type: "terraformApply"
+    name: "Apply"
+    step: 200
+  - type: "customScripts"
+    name: "Queue downstream run"
+    step: 300
+    commands:
+      - runtime: "BASH"
+        priority: 100
+        before: true
+        script: |
+          curl --fail --silent --show-error -X POST "%s" \
+            -H "Authorization: Bearer $%s" \
+            -H "Content-Type: application/vnd.api+json" \
+            --data '%s'
+`
+
+type WorkspaceRunTriggerResource struct {
+	client     *http.Client
+	endpoint   string
+	token      string
+	workspaces *client.Crud[client.WorkspaceEntity]
+	templates  *client.Crud[client.OrganizationTemplateEntity]
+	variables  *client.Crud[client.WorkspaceVariableEntity]
+}
+
+type WorkspaceRunTriggerResourceModel struct {
+	ID                    types.String `tfsdk:"id"`
+	OrganizationId        types.String `tfsdk:"organization_id"`
+	WorkspaceId           types.String `tfsdk:"workspace_id"`
+	DownstreamWorkspaceId types.String `tfsdk:"downstream_workspace_id"`
+	DownstreamTemplateId  types.String `tfsdk:"downstream_template_id"`
+	Token                 types.String `tfsdk:"token"`
+	ApiUrl                types.String `tfsdk:"api_url"`
+	TemplateId            types.String `tfsdk:"template_id"`
+	VariableId            types.String `tfsdk:"variable_id"`
+	VariableKey           types.String `tfsdk:"variable_key"`
+}
+
+func NewWorkspaceRunTriggerResource() resource.Resource {
+	return &WorkspaceRunTriggerResource{}
+}
+
+func (r *WorkspaceRunTriggerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_run_trigger"
+}
+
+func (r *WorkspaceRunTriggerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Queue a run in a downstream workspace after a successful apply in a source workspace. " +
+			"Terrakube has no run trigger object, so the resource creates a template running plan and apply followed by a step queueing the downstream job, " +
+			"and a sensitive environment variable in the source workspace holding the token used by that step. " +
+			"Runs of the source workspace queue the downstream run when they use `template_id`, for example from a `terrakube_workspace_webhook` or a `terrakube_workspace_schedule`. " +
+			"The executor image must provide curl.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Run trigger Id, the id of the template",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Id of the source workspace",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"downstream_workspace_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Id of the workspace where a run is queued",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"downstream_template_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Id of the template run in the downstream workspace",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"token": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				Description: "Terrakube token allowed to manage the jobs of the downstream workspace, for example from a `terrakube_team_token`",
+			},
+			"api_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "Terrakube API url reachable from the executor, default is the endpoint of the provider",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"template_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Id of the template created for the trigger, run it in the source workspace to queue the downstream run",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"variable_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Id of the sensitive variable created in the source workspace",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"variable_key": schema.StringAttribute{
+				Computed:    true,
+				Description: "Key of the sensitive variable created in the source workspace",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *WorkspaceRunTriggerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Workspace Run Trigger Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+	r.workspaces = client.NewCrud[client.WorkspaceEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/workspace")
+	r.templates = client.NewCrud[client.OrganizationTemplateEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/template")
+	r.variables = client.NewCrud[client.WorkspaceVariableEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/workspace/%s/variable")
+
+	tflog.Debug(ctx, "Configuring Workspace Run Trigger resource", map[string]any{"success": true})
+}
+
+func (r *WorkspaceRunTriggerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan WorkspaceRunTriggerResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	orgId := plan.OrganizationId.ValueString()
+	variableKey := runTriggerVariableKey(plan.DownstreamWorkspaceId.ValueString())
+
+	content, err := r.templateContent(plan, variableKey)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating run trigger template", apiErrorDetail(err, fmt.Sprintf("Error creating run trigger template: %s", err)))
+		return
+	}
+
+	template, err := r.templates.Create(ctx, &client.OrganizationTemplateEntity{
+		Name:        fmt.Sprintf("Run trigger %s to %s", plan.WorkspaceId.ValueString(), plan.DownstreamWorkspaceId.ValueString()),
+		Description: fmt.Sprintf(runTriggerDescription, plan.DownstreamTemplateId.ValueString(), plan.DownstreamWorkspaceId.ValueString()),
+		Version:     "1.0.0",
+		Content:     base64.StdEncoding.EncodeToString([]byte(content)),
+	}, orgId)
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace run trigger resource request", apiErrorDetail(err, fmt.Sprintf("Error creating run trigger template: %s", err)))
+		return
+	}
+
+	variable, err := r.variables.Create(ctx, &client.WorkspaceVariableEntity{
+		Key:         variableKey,
+		Value:       plan.Token.ValueString(),
+		Description: fmt.Sprintf("Token used by template %s to queue runs in workspace %s", template.ID, plan.DownstreamWorkspaceId.ValueString()),
+		Category:    "ENV",
+		Sensitive:   true,
+	}, orgId, plan.WorkspaceId.ValueString())
+	if err != nil {
+		// The template is useless without its token.
+		if deleteErr := r.templates.Delete(ctx, template.ID, orgId); deleteErr != nil {
+			tflog.Warn(ctx, "Error deleting run trigger template", map[string]any{"template": template.ID, "error": deleteErr.Error()})
+		}
+		resp.Diagnostics.AddError("Error executing workspace run trigger resource request", apiErrorDetail(err, fmt.Sprintf("Error creating run trigger token variable: %s", err)))
+		return
+	}
+
+	plan.ID = types.StringValue(template.ID)
+	plan.TemplateId = types.StringValue(template.ID)
+	plan.VariableId = types.StringValue(variable.ID)
+	plan.VariableKey = types.StringValue(variableKey)
+
+	tflog.Info(ctx, "Workspace Run Trigger Resource Created", map[string]any{"success": true})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *WorkspaceRunTriggerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state WorkspaceRunTriggerResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	orgId := state.OrganizationId.ValueString()
+
+	template, err := r.templates.Get(ctx, state.ID.ValueString(), orgId)
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace run trigger resource request", apiErrorDetail(err, fmt.Sprintf("Error reading run trigger template: %s", err)))
+		return
+	}
+
+	var downstreamTemplateId, downstreamWorkspaceId string
+	if _, err := fmt.Sscanf(template.Description, runTriggerDescription, &downstreamTemplateId, &downstreamWorkspaceId); err != nil {
+		resp.Diagnostics.AddError("Unexpected run trigger template", fmt.Sprintf("Template %s is not a run trigger, its description is %q.", template.ID, template.Description))
+		return
+	}
+
+	// The trigger is gone with either workspace.
+	for _, workspaceId := range []string{state.WorkspaceId.ValueString(), downstreamWorkspaceId} {
+		workspace, err := r.workspaces.Get(ctx, workspaceId, orgId)
+		if (errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound) || (err == nil && workspace.Deleted) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Error executing workspace run trigger resource request", apiErrorDetail(err, fmt.Sprintf("Error reading workspace %s: %s", workspaceId, err)))
+			return
+		}
+	}
+
+	variableKey := runTriggerVariableKey(downstreamWorkspaceId)
+	variables, err := r.variables.List(ctx, orgId, state.WorkspaceId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace run trigger resource request", apiErrorDetail(err, fmt.Sprintf("Error reading the variables of workspace %s: %s", state.WorkspaceId.ValueString(), err)))
+		return
+	}
+
+	state.VariableId = types.StringNull()
+	for _, variable := range variables {
+		if variable.Key == variableKey {
+			state.VariableId = types.StringValue(variable.ID)
+		}
+	}
+	// Without its token the trigger no longer works, it is created again.
+	if state.VariableId.IsNull() {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.TemplateId = types.StringValue(template.ID)
+	state.DownstreamTemplateId = types.StringValue(downstreamTemplateId)
+	state.DownstreamWorkspaceId = types.StringValue(downstreamWorkspaceId)
+	state.VariableKey = types.StringValue(variableKey)
+
+	// Set refreshed state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Workspace Run Trigger Resource reading", map[string]any{"success": true})
+}
+
+func (r *WorkspaceRunTriggerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only the token changes in place, every other attribute forces a new
+	// trigger.
+	var plan WorkspaceRunTriggerResourceModel
+	var state WorkspaceRunTriggerResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Token.Equal(state.Token) {
+		err := r.variables.UpdateWithout(ctx, state.VariableId.ValueString(), &client.WorkspaceVariableEntity{
+			ID:    state.VariableId.ValueString(),
+			Key:   state.VariableKey.ValueString(),
+			Value: plan.Token.ValueString(),
+		}, []string{"description", "category", "sensitive", "hcl"}, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error executing workspace run trigger resource request", apiErrorDetail(err, fmt.Sprintf("Error updating run trigger token variable: %s", err)))
+			return
+		}
+	}
+
+	plan.ID = state.ID
+	plan.TemplateId = state.TemplateId
+	plan.VariableId = state.VariableId
+	plan.VariableKey = state.VariableKey
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *WorkspaceRunTriggerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data WorkspaceRunTriggerResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.variables.Delete(ctx, data.VariableId.ValueString(), data.OrganizationId.ValueString(), data.WorkspaceId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace run trigger resource request", apiErrorDetail(err, fmt.Sprintf("Error deleting run trigger token variable: %s", err)))
+		return
+	}
+
+	err = r.templates.Delete(ctx, data.TemplateId.ValueString(), data.OrganizationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace run trigger resource request", apiErrorDetail(err, fmt.Sprintf("Error deleting run trigger template: %s", err)))
+		return
+	}
+}
+
+func (r *WorkspaceRunTriggerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,workspace_ID,ID', Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[2])...)
+}
+
+// templateContent returns the flow of the trigger template, the last step
+// posts the downstream job with the token of the variable.
+func (r *WorkspaceRunTriggerResource) templateContent(plan WorkspaceRunTriggerResourceModel, variableKey string) (string, error) {
+	apiUrl := r.endpoint
+	if !plan.ApiUrl.IsNull() {
+		apiUrl = plan.ApiUrl.ValueString()
+	}
+
+	var job bytes.Buffer
+	err := jsonapi.MarshalPayloadWithoutIncluded(&job, &client.JobEntity{
+		TemplateReference: plan.DownstreamTemplateId.ValueString(),
+		Workspace:         &client.WorkspaceEntity{ID: plan.DownstreamWorkspaceId.ValueString()},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	jobUrl := fmt.Sprintf("%s/api/v1/organization/%s/job", strings.TrimSuffix(apiUrl, "/"), plan.OrganizationId.ValueString())
+	return fmt.Sprintf(runTriggerTemplate, jobUrl, variableKey, strings.TrimSpace(job.String())), nil
+}
+
+// runTriggerVariableKey returns the key of the token variable, one per
+// downstream workspace so a workspace can trigger several others.
+func runTriggerVariableKey(downstreamWorkspaceId string) string {
+	return runTriggerTokenPrefix + strings.ToUpper(strings.ReplaceAll(downstreamWorkspaceId, "-", "_"))
+}
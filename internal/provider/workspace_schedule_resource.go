@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +10,8 @@ import (
 	"terraform-provider-terrakube/internal/client"
 
 	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 
@@ -29,6 +30,7 @@ type WorkspaceScheduleResource struct {
 	client   *http.Client
 	endpoint string
 	token    string
+	warnings *warningPolicy
 }
 
 type WorkspaceScheduleResourceModel struct {
@@ -36,6 +38,8 @@ type WorkspaceScheduleResourceModel struct {
 	WorkspaceId types.String `tfsdk:"workspace_id"`
 	TemplateId  types.String `tfsdk:"template_id"`
 	Schedule    types.String `tfsdk:"schedule"`
+
+	CheckDuplicates types.Bool `tfsdk:"check_duplicates"`
 }
 
 func NewWorkspaceScheduleResource() resource.Resource {
@@ -70,6 +74,12 @@ func (r *WorkspaceScheduleResource) Schema(ctx context.Context, req resource.Sch
 				Required:    true,
 				Description: "Workspace Id",
 			},
+			"check_duplicates": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Warn on create when the workspace already has a schedule with the same expression, since the API accepts identical schedules. Default is `false`.",
+			},
 		},
 	}
 }
@@ -89,20 +99,11 @@ func (r *WorkspaceScheduleResource) Configure(ctx context.Context, req resource.
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.warnings = providerData.Warnings
 
 	tflog.Debug(ctx, "Configuring Workspace Schedule resource", map[string]any{"success": true})
 }
@@ -116,6 +117,10 @@ func (r *WorkspaceScheduleResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
+	if plan.CheckDuplicates.ValueBool() {
+		r.warnDuplicateSchedule(ctx, plan, &resp.Diagnostics)
+	}
+
 	bodyRequest := &client.WorkspaceScheduleEntity{
 		Schedule:   plan.Schedule.ValueString(),
 		TemplateId: plan.TemplateId.ValueString(),
@@ -129,7 +134,7 @@ func (r *WorkspaceScheduleResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
-	workspaceScheduleRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/workspace/%s/schedule", r.endpoint, plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
+	workspaceScheduleRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/workspace/%s/schedule", r.endpoint, plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
 	workspaceScheduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceScheduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -154,7 +159,7 @@ func (r *WorkspaceScheduleResource) Create(ctx context.Context, req resource.Cre
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspaceSchedule)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(workspaceScheduleResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, workspaceScheduleResponse.Status)))
 		return
 	}
 
@@ -201,7 +206,7 @@ func (r *WorkspaceScheduleResource) Read(ctx context.Context, req resource.ReadR
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspaceSchedule)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(workspaceScheduleResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, workspaceScheduleResponse.Status)))
 		return
 	}
 
@@ -211,6 +216,10 @@ func (r *WorkspaceScheduleResource) Read(ctx context.Context, req resource.ReadR
 	state.TemplateId = types.StringValue(workspaceSchedule.TemplateId)
 	state.ID = types.StringValue(workspaceSchedule.ID)
 
+	if state.CheckDuplicates.IsNull() {
+		state.CheckDuplicates = types.BoolValue(false)
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -282,7 +291,7 @@ func (r *WorkspaceScheduleResource) Update(ctx context.Context, req resource.Upd
 
 	bodyResponse, err = io.ReadAll(workspaceScheduleResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading Workspace schedule resource response body", fmt.Sprintf("Error reading Workspace schedule resource response body: %s", err))
+		resp.Diagnostics.AddError("Error reading Workspace schedule resource response body", responseErrorDetail(workspaceScheduleResponse, fmt.Sprintf("Error reading Workspace schedule resource response body, error: %s, response status: %s", err, workspaceScheduleResponse.Status)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -291,7 +300,7 @@ func (r *WorkspaceScheduleResource) Update(ctx context.Context, req resource.Upd
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspaceSchedule)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(workspaceScheduleResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, workspaceScheduleResponse.Status)))
 		return
 	}
 
@@ -312,18 +321,25 @@ func (r *WorkspaceScheduleResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/workspace/%s/schedule/%s", r.endpoint, data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
+	workspaceRequest, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/workspace/%s/schedule/%s", r.endpoint, data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
 	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating Workspace schedule resource request", fmt.Sprintf("Error creating schedule schedule resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(workspaceRequest)
+	deleteResponse, err := r.client.Do(workspaceRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing Workspace schedule resource request", fmt.Sprintf("Error executing Workspace schedule resource request: %s", err))
 		return
 	}
+	defer deleteResponse.Body.Close()
+
+	if deleteResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(deleteResponse.Body)
+		resp.Diagnostics.AddError("Error executing Workspace schedule resource request", responseErrorDetail(deleteResponse, fmt.Sprintf("Error executing Workspace schedule resource request, response status: %s, response body: %s", deleteResponse.Status, bodyResponse)))
+		return
+	}
 }
 
 func (r *WorkspaceScheduleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -341,3 +357,24 @@ func (r *WorkspaceScheduleResource) ImportState(ctx context.Context, req resourc
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), idParts[1])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[2])...)
 }
+
+// warnDuplicateSchedule warns when the workspace already runs a schedule with
+// the same expression.
+func (r *WorkspaceScheduleResource) warnDuplicateSchedule(ctx context.Context, plan WorkspaceScheduleResourceModel, diags *diag.Diagnostics) {
+	schedules, err := listWorkspaceSchedules(ctx, r.client, r.endpoint, r.token, plan.WorkspaceId.ValueString())
+	if err != nil {
+		r.warnings.add(diags, "Unable to check duplicate schedules", fmt.Sprintf("Unable to list the workspace schedules: %s", err))
+		return
+	}
+
+	for _, schedule := range schedules {
+		if strings.TrimSpace(schedule.Schedule) == strings.TrimSpace(plan.Schedule.ValueString()) {
+			r.warnings.addAttribute(
+				diags,
+				path.Root("schedule"),
+				"Duplicate workspace schedule",
+				fmt.Sprintf("The workspace already has schedule %s with expression %q and template %s, the new schedule triggers the same runs again.", schedule.ID, schedule.Schedule, schedule.TemplateId),
+			)
+		}
+	}
+}
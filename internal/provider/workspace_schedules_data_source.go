@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &WorkspaceSchedulesDataSource{}
+	_ datasource.DataSourceWithConfigure = &WorkspaceSchedulesDataSource{}
+)
+
+type WorkspaceSchedulesDataSourceModel struct {
+	WorkspaceId types.String                     `tfsdk:"workspace_id"`
+	Schedules   []WorkspaceScheduleListItemModel `tfsdk:"schedules"`
+}
+
+type WorkspaceScheduleListItemModel struct {
+	ID         types.String `tfsdk:"id"`
+	Cron       types.String `tfsdk:"cron"`
+	TemplateId types.String `tfsdk:"template_id"`
+	Enabled    types.Bool   `tfsdk:"enabled"`
+}
+
+type WorkspaceSchedulesDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewWorkspaceSchedulesDataSource() datasource.DataSource {
+	return &WorkspaceSchedulesDataSource{}
+}
+
+func (d *WorkspaceSchedulesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Workspace Schedules Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.HttpClient
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	tflog.Info(ctx, "Creating Workspace Schedules datasource")
+}
+
+func (d *WorkspaceSchedulesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_schedules"
+}
+
+func (d *WorkspaceSchedulesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the schedules of a workspace sorted by cron expression, for example to check whether a schedule already exists before adding one.",
+		Attributes: map[string]schema.Attribute{
+			"workspace_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Workspace Id",
+			},
+			"schedules": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Schedules of the workspace",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Schedule Id",
+						},
+						"cron": schema.StringAttribute{
+							Computed:    true,
+							Description: "Schedule expression using java quartz notation",
+						},
+						"template_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Template Id used when triggering a job",
+						},
+						"enabled": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the schedule is enabled, null when the Terrakube API does not report it",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *WorkspaceSchedulesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state WorkspaceSchedulesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schedules, err := listWorkspaceSchedules(ctx, d.client, d.endpoint, d.token, state.WorkspaceId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading workspace schedules", apiErrorDetail(err, fmt.Sprintf("Error reading workspace schedules: %s", err)))
+		return
+	}
+
+	state.Schedules = make([]WorkspaceScheduleListItemModel, 0, len(schedules))
+	for _, schedule := range schedules {
+		state.Schedules = append(state.Schedules, WorkspaceScheduleListItemModel{
+			ID:         types.StringValue(schedule.ID),
+			Cron:       types.StringValue(schedule.Schedule),
+			TemplateId: types.StringValue(schedule.TemplateId),
+			Enabled:    types.BoolPointerValue(schedule.Enabled),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// listWorkspaceSchedules returns every schedule of the workspace sorted by
+// cron expression and id.
+func listWorkspaceSchedules(ctx context.Context, httpClient *http.Client, endpoint string, token string, workspaceId string) ([]*client.WorkspaceScheduleEntity, error) {
+	items, err := fetchAllPages(ctx, httpClient, token, fmt.Sprintf("%s/api/v1/workspace/%s/schedule", endpoint, workspaceId), reflect.TypeOf(new(client.WorkspaceScheduleEntity)))
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*client.WorkspaceScheduleEntity, 0, len(items))
+	for _, item := range items {
+		schedules = append(schedules, item.(*client.WorkspaceScheduleEntity))
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool {
+		if schedules[i].Schedule != schedules[j].Schedule {
+			return schedules[i].Schedule < schedules[j].Schedule
+		}
+		return schedules[i].ID < schedules[j].ID
+	})
+
+	return schedules, nil
+}
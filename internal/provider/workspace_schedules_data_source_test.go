@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestWorkspaceSchedulesDataSource(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.put("/api/v1/workspace/w1/schedule/s3", "schedule", map[string]any{"cron": "0 0 1 * * ?", "templateReference": "tp2", "enabled": false})
+	api.put("/api/v1/workspace/w1/schedule/s1", "schedule", map[string]any{"cron": "0 30 2 * * ?", "templateReference": "tp1"})
+	api.put("/api/v1/workspace/w1/schedule/s2", "schedule", map[string]any{"cron": "0 0 1 * * ?", "templateReference": "tp1", "enabled": true})
+	api.put("/api/v1/workspace/w2/schedule/s4", "schedule", map[string]any{"cron": "0 0 3 * * ?", "templateReference": "tp1"})
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	state, diagnostics := terrakube.readDataSource("terrakube_workspace_schedules", map[string]tftypes.Value{
+		"workspace_id": tftypes.NewValue(tftypes.String, "w1"),
+	})
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var schedules []tftypes.Value
+	if err := attribute(t, state, "schedules").As(&schedules); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []struct {
+		id       string
+		cron     string
+		template string
+		enabled  tftypes.Value
+	}{
+		{"s2", "0 0 1 * * ?", "tp1", tftypes.NewValue(tftypes.Bool, true)},
+		{"s3", "0 0 1 * * ?", "tp2", tftypes.NewValue(tftypes.Bool, false)},
+		{"s1", "0 30 2 * * ?", "tp1", tftypes.NewValue(tftypes.Bool, nil)},
+	}
+	if len(schedules) != len(expected) {
+		t.Fatalf("expected %d schedules, got %s", len(expected), state)
+	}
+	for i, schedule := range expected {
+		if id, cron, template := stringAttribute(t, schedules[i], "id"), stringAttribute(t, schedules[i], "cron"), stringAttribute(t, schedules[i], "template_id"); id != schedule.id || cron != schedule.cron || template != schedule.template {
+			t.Errorf("schedule %d: expected %s %q %s, got %s %q %s", i, schedule.id, schedule.cron, schedule.template, id, cron, template)
+		}
+		if enabled := attribute(t, schedules[i], "enabled"); !enabled.Equal(schedule.enabled) {
+			t.Errorf("schedule %d: expected enabled %s, got %s", i, schedule.enabled, enabled)
+		}
+	}
+
+	state, diagnostics = terrakube.readDataSource("terrakube_workspace_schedules", map[string]tftypes.Value{
+		"workspace_id": tftypes.NewValue(tftypes.String, "w3"),
+	})
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := attribute(t, state, "schedules").As(&schedules); err != nil || schedules == nil || len(schedules) != 0 {
+		t.Errorf("a workspace without schedules should have an empty list, got %s", state)
+	}
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	dnsLabelMaxLength = 63
+	slugHashLength    = 8
+)
+
+func workspaceSlugSchema() schema.StringAttribute {
+	return schema.StringAttribute{
+		Computed:    true,
+		Description: "Short name of the workspace usable in tags and DNS labels: the name in lower case with every character other than ASCII letters and digits replaced by dashes, followed by a hash of the workspace id. Computed by the provider, it only changes when the workspace is renamed.",
+	}
+}
+
+// workspaceSlug returns a DNS label for the workspace. The hash of the id
+// keeps the slugs of workspaces with the same name, in the same or other
+// organizations, apart.
+func workspaceSlug(name string, id string) string {
+	var builder strings.Builder
+	dash := false
+	for _, char := range strings.ToLower(name) {
+		if (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') {
+			builder.WriteRune(char)
+			dash = false
+		} else if !dash && builder.Len() > 0 {
+			builder.WriteByte('-')
+			dash = true
+		}
+	}
+
+	prefix := strings.TrimRight(builder.String(), "-")
+	if len(prefix) > dnsLabelMaxLength-slugHashLength-1 {
+		prefix = strings.TrimRight(prefix[:dnsLabelMaxLength-slugHashLength-1], "-")
+	}
+	if prefix == "" {
+		prefix = "workspace"
+	}
+
+	hash := sha256.Sum256([]byte(id))
+	return prefix + "-" + hex.EncodeToString(hash[:])[:slugHashLength]
+}
+
+// planWorkspaceSlug sets the slug of an existing workspace in the plan, so a
+// rename shows the new slug instead of an unknown value.
+func planWorkspaceSlug(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var id, name types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	if resp.Diagnostics.HasError() || name.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("slug"), workspaceSlug(name.ValueString(), id.ValueString()))...)
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+func TestWorkspaceSlug(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		prefix string
+	}{
+		{"network", "network"},
+		{"Platform Network (prod)", "platform-network-prod"},
+		{"--edge__case--", "edge-case"},
+		{"Ünïcödé Wörkspace", "n-c-d-w-rkspace"},
+		{"ステージング", "workspace"},
+		{"", "workspace"},
+		{strings.Repeat("a", 100), strings.Repeat("a", 54)},
+		{strings.Repeat("a", 53) + " tail", strings.Repeat("a", 53)},
+		{strings.Repeat("é", 40) + "network", "network"},
+	} {
+		slug := workspaceSlug(test.name, "w1")
+		if !strings.HasPrefix(slug, test.prefix+"-") || len(slug) != len(test.prefix)+1+slugHashLength {
+			t.Errorf("workspaceSlug(%q) = %s, expected the prefix %s", test.name, slug, test.prefix)
+		}
+		if len(slug) > dnsLabelMaxLength || !dnsLabel.MatchString(slug) {
+			t.Errorf("workspaceSlug(%q) = %s is not a DNS label", test.name, slug)
+		}
+	}
+
+	if workspaceSlug("network", "w1") != workspaceSlug("network", "w1") {
+		t.Errorf("the slug should be stable")
+	}
+	if workspaceSlug("network", "w1") == workspaceSlug("network", "w2") {
+		t.Errorf("workspaces with the same name should get different slugs")
+	}
+}
+
+func TestWorkspaceSlugPlan(t *testing.T) {
+	t.Parallel()
+
+	_, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+	state := workspaceCliState(terrakube, map[string]tftypes.Value{"slug": tftypes.NewValue(tftypes.String, workspaceSlug("network", "w1"))})
+
+	plan := terrakube.plan("terrakube_workspace_cli", state, workspaceCliConfig(terrakube, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "Réseau Production"),
+	}))
+	if err := diagnosticsError(plan.Diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	planned := terrakube.value("terrakube_workspace_cli", plan.PlannedState)
+	if slug := stringAttribute(t, planned, "slug"); slug != workspaceSlug("Réseau Production", "w1") || !strings.HasPrefix(slug, "r-seau-production-") {
+		t.Errorf("a rename should plan the new slug, got %s", slug)
+	}
+}
@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &WorkspaceSshKeyResource{}
+var _ resource.ResourceWithImportState = &WorkspaceSshKeyResource{}
+
+type WorkspaceSshKeyResource struct {
+	client     *http.Client
+	endpoint   string
+	token      string
+	workspaces *client.Crud[client.WorkspaceEntity]
+}
+
+type WorkspaceSshKeyResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	OrganizationId types.String `tfsdk:"organization_id"`
+	WorkspaceId    types.String `tfsdk:"workspace_id"`
+	SshId          types.String `tfsdk:"ssh_id"`
+}
+
+func NewWorkspaceSshKeyResource() resource.Resource {
+	return &WorkspaceSshKeyResource{}
+}
+
+func (r *WorkspaceSshKeyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_ssh_key"
+}
+
+func (r *WorkspaceSshKeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Assign an SSH key of the organization to a workspace, used to clone private git submodules. " +
+			"Destroying the resource detaches the key from the workspace, the key itself is kept.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Id of the association, the workspace id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube workspace id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ssh_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Id of the organization SSH key assigned to the workspace",
+			},
+		},
+	}
+}
+
+func (r *WorkspaceSshKeyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Workspace SSH Key Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+	r.workspaces = client.NewCrud[client.WorkspaceEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/workspace")
+
+	tflog.Debug(ctx, "Configuring Workspace SSH Key resource", map[string]any{"success": true})
+}
+
+func (r *WorkspaceSshKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan WorkspaceSshKeyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sshId := plan.SshId.ValueString()
+	err := r.workspaces.PatchRelationship(ctx, plan.WorkspaceId.ValueString(), "ssh", &sshId, plan.OrganizationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace ssh key resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace ssh key resource request: %s", err)))
+		return
+	}
+
+	plan.ID = plan.WorkspaceId
+
+	tflog.Info(ctx, "Workspace SSH Key Resource Created", map[string]any{"success": true})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *WorkspaceSshKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state WorkspaceSshKeyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace, err := r.workspaces.Get(ctx, state.WorkspaceId.ValueString(), state.OrganizationId.ValueString())
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace ssh key resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace ssh key resource request: %s", err)))
+		return
+	}
+
+	// A key detached in the UI plans to assign it again, a key swapped in the
+	// UI is reported as a change of ssh_id.
+	if workspace.Deleted || workspace.Ssh == nil || workspace.Ssh.ID == "" {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.ID = types.StringValue(workspace.ID)
+	state.SshId = types.StringValue(workspace.Ssh.ID)
+
+	// Set refreshed state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Workspace SSH Key Resource reading", map[string]any{"success": true})
+}
+
+func (r *WorkspaceSshKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan WorkspaceSshKeyResourceModel
+	var state WorkspaceSshKeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sshId := plan.SshId.ValueString()
+	err := r.workspaces.PatchRelationship(ctx, state.WorkspaceId.ValueString(), "ssh", &sshId, state.OrganizationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace ssh key resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace ssh key resource request: %s", err)))
+		return
+	}
+
+	plan.ID = state.ID
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *WorkspaceSshKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data WorkspaceSshKeyResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.workspaces.PatchRelationship(ctx, data.WorkspaceId.ValueString(), "ssh", nil, data.OrganizationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace ssh key resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace ssh key resource request: %s", err)))
+		return
+	}
+}
+
+func (r *WorkspaceSshKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,workspace_ID', Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+}
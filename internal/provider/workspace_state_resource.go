@@ -0,0 +1,274 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &WorkspaceStateResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceStateResource{}
+
+type WorkspaceStateResource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+type WorkspaceStateResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	WorkspaceId types.String `tfsdk:"workspace_id"`
+	State       types.String `tfsdk:"state"`
+	Serial      types.Int64  `tfsdk:"serial"`
+	Lineage     types.String `tfsdk:"lineage"`
+	Sha256      types.String `tfsdk:"sha256"`
+}
+
+func NewWorkspaceStateResource() resource.Resource {
+	return &WorkspaceStateResource{}
+}
+
+func (r *WorkspaceStateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_state"
+}
+
+func (r *WorkspaceStateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Push a state version to a workspace, used to seed a workspace migrated from another backend. " +
+			"A new state version is pushed only when the content of the state changes. " +
+			"Destroying the resource keeps the state versions in Terrakube, the state history can not be deleted.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Id of the last state version pushed",
+			},
+			"workspace_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube workspace id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"state": schema.StringAttribute{
+				Required:  true,
+				Sensitive: true,
+				Description: "Path of a state file, or the state itself as JSON, for example read with `file()`. " +
+					"Only states with format version 4, written by Terraform 0.12 and later or OpenTofu, are accepted.",
+			},
+			"serial": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Serial of the state pushed",
+			},
+			"lineage": schema.StringAttribute{
+				Computed:    true,
+				Description: "Lineage of the state pushed",
+			},
+			"sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 checksum of the state pushed, a state file edited on disk is planned as a change of this attribute",
+			},
+		},
+	}
+}
+
+func (r *WorkspaceStateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Workspace State Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+
+	tflog.Debug(ctx, "Configuring Workspace State resource", map[string]any{"success": true})
+}
+
+// ModifyPlan reads the state at plan time, so an invalid state fails the plan
+// and the serial, lineage and checksum of the new version are shown in it.
+// The version is only pushed again when the checksum changes.
+func (r *WorkspaceStateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan WorkspaceStateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.State.IsUnknown() {
+		return
+	}
+
+	header, checksums, err := readStateHeader(workspaceStateSource(plan.State.ValueString()))
+	if errors.Is(err, os.ErrNotExist) {
+		// The file may be written by another resource during the apply.
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("state"), "Invalid Terraform state", err.Error())
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state WorkspaceStateResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if state.Sha256.ValueString() == checksums.sha256 {
+			plan.ID = state.ID
+			plan.Serial = state.Serial
+			plan.Lineage = state.Lineage
+			plan.Sha256 = state.Sha256
+			resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+			return
+		}
+	}
+
+	plan.ID = types.StringUnknown()
+	plan.Serial = types.Int64Value(*header.Serial)
+	plan.Lineage = types.StringValue(header.Lineage)
+	plan.Sha256 = types.StringValue(checksums.sha256)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *WorkspaceStateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan WorkspaceStateResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.push(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Workspace State Resource Created", map[string]any{"success": true})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read keeps the state unchanged, the state versions of a workspace can not
+// be changed after they are pushed.
+func (r *WorkspaceStateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state WorkspaceStateResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Workspace State Resource reading", map[string]any{"success": true})
+}
+
+func (r *WorkspaceStateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan WorkspaceStateResourceModel
+	var state WorkspaceStateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Moving the same content between a file and an inline value does not
+	// push a new version.
+	if !plan.Sha256.IsUnknown() && plan.Sha256.ValueString() == state.Sha256.ValueString() {
+		plan.ID = state.ID
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	r.push(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *WorkspaceStateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data WorkspaceStateResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Not routed through warnings_as_errors, an error here would keep the
+	// resource in state with no way to destroy it.
+	resp.Diagnostics.AddWarning(
+		"State versions kept",
+		fmt.Sprintf("The state history of workspace %s can not be deleted, state version %s is kept in Terrakube and only removed from the Terraform state.", data.WorkspaceId.ValueString(), data.ID.ValueString()),
+	)
+}
+
+// push uploads the state of the plan and sets the computed attributes. The
+// checksum planned is compared first, a file edited between plan and apply
+// fails instead of pushing content that was not reviewed.
+func (r *WorkspaceStateResource) push(ctx context.Context, plan *WorkspaceStateResourceModel, diags *diag.Diagnostics) {
+	source := workspaceStateSource(plan.State.ValueString())
+
+	header, checksums, err := readStateHeader(source)
+	if err != nil {
+		diags.AddAttributeError(path.Root("state"), "Invalid Terraform state", err.Error())
+		return
+	}
+	if !plan.Sha256.IsUnknown() && plan.Sha256.ValueString() != checksums.sha256 {
+		diags.AddAttributeError(path.Root("state"), "State changed after plan", fmt.Sprintf("The %s has checksum %s, the plan was made with %s. Run terraform plan again.", source.name, checksums.sha256, plan.Sha256.ValueString()))
+		return
+	}
+
+	id, err := uploadStateVersion(ctx, r.client, r.endpoint, r.token, plan.WorkspaceId.ValueString(), source)
+	if err != nil {
+		diags.AddError("Error uploading workspace state", apiErrorDetail(err, fmt.Sprintf("Error uploading %s to workspace %s: %s", source.name, plan.WorkspaceId.ValueString(), err)))
+		return
+	}
+	if id == "" {
+		// Older Terrakube versions do not return the id of the state version.
+		id = fmt.Sprintf("%s-%d", header.Lineage, *header.Serial)
+	}
+
+	plan.ID = types.StringValue(id)
+	plan.Serial = types.Int64Value(*header.Serial)
+	plan.Lineage = types.StringValue(header.Lineage)
+	plan.Sha256 = types.StringValue(checksums.sha256)
+}
+
+// workspaceStateSource reads the state attribute as JSON when it is an
+// object and as the path of a state file otherwise.
+func workspaceStateSource(value string) stateSource {
+	if strings.HasPrefix(strings.TrimSpace(value), "{") {
+		return stateContentSource("state attribute", value)
+	}
+	return stateFileSource(value)
+}
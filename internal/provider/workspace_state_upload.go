@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+func initialStateFileSchema() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional: true,
+		Description: "Path of a state file uploaded as the first state version right after the workspace is created, used to migrate existing workspaces. " +
+			"It is only used on create, later changes are ignored.",
+	}
+}
+
+type stateFileHeader struct {
+	Version          *int   `json:"version"`
+	TerraformVersion string `json:"terraform_version"`
+	Serial           *int64 `json:"serial"`
+	Lineage          string `json:"lineage"`
+}
+
+// stateSource is a state file or a state given inline, name is used in the
+// errors to point at the file or attribute.
+type stateSource struct {
+	name string
+	open func() (io.ReadCloser, error)
+}
+
+func stateFileSource(stateFile string) stateSource {
+	return stateSource{
+		name: "state file " + stateFile,
+		open: func() (io.ReadCloser, error) {
+			file, err := os.Open(stateFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to open state file: %w", err)
+			}
+			return file, nil
+		},
+	}
+}
+
+func stateContentSource(name string, content string) stateSource {
+	return stateSource{
+		name: name,
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(content)), nil
+		},
+	}
+}
+
+// uploadInitialState pushes the state file through the remote backend API
+// implemented by Terrakube.
+func uploadInitialState(ctx context.Context, httpClient *http.Client, endpoint string, token string, workspaceId string, stateFile string) error {
+	_, err := uploadStateVersion(ctx, httpClient, endpoint, token, workspaceId, stateFileSource(stateFile))
+	return err
+}
+
+// uploadStateVersion creates a state version from source and returns its id.
+// The workspace is locked while the state version is created, like
+// terraform state push does.
+func uploadStateVersion(ctx context.Context, httpClient *http.Client, endpoint string, token string, workspaceId string, source stateSource) (string, error) {
+	header, checksums, err := readStateHeader(source)
+	if err != nil {
+		return "", err
+	}
+
+	workspaceUrl := fmt.Sprintf("%s/remote/tfe/v2/workspaces/%s", endpoint, workspaceId)
+
+	if err := remoteBackendAction(httpClient, token, workspaceUrl+"/actions/lock"); err != nil {
+		return "", fmt.Errorf("unable to lock workspace: %w", err)
+	}
+	defer func() {
+		if err := remoteBackendAction(httpClient, token, workspaceUrl+"/actions/unlock"); err != nil {
+			tflog.Warn(ctx, "Unable to unlock workspace after the state upload", map[string]any{"error": err.Error()})
+		}
+	}()
+
+	file, err := source.open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	// The request body is streamed so large states are never held in memory
+	// as a base64 string.
+	body, writer := io.Pipe()
+	go func() {
+		prefix, _ := json.Marshal(map[string]any{"serial": *header.Serial, "md5": checksums.md5, "lineage": header.Lineage})
+		_, err := fmt.Fprintf(writer, `{"data":{"type":"state-versions","attributes":%s,"state":"`, prefix[:len(prefix)-1])
+		if err == nil {
+			encoder := base64.NewEncoder(base64.StdEncoding, writer)
+			if _, err = io.Copy(encoder, file); err == nil {
+				err = encoder.Close()
+			}
+		}
+		if err == nil {
+			_, err = io.WriteString(writer, `"}}}`)
+		}
+		writer.CloseWithError(err)
+	}()
+
+	request, err := http.NewRequest(http.MethodPost, workspaceUrl+"/state-versions", body)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error executing request: %w", err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response body: %w", err)
+	}
+
+	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(responseBody)})
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("state upload rejected with status %s: %s", response.Status, string(responseBody))
+	}
+
+	var stateVersion struct {
+		Data struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Serial  *int64 `json:"serial"`
+				Lineage string `json:"lineage"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(responseBody, &stateVersion); err != nil {
+		return "", fmt.Errorf("error unmarshal state version response: %w", err)
+	}
+
+	uploaded := stateVersion.Data.Attributes
+	if uploaded.Serial != nil && *uploaded.Serial != *header.Serial {
+		return "", fmt.Errorf("uploaded state has serial %d, expected %d", *uploaded.Serial, *header.Serial)
+	}
+	if uploaded.Lineage != "" && uploaded.Lineage != header.Lineage {
+		return "", fmt.Errorf("uploaded state has lineage %q, expected %q", uploaded.Lineage, header.Lineage)
+	}
+
+	return stateVersion.Data.ID, nil
+}
+
+type stateChecksums struct {
+	md5    string
+	sha256 string
+}
+
+// readStateHeader returns the serial and lineage of the state with the
+// checksums of its content. Anything that is not a state written by a
+// current Terraform or OpenTofu version is rejected before the workspace
+// is locked.
+func readStateHeader(source stateSource) (*stateFileHeader, *stateChecksums, error) {
+	file, err := source.open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+	hash := io.MultiWriter(md5Hash, sha256Hash)
+	header := &stateFileHeader{}
+	if err := json.NewDecoder(io.TeeReader(file, hash)).Decode(header); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse %s as JSON: %w", source.name, err)
+	}
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, nil, fmt.Errorf("unable to read %s: %w", source.name, err)
+	}
+
+	switch {
+	case header.Version == nil:
+		return nil, nil, fmt.Errorf("%s is not a Terraform state, it has no version", source.name)
+	case *header.Version != 4:
+		return nil, nil, fmt.Errorf("%s has state format version %d, only version 4 written by Terraform 0.12 and later is supported", source.name, *header.Version)
+	case header.TerraformVersion == "":
+		return nil, nil, fmt.Errorf("%s is not a Terraform state, it has no terraform_version", source.name)
+	case header.Serial == nil:
+		return nil, nil, fmt.Errorf("%s is not a Terraform state, it has no serial", source.name)
+	case header.Lineage == "":
+		return nil, nil, fmt.Errorf("%s has no lineage", source.name)
+	}
+
+	return header, &stateChecksums{md5: hex.EncodeToString(md5Hash.Sum(nil)), sha256: hex.EncodeToString(sha256Hash.Sum(nil))}, nil
+}
+
+func remoteBackendAction(httpClient *http.Client, token string, actionUrl string) error {
+	request, err := http.NewRequest(http.MethodPost, actionUrl, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		body, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("status %s: %s", response.Status, string(body))
+	}
+
+	return nil
+}
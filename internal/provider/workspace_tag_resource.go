@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -88,17 +87,7 @@ func (r *WorkspaceTagResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -127,7 +116,7 @@ func (r *WorkspaceTagResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	workspaceTagRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/workspaceTag", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
+	workspaceTagRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/workspaceTag", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
 	workspaceTagRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceTagRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -229,18 +218,28 @@ func (r *WorkspaceTagResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/workspaceTag/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.TagID.ValueString()), nil)
-	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	// Only the association of the workspace is deleted, the organization tag
+	// and its other workspaces are left untouched.
+	reqOrg, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/workspaceTag/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating workspace tag resource request", fmt.Sprintf("Error creating workspace tag resource request: %s", err))
 		return
 	}
+	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 
-	_, err = r.client.Do(reqOrg)
+	deleteResponse, err := r.client.Do(reqOrg)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing workspace tag resource request", fmt.Sprintf("Error executing workspace tag resource request: %s", err))
 		return
 	}
+	defer deleteResponse.Body.Close()
+
+	// An association that is already gone does not need to be deleted.
+	if deleteResponse.StatusCode >= 400 && deleteResponse.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(deleteResponse.Body)
+		resp.Diagnostics.AddError("Error deleting workspace tag", responseErrorDetail(deleteResponse, fmt.Sprintf("Workspace tag %s was not deleted, status %s: %s", data.ID.ValueString(), deleteResponse.Status, string(body))))
+		return
+	}
 }
 
 func (r *WorkspaceTagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
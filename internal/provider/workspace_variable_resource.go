@@ -3,16 +3,21 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 
 	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -24,11 +29,13 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &WorkspaceVariableResource{}
 var _ resource.ResourceWithImportState = &WorkspaceVariableResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceVariableResource{}
 
 type WorkspaceVariableResource struct {
 	client   *http.Client
 	endpoint string
 	token    string
+	warnings *warningPolicy
 }
 
 type WorkspaceVariableResourceModel struct {
@@ -41,6 +48,8 @@ type WorkspaceVariableResourceModel struct {
 	Category       types.String `tfsdk:"category"`
 	Sensitive      types.Bool   `tfsdk:"sensitive"`
 	Hcl            types.Bool   `tfsdk:"hcl"`
+
+	CheckGlobalConflicts types.Bool `tfsdk:"check_global_conflicts"`
 }
 
 func NewWorkspaceVariableResource() resource.Resource {
@@ -73,7 +82,10 @@ func (r *WorkspaceVariableResource) Schema(ctx context.Context, req resource.Sch
 			},
 			"key": schema.StringAttribute{
 				Required:    true,
-				Description: "Variable key",
+				Description: "Variable key. Changing it forces a new variable.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"value": schema.StringAttribute{
 				Required:    true,
@@ -85,7 +97,13 @@ func (r *WorkspaceVariableResource) Schema(ctx context.Context, req resource.Sch
 			},
 			"category": schema.StringAttribute{
 				Required:    true,
-				Description: "Variable category (ENV or TERRAFORM). ENV variables are injected in workspace environment at runtime.",
+				Description: "Variable category (ENV or TERRAFORM). ENV variables are injected in workspace environment at runtime. Changing it forces a new variable.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("ENV", "TERRAFORM"),
+				},
 			},
 			"sensitive": schema.BoolAttribute{
 				Required:    true,
@@ -95,6 +113,12 @@ func (r *WorkspaceVariableResource) Schema(ctx context.Context, req resource.Sch
 				Required:    true,
 				Description: "Parse this field as HashiCorp Configuration Language (HCL). This allows you to interpolate values at runtime.",
 			},
+			"check_global_conflicts": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Warn during plan when an organization global variable with the same key and category exists, since the workspace variable takes precedence over it. Default is `false`.",
+			},
 		},
 	}
 }
@@ -114,20 +138,11 @@ func (r *WorkspaceVariableResource) Configure(ctx context.Context, req resource.
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.warnings = providerData.Warnings
 
 	tflog.Debug(ctx, "Configuring Workspace Variable resource", map[string]any{"success": true})
 }
@@ -158,7 +173,7 @@ func (r *WorkspaceVariableResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
-	workspaceVarRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/variable", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
+	workspaceVarRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/variable", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
 	workspaceVarRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceVarRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -166,26 +181,51 @@ func (r *WorkspaceVariableResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
-	workspaceVarResponse, err := r.client.Do(workspaceVarRequest)
+	// Terrakube has no idempotency key, the variables matching the plan
+	// before the create tell the one made by a create whose response was
+	// lost apart from the others.
+	existing, err := r.matchingVariables(ctx, plan)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace variable  resource request", fmt.Sprintf("Error executing workspace variable  resource request: %s", err))
+		resp.Diagnostics.AddError("Error listing workspace variables", apiErrorDetail(err, fmt.Sprintf("Error listing the workspace variables before the create: %s", err)))
 		return
 	}
 
-	bodyResponse, err := io.ReadAll(workspaceVarResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading workspace variable  resource response")
-	}
 	workspaceVariable := &client.WorkspaceVariableEntity{}
+	workspaceVarResponse, err := r.client.Do(workspaceVarRequest)
+	if err != nil {
+		if client.RequestMayHaveArrived(err) {
+			if matches, findErr := r.matchingVariables(ctx, plan); findErr == nil {
+				workspaceVariable, err = client.CreatedEntity(existing, matches, err)
+			}
+		}
+		var lost *client.LostCreateError
+		if errors.As(err, &lost) {
+			resp.Diagnostics.AddError(
+				"Workspace variable create response lost",
+				fmt.Sprintf("The response of the create of variable %q was lost and the variables %s with this key and category were created meanwhile. Import the variable created by this apply with 'organization_ID,workspace_ID,ID'.", plan.Key.ValueString(), strings.Join(lost.IDs, ", ")),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Error executing workspace variable  resource request", fmt.Sprintf("Error executing workspace variable  resource request: %s", err))
+			return
+		}
+		tflog.Warn(ctx, "Create response lost, adopting the workspace variable created meanwhile", map[string]any{"key": plan.Key.ValueString()})
+	} else {
+		bodyResponse, err := io.ReadAll(workspaceVarResponse.Body)
+		if err != nil {
+			tflog.Error(ctx, "Error reading workspace variable  resource response")
+		}
 
-	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspaceVariable)
+		err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspaceVariable)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
-		return
-	}
+		if err != nil {
+			resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(workspaceVarResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, workspaceVarResponse.Status)))
+			return
+		}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+		tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+	}
 
 	if workspaceVariable.Sensitive {
 		tflog.Info(ctx, "Variable value is not included in response, setting values the same as the plan for sensitive=true...")
@@ -229,6 +269,12 @@ func (r *WorkspaceVariableResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
+	if workspaceVariableResponse.StatusCode == http.StatusNotFound {
+		tflog.Warn(ctx, "Workspace variable not found, removing it from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
 	bodyResponse, err := io.ReadAll(workspaceVariableResponse.Body)
 	if err != nil {
 		tflog.Error(ctx, "Error reading workspace variable resource response")
@@ -239,12 +285,14 @@ func (r *WorkspaceVariableResource) Read(ctx context.Context, req resource.ReadR
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspaceVariable)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(workspaceVariableResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, workspaceVariableResponse.Status)))
 		return
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
+	// The API never returns sensitive values, the value in state is kept so
+	// it does not show as a change on every plan.
 	if workspaceVariable.Sensitive {
 		tflog.Info(ctx, "Variable value is not included in response, setting values the same as the current state value")
 		state.Value = types.StringValue(state.Value.ValueString())
@@ -260,6 +308,10 @@ func (r *WorkspaceVariableResource) Read(ctx context.Context, req resource.ReadR
 	state.Hcl = types.BoolValue(workspaceVariable.Hcl)
 	state.ID = types.StringValue(workspaceVariable.ID)
 
+	if state.CheckGlobalConflicts.IsNull() {
+		state.CheckGlobalConflicts = types.BoolValue(false)
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -291,7 +343,7 @@ func (r *WorkspaceVariableResource) Update(ctx context.Context, req resource.Upd
 	}
 
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err := client.MarshalSparsePayload(out, bodyRequest, unchangedValue(plan.Value, state.Value)...)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
@@ -335,7 +387,7 @@ func (r *WorkspaceVariableResource) Update(ctx context.Context, req resource.Upd
 
 	bodyResponse, err = io.ReadAll(workspaceVariableResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading Workspace variable resource response body", fmt.Sprintf("Error reading Workspace variable resource response body: %s", err))
+		resp.Diagnostics.AddError("Error reading Workspace variable resource response body", responseErrorDetail(workspaceVariableResponse, fmt.Sprintf("Error reading Workspace variable resource response body, error: %s, response status: %s", err, workspaceVariableResponse.Status)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -344,7 +396,7 @@ func (r *WorkspaceVariableResource) Update(ctx context.Context, req resource.Upd
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspaceVariable)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(workspaceVariableResponse, fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, workspaceVariableResponse.Status)))
 		return
 	}
 
@@ -376,18 +428,58 @@ func (r *WorkspaceVariableResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/variable/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
+	workspaceRequest, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/variable/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
 	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating Workspace variable resource request", fmt.Sprintf("Error creating Workspace variable resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(workspaceRequest)
+	deleteResponse, err := r.client.Do(workspaceRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing Workspace variable resource request", fmt.Sprintf("Error executing Workspace variable resource request: %s", err))
 		return
 	}
+	defer deleteResponse.Body.Close()
+
+	if deleteResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(deleteResponse.Body)
+		resp.Diagnostics.AddError("Error executing Workspace variable resource request", responseErrorDetail(deleteResponse, fmt.Sprintf("Error executing Workspace variable resource request, response status: %s, response body: %s", deleteResponse.Status, bodyResponse)))
+		return
+	}
+}
+
+func (r *WorkspaceVariableResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan WorkspaceVariableResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plan.CheckGlobalConflicts.ValueBool() {
+		return
+	}
+
+	if plan.OrganizationId.IsUnknown() || plan.Key.IsUnknown() || plan.Category.IsUnknown() {
+		return
+	}
+
+	globals, err := listOrganizationVariables(ctx, r.client, r.endpoint, r.token, plan.OrganizationId.ValueString())
+	if err != nil {
+		r.warnings.add(&resp.Diagnostics, "Unable to check global variable conflicts", fmt.Sprintf("Unable to list the organization global variables: %s", err))
+		return
+	}
+
+	for _, global := range globals {
+		if global.Key == plan.Key.ValueString() && global.Category == plan.Category.ValueString() {
+			r.warnings.addAttribute(
+				&resp.Diagnostics,
+				path.Root("key"),
+				"Workspace variable shadows a global variable",
+				fmt.Sprintf("The organization global variable %q (%s, id %s) has the same key and category, the workspace variable value takes precedence over it in this workspace.", global.Key, global.Category, global.ID),
+			)
+		}
+	}
 }
 
 func (r *WorkspaceVariableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -405,3 +497,30 @@ func (r *WorkspaceVariableResource) ImportState(ctx context.Context, req resourc
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), idParts[1])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[2])...)
 }
+
+// unchangedValue lists the value attribute when the update does not change
+// it, so the PATCH leaves it out and the value stored by Terrakube, maybe
+// rotated since the last apply, is kept.
+func unchangedValue(plan types.String, state types.String) []string {
+	if plan.Equal(state) {
+		return []string{"value"}
+	}
+	return nil
+}
+
+// matchingVariables returns by id the variables of the workspace with the
+// key and category of the plan.
+func (r *WorkspaceVariableResource) matchingVariables(ctx context.Context, plan WorkspaceVariableResourceModel) (map[string]*client.WorkspaceVariableEntity, error) {
+	query := url.Values{}
+	query.Set("filter[variable]", fmt.Sprintf("key==%s;category==%s", rsqlString(plan.Key.ValueString()), rsqlString(plan.Category.ValueString())))
+	items, err := fetchAllPages(ctx, r.client, r.token, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/variable?%s", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString(), query.Encode()), reflect.TypeOf(new(client.WorkspaceVariableEntity)))
+	if err != nil {
+		return nil, err
+	}
+
+	variables := map[string]*client.WorkspaceVariableEntity{}
+	for _, item := range items {
+		variables[item.(*client.WorkspaceVariableEntity).ID] = item.(*client.WorkspaceVariableEntity)
+	}
+	return variables, nil
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// workspaceVariableConfig returns a terrakube_workspace_variable
+// configuration of workspace w1, the attributes are added to the key and
+// category.
+func workspaceVariableConfig(terrakube *testProvider, key string, category string, attributes map[string]tftypes.Value) tftypes.Value {
+	values := map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "o1"),
+		"workspace_id":    tftypes.NewValue(tftypes.String, "w1"),
+		"key":             tftypes.NewValue(tftypes.String, key),
+		"value":           tftypes.NewValue(tftypes.String, "value"),
+		"description":     tftypes.NewValue(tftypes.String, "description"),
+		"category":        tftypes.NewValue(tftypes.String, category),
+		"sensitive":       tftypes.NewValue(tftypes.Bool, false),
+		"hcl":             tftypes.NewValue(tftypes.Bool, false),
+	}
+	for name, value := range attributes {
+		values[name] = value
+	}
+	return terrakube.object("terrakube_workspace_variable", values)
+}
+
+func TestWorkspaceVariableGlobalConflicts(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	api.put("/api/v1/organization/o1/globalvar/g1", "globalvar", map[string]any{"key": "region", "value": "eu-west-1", "category": "TERRAFORM"})
+	api.put("/api/v1/organization/o1/globalvar/g2", "globalvar", map[string]any{"key": "token", "value": "", "category": "ENV"})
+	terrakube := newTestProvider(t, server.URL, nil)
+	checkConflicts := map[string]tftypes.Value{"check_global_conflicts": tftypes.NewValue(tftypes.Bool, true)}
+
+	for _, test := range []struct {
+		name     string
+		config   tftypes.Value
+		conflict string
+	}{
+		{"same key and category", workspaceVariableConfig(terrakube, "region", "TERRAFORM", checkConflicts), `"region" (TERRAFORM, id g1)`},
+		{"other category", workspaceVariableConfig(terrakube, "token", "TERRAFORM", checkConflicts), ""},
+		{"other key", workspaceVariableConfig(terrakube, "zone", "TERRAFORM", checkConflicts), ""},
+		{"check disabled", workspaceVariableConfig(terrakube, "region", "TERRAFORM", nil), ""},
+	} {
+		plan := terrakube.plan("terrakube_workspace_variable", terrakube.null("terrakube_workspace_variable"), test.config)
+		if err := diagnosticsError(plan.Diagnostics); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.name, err)
+		}
+
+		var conflicts []string
+		for _, diagnostic := range plan.Diagnostics {
+			if diagnostic.Severity == tfprotov6.DiagnosticSeverityWarning && diagnostic.Summary == "Workspace variable shadows a global variable" {
+				conflicts = append(conflicts, diagnostic.Detail)
+			}
+		}
+		switch {
+		case test.conflict == "" && len(conflicts) != 0:
+			t.Errorf("%s: expected no conflict, got %v", test.name, conflicts)
+		case test.conflict != "" && (len(conflicts) != 1 || !strings.Contains(conflicts[0], test.conflict)):
+			t.Errorf("%s: expected a conflict with %s, got %v", test.name, test.conflict, conflicts)
+		}
+	}
+}
+
+// TestWorkspaceVariableKeepsRotatedValue changes the description of a
+// sensitive variable whose value was rotated in Terrakube, the update must
+// not send back the value of the state.
+func TestWorkspaceVariableKeepsRotatedValue(t *testing.T) {
+	t.Parallel()
+
+	api, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+	sensitive := func(value string, description string) tftypes.Value {
+		return workspaceVariableConfig(terrakube, "password", "ENV", map[string]tftypes.Value{
+			"value":       tftypes.NewValue(tftypes.String, value),
+			"description": tftypes.NewValue(tftypes.String, description),
+			"sensitive":   tftypes.NewValue(tftypes.Bool, true),
+		})
+	}
+
+	state, diagnostics := terrakube.apply("terrakube_workspace_variable", terrakube.null("terrakube_workspace_variable"), sensitive("initial", "description"))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	variablePath := "/api/v1/organization/o1/workspace/w1/variable/" + stringAttribute(t, state, "id")
+	api.set(variablePath, "value", "rotated")
+
+	state, diagnostics = terrakube.apply("terrakube_workspace_variable", state, sensitive("initial", "rotated monthly"))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attributes := api.attributes(variablePath); attributes["value"] != "rotated" || attributes["description"] != "rotated monthly" {
+		t.Errorf("a description change should leave the rotated value, got %v", attributes)
+	}
+
+	_, diagnostics = terrakube.apply("terrakube_workspace_variable", state, sensitive("changed", "rotated monthly"))
+	if err := diagnosticsError(diagnostics); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if value := api.attributes(variablePath)["value"]; value != "changed" {
+		t.Errorf("a value change should be sent, got %v", value)
+	}
+}
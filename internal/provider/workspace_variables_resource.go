@@ -0,0 +1,371 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &WorkspaceVariablesResource{}
+var _ resource.ResourceWithImportState = &WorkspaceVariablesResource{}
+
+type WorkspaceVariablesResource struct {
+	client    *http.Client
+	endpoint  string
+	token     string
+	variables *client.Crud[client.WorkspaceVariableEntity]
+
+	parallelism int
+}
+
+type WorkspaceVariablesResourceModel struct {
+	ID             types.String                           `tfsdk:"id"`
+	OrganizationId types.String                           `tfsdk:"organization_id"`
+	WorkspaceId    types.String                           `tfsdk:"workspace_id"`
+	Variables      map[string]WorkspaceVariablesItemModel `tfsdk:"variables"`
+}
+
+type WorkspaceVariablesItemModel struct {
+	ID          types.String `tfsdk:"id"`
+	Value       types.String `tfsdk:"value"`
+	Description types.String `tfsdk:"description"`
+	Category    types.String `tfsdk:"category"`
+	Sensitive   types.Bool   `tfsdk:"sensitive"`
+	Hcl         types.Bool   `tfsdk:"hcl"`
+}
+
+func NewWorkspaceVariablesResource() resource.Resource {
+	return &WorkspaceVariablesResource{}
+}
+
+func (r *WorkspaceVariablesResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_variables"
+}
+
+func (r *WorkspaceVariablesResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manage several variables of a workspace keyed by variable key. Variables are applied one by one: when some of them fail the others are still saved in state and one error is reported per failed variable, the next apply only retries the failed ones. Variables of the workspace that are not in the map are left untouched.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terrakube workspace id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube workspace id",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"variables": schema.MapNestedAttribute{
+				Required:    true,
+				Description: "Workspace variables keyed by variable key",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Variable Id",
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.UseStateForUnknown(),
+							},
+						},
+						"value": schema.StringAttribute{
+							Required:    true,
+							Sensitive:   true,
+							Description: "Variable value",
+						},
+						"description": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString(""),
+							Description: "Variable description",
+						},
+						"category": schema.StringAttribute{
+							Required:    true,
+							Description: "Variable category (ENV or TERRAFORM). ENV variables are injected in workspace environment at runtime. Changing it deletes the variable and creates it again.",
+						},
+						"sensitive": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+							Description: "Sensitive variables are never shown in the UI or API, default is `false`.",
+						},
+						"hcl": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+							Description: "Parse this field as HashiCorp Configuration Language (HCL), default is `false`.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *WorkspaceVariablesResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Workspace Variables Resource Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerData.HttpClient
+	r.endpoint = providerData.Endpoint
+	r.token = providerData.Token
+	r.parallelism = providerData.APIParallelism
+	r.variables = client.NewCrud[client.WorkspaceVariableEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/workspace/%s/variable")
+
+	tflog.Debug(ctx, "Configuring Workspace Variables resource", map[string]any{"success": true})
+}
+
+func (r *WorkspaceVariablesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan WorkspaceVariablesResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := plan
+	state.ID = plan.WorkspaceId
+	state.Variables = map[string]WorkspaceVariablesItemModel{}
+
+	failures := r.apply(ctx, &state, plan.Variables)
+	failures.appendTo(&resp.Diagnostics, path.Root("variables"))
+
+	tflog.Info(ctx, "Workspace Variables Resource Created", map[string]any{"applied": len(state.Variables)})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *WorkspaceVariablesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state WorkspaceVariablesResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	variables, err := r.variables.List(ctx, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace variables resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace variables resource request: %s", err)))
+		return
+	}
+
+	byId := make(map[string]*client.WorkspaceVariableEntity, len(variables))
+	for _, variable := range variables {
+		byId[variable.ID] = variable
+	}
+
+	// An imported resource has no variables yet and adopts every variable
+	// of the workspace.
+	if state.Variables == nil {
+		state.Variables = make(map[string]WorkspaceVariablesItemModel, len(variables))
+		for _, variable := range variables {
+			state.Variables[variable.Key] = WorkspaceVariablesItemModel{ID: types.StringValue(variable.ID), Value: types.StringValue("")}
+		}
+	}
+
+	refreshed := make(map[string]WorkspaceVariablesItemModel, len(state.Variables))
+	for key, item := range state.Variables {
+		variable, ok := byId[item.ID.ValueString()]
+		if !ok || variable.Key != key {
+			tflog.Info(ctx, "Workspace variable removed outside of Terraform", map[string]any{"key": key})
+			continue
+		}
+		refreshed[key] = workspaceVariablesItem(variable, item.Value)
+	}
+	state.Variables = refreshed
+	state.ID = state.WorkspaceId
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+
+	tflog.Info(ctx, "Workspace Variables Resource reading", map[string]any{"success": true})
+}
+
+func (r *WorkspaceVariablesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan WorkspaceVariablesResourceModel
+	var state WorkspaceVariablesResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failures := r.apply(ctx, &state, plan.Variables)
+	failures.appendTo(&resp.Diagnostics, path.Root("variables"))
+
+	tflog.Info(ctx, "Workspace Variables Resource Updated", map[string]any{"applied": len(state.Variables)})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *WorkspaceVariablesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state WorkspaceVariablesResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failures := r.apply(ctx, &state, nil)
+	if !failures.empty() {
+		failures.appendTo(&resp.Diagnostics, path.Root("variables"))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	}
+}
+
+func (r *WorkspaceVariablesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,workspace_ID', Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+}
+
+// apply moves the variables in state towards the desired ones. Every item is
+// attempted, the state only records the items the API accepted so a failed
+// item keeps its previous value, or stays absent, until the next apply.
+func (r *WorkspaceVariablesResource) apply(ctx context.Context, state *WorkspaceVariablesResourceModel, desired map[string]WorkspaceVariablesItemModel) *itemFailures {
+	orgId := state.OrganizationId.ValueString()
+	wsId := state.WorkspaceId.ValueString()
+	failures := newItemFailures("workspace variable")
+
+	var removed []string
+	for key := range state.Variables {
+		if _, ok := desired[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	errs := deleteAll(ctx, r.parallelism, removed, func(ctx context.Context, key string) error {
+		return r.variables.Delete(ctx, state.Variables[key].ID.ValueString(), orgId, wsId)
+	})
+	for _, key := range removed {
+		if err, ok := errs[key]; ok {
+			failures.add(key, err)
+			continue
+		}
+		delete(state.Variables, key)
+	}
+
+	for key, item := range desired {
+		entity := &client.WorkspaceVariableEntity{
+			Key:         key,
+			Value:       item.Value.ValueString(),
+			Description: item.Description.ValueString(),
+			Category:    item.Category.ValueString(),
+			Sensitive:   item.Sensitive.ValueBool(),
+			Hcl:         item.Hcl.ValueBool(),
+		}
+
+		current, exists := state.Variables[key]
+		if !exists {
+			created, err := r.variables.Create(ctx, entity, orgId, wsId)
+			if err != nil {
+				failures.add(key, err)
+				continue
+			}
+			state.Variables[key] = workspaceVariablesItem(created, item.Value)
+			continue
+		}
+
+		if workspaceVariablesItemEqual(current, item) {
+			continue
+		}
+
+		// Terrakube does not reliably move a variable to another category,
+		// the variable is created again instead.
+		if !current.Category.Equal(item.Category) {
+			if err := r.variables.Delete(ctx, current.ID.ValueString(), orgId, wsId); err != nil {
+				failures.add(key, err)
+				continue
+			}
+			delete(state.Variables, key)
+
+			created, err := r.variables.Create(ctx, entity, orgId, wsId)
+			if err != nil {
+				failures.add(key, err)
+				continue
+			}
+			state.Variables[key] = workspaceVariablesItem(created, item.Value)
+			continue
+		}
+
+		entity.ID = current.ID.ValueString()
+		if err := r.variables.UpdateWithout(ctx, entity.ID, entity, unchangedValue(item.Value, current.Value), orgId, wsId); err != nil {
+			failures.add(key, err)
+			continue
+		}
+		item.ID = current.ID
+		state.Variables[key] = item
+	}
+
+	return failures
+}
+
+// workspaceVariablesItem converts the API variable, the value of sensitive
+// variables is not returned and is kept from Terraform.
+func workspaceVariablesItem(variable *client.WorkspaceVariableEntity, value types.String) WorkspaceVariablesItemModel {
+	if !variable.Sensitive {
+		value = types.StringValue(variable.Value)
+	}
+
+	return WorkspaceVariablesItemModel{
+		ID:          types.StringValue(variable.ID),
+		Value:       value,
+		Description: types.StringValue(variable.Description),
+		Category:    types.StringValue(variable.Category),
+		Sensitive:   types.BoolValue(variable.Sensitive),
+		Hcl:         types.BoolValue(variable.Hcl),
+	}
+}
+
+func workspaceVariablesItemEqual(current WorkspaceVariablesItemModel, desired WorkspaceVariablesItemModel) bool {
+	return current.Value.Equal(desired.Value) &&
+		current.Description.Equal(desired.Description) &&
+		current.Category.Equal(desired.Category) &&
+		current.Sensitive.Equal(desired.Sensitive) &&
+		current.Hcl.Equal(desired.Hcl)
+}
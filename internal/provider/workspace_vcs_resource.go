@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -28,26 +28,38 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &WorkspaceVcsResource{}
 var _ resource.ResourceWithImportState = &WorkspaceVcsResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceVcsResource{}
 
 type WorkspaceVcsResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client        *http.Client
+	endpoint      string
+	token         string
+	variables     *client.Crud[client.WorkspaceVariableEntity]
+	organizations *organizationCache
+	airgap        *airgapPolicy
+	warnings      *warningPolicy
 }
 
 type WorkspaceVcsResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	Name           types.String `tfsdk:"name"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	Description    types.String `tfsdk:"description"`
-	IaCType        types.String `tfsdk:"iac_type"`
-	TemplateId     types.String `tfsdk:"template_id"`
-	IaCVersion     types.String `tfsdk:"iac_version"`
-	Repository     types.String `tfsdk:"repository"`
-	Branch         types.String `tfsdk:"branch"`
-	Folder         types.String `tfsdk:"folder"`
-	ExecutionMode  types.String `tfsdk:"execution_mode"`
-	VcsId          types.String `tfsdk:"vcs_id"`
+	ID                     types.String           `tfsdk:"id"`
+	Name                   types.String           `tfsdk:"name"`
+	OrganizationId         types.String           `tfsdk:"organization_id"`
+	Description            descriptionValue       `tfsdk:"description"`
+	IaCType                types.String           `tfsdk:"iac_type"`
+	TemplateId             types.String           `tfsdk:"template_id"`
+	IaCVersion             types.String           `tfsdk:"iac_version"`
+	Repository             types.String           `tfsdk:"repository"`
+	Branch                 types.String           `tfsdk:"branch"`
+	Folder                 types.String           `tfsdk:"folder"`
+	ExecutionMode          types.String           `tfsdk:"execution_mode"`
+	EffectiveExecutionMode types.String           `tfsdk:"effective_execution_mode"`
+	VcsId                  types.String           `tfsdk:"vcs_id"`
+	CliArgs                *WorkspaceCliArgsModel `tfsdk:"cli_args"`
+	ProviderMirrorUrl      types.String           `tfsdk:"provider_mirror_url"`
+	InitialStateFile       types.String           `tfsdk:"initial_state_file"`
+	AllowDowngrade         types.Bool             `tfsdk:"allow_version_downgrade"`
+	Slug                   types.String           `tfsdk:"slug"`
+	BranchDriftWarn        types.Bool             `tfsdk:"detect_branch_drift_only_warn"`
 }
 
 func NewWorkspaceVcsResource() resource.Resource {
@@ -81,18 +93,23 @@ func (r *WorkspaceVcsResource) Schema(ctx context.Context, req resource.SchemaRe
 				Description: "Workspace VCS name",
 			},
 			"description": schema.StringAttribute{
+				CustomType:  descriptionType{},
 				Optional:    true,
 				Description: "Workspace VCS description",
 			},
+			"allow_version_downgrade": allowVersionDowngradeSchema(),
+			"slug":                    workspaceSlugSchema(),
+			"cli_args":                workspaceCliArgsSchema(),
+			"provider_mirror_url":     workspaceProviderMirrorSchema(),
+			"initial_state_file":      initialStateFileSchema(),
 			"execution_mode": schema.StringAttribute{
 				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString("remote"),
-				Description: "Workspace VCS execution mode (remote or local)",
+				Description: "Workspace VCS execution mode (remote or local). When not set the workspace uses the execution mode of the organization.",
 				Validators: []validator.String{
 					stringvalidator.OneOf("remote", "local"),
 				},
 			},
+			"effective_execution_mode": effectiveExecutionModeSchema(),
 			"iac_type": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
@@ -108,7 +125,10 @@ func (r *WorkspaceVcsResource) Schema(ctx context.Context, req resource.SchemaRe
 			},
 			"repository": schema.StringAttribute{
 				Required:    true,
-				Description: "Workspace VCS repository",
+				Description: "Workspace VCS repository. Changing it forces a new workspace, the runs and state of the workspace belong to the previous repository.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"template_id": schema.StringAttribute{
 				Required:    true,
@@ -120,6 +140,12 @@ func (r *WorkspaceVcsResource) Schema(ctx context.Context, req resource.SchemaRe
 				Default:     stringdefault.StaticString("main"),
 				Description: "Workspace VCS branch",
 			},
+			"detect_branch_drift_only_warn": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Report a branch changed outside of Terraform, for example during a hotfix, with a warning instead of planning to change it back, default is `false`. The configured branch is still sent when the workspace is updated for another reason.",
+			},
 			"folder": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
@@ -149,20 +175,14 @@ func (r *WorkspaceVcsResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.airgap = providerData.Airgap
+	r.warnings = providerData.Warnings
+	r.variables = client.NewCrud[client.WorkspaceVariableEntity](r.client, r.endpoint, r.token, "/api/v1/organization/%s/workspace/%s/variable")
+	r.organizations = providerData.Organizations
 
 	tflog.Debug(ctx, "Configuring Workspace VCS resource", map[string]any{"success": true})
 }
@@ -176,6 +196,12 @@ func (r *WorkspaceVcsResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
+	executionMode, err := requestedExecutionMode(plan.ExecutionMode, r.organizationExecutionMode(plan.OrganizationId.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading organization execution mode", apiErrorDetail(err, fmt.Sprintf("Error reading the execution mode of organization %s: %s", plan.OrganizationId.ValueString(), err)))
+		return
+	}
+
 	bodyRequest := &client.WorkspaceEntity{
 		Name:          plan.Name.ValueString(),
 		Description:   plan.Description.ValueString(),
@@ -185,7 +211,7 @@ func (r *WorkspaceVcsResource) Create(ctx context.Context, req resource.CreateRe
 		IaCVersion:    plan.IaCVersion.ValueString(),
 		Folder:        plan.Folder.ValueString(),
 		TemplateId:    plan.TemplateId.ValueString(),
-		ExecutionMode: plan.ExecutionMode.ValueString(),
+		ExecutionMode: executionMode,
 	}
 
 	if !plan.VcsId.IsNull() {
@@ -194,14 +220,14 @@ func (r *WorkspaceVcsResource) Create(ctx context.Context, req resource.CreateRe
 	}
 
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err = jsonapi.MarshalPayload(out, bodyRequest)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
 		return
 	}
 
-	workspaceVcsRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	workspaceVcsRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
 	workspaceVcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceVcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -211,7 +237,7 @@ func (r *WorkspaceVcsResource) Create(ctx context.Context, req resource.CreateRe
 
 	workspaceVcsResponse, err := r.client.Do(workspaceVcsRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request, response status: %s, response body: %s, error: %s", workspaceVcsResponse.Status, workspaceVcsResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request: %s", err))
 		return
 	}
 
@@ -225,7 +251,7 @@ func (r *WorkspaceVcsResource) Create(ctx context.Context, req resource.CreateRe
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), newWorkspaceVcs)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", workspaceVcsResponse.Status, workspaceVcsResponse.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(workspaceVcsResponse, fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", workspaceVcsResponse.Status, workspaceVcsResponse.Body, err)))
 		return
 	}
 
@@ -233,14 +259,15 @@ func (r *WorkspaceVcsResource) Create(ctx context.Context, req resource.CreateRe
 
 	plan.ID = types.StringValue(newWorkspaceVcs.ID)
 	plan.Name = types.StringValue(newWorkspaceVcs.Name)
-	plan.Description = types.StringValue(newWorkspaceVcs.Description)
+	plan.Description = newDescriptionValue(newWorkspaceVcs.Description)
 	plan.Repository = types.StringValue(newWorkspaceVcs.Source)
 	plan.Branch = types.StringValue(newWorkspaceVcs.Branch)
 	plan.IaCType = types.StringValue(newWorkspaceVcs.IaCType)
 	plan.IaCVersion = types.StringValue(newWorkspaceVcs.IaCVersion)
 
 	plan.TemplateId = types.StringValue(newWorkspaceVcs.TemplateId)
-	plan.ExecutionMode = types.StringValue(newWorkspaceVcs.ExecutionMode)
+	plan.ExecutionMode, plan.EffectiveExecutionMode = appliedExecutionMode(plan.ExecutionMode, newWorkspaceVcs.ExecutionMode)
+	plan.Slug = types.StringValue(workspaceSlug(plan.Name.ValueString(), plan.ID.ValueString()))
 
 	if !plan.VcsId.IsNull() {
 		plan.VcsId = types.StringValue(newWorkspaceVcs.Vcs.ID)
@@ -252,6 +279,24 @@ func (r *WorkspaceVcsResource) Create(ctx context.Context, req resource.CreateRe
 
 	tflog.Info(ctx, "Workspace VCS Resource Created", map[string]any{"success": true})
 
+	if !plan.InitialStateFile.IsNull() {
+		if err := uploadInitialState(ctx, r.client, r.endpoint, r.token, plan.ID.ValueString(), plan.InitialStateFile.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("initial_state_file"), "Error uploading initial state", apiErrorDetail(err, fmt.Sprintf("Error uploading initial state %s: %s", plan.InitialStateFile.ValueString(), err)))
+		}
+	}
+
+	if plan.CliArgs != nil {
+		if err := syncWorkspaceCliArgs(ctx, r.variables, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.CliArgs); err != nil {
+			resp.Diagnostics.AddError("Error setting workspace cli arguments", apiErrorDetail(err, fmt.Sprintf("Error setting workspace cli arguments: %s", err)))
+		}
+	}
+
+	if !plan.ProviderMirrorUrl.IsNull() {
+		if err := syncWorkspaceProviderMirror(ctx, r.variables, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.ProviderMirrorUrl); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("provider_mirror_url"), "Error setting workspace provider mirror", apiErrorDetail(err, fmt.Sprintf("Error setting workspace provider mirror: %s", err)))
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -263,49 +308,84 @@ func (r *WorkspaceVcsResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
-	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	workspaceRequest.Header.Add("Content-Type", "application/vnd.api+json")
+	body, validators, notModified, err := client.ConditionalGet(ctx, r.client, r.token, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), readCacheValidators(ctx, req.Private))
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating workspace vcs resource request", fmt.Sprintf("Error creating workspace cli resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing workspace vcs resource request", apiErrorDetail(err, fmt.Sprintf("Error executing workspace vcs resource request: %s", err)))
 		return
 	}
 
-	workspaceResponse, err := r.client.Do(workspaceRequest)
+	workspaceMode := storedExecutionMode(state.ExecutionMode, state.EffectiveExecutionMode)
+
+	// A workspace that did not change since the last read keeps its state,
+	// only the cli arguments stored as variables are read again.
+	if !notModified {
+		workspace := &client.WorkspaceEntity{}
+		if err := jsonapi.UnmarshalPayload(bytes.NewReader(body), workspace); err != nil {
+			resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+			return
+		}
+
+		state.Name = types.StringValue(workspace.Name)
+		state.Description = newDescriptionValue(workspace.Description)
+		workspaceMode = workspace.ExecutionMode
+		state.Repository = types.StringValue(workspace.Source)
+		if state.BranchDriftWarn.ValueBool() && !state.Branch.IsNull() && state.Branch.ValueString() != workspace.Branch {
+			r.warnings.addAttribute(
+				&resp.Diagnostics,
+				path.Root("branch"),
+				"Workspace branch drift",
+				fmt.Sprintf("Workspace %q uses branch %q in Terrakube but %q is configured. The change is not planned because detect_branch_drift_only_warn is enabled.", workspace.Name, workspace.Branch, state.Branch.ValueString()),
+			)
+			// The state keeps the configured branch, the validators are not
+			// stored so the next read compares the branch and warns again.
+			validators = client.CacheValidators{}
+		} else {
+			state.Branch = types.StringValue(workspace.Branch)
+		}
+		state.IaCType = types.StringValue(workspace.IaCType)
+		state.Folder = types.StringValue(workspace.Folder)
+		state.TemplateId = types.StringValue(workspace.TemplateId)
+		state.IaCVersion = types.StringValue(workspace.IaCVersion)
+		state.ID = types.StringValue(workspace.ID)
+
+		if workspace.Vcs != nil {
+			state.VcsId = types.StringValue(workspace.Vcs.ID)
+		}
+	}
+	resp.Diagnostics.Append(writeCacheValidators(ctx, resp.Private, validators)...)
+
+	state.ExecutionMode, state.EffectiveExecutionMode, err = resolveExecutionMode(state.ExecutionMode, workspaceMode, r.organizationExecutionMode(state.OrganizationId.ValueString()))
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace cli resource request, response status: %s, response body: %s, error: %s", workspaceResponse.Status, workspaceResponse.Body, err))
+		resp.Diagnostics.AddError("Error reading organization execution mode", apiErrorDetail(err, fmt.Sprintf("Error reading the execution mode of organization %s: %s", state.OrganizationId.ValueString(), err)))
 		return
 	}
 
-	bodyResponse, err := io.ReadAll(workspaceResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading workspace vcs resource response, response status: %s, response body: %s, error: %s", workspaceResponse.Status, workspaceResponse.Body, err))
+	if state.AllowDowngrade.IsNull() {
+		state.AllowDowngrade = types.BoolValue(false)
 	}
-	workspace := &client.WorkspaceEntity{}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspace)
+	state.Slug = types.StringValue(workspaceSlug(state.Name.ValueString(), state.ID.ValueString()))
 
-	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", workspaceResponse.Status, workspaceResponse.Body, err))
-		return
+	if state.BranchDriftWarn.IsNull() {
+		state.BranchDriftWarn = types.BoolValue(false)
 	}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
-
-	state.Name = types.StringValue(workspace.Name)
-	state.Description = types.StringValue(workspace.Description)
-	state.ExecutionMode = types.StringValue(workspace.ExecutionMode)
-	state.Repository = types.StringValue(workspace.Source)
-	state.Branch = types.StringValue(workspace.Branch)
-	state.IaCType = types.StringValue(workspace.IaCType)
-	state.Folder = types.StringValue(workspace.Folder)
-	state.TemplateId = types.StringValue(workspace.TemplateId)
-	state.IaCVersion = types.StringValue(workspace.IaCVersion)
-	state.ID = types.StringValue(workspace.ID)
+	if state.CliArgs != nil {
+		cliArgs, err := readWorkspaceCliArgs(ctx, r.variables, state.OrganizationId.ValueString(), state.ID.ValueString(), state.CliArgs)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading workspace cli arguments", apiErrorDetail(err, fmt.Sprintf("Error reading workspace cli arguments: %s", err)))
+			return
+		}
+		state.CliArgs = cliArgs
+	}
 
-	if workspace.Vcs != nil {
-		state.VcsId = types.StringValue(workspace.Vcs.ID)
+	if !state.ProviderMirrorUrl.IsNull() {
+		mirrorUrl, err := readWorkspaceProviderMirror(ctx, r.variables, state.OrganizationId.ValueString(), state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading workspace provider mirror", apiErrorDetail(err, fmt.Sprintf("Error reading workspace provider mirror: %s", err)))
+			return
+		}
+		state.ProviderMirrorUrl = mirrorUrl
 	}
 
 	// Set refreshed state
@@ -328,10 +408,19 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
+	// The validators of the last read do not describe the updated workspace.
+	resp.Diagnostics.Append(clearCacheValidators(ctx, resp.Private)...)
+
+	executionMode, err := requestedExecutionMode(plan.ExecutionMode, r.organizationExecutionMode(plan.OrganizationId.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading organization execution mode", apiErrorDetail(err, fmt.Sprintf("Error reading the execution mode of organization %s: %s", plan.OrganizationId.ValueString(), err)))
+		return
+	}
+
 	bodyRequest := &client.WorkspaceEntity{
 		IaCVersion:    plan.IaCVersion.ValueString(),
 		IaCType:       plan.IaCType.ValueString(),
-		ExecutionMode: plan.ExecutionMode.ValueString(),
+		ExecutionMode: executionMode,
 		Description:   plan.Description.ValueString(),
 		Source:        plan.Repository.ValueString(),
 		Branch:        plan.Branch.ValueString(),
@@ -347,7 +436,7 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 	}
 
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err = jsonapi.MarshalPayload(out, bodyRequest)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
@@ -364,7 +453,7 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 
 	organizationResponse, err := r.client.Do(organizationRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request: %s", err))
 		return
 	}
 
@@ -375,6 +464,16 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
+	// Removing vcs_id must detach the relationship explicitly, an omitted
+	// relationship is left unchanged by the PATCH above.
+	if plan.VcsId.IsNull() && !state.VcsId.IsNull() {
+		workspaceUrl := fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString())
+		if err := client.PatchRelationship(ctx, r.client, r.token, workspaceUrl, "vcs", nil); err != nil {
+			resp.Diagnostics.AddError("Error detaching workspace vcs connection", apiErrorDetail(err, fmt.Sprintf("Error detaching workspace vcs connection: %s", err)))
+			return
+		}
+	}
+
 	organizationRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
 	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
@@ -385,13 +484,13 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 
 	organizationResponse, err = r.client.Do(organizationRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request: %s", err))
 		return
 	}
 
 	bodyResponse, err = io.ReadAll(organizationResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading workspace vcs resource response body", fmt.Sprintf("Error reading workspace vcs resource response body, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err))
+		resp.Diagnostics.AddError("Error reading workspace vcs resource response body", responseErrorDetail(organizationResponse, fmt.Sprintf("Error reading workspace vcs resource response body, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -400,24 +499,46 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspace)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(organizationResponse, fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err)))
 		return
 	}
 
 	plan.ID = types.StringValue(state.ID.ValueString())
 	plan.Name = types.StringValue(workspace.Name)
-	plan.Description = types.StringValue(workspace.Description)
+	plan.Description = newDescriptionValue(workspace.Description)
 	plan.Repository = types.StringValue(workspace.Source)
 	plan.Branch = types.StringValue(workspace.Branch)
 	plan.IaCType = types.StringValue(workspace.IaCType)
 	plan.IaCVersion = types.StringValue(workspace.IaCVersion)
-	plan.ExecutionMode = types.StringValue(workspace.ExecutionMode)
+	plan.ExecutionMode, plan.EffectiveExecutionMode = appliedExecutionMode(plan.ExecutionMode, workspace.ExecutionMode)
+	plan.Slug = types.StringValue(workspaceSlug(plan.Name.ValueString(), plan.ID.ValueString()))
 	plan.Folder = types.StringValue(workspace.Folder)
 	plan.TemplateId = types.StringValue(workspace.TemplateId)
 	if workspace.Vcs != nil {
 		plan.VcsId = types.StringValue(workspace.Vcs.ID)
 	}
 
+	if !plan.InitialStateFile.Equal(state.InitialStateFile) {
+		r.warnings.addAttribute(
+			&resp.Diagnostics,
+			path.Root("initial_state_file"),
+			"Initial state file ignored",
+			"initial_state_file is only uploaded when the workspace is created, the new value has not been uploaded.",
+		)
+	}
+
+	if plan.CliArgs != nil || state.CliArgs != nil {
+		if err := syncWorkspaceCliArgs(ctx, r.variables, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.CliArgs); err != nil {
+			resp.Diagnostics.AddError("Error setting workspace cli arguments", apiErrorDetail(err, fmt.Sprintf("Error setting workspace cli arguments: %s", err)))
+		}
+	}
+
+	if !plan.ProviderMirrorUrl.IsNull() || !state.ProviderMirrorUrl.IsNull() {
+		if err := syncWorkspaceProviderMirror(ctx, r.variables, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.ProviderMirrorUrl); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("provider_mirror_url"), "Error setting workspace provider mirror", apiErrorDetail(err, fmt.Sprintf("Error setting workspace provider mirror: %s", err)))
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -457,7 +578,7 @@ func (r *WorkspaceVcsResource) Delete(ctx context.Context, req resource.DeleteRe
 		IaCType:       data.IaCType.ValueString(),
 		TemplateId:    data.TemplateId.ValueString(),
 		IaCVersion:    data.IaCVersion.ValueString(),
-		ExecutionMode: data.ExecutionMode.ValueString(),
+		ExecutionMode: storedExecutionMode(data.ExecutionMode, data.EffectiveExecutionMode),
 		Deleted:       true,
 	}
 
@@ -481,14 +602,28 @@ func (r *WorkspaceVcsResource) Delete(ctx context.Context, req resource.DeleteRe
 	}
 
 	workspaceVcsResponse, err := r.client.Do(workspaceVcsRequest)
-	if err != nil || workspaceVcsResponse.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError("Error executing vcs resource request", fmt.Sprintf("Error executing vcs resource request, response status: %s, response body: %s, error: %s", workspaceVcsResponse.Status, workspaceVcsResponse.Body, err))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing vcs resource request", fmt.Sprintf("Error executing vcs resource request: %s", err))
+		return
+	}
+	defer workspaceVcsResponse.Body.Close()
+
+	if workspaceVcsResponse.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(workspaceVcsResponse.Body)
+		resp.Diagnostics.AddError("Error executing vcs resource request", responseErrorDetail(workspaceVcsResponse, fmt.Sprintf("Error executing vcs resource request, response status: %s, response body: %s", workspaceVcsResponse.Status, bodyResponse)))
 		return
 	}
 
 	tflog.Info(ctx, "Delete response code: "+strconv.Itoa(workspaceVcsResponse.StatusCode))
 }
 
+func (r *WorkspaceVcsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	warnOnIaCVersionDowngrade(ctx, r.warnings, req, resp)
+	r.airgap.warnExternalSource(ctx, req.Plan, "repository", &resp.Diagnostics)
+	planWorkspaceSlug(ctx, req, resp)
+	planEffectiveExecutionMode(ctx, req, resp)
+}
+
 func (r *WorkspaceVcsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	idParts := strings.Split(req.ID, ",")
 
@@ -502,4 +637,15 @@ func (r *WorkspaceVcsResource) ImportState(ctx context.Context, req resource.Imp
 
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), idParts[0])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	addWorkspaceImportReport(ctx, &resp.Diagnostics, r.client, r.endpoint, r.token, idParts[0], idParts[1])
+}
+
+func (r *WorkspaceVcsResource) organizationExecutionMode(organizationId string) func() (string, error) {
+	return func() (string, error) {
+		return organizationExecutionMode(r.organizations, r.client, r.endpoint, r.token, organizationId)
+	}
 }
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func allowVersionDowngradeSchema() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Optional:    true,
+		Computed:    true,
+		Default:     booldefault.StaticBool(false),
+		Description: "Acknowledge that iac_version may be lowered, default is `false`. Without it a downgrade is reported with a warning because a state written by a newer version cannot be read by an older one.",
+	}
+}
+
+// warnOnIaCVersionDowngrade adds a warning when the plan lowers the
+// iac_version of an existing workspace, unless allow_version_downgrade is
+// set. Versions that are unknown or not valid semver are not compared.
+func warnOnIaCVersionDowngrade(ctx context.Context, warnings *warningPolicy, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var current, planned types.String
+	var allowDowngrade types.Bool
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("iac_version"), &current)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("iac_version"), &planned)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("allow_version_downgrade"), &allowDowngrade)...)
+	if resp.Diagnostics.HasError() || allowDowngrade.ValueBool() {
+		return
+	}
+
+	if current.IsNull() || current.IsUnknown() || planned.IsNull() || planned.IsUnknown() {
+		return
+	}
+
+	currentVersion, err := version.NewVersion(current.ValueString())
+	if err != nil {
+		return
+	}
+	plannedVersion, err := version.NewVersion(planned.ValueString())
+	if err != nil {
+		return
+	}
+
+	if plannedVersion.LessThan(currentVersion) {
+		warnings.addAttribute(
+			&resp.Diagnostics,
+			path.Root("iac_version"),
+			"Workspace version downgrade",
+			fmt.Sprintf("iac_version is lowered from %s to %s. A state written by %s may not be readable by %s and the next runs of the workspace would fail. "+
+				"Set allow_version_downgrade = true to acknowledge the downgrade.", current.ValueString(), planned.ValueString(), current.ValueString(), planned.ValueString()),
+		)
+	}
+}
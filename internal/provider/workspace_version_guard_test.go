@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestWorkspaceVersionDowngrade(t *testing.T) {
+	t.Parallel()
+
+	_, server := newFakeAPI(t)
+	terrakube := newTestProvider(t, server.URL, nil)
+
+	for _, test := range []struct {
+		current   string
+		planned   string
+		allow     bool
+		downgrade bool
+	}{
+		{"1.6.0", "1.5.7", false, true},
+		{"1.6.0", "1.5.7", true, false},
+		{"1.5.7", "1.6.0", false, false},
+		{"1.6.0", "1.6.0-rc1", false, true},
+		{"1.6.0-rc1", "1.6.0", false, false},
+		{"1.6.0-beta2", "1.6.0-beta1", false, true},
+		{"1.6.0-beta1", "1.6.0-rc1", false, false},
+		{"1.6.0-alpha20230719", "1.5.7", false, true},
+		{"1.5.7", "1.6.0-alpha20230719", false, false},
+		{"1.6.0", "latest", false, false},
+	} {
+		state := workspaceCliState(terrakube, map[string]tftypes.Value{"iac_version": tftypes.NewValue(tftypes.String, test.current)})
+		config := workspaceCliConfig(terrakube, map[string]tftypes.Value{
+			"iac_version":             tftypes.NewValue(tftypes.String, test.planned),
+			"allow_version_downgrade": tftypes.NewValue(tftypes.Bool, test.allow),
+		})
+
+		plan := terrakube.plan("terrakube_workspace_cli", state, config)
+		if err := diagnosticsError(plan.Diagnostics); err != nil {
+			t.Fatalf("%s to %s: unexpected error: %s", test.current, test.planned, err)
+		}
+		if warned := hasDiagnostic(plan.Diagnostics, tfprotov6.DiagnosticSeverityWarning, "Workspace version downgrade"); warned != test.downgrade {
+			t.Errorf("%s to %s (allow %t): expected a downgrade warning %t, got %v", test.current, test.planned, test.allow, test.downgrade, plan.Diagnostics)
+		}
+	}
+}
@@ -3,16 +3,18 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -28,6 +30,14 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &WorkspaceWebhookResource{}
 var _ resource.ResourceWithImportState = &WorkspaceWebhookResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceWebhookResource{}
+
+const (
+	webhookRegistered            = "registered"
+	webhookNotRegistered         = "not_registered"
+	webhookRegistrationTimeout   = 5 * time.Minute
+	webhookRegistrationFrequency = 5 * time.Second
+)
 
 type WorkspaceWebhookResource struct {
 	client   *http.Client
@@ -44,6 +54,9 @@ type WorkspaceWebhookResourceModel struct {
 	TemplateId     types.String `tfsdk:"template_id"`
 	RemoteHookId   types.String `tfsdk:"remote_hook_id"`
 	Event          types.String `tfsdk:"event"`
+
+	RegistrationStatus        types.String `tfsdk:"registration_status"`
+	WaitForRemoteRegistration types.Bool   `tfsdk:"wait_for_remote_registration"`
 }
 
 func NewWorkspaceWebhookResource() resource.Resource {
@@ -102,6 +115,16 @@ func (r *WorkspaceWebhookResource) Schema(ctx context.Context, req resource.Sche
 					stringvalidator.OneOf("PUSH"),
 				},
 			},
+			"registration_status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Status of the hook on the VCS provider, `registered` when Terrakube was able to create it, `not_registered` otherwise.",
+			},
+			"wait_for_remote_registration": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Wait until the hook is registered on the VCS provider and fail the apply when it is not, default is `false`. When enabled an unregistered webhook is updated on the next apply to retry the registration.",
+			},
 		},
 	}
 }
@@ -121,17 +144,7 @@ func (r *WorkspaceWebhookResource) Configure(ctx context.Context, req resource.C
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.HttpClient
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -167,7 +180,7 @@ func (r *WorkspaceWebhookResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	request.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -177,7 +190,7 @@ func (r *WorkspaceWebhookResource) Create(ctx context.Context, req resource.Crea
 
 	response, err := r.client.Do(request)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request: %s", err))
 		return
 	}
 
@@ -185,27 +198,42 @@ func (r *WorkspaceWebhookResource) Create(ctx context.Context, req resource.Crea
 	if err != nil {
 		tflog.Error(ctx, fmt.Sprintf("Error reading workspace webhook resource, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
 	}
+	if response.StatusCode >= 400 {
+		statusErr := client.NewStatusError(response, bodyResponse)
+		resp.Diagnostics.AddError("Error creating workspace webhook", apiErrorDetail(statusErr, webhookErrorDetail("Error creating workspace webhook", statusErr)))
+		return
+	}
 	webhook := &client.WorkspaceWebhookEntity{}
 
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), webhook)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(response, fmt.Sprintf("Error unmarshal payload response: response status %s, response body: %s, error: %s", response.Status, response.Body, err)))
 		return
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
+	var registrationErr error
+	if plan.WaitForRemoteRegistration.ValueBool() {
+		webhook, registrationErr = r.waitForRemoteRegistration(ctx, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString(), webhook)
+	}
+
 	plan.Path, _ = types.ListValueFrom(ctx, types.StringType, strings.Split(webhook.Path, ","))
 	plan.Branch, _ = types.ListValueFrom(ctx, types.StringType, strings.Split(webhook.Branch, ","))
 	plan.TemplateId = types.StringValue(webhook.TemplateId)
 	plan.RemoteHookId = types.StringValue(webhook.RemoteHookId)
 	plan.Event = types.StringValue(webhook.Event)
 	plan.ID = types.StringValue(webhook.ID)
+	plan.RegistrationStatus = types.StringValue(webhookRegistrationStatus(webhook.RemoteHookId))
 
 	tflog.Info(ctx, "Workspace Webhook Resource Created", map[string]any{"success": true})
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+
+	if registrationErr != nil {
+		resp.Diagnostics.AddError("Remote hook registration failed", apiErrorDetail(registrationErr, registrationErr.Error()))
+	}
 }
 
 func (r *WorkspaceWebhookResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -216,7 +244,7 @@ func (r *WorkspaceWebhookResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	request.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -226,7 +254,7 @@ func (r *WorkspaceWebhookResource) Read(ctx context.Context, req resource.ReadRe
 
 	response, err := r.client.Do(request)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request: %s", err))
 		return
 	}
 
@@ -240,7 +268,7 @@ func (r *WorkspaceWebhookResource) Read(ctx context.Context, req resource.ReadRe
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), webhook)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", responseErrorDetail(response, fmt.Sprintf("Error unmarshal payload response, response status %s, response body: %s, error: %s", response.Status, response.Body, err)))
 		return
 	}
 
@@ -252,6 +280,11 @@ func (r *WorkspaceWebhookResource) Read(ctx context.Context, req resource.ReadRe
 	state.RemoteHookId = types.StringValue(webhook.RemoteHookId)
 	state.Event = types.StringValue(webhook.Event)
 	state.ID = types.StringValue(webhook.ID)
+	state.RegistrationStatus = types.StringValue(webhookRegistrationStatus(webhook.RemoteHookId))
+
+	if state.WaitForRemoteRegistration.IsNull() {
+		state.WaitForRemoteRegistration = types.BoolValue(false)
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -293,7 +326,7 @@ func (r *WorkspaceWebhookResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
-	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	request.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -303,7 +336,7 @@ func (r *WorkspaceWebhookResource) Update(ctx context.Context, req resource.Upda
 
 	response, err := r.client.Do(request)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request: %s", err))
 		return
 	}
 
@@ -311,10 +344,15 @@ func (r *WorkspaceWebhookResource) Update(ctx context.Context, req resource.Upda
 	if err != nil {
 		tflog.Error(ctx, fmt.Sprintf("Error reading Workspace webhook resource response, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
 	}
+	if response.StatusCode >= 400 {
+		statusErr := client.NewStatusError(response, bodyResponse)
+		resp.Diagnostics.AddError("Error updating workspace webhook", apiErrorDetail(statusErr, webhookErrorDetail("Error updating workspace webhook", statusErr)))
+		return
+	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	request, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
+	request, err = http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	request.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -324,13 +362,13 @@ func (r *WorkspaceWebhookResource) Update(ctx context.Context, req resource.Upda
 
 	response, err = r.client.Do(request)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request: %s", err))
 		return
 	}
 
 	bodyResponse, err = io.ReadAll(response.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading workspace webhook resource response body", fmt.Sprintf("Error reading workspace webhook resource response body, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error reading workspace webhook resource response body", responseErrorDetail(response, fmt.Sprintf("Error reading workspace webhook resource response body, response status %s, response body: %s, error: %s", response.Status, response.Body, err)))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -343,14 +381,40 @@ func (r *WorkspaceWebhookResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
+	var registrationErr error
+	if plan.WaitForRemoteRegistration.ValueBool() {
+		webhook, registrationErr = r.waitForRemoteRegistration(ctx, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), webhook)
+	}
+
 	plan.ID = types.StringValue(state.ID.ValueString())
 	plan.Path, _ = types.ListValueFrom(ctx, types.StringType, strings.Split(webhook.Path, ","))
 	plan.Branch, _ = types.ListValueFrom(ctx, types.StringType, strings.Split(webhook.Branch, ","))
 	plan.TemplateId = types.StringValue(webhook.TemplateId)
 	plan.RemoteHookId = types.StringValue(webhook.RemoteHookId)
 	plan.Event = types.StringValue(webhook.Event)
+	plan.RegistrationStatus = types.StringValue(webhookRegistrationStatus(webhook.RemoteHookId))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+
+	if registrationErr != nil {
+		resp.Diagnostics.AddError("Remote hook registration failed", apiErrorDetail(registrationErr, registrationErr.Error()))
+	}
+}
+
+func (r *WorkspaceWebhookResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan WorkspaceWebhookResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plan.WaitForRemoteRegistration.ValueBool() {
+		return
+	}
+
+	// Planning the registered status makes an unregistered webhook show a
+	// change, so the Update retries the registration on the VCS provider.
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("registration_status"), webhookRegistered)...)
 }
 
 func (r *WorkspaceWebhookResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -363,7 +427,7 @@ func (r *WorkspaceWebhookResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
-	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating workspace webhook resource request", fmt.Sprintf("Error creating workspace webhook resource request: %s", err))
@@ -371,8 +435,15 @@ func (r *WorkspaceWebhookResource) Delete(ctx context.Context, req resource.Dele
 	}
 
 	response, err := r.client.Do(request)
-	if err != nil || response.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request: %s", err))
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent {
+		bodyResponse, _ := io.ReadAll(response.Body)
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", responseErrorDetail(response, fmt.Sprintf("Error executing workspace webhook resource request, response status: %s, response body: %s", response.Status, bodyResponse)))
 		return
 	}
 }
@@ -392,3 +463,85 @@ func (r *WorkspaceWebhookResource) ImportState(ctx context.Context, req resource
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), idParts[1])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[2])...)
 }
+
+func (r *WorkspaceWebhookResource) getWebhook(ctx context.Context, organizationId string, workspaceId string, id string) (*client.WorkspaceWebhookEntity, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, organizationId, workspaceId, id), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 400 {
+		return nil, client.NewStatusError(response, bodyResponse)
+	}
+
+	webhook := &client.WorkspaceWebhookEntity{}
+	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), webhook)
+	if err != nil {
+		return nil, fmt.Errorf("response status %s, response body: %s, error: %s", response.Status, string(bodyResponse), err)
+	}
+
+	return webhook, nil
+}
+
+// waitForRemoteRegistration polls the webhook until Terrakube reports the
+// remote hook id created on the VCS provider.
+func (r *WorkspaceWebhookResource) waitForRemoteRegistration(ctx context.Context, organizationId string, workspaceId string, webhook *client.WorkspaceWebhookEntity) (*client.WorkspaceWebhookEntity, error) {
+	if webhook.RemoteHookId != "" {
+		return webhook, nil
+	}
+
+	current := webhook
+	err := waitFor(ctx, webhookRegistrationTimeout, webhookRegistrationFrequency, func() (bool, error) {
+		refreshed, err := r.getWebhook(ctx, organizationId, workspaceId, webhook.ID)
+		if err != nil {
+			return false, err
+		}
+		current = refreshed
+		tflog.Debug(ctx, "Waiting for remote hook registration", map[string]any{"webhook": webhook.ID, "remoteHookId": refreshed.RemoteHookId})
+		return refreshed.RemoteHookId != "", nil
+	})
+
+	if err != nil {
+		return current, fmt.Errorf("Terrakube did not register webhook %s on the VCS provider: %w. %s"+
+			"Check the Terrakube API logs for the VCS provider error, usually the VCS connection is missing the permission to manage repository webhooks. "+
+			"Once fixed, run apply again to retry the registration.", webhook.ID, err, webhookRemoteError(err))
+	}
+
+	return current, nil
+}
+
+// webhookRemoteError returns the error Terrakube relayed from the VCS
+// provider as a sentence, empty when err is not an API error with a message.
+func webhookRemoteError(err error) string {
+	var statusErr *client.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Detail() == "" {
+		return ""
+	}
+	return fmt.Sprintf("VCS provider error: %s. ", statusErr.Detail())
+}
+
+// webhookErrorDetail describes a rejected create or update, Terrakube
+// registers the hook on the VCS provider while handling the request so the
+// message of the VCS provider is in the response.
+func webhookErrorDetail(summary string, statusErr *client.StatusError) string {
+	return fmt.Sprintf("%s, response status: %s. %sResponse body: %s", summary, statusErr.Status, webhookRemoteError(statusErr), statusErr.Body)
+}
+
+func webhookRegistrationStatus(remoteHookId string) string {
+	if remoteHookId == "" {
+		return webhookNotRegistered
+	}
+	return webhookRegistered
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const webhookCollectionPath = "/api/v1/organization/o1/workspace/w1/webhook"
+
+// vcsRejection answers with the error Terrakube relays when the VCS
+// provider refuses to create the hook.
+func vcsRejection(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	fmt.Fprint(w, `{"errors":[{"title":"Bad Request","detail":"GitHub: Resource not accessible by integration"}]}`)
+}
+
+func TestWorkspaceWebhookRemoteError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name    string
+		handle  func(w http.ResponseWriter, r *http.Request) bool
+		summary string
+	}{
+		{
+			name: "create rejected",
+			handle: func(w http.ResponseWriter, r *http.Request) bool {
+				if r.Method != http.MethodPost {
+					return false
+				}
+				vcsRejection(w, http.StatusBadRequest)
+				return true
+			},
+			summary: "Error creating workspace webhook",
+		},
+		{
+			name: "registration failed",
+			handle: func(w http.ResponseWriter, r *http.Request) bool {
+				if r.Method != http.MethodGet || !strings.HasPrefix(r.URL.Path, webhookCollectionPath+"/") {
+					return false
+				}
+				vcsRejection(w, http.StatusBadGateway)
+				return true
+			},
+			summary: "Remote hook registration failed",
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			api, server := newFakeAPI(t)
+			api.handle = test.handle
+			terrakube := newTestProvider(t, server.URL, nil)
+
+			_, diagnostics := terrakube.apply("terrakube_workspace_webhook", terrakube.null("terrakube_workspace_webhook"), terrakube.object("terrakube_workspace_webhook", map[string]tftypes.Value{
+				"organization_id":              tftypes.NewValue(tftypes.String, "o1"),
+				"workspace_id":                 tftypes.NewValue(tftypes.String, "w1"),
+				"wait_for_remote_registration": tftypes.NewValue(tftypes.Bool, true),
+			}))
+
+			for _, diagnostic := range diagnostics {
+				if diagnostic.Summary == test.summary {
+					if !strings.Contains(diagnostic.Detail, "VCS provider error: GitHub: Resource not accessible by integration") {
+						t.Errorf("the detail does not carry the VCS provider error: %s", diagnostic.Detail)
+					}
+					return
+				}
+			}
+			t.Errorf("expected the diagnostic %q, got %v", test.summary, diagnostics)
+		})
+	}
+}
@@ -1,13 +1,12 @@
 package main
 
 import (
-	"context"
 	"flag"
 	"log"
 
 	"terraform-provider-terrakube/internal/provider"
 
-	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
 )
 
 // Run "go generate" to format example terraform files and generate the docs for the registry/website
@@ -35,12 +34,14 @@ func main() {
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := providerserver.ServeOpts{
-		Address: "registry.terraform.io/AzBuilder/terrakube",
-		Debug:   debug,
+	var opts []tf6server.ServeOpt
+	if debug {
+		opts = append(opts, tf6server.WithManagedDebug())
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	// The provider server is wrapped to log the API request summary while
+	// the plugin logs still reach Terraform.
+	err := tf6server.Serve("registry.terraform.io/AzBuilder/terrakube", provider.NewProtocol6WithRequestSummary(provider.New(version)), opts...)
 
 	if err != nil {
 		log.Fatal(err.Error())